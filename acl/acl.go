@@ -41,6 +41,55 @@ func (c capabilitySet) Clear() {
 	}
 }
 
+// NodeAttributes are the attributes of a node matched by the selectors of
+// node rules.
+type NodeAttributes struct {
+	Name       string
+	Class      string
+	Datacenter string
+	Meta       map[string]string
+}
+
+// nodeRule grants a node policy for the nodes with an attribute matching a
+// glob pattern
+type nodeRule struct {
+	// attribute is the attribute of the node matched by the rule, one of
+	// "name", "class", "datacenter" or "meta.<key>"
+	attribute string
+
+	// pattern is the glob pattern matched against the attribute
+	pattern string
+
+	// policy is the node policy granted for the matching nodes
+	policy string
+}
+
+// matches returns true if the node has an attribute matching the rule. Nodes
+// missing the meta key of a rule don't match it.
+func (r *nodeRule) matches(node *NodeAttributes) bool {
+	if node == nil {
+		return false
+	}
+
+	var value string
+	switch r.attribute {
+	case NodeAttributeName:
+		value = node.Name
+	case NodeAttributeClass:
+		value = node.Class
+	case NodeAttributeDatacenter:
+		value = node.Datacenter
+	default:
+		key := strings.TrimPrefix(r.attribute, NodeAttributeMetaPrefix)
+		v, ok := node.Meta[key]
+		if !ok {
+			return false
+		}
+		value = v
+	}
+	return glob.Glob(r.pattern, value)
+}
+
 // ACL object is used to convert a set of policies into a structure that
 // can be efficiently evaluated to determine if an action is allowed.
 type ACL struct {
@@ -64,8 +113,13 @@ type ACL struct {
 	variables         *iradix.Tree
 	wildcardVariables *iradix.Tree
 
-	agent    string
-	node     string
+	agent string
+	node  string
+
+	// nodeRules are the node rules of the policies, granting a node policy
+	// for the nodes matching their selector
+	nodeRules []*nodeRule
+
 	operator string
 	quota    string
 	plugin   string
@@ -225,6 +279,17 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 		if policy.Node != nil {
 			acl.node = maxPrivilege(acl.node, policy.Node.Policy)
 		}
+		for _, rule := range policy.NodeRules {
+			attr, pattern, err := ParseNodeSelector(rule.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid node rule %q: %v", rule.Selector, err)
+			}
+			acl.nodeRules = append(acl.nodeRules, &nodeRule{
+				attribute: attr,
+				pattern:   pattern,
+				policy:    rule.Policy,
+			})
+		}
 		if policy.Operator != nil {
 			acl.operator = maxPrivilege(acl.operator, policy.Operator.Policy)
 		}
@@ -584,6 +649,77 @@ func (a *ACL) AllowNodeWrite() bool {
 	}
 }
 
+// AllowNodeReadFor checks if read operations are allowed for the given node,
+// taking the node rules matching the node into account
+func (a *ACL) AllowNodeReadFor(node *NodeAttributes) bool {
+	if a.management {
+		return true
+	}
+	switch a.nodePolicy(node) {
+	case PolicyWrite, PolicyRead:
+		return true
+	default:
+		return false
+	}
+}
+
+// AllowNodeWriteFor checks if write operations are allowed for the given
+// node, taking the node rules matching the node into account
+func (a *ACL) AllowNodeWriteFor(node *NodeAttributes) bool {
+	if a.management {
+		return true
+	}
+	return a.nodePolicy(node) == PolicyWrite
+}
+
+// AllowAnyNodeRead checks if read operations are allowed for a node, either
+// by the node policy or by a node rule. It is used to reject requests before
+// looking up the node they target.
+func (a *ACL) AllowAnyNodeRead() bool {
+	if a.AllowNodeRead() {
+		return true
+	}
+	if a.node == PolicyDeny {
+		return false
+	}
+	for _, rule := range a.nodeRules {
+		if rule.policy == PolicyWrite || rule.policy == PolicyRead {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowAnyNodeWrite checks if write operations are allowed for a node, either
+// by the node policy or by a node rule. It is used to reject requests before
+// looking up the node they target.
+func (a *ACL) AllowAnyNodeWrite() bool {
+	if a.AllowNodeWrite() {
+		return true
+	}
+	if a.node == PolicyDeny {
+		return false
+	}
+	for _, rule := range a.nodeRules {
+		if rule.policy == PolicyWrite {
+			return true
+		}
+	}
+	return false
+}
+
+// nodePolicy returns the node policy for the given node, which is the maximum
+// privilege of the node policy and of the node rules matching the node
+func (a *ACL) nodePolicy(node *NodeAttributes) string {
+	policy := a.node
+	for _, rule := range a.nodeRules {
+		if rule.matches(node) {
+			policy = maxPrivilege(policy, rule.policy)
+		}
+	}
+	return policy
+}
+
 // AllowOperatorRead checks if read operations are allowed for a operator
 func (a *ACL) AllowOperatorRead() bool {
 	switch {
@@ -448,6 +448,108 @@ func TestWildcardHostVolumeMatching(t *testing.T) {
 	}
 }
 
+func TestNodeRuleMatching(t *testing.T) {
+	ci.Parallel(t)
+
+	gpu := &NodeAttributes{
+		Name:       "gpu-1",
+		Class:      "gpu-a100",
+		Datacenter: "dc1",
+		Meta:       map[string]string{"team": "ml"},
+	}
+	web := &NodeAttributes{
+		Name:       "web-1",
+		Class:      "web",
+		Datacenter: "dc2",
+	}
+
+	tests := []struct {
+		Name      string
+		Policy    string
+		ReadGPU   bool
+		WriteGPU  bool
+		ReadWeb   bool
+		WriteWeb  bool
+		AnyRead   bool
+		AnyWrite  bool
+		NodeRead  bool
+		NodeWrite bool
+	}{
+		{
+			Name:     "class rule",
+			Policy:   `node "class:gpu-*" { policy = "write" }`,
+			ReadGPU:  true,
+			WriteGPU: true,
+			AnyRead:  true,
+			AnyWrite: true,
+		},
+		{
+			Name: "meta rule",
+			Policy: `node { policy = "read" }
+			         node "meta.team:ml" { policy = "write" }`,
+			ReadGPU:  true,
+			WriteGPU: true,
+			ReadWeb:  true,
+			AnyRead:  true,
+			AnyWrite: true,
+			NodeRead: true,
+		},
+		{
+			Name:     "datacenter rule",
+			Policy:   `node "datacenter:dc2" { policy = "read" }`,
+			ReadWeb:  true,
+			AnyRead:  true,
+			AnyWrite: false,
+		},
+		{
+			Name:   "missing meta key",
+			Policy: `node "meta.rack:*" { policy = "write" }`,
+			// No node has the meta key
+			AnyRead:  true,
+			AnyWrite: true,
+		},
+		{
+			Name: "deny rule takes precedence",
+			Policy: `node { policy = "write" }
+			         node "name:gpu-*" { policy = "deny" }`,
+			ReadWeb:   true,
+			WriteWeb:  true,
+			AnyRead:   true,
+			AnyWrite:  true,
+			NodeRead:  true,
+			NodeWrite: true,
+		},
+		{
+			Name: "deny node policy takes precedence",
+			Policy: `node { policy = "deny" }
+			         node "class:gpu-*" { policy = "write" }`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			policy, err := Parse(tc.Policy)
+			require.NoError(t, err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			require.NoError(t, err)
+
+			require.Equal(t, tc.ReadGPU, acl.AllowNodeReadFor(gpu))
+			require.Equal(t, tc.WriteGPU, acl.AllowNodeWriteFor(gpu))
+			require.Equal(t, tc.ReadWeb, acl.AllowNodeReadFor(web))
+			require.Equal(t, tc.WriteWeb, acl.AllowNodeWriteFor(web))
+			require.Equal(t, tc.AnyRead, acl.AllowAnyNodeRead())
+			require.Equal(t, tc.AnyWrite, acl.AllowAnyNodeWrite())
+			require.Equal(t, tc.NodeRead, acl.AllowNodeRead())
+			require.Equal(t, tc.NodeWrite, acl.AllowNodeWrite())
+		})
+	}
+
+	// Management tokens are allowed any node operation
+	require.True(t, ManagementACL.AllowNodeWriteFor(gpu))
+	require.True(t, ManagementACL.AllowAnyNodeWrite())
+}
+
 func TestVariablesMatching(t *testing.T) {
 	ci.Parallel(t)
 
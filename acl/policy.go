@@ -3,8 +3,10 @@ package acl
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
 )
 
 const (
@@ -46,6 +48,8 @@ const (
 	NamespaceCapabilityReadJobScaling       = "read-job-scaling"
 	NamespaceCapabilityScaleJob             = "scale-job"
 	NamespaceCapabilitySubmitRecommendation = "submit-recommendation"
+	NamespaceCapabilityRegisterJobTemplate  = "register-job-template"
+	NamespaceCapabilityRunJobTemplate       = "run-job-template"
 )
 
 var (
@@ -78,12 +82,23 @@ const (
 	VariablesCapabilityDeny    = "deny"
 )
 
+const (
+	// The following are the attributes of a node that can be matched by the
+	// selector of a node rule, such as "class:gpu-*". Meta attributes are
+	// selected by key, such as "meta.team:storage".
+	NodeAttributeName       = "name"
+	NodeAttributeClass      = "class"
+	NodeAttributeDatacenter = "datacenter"
+	NodeAttributeMetaPrefix = "meta."
+)
+
 // Policy represents a parsed HCL or JSON policy.
 type Policy struct {
 	Namespaces  []*NamespacePolicy  `hcl:"namespace,expand"`
 	HostVolumes []*HostVolumePolicy `hcl:"host_volume,expand"`
 	Agent       *AgentPolicy        `hcl:"agent"`
 	Node        *NodePolicy         `hcl:"node"`
+	NodeRules   []*NodeRulePolicy   `hcl:"-"`
 	Operator    *OperatorPolicy     `hcl:"operator"`
 	Quota       *QuotaPolicy        `hcl:"quota"`
 	Plugin      *PluginPolicy       `hcl:"plugin"`
@@ -97,6 +112,7 @@ func (p *Policy) IsEmpty() bool {
 		len(p.HostVolumes) == 0 &&
 		p.Agent == nil &&
 		p.Node == nil &&
+		len(p.NodeRules) == 0 &&
 		p.Operator == nil &&
 		p.Quota == nil &&
 		p.Plugin == nil
@@ -134,6 +150,13 @@ type NodePolicy struct {
 	Policy string
 }
 
+// NodeRulePolicy is the policy for the nodes matching a selector, such as
+// "class:gpu-*" or "meta.team:storage"
+type NodeRulePolicy struct {
+	Selector string `hcl:"-"`
+	Policy   string
+}
+
 type OperatorPolicy struct {
 	Policy string
 }
@@ -173,7 +196,8 @@ func isNamespaceCapabilityValid(cap string) bool {
 		NamespaceCapabilityReadFS, NamespaceCapabilityAllocLifecycle,
 		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec,
 		NamespaceCapabilityCSIReadVolume, NamespaceCapabilityCSIWriteVolume, NamespaceCapabilityCSIListVolume, NamespaceCapabilityCSIMountVolume, NamespaceCapabilityCSIRegisterPlugin,
-		NamespaceCapabilityListScalingPolicies, NamespaceCapabilityReadScalingPolicy, NamespaceCapabilityReadJobScaling, NamespaceCapabilityScaleJob:
+		NamespaceCapabilityListScalingPolicies, NamespaceCapabilityReadScalingPolicy, NamespaceCapabilityReadJobScaling, NamespaceCapabilityScaleJob,
+		NamespaceCapabilityRegisterJobTemplate, NamespaceCapabilityRunJobTemplate:
 		return true
 	// Separate the enterprise-only capabilities
 	case NamespaceCapabilitySentinelOverride, NamespaceCapabilitySubmitRecommendation:
@@ -223,6 +247,8 @@ func expandNamespacePolicy(policy string) []string {
 		NamespaceCapabilityCSIMountVolume,
 		NamespaceCapabilityCSIWriteVolume,
 		NamespaceCapabilitySubmitRecommendation,
+		NamespaceCapabilityRegisterJobTemplate,
+		NamespaceCapabilityRunJobTemplate,
 	}...)
 
 	switch policy {
@@ -299,6 +325,9 @@ func Parse(rules string) (*Policy, error) {
 	if err := hclDecode(p, rules); err != nil {
 		return nil, fmt.Errorf("Failed to parse ACL Policy: %v", err)
 	}
+	if err := hclDecodeNodeRules(p, rules); err != nil {
+		return nil, fmt.Errorf("Failed to parse ACL Policy: %v", err)
+	}
 
 	// At least one valid policy must be specified, we don't want to store only
 	// raw data
@@ -378,6 +407,15 @@ func Parse(rules string) (*Policy, error) {
 		return nil, fmt.Errorf("Invalid node policy: %#v", p.Node)
 	}
 
+	for _, rule := range p.NodeRules {
+		if _, _, err := ParseNodeSelector(rule.Selector); err != nil {
+			return nil, fmt.Errorf("Invalid node rule %q: %v", rule.Selector, err)
+		}
+		if !isPolicyValid(rule.Policy) {
+			return nil, fmt.Errorf("Invalid node rule policy: %#v", rule)
+		}
+	}
+
 	if p.Operator != nil && !isPolicyValid(p.Operator.Policy) {
 		return nil, fmt.Errorf("Invalid operator policy: %#v", p.Operator)
 	}
@@ -402,3 +440,79 @@ func hclDecode(p *Policy, rules string) (err error) {
 
 	return hcl.Decode(p, rules)
 }
+
+// hclDecodeNodeRules decodes the labeled node blocks of the rules into node
+// rules. The labeled blocks can't be decoded along the unlabeled node block by
+// hcl.Decode, as both share the same key.
+func hclDecodeNodeRules(p *Policy, rules string) (err error) {
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			err = fmt.Errorf("invalid acl policy: %v", rerr)
+		}
+	}()
+
+	root, err := hcl.Parse(rules)
+	if err != nil {
+		return err
+	}
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return fmt.Errorf("root should be an object")
+	}
+
+	// The unlabeled node block was decoded into an empty node policy if the
+	// rules only have labeled node blocks
+	matches := list.Filter("node")
+	if len(matches.Elem().Items) == 0 {
+		p.Node = nil
+	}
+
+	// JSON rules nest the node rules in the node object along the node
+	// policy when both are set
+	items := matches.Children().Items
+	for _, item := range matches.Elem().Items {
+		if obj, ok := item.Val.(*ast.ObjectType); ok {
+			for _, nested := range obj.List.Items {
+				if _, ok := nested.Val.(*ast.ObjectType); ok {
+					items = append(items, nested)
+				}
+			}
+		}
+	}
+
+	for _, item := range items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("node rules must have exactly one selector")
+		}
+		selector, ok := item.Keys[0].Token.Value().(string)
+		if !ok {
+			return fmt.Errorf("node rule selector must be a string")
+		}
+		rule := &NodeRulePolicy{Selector: selector}
+		if err := hcl.DecodeObject(rule, item.Val); err != nil {
+			return err
+		}
+		p.NodeRules = append(p.NodeRules, rule)
+	}
+	return nil
+}
+
+// ParseNodeSelector splits the selector of a node rule into the attribute of
+// the node it matches and the glob pattern matched against the attribute.
+// The attribute is one of "name", "class", "datacenter" or "meta.<key>".
+func ParseNodeSelector(selector string) (string, string, error) {
+	attr, pattern, ok := strings.Cut(selector, ":")
+	if !ok {
+		return "", "", fmt.Errorf("selector must be of the form <attribute>:<pattern>")
+	}
+	switch {
+	case attr == NodeAttributeName, attr == NodeAttributeClass, attr == NodeAttributeDatacenter:
+	case strings.HasPrefix(attr, NodeAttributeMetaPrefix) && len(attr) > len(NodeAttributeMetaPrefix):
+	default:
+		return "", "", fmt.Errorf("unknown node attribute %q", attr)
+	}
+	if pattern == "" {
+		return "", "", fmt.Errorf("missing pattern for node attribute %q", attr)
+	}
+	return attr, pattern, nil
+}
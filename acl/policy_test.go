@@ -126,6 +126,8 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityCSIMountVolume,
 							NamespaceCapabilityCSIWriteVolume,
 							NamespaceCapabilitySubmitRecommendation,
+							NamespaceCapabilityRegisterJobTemplate,
+							NamespaceCapabilityRunJobTemplate,
 						},
 					},
 					{
@@ -236,6 +238,102 @@ func TestParse(t *testing.T) {
 			"Invalid node policy",
 			nil,
 		},
+		{
+			`
+			node {
+				policy = "read"
+			}
+			node "class:gpu-*" {
+				policy = "write"
+			}
+			node "meta.team:storage" {
+				policy = "deny"
+			}
+			`,
+			"",
+			&Policy{
+				Node: &NodePolicy{
+					Policy: PolicyRead,
+				},
+				NodeRules: []*NodeRulePolicy{
+					{
+						Selector: "class:gpu-*",
+						Policy:   PolicyWrite,
+					},
+					{
+						Selector: "meta.team:storage",
+						Policy:   PolicyDeny,
+					},
+				},
+			},
+		},
+		{
+			`
+			node "datacenter:dc1" {
+				policy = "read"
+			}
+			`,
+			"",
+			&Policy{
+				NodeRules: []*NodeRulePolicy{
+					{
+						Selector: "datacenter:dc1",
+						Policy:   PolicyRead,
+					},
+				},
+			},
+		},
+		{
+			`
+			{
+				"node": {
+					"policy": "read",
+					"name:web-*": {
+						"policy": "write"
+					}
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Node: &NodePolicy{
+					Policy: PolicyRead,
+				},
+				NodeRules: []*NodeRulePolicy{
+					{
+						Selector: "name:web-*",
+						Policy:   PolicyWrite,
+					},
+				},
+			},
+		},
+		{
+			`
+			node "class:gpu-*" {
+				policy = "foo"
+			}
+			`,
+			"Invalid node rule policy",
+			nil,
+		},
+		{
+			`
+			node "pool:gpu" {
+				policy = "read"
+			}
+			`,
+			`unknown node attribute "pool"`,
+			nil,
+		},
+		{
+			`
+			node "class" {
+				policy = "read"
+			}
+			`,
+			"selector must be of the form <attribute>:<pattern>",
+			nil,
+		},
 		{
 			`
 			operator {
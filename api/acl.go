@@ -345,6 +345,22 @@ type ACLToken struct {
 	// creation. This is a string version of a time.Duration like "2m".
 	ExpirationTTL time.Duration `json:",omitempty"`
 
+	// Disabled is set on the local client tokens that were not used within
+	// the idle timeout of the region. Disabled tokens are rejected until they
+	// are enabled again by updating the token.
+	Disabled bool `json:",omitempty"`
+
+	// EnableTime is when the token was last enabled after being disabled. It
+	// is read-only.
+	EnableTime *time.Time `json:",omitempty"`
+
+	// LastUsedTime and LastUsedIP detail when the token was last used to
+	// make a request handled by the servers of the region, and the IP address
+	// the request came from. They are read-only and not set if the token was
+	// never used.
+	LastUsedTime *time.Time `json:",omitempty"`
+	LastUsedIP   string     `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -377,6 +393,14 @@ type ACLTokenListStub struct {
 	// indicates no expiration has been set on the token.
 	ExpirationTime *time.Time `json:",omitempty"`
 
+	// Disabled is set on tokens disabled for being idle.
+	Disabled bool `json:",omitempty"`
+
+	// LastUsedTime and LastUsedIP detail when and from where the token was
+	// last used. They are not set if the token was never used.
+	LastUsedTime *time.Time `json:",omitempty"`
+	LastUsedIP   string     `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
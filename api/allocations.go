@@ -247,6 +247,7 @@ type Allocation struct {
 	RescheduleTracker     *RescheduleTracker
 	PreemptedAllocations  []string
 	PreemptedByAllocation string
+	PreemptedTime         int64
 	CreateIndex           uint64
 	ModifyIndex           uint64
 	AllocModifyIndex      uint64
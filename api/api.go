@@ -165,7 +165,9 @@ type HttpBasicAuth struct {
 
 // Config is used to configure the creation of a client
 type Config struct {
-	// Address is the address of the Nomad agent
+	// Address is the address of the Nomad agent. An address with the unix
+	// scheme, such as "unix:///secrets/api.sock", connects to the agent over
+	// a unix socket.
 	Address string
 
 	// Region to use. If not provided, the default agent region is used.
@@ -464,7 +466,9 @@ func NewClient(config *Config) (*Client, error) {
 
 	if config.Address == "" {
 		config.Address = defConfig.Address
-	} else if _, err := url.Parse(config.Address); err != nil {
+	}
+	address, err := url.Parse(config.Address)
+	if err != nil {
 		return nil, fmt.Errorf("invalid address '%s': %v", config.Address, err)
 	}
 
@@ -474,6 +478,9 @@ func NewClient(config *Config) (*Client, error) {
 		if err := ConfigureTLS(httpClient, config.TLSConfig); err != nil {
 			return nil, err
 		}
+		if address.Scheme == "unix" {
+			configureUnixSocket(httpClient, address.Path)
+		}
 	}
 
 	client := &Client{
@@ -483,6 +490,16 @@ func NewClient(config *Config) (*Client, error) {
 	return client, nil
 }
 
+// configureUnixSocket configures the HTTP client to connect to the agent over
+// the unix socket at path, whatever the host of the request.
+func configureUnixSocket(httpClient *http.Client, path string) {
+	transport := httpClient.Transport.(*http.Transport)
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", path)
+	}
+}
+
 // Close closes the client's idle keep-alived connections. The default
 // client configuration uses keep-alive to maintain connections and
 // you should instantiate a single Client and reuse it for all
@@ -723,13 +740,19 @@ func (c *Client) newRequest(method, path string) (*request, error) {
 	if err != nil {
 		return nil, err
 	}
+	scheme, host := base.Scheme, base.Host
+	if scheme == "unix" {
+		// Requests over a unix socket are sent to a placeholder host, as
+		// the HTTP client dials the socket instead
+		scheme, host = "http", "localhost"
+	}
 	r := &request{
 		config: &c.config,
 		method: method,
 		url: &url.URL{
-			Scheme:  base.Scheme,
+			Scheme:  scheme,
 			User:    base.User,
-			Host:    base.Host,
+			Host:    host,
 			Path:    u.Path,
 			RawPath: u.RawPath,
 		},
@@ -8,9 +8,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -352,6 +354,29 @@ func TestClientHeader(t *testing.T) {
 	}
 }
 
+func TestClient_UnixSocket(t *testing.T) {
+	testutil.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "api.sock")
+	ln, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"127.0.0.1:4647"`))
+	}))
+	srv.Listener = ln
+	srv.Start()
+	defer srv.Close()
+
+	c, err := NewClient(&Config{Address: "unix://" + path})
+	require.NoError(t, err)
+	require.Equal(t, "unix://"+path, c.Address())
+
+	leader, err := c.Status().Leader()
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:4647", leader)
+}
+
 func TestQueryString(t *testing.T) {
 	testutil.Parallel(t)
 	c, s := makeClient(t, nil, nil)
@@ -64,6 +64,18 @@ func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*Allocation
 	return resp, qm, nil
 }
 
+// Explain re-runs feasibility checking for the task groups of an evaluation
+// against the current cluster state and returns a per-node breakdown of why
+// each task group can or can not be placed.
+func (e *Evaluations) Explain(evalID string, q *QueryOptions) (*EvalExplainResponse, *QueryMeta, error) {
+	var resp EvalExplainResponse
+	qm, err := e.client.query("/v1/evaluation/"+evalID+"/explain", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 const (
 	EvalStatusBlocked   = "blocked"
 	EvalStatusPending   = "pending"
@@ -128,6 +140,37 @@ type EvaluationStub struct {
 	ModifyTime        int64
 }
 
+// EvalExplainResponse is used to serialize the placement explanation of an
+// evaluation's task groups.
+type EvalExplainResponse struct {
+	EvalID     string
+	JobID      string
+	TaskGroups map[string]*TaskGroupExplanation
+}
+
+// TaskGroupExplanation is the placement explanation of a single task group.
+// Nodes is sorted so the node that came closest to fitting is first.
+type TaskGroupExplanation struct {
+	TaskGroup string
+	Previous  *AllocationMetric
+	Metrics   *AllocationMetric
+	Nodes     []*NodeExplanation
+}
+
+// NodeExplanation describes whether a task group fits a single node.
+type NodeExplanation struct {
+	NodeID             string
+	NodeName           string
+	NodeClass          string
+	Datacenter         string
+	Feasible           bool
+	FilteredBy         string
+	ExhaustedDimension string
+	Score              float64
+	CPUShortfall       int64
+	MemoryShortfall    int64
+}
+
 type EvalDeleteRequest struct {
 	EvalIDs []string
 	WriteRequest
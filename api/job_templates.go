@@ -0,0 +1,135 @@
+package api
+
+import (
+	"net/url"
+	"sort"
+)
+
+// JobTemplates is used to query the job template endpoints.
+type JobTemplates struct {
+	client *Client
+}
+
+// JobTemplates returns a new handle on the job templates.
+func (c *Client) JobTemplates() *JobTemplates {
+	return &JobTemplates{client: c}
+}
+
+// List is used to list the job templates of the namespace of the query
+// options. Use the "*" namespace to list the templates of all namespaces.
+func (j *JobTemplates) List(q *QueryOptions) ([]*JobTemplateListStub, *QueryMeta, error) {
+	var resp []*JobTemplateListStub
+	qm, err := j.client.query("/v1/job-templates", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(resp, func(i, k int) bool {
+		if resp[i].Namespace != resp[k].Namespace {
+			return resp[i].Namespace < resp[k].Namespace
+		}
+		return resp[i].Name < resp[k].Name
+	})
+	return resp, qm, nil
+}
+
+// PrefixList is used to list the job templates whose name starts with the
+// prefix.
+func (j *JobTemplates) PrefixList(prefix string, q *QueryOptions) ([]*JobTemplateListStub, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{Prefix: prefix}
+	} else {
+		q.Prefix = prefix
+	}
+	return j.List(q)
+}
+
+// Info is used to query a single job template by its name.
+func (j *JobTemplates) Info(name string, q *QueryOptions) (*JobTemplate, *QueryMeta, error) {
+	var resp JobTemplate
+	qm, err := j.client.query("/v1/job-template/"+url.PathEscape(name), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to register or update a job template. The returned
+// template includes the input variables declared by its source.
+func (j *JobTemplates) Register(tmpl *JobTemplate, q *WriteOptions) (*JobTemplate, *WriteMeta, error) {
+	var resp JobTemplate
+	wm, err := j.client.write("/v1/job-template/"+url.PathEscape(tmpl.Name), tmpl, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete a job template. The jobs run from the template
+// are not affected.
+func (j *JobTemplates) Delete(name string, q *WriteOptions) (*WriteMeta, error) {
+	wm, err := j.client.delete("/v1/job-template/"+url.PathEscape(name), nil, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Run is used to render a job template with the values of its input
+// variables and register the resulting job.
+func (j *JobTemplates) Run(name string, vars map[string]string, q *WriteOptions) (*JobTemplateRunResponse, *WriteMeta, error) {
+	req := &JobTemplateRunRequest{Variables: vars}
+	var resp JobTemplateRunResponse
+	wm, err := j.client.write("/v1/job-template/"+url.PathEscape(name)+"/run", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// JobTemplate is an HCL2 jobspec stored on the servers, which can be run
+// with the values of its input variables to register a job.
+type JobTemplate struct {
+	Name        string
+	Namespace   string
+	Description string
+	Source      string
+
+	// Variables are the input variables declared by the source. They are
+	// set by the server when the template is registered.
+	Variables []*JobTemplateVariable
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobTemplateVariable is an input variable declared by a job template.
+type JobTemplateVariable struct {
+	Name        string
+	Description string
+	Required    bool
+	Sensitive   bool
+}
+
+// JobTemplateListStub is the summary of a job template returned when listing
+// templates.
+type JobTemplateListStub struct {
+	Name        string
+	Namespace   string
+	Description string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobTemplateRunRequest is used to run a job template.
+type JobTemplateRunRequest struct {
+	Variables map[string]string
+}
+
+// JobTemplateRunResponse is the response to running a job template.
+type JobTemplateRunResponse struct {
+	JobID           string
+	EvalID          string
+	EvalCreateIndex uint64
+	JobModifyIndex  uint64
+	Warnings        string
+}
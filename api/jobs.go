@@ -116,6 +116,7 @@ type RegisterOptions struct {
 	PolicyOverride bool
 	PreserveCounts bool
 	EvalPriority   int
+	Submission     *JobSubmission
 }
 
 // Register is used to register a new job. It returns the ID
@@ -145,6 +146,7 @@ func (j *Jobs) RegisterOpts(job *Job, opts *RegisterOptions, q *WriteOptions) (*
 		req.PolicyOverride = opts.PolicyOverride
 		req.PreserveCounts = opts.PreserveCounts
 		req.EvalPriority = opts.EvalPriority
+		req.Submission = opts.Submission
 	}
 
 	var resp JobRegisterResponse
@@ -252,6 +254,60 @@ func (j *Jobs) Versions(jobID string, diffs bool, q *QueryOptions) ([]*Job, []*J
 	return resp.Versions, resp.Diffs, qm, nil
 }
 
+// Submission is used to retrieve the jobspec the given version of the job was
+// parsed from.
+func (j *Jobs) Submission(jobID string, version int, q *QueryOptions) (*JobSubmission, *QueryMeta, error) {
+	var sub JobSubmission
+	qm, err := j.client.query(fmt.Sprintf("/v1/job/%s/submission?version=%d", url.PathEscape(jobID), version), &sub, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &sub, qm, nil
+}
+
+// DiffVersions is used to diff two versions of a job. The version to diff to
+// defaults to the latest version, and the version to diff from to the one
+// before it.
+func (j *Jobs) DiffVersions(jobID string, from, to *uint64, q *QueryOptions) (*JobDiffResponse, *QueryMeta, error) {
+	var resp JobDiffResponse
+	qm, err := j.client.query(jobDiffPath(jobID, from, to), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// DiffJob is used to diff a version of a job and a job which isn't
+// registered, such as a local jobspec. The version to diff from defaults to
+// the latest version.
+func (j *Jobs) DiffJob(job *Job, from *uint64, q *QueryOptions) (*JobDiffResponse, *QueryMeta, error) {
+	if job == nil || job.ID == nil {
+		return nil, nil, errors.New("missing job ID")
+	}
+	var resp JobDiffResponse
+	req := &JobDiffRequest{Job: job}
+	qm, err := j.client.putQuery(jobDiffPath(*job.ID, from, nil), req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+func jobDiffPath(jobID string, from, to *uint64) string {
+	v := url.Values{}
+	if from != nil {
+		v.Set("from", strconv.FormatUint(*from, 10))
+	}
+	if to != nil {
+		v.Set("to", strconv.FormatUint(*to, 10))
+	}
+	path := "/v1/job/" + url.PathEscape(jobID) + "/diff"
+	if len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+	return path
+}
+
 // Allocations is used to return the allocs for a given job ID.
 func (j *Jobs) Allocations(jobID string, allAllocs bool, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
 	var resp []*AllocationListStub
@@ -453,13 +509,38 @@ func (j *Jobs) Summary(jobID string, q *QueryOptions) (*JobSummary, *QueryMeta,
 
 func (j *Jobs) Dispatch(jobID string, meta map[string]string,
 	payload []byte, idPrefixTemplate string, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
-	var resp JobDispatchResponse
-	req := &JobDispatchRequest{
-		JobID:            jobID,
+	opts := &DispatchOptions{
 		Meta:             meta,
 		Payload:          payload,
 		IdPrefixTemplate: idPrefixTemplate,
 	}
+	return j.DispatchOpts(jobID, opts, q)
+}
+
+// DispatchOptions are the options to dispatch a parameterized job with.
+type DispatchOptions struct {
+	Meta             map[string]string
+	Payload          []byte
+	IdPrefixTemplate string
+
+	// PayloadVariable is the variable to read the payload from, instead of
+	// Payload. The caller needs read access to the variable.
+	PayloadVariable *DispatchPayloadVariable
+}
+
+// DispatchOpts is used to dispatch a parameterized job with the given
+// options. Set the IdempotencyToken of the write options to only dispatch
+// the job once: if a job was already dispatched with the token, its ID is
+// returned with AlreadyDispatched set.
+func (j *Jobs) DispatchOpts(jobID string, opts *DispatchOptions, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
+	var resp JobDispatchResponse
+	req := &JobDispatchRequest{JobID: jobID}
+	if opts != nil {
+		req.Meta = opts.Meta
+		req.Payload = opts.Payload
+		req.PayloadVariable = opts.PayloadVariable
+		req.IdPrefixTemplate = opts.IdPrefixTemplate
+	}
 	wm, err := j.client.write("/v1/job/"+url.PathEscape(jobID)+"/dispatch", req, &resp, q)
 	if err != nil {
 		return nil, nil, err
@@ -863,6 +944,14 @@ type ParameterizedJobConfig struct {
 	MetaOptional []string `mapstructure:"meta_optional" hcl:"meta_optional,optional"`
 }
 
+// PreemptionPolicy is used to configure how the allocations of a job may be
+// preempted by higher priority jobs.
+type PreemptionPolicy struct {
+	NeverPreempt       bool `mapstructure:"never_preempt" hcl:"never_preempt,optional"`
+	MaxPerHour         int  `mapstructure:"max_per_hour" hcl:"max_per_hour,optional"`
+	ProtectDeployments bool `mapstructure:"protect_deployments" hcl:"protect_deployments,optional"`
+}
+
 // Job is used to serialize a job.
 type Job struct {
 	/* Fields parsed from HCL config */
@@ -883,6 +972,7 @@ type Job struct {
 	Spreads          []*Spread               `hcl:"spread,block"`
 	Periodic         *PeriodicConfig         `hcl:"periodic,block"`
 	ParameterizedJob *ParameterizedJobConfig `hcl:"parameterized,block"`
+	Preemption       *PreemptionPolicy       `hcl:"preemption,block"`
 	Reschedule       *ReschedulePolicy       `hcl:"reschedule,block"`
 	Migrate          *MigrateStrategy        `hcl:"migrate,block"`
 	Meta             map[string]string       `hcl:"meta,block"`
@@ -1265,9 +1355,54 @@ type JobRegisterRequest struct {
 	// change the job priority which also impacts preemption.
 	EvalPriority int `json:",omitempty"`
 
+	// Submission is the jobspec the job was parsed from, stored alongside
+	// the new version of the job.
+	Submission *JobSubmission `json:",omitempty"`
+
 	WriteRequest
 }
 
+const (
+	// JobSubmissionFormatHCL1 is the format of a jobspec parsed with the
+	// HCL1 parser.
+	JobSubmissionFormatHCL1 = "hcl1"
+
+	// JobSubmissionFormatHCL2 is the format of a jobspec parsed with the
+	// HCL2 parser.
+	JobSubmissionFormatHCL2 = "hcl2"
+
+	// JobSubmissionFormatJSON is the format of a jobspec submitted as JSON.
+	JobSubmissionFormatJSON = "json"
+
+	// JobSubmissionSensitiveValue replaces the value of the variables
+	// declared as sensitive.
+	JobSubmissionSensitiveValue = "<sensitive>"
+)
+
+// JobSubmission is the jobspec a version of a job was parsed from.
+type JobSubmission struct {
+	// Source is the content of the jobspec.
+	Source string
+
+	// Format is the format of the source: hcl1, hcl2 or json.
+	Format string
+
+	// Variables are the values of the HCL2 input variables set from the
+	// command line, variable files or the environment, formatted as HCL
+	// expressions. The values of sensitive variables are redacted.
+	Variables map[string]string `json:",omitempty"`
+
+	// Includes are the sources of the files included by an HCL2 jobspec,
+	// keyed by their path relative to the directory of the jobspec.
+	Includes map[string]string `json:",omitempty"`
+
+	// The fields below are set by the server.
+	Namespace      string `json:",omitempty"`
+	JobID          string `json:",omitempty"`
+	Version        uint64 `json:",omitempty"`
+	JobModifyIndex uint64 `json:",omitempty"`
+}
+
 // JobRegisterResponse is used to respond to a job registration
 type JobRegisterResponse struct {
 	EvalID          string
@@ -1289,6 +1424,30 @@ type JobDeregisterResponse struct {
 	QueryMeta
 }
 
+// JobDiffRequest is used to diff a version of a job and a job which isn't
+// registered.
+type JobDiffRequest struct {
+	Job *Job
+}
+
+// JobDiffResponse is the diff of two versions of a job, or of a version of a
+// job and a job which isn't registered.
+type JobDiffResponse struct {
+	Diff *JobDiff
+
+	// FromVersion is the version the diff is from. It is nil if the job has
+	// no version to diff from, in which case the whole job is added.
+	FromVersion *uint64
+
+	// ToVersion is the version the diff is to. It is nil when diffing to a
+	// job which isn't registered.
+	ToVersion *uint64
+
+	// Warnings are the warnings of the admission controllers for the job
+	// which isn't registered.
+	Warnings string
+}
+
 type JobPlanRequest struct {
 	Job            *Job
 	Diff           bool
@@ -1349,8 +1508,9 @@ type ObjectDiff struct {
 }
 
 type PlanAnnotations struct {
-	DesiredTGUpdates map[string]*DesiredUpdates
-	PreemptedAllocs  []*AllocationListStub
+	DesiredTGUpdates  map[string]*DesiredUpdates
+	PreemptedAllocs   []*AllocationListStub
+	PreemptionReasons map[string]string
 }
 
 type DesiredUpdates struct {
@@ -1369,13 +1529,26 @@ type JobDispatchRequest struct {
 	Payload          []byte
 	Meta             map[string]string
 	IdPrefixTemplate string
+	PayloadVariable  *DispatchPayloadVariable
+}
+
+// DispatchPayloadVariable is the variable a dispatched job reads its payload
+// from, in the namespace of the job.
+type DispatchPayloadVariable struct {
+	// Path is the path of the variable
+	Path string
+
+	// Item is the item of the variable to use as the payload. If empty, the
+	// payload is the JSON object of all the items of the variable.
+	Item string
 }
 
 type JobDispatchResponse struct {
-	DispatchedJobID string
-	EvalID          string
-	EvalCreateIndex uint64
-	JobCreateIndex  uint64
+	DispatchedJobID   string
+	EvalID            string
+	EvalCreateIndex   uint64
+	JobCreateIndex    uint64
+	AlreadyDispatched bool
 	WriteMeta
 }
 
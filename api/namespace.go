@@ -47,6 +47,17 @@ func (n *Namespaces) Info(name string, q *QueryOptions) (*Namespace, *QueryMeta,
 	return &resp, qm, nil
 }
 
+// Usage is used to query the resources used by the allocations of a
+// namespace.
+func (n *Namespaces) Usage(name string, q *QueryOptions) (*NamespaceUsage, *QueryMeta, error) {
+	var resp NamespaceUsage
+	qm, err := n.client.query("/v1/namespace/"+name+"/usage", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Register is used to register a namespace.
 func (n *Namespaces) Register(namespace *Namespace, q *WriteOptions) (*WriteMeta, error) {
 	wm, err := n.client.write("/v1/namespace", namespace, nil, q)
@@ -71,6 +82,7 @@ type Namespace struct {
 	Description  string
 	Quota        string
 	Capabilities *NamespaceCapabilities `hcl:"capabilities,block"`
+	Limits       *NamespaceLimits       `hcl:"limits,block"`
 	Meta         map[string]string
 	CreateIndex  uint64
 	ModifyIndex  uint64
@@ -81,6 +93,23 @@ type NamespaceCapabilities struct {
 	DisabledTaskDrivers []string `hcl:"disabled_task_drivers"`
 }
 
+// NamespaceLimits is the maximum amount of resources the allocations of a
+// namespace may use. A zero value means the dimension is not limited.
+type NamespaceLimits struct {
+	CPU      int `hcl:"cpu"`
+	MemoryMB int `hcl:"memory"`
+	Allocs   int `hcl:"allocs"`
+}
+
+// NamespaceUsage is the amount of resources used by the non-terminal
+// allocations of a namespace.
+type NamespaceUsage struct {
+	Namespace string
+	CPU       int
+	MemoryMB  int
+	Allocs    int
+}
+
 // NamespaceIndexSort is a wrapper to sort Namespaces by CreateIndex. We
 // reverse the test so that we get the highest index first.
 type NamespaceIndexSort []*Namespace
@@ -582,6 +582,14 @@ type NodeCpuResources struct {
 	CpuShares          int64
 	TotalCpuCores      uint16
 	ReservableCpuCores []uint16
+	NUMANodes          []*NodeNUMANode
+	ThreadSiblings     [][]uint16
+}
+
+// NodeNUMANode is a NUMA node of the machine and the cpus local to it.
+type NodeNUMANode struct {
+	ID    uint16
+	Cores []uint16
 }
 
 type NodeMemoryResources struct {
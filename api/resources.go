@@ -14,6 +14,7 @@ type Resources struct {
 	DiskMB      *int               `mapstructure:"disk" hcl:"disk,optional"`
 	Networks    []*NetworkResource `hcl:"network,block"`
 	Devices     []*RequestedDevice `hcl:"device,block"`
+	NUMA        *NUMAResource      `hcl:"numa,block"`
 
 	// COMPAT(0.10)
 	// XXX Deprecated. Please do not use. The field will be removed in Nomad
@@ -47,6 +48,9 @@ func (r *Resources) Canonicalize() {
 	for _, d := range r.Devices {
 		d.Canonicalize()
 	}
+	if r.NUMA != nil {
+		r.NUMA.Canonicalize()
+	}
 }
 
 // DefaultResources is a small resources object that contains the
@@ -94,6 +98,22 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.NUMA != nil {
+		r.NUMA = other.NUMA
+	}
+}
+
+// NUMAResource is used to request that the reserved cores of a task, and the
+// devices local to them, are placed on a single NUMA node.
+type NUMAResource struct {
+	// Affinity is one of "none", "prefer" or "require".
+	Affinity string `hcl:"affinity,optional"`
+}
+
+func (n *NUMAResource) Canonicalize() {
+	if n.Affinity == "" {
+		n.Affinity = "none"
+	}
 }
 
 type Port struct {
@@ -239,6 +259,10 @@ func (a Attribute) String() string {
 type NodeDeviceLocality struct {
 	// PciBusID is the PCI Bus ID for the device.
 	PciBusID string
+
+	// NUMANode is the ID of the NUMA node local to the device, or nil if it
+	// is unknown.
+	NUMANode *uint16
 }
 
 // RequestedDevice is used to request a device for a task.
@@ -452,6 +452,7 @@ type TaskGroup struct {
 	MaxClientDisconnect       *time.Duration            `mapstructure:"max_client_disconnect" hcl:"max_client_disconnect,optional"`
 	Scaling                   *ScalingPolicy            `hcl:"scaling,block"`
 	Consul                    *Consul                   `hcl:"consul,block"`
+	Gang                      *bool                     `hcl:"gang,optional"`
 }
 
 // NewTaskGroup creates a new TaskGroup.
@@ -678,31 +679,34 @@ func (l *TaskLifecycle) Empty() bool {
 
 // Task is a single process in a task group.
 type Task struct {
-	Name            string                 `hcl:"name,label"`
-	Driver          string                 `hcl:"driver,optional"`
-	User            string                 `hcl:"user,optional"`
-	Lifecycle       *TaskLifecycle         `hcl:"lifecycle,block"`
-	Config          map[string]interface{} `hcl:"config,block"`
-	Constraints     []*Constraint          `hcl:"constraint,block"`
-	Affinities      []*Affinity            `hcl:"affinity,block"`
-	Env             map[string]string      `hcl:"env,block"`
-	Services        []*Service             `hcl:"service,block"`
-	Resources       *Resources             `hcl:"resources,block"`
-	RestartPolicy   *RestartPolicy         `hcl:"restart,block"`
-	Meta            map[string]string      `hcl:"meta,block"`
-	KillTimeout     *time.Duration         `mapstructure:"kill_timeout" hcl:"kill_timeout,optional"`
-	LogConfig       *LogConfig             `mapstructure:"logs" hcl:"logs,block"`
-	Artifacts       []*TaskArtifact        `hcl:"artifact,block"`
-	Vault           *Vault                 `hcl:"vault,block"`
-	Templates       []*Template            `hcl:"template,block"`
-	DispatchPayload *DispatchPayloadConfig `hcl:"dispatch_payload,block"`
-	VolumeMounts    []*VolumeMount         `hcl:"volume_mount,block"`
-	CSIPluginConfig *TaskCSIPluginConfig   `mapstructure:"csi_plugin" json:",omitempty" hcl:"csi_plugin,block"`
-	Leader          bool                   `hcl:"leader,optional"`
-	ShutdownDelay   time.Duration          `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
-	KillSignal      string                 `mapstructure:"kill_signal" hcl:"kill_signal,optional"`
-	Kind            string                 `hcl:"kind,optional"`
-	ScalingPolicies []*ScalingPolicy       `hcl:"scaling,block"`
+	Name              string                 `hcl:"name,label"`
+	Driver            string                 `hcl:"driver,optional"`
+	User              string                 `hcl:"user,optional"`
+	Lifecycle         *TaskLifecycle         `hcl:"lifecycle,block"`
+	Config            map[string]interface{} `hcl:"config,block"`
+	Constraints       []*Constraint          `hcl:"constraint,block"`
+	Affinities        []*Affinity            `hcl:"affinity,block"`
+	Env               map[string]string      `hcl:"env,block"`
+	Services          []*Service             `hcl:"service,block"`
+	Resources         *Resources             `hcl:"resources,block"`
+	RestartPolicy     *RestartPolicy         `hcl:"restart,block"`
+	Meta              map[string]string      `hcl:"meta,block"`
+	KillTimeout       *time.Duration         `mapstructure:"kill_timeout" hcl:"kill_timeout,optional"`
+	LogConfig         *LogConfig             `mapstructure:"logs" hcl:"logs,block"`
+	Artifacts         []*TaskArtifact        `hcl:"artifact,block"`
+	Vault             *Vault                 `hcl:"vault,block"`
+	Templates         []*Template            `hcl:"template,block"`
+	DispatchPayload   *DispatchPayloadConfig `hcl:"dispatch_payload,block"`
+	VolumeMounts      []*VolumeMount         `hcl:"volume_mount,block"`
+	CSIPluginConfig   *TaskCSIPluginConfig   `mapstructure:"csi_plugin" json:",omitempty" hcl:"csi_plugin,block"`
+	Leader            bool                   `hcl:"leader,optional"`
+	ShutdownDelay     time.Duration          `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
+	KillSignal        string                 `mapstructure:"kill_signal" hcl:"kill_signal,optional"`
+	Kind              string                 `hcl:"kind,optional"`
+	ScalingPolicies   []*ScalingPolicy       `hcl:"scaling,block"`
+	Identities        []*WorkloadIdentity    `hcl:"identity,block"`
+	EnvFromVariables  []*EnvFromVariable     `mapstructure:"env_from_variable" hcl:"env_from_variable,block"`
+	FileFromVariables []*FileFromVariable    `mapstructure:"file_from_variable" hcl:"file_from_variable,block"`
 }
 
 func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
@@ -728,6 +732,15 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 	for _, tmpl := range t.Templates {
 		tmpl.Canonicalize()
 	}
+	for _, wi := range t.Identities {
+		wi.Canonicalize()
+	}
+	for _, e := range t.EnvFromVariables {
+		e.Canonicalize()
+	}
+	for _, f := range t.FileFromVariables {
+		f.Canonicalize()
+	}
 	for _, s := range t.Services {
 		s.Canonicalize(t, tg, job)
 	}
@@ -920,6 +933,89 @@ func (v *Vault) Canonicalize() {
 	}
 }
 
+// WorkloadIdentity is a named identity signed for a task in addition to its
+// default identity, meant to be presented to third parties.
+type WorkloadIdentity struct {
+	Name         string            `hcl:"name,label"`
+	Audience     []string          `mapstructure:"aud" hcl:"aud,optional"`
+	TTL          *time.Duration    `mapstructure:"ttl" hcl:"ttl,optional"`
+	Env          *bool             `hcl:"env,optional"`
+	File         *bool             `hcl:"file,optional"`
+	ChangeMode   *string           `mapstructure:"change_mode" hcl:"change_mode,optional"`
+	ChangeSignal *string           `mapstructure:"change_signal" hcl:"change_signal,optional"`
+	Claims       map[string]string `hcl:"claims,block"`
+}
+
+func (wi *WorkloadIdentity) Canonicalize() {
+	if wi.TTL == nil {
+		wi.TTL = pointerOf(time.Duration(0))
+	}
+	if wi.Env == nil {
+		wi.Env = pointerOf(false)
+	}
+	if wi.File == nil {
+		wi.File = pointerOf(true)
+	}
+	if wi.ChangeMode == nil {
+		wi.ChangeMode = pointerOf("noop")
+	}
+	if wi.ChangeSignal == nil {
+		wi.ChangeSignal = pointerOf("")
+	}
+}
+
+// EnvFromVariable exposes the items of a variable to a task as environment
+// variables.
+type EnvFromVariable struct {
+	Path       *string `hcl:"path,optional"`
+	Prefix     *string `hcl:"prefix,optional"`
+	ChangeMode *string `mapstructure:"change_mode" hcl:"change_mode,optional"`
+}
+
+func (e *EnvFromVariable) Canonicalize() {
+	if e.Path == nil {
+		e.Path = pointerOf("")
+	}
+	if e.Prefix == nil {
+		e.Prefix = pointerOf("")
+	}
+	if e.ChangeMode == nil {
+		e.ChangeMode = pointerOf("restart")
+	}
+}
+
+// FileFromVariable writes an item of a variable to a file in the secrets
+// directory of a task.
+type FileFromVariable struct {
+	Path         *string `hcl:"path,optional"`
+	Item         *string `hcl:"item,optional"`
+	Destination  *string `hcl:"destination,optional"`
+	Perms        *string `hcl:"perms,optional"`
+	ChangeMode   *string `mapstructure:"change_mode" hcl:"change_mode,optional"`
+	ChangeSignal *string `mapstructure:"change_signal" hcl:"change_signal,optional"`
+}
+
+func (f *FileFromVariable) Canonicalize() {
+	if f.Path == nil {
+		f.Path = pointerOf("")
+	}
+	if f.Item == nil {
+		f.Item = pointerOf("")
+	}
+	if f.Destination == nil {
+		f.Destination = pointerOf("")
+	}
+	if f.Perms == nil {
+		f.Perms = pointerOf("0644")
+	}
+	if f.ChangeMode == nil {
+		f.ChangeMode = pointerOf("restart")
+	}
+	if f.ChangeSignal == nil {
+		f.ChangeSignal = pointerOf("")
+	}
+}
+
 // NewTask creates and initializes a new Task.
 func NewTask(name, driver string) *Task {
 	return &Task{
@@ -520,6 +520,56 @@ func TestTask_Canonicalize_Vault(t *testing.T) {
 	}
 }
 
+func TestTask_Canonicalize_WorkloadIdentity(t *testing.T) {
+	testutil.Parallel(t)
+	testCases := []struct {
+		name     string
+		input    *WorkloadIdentity
+		expected *WorkloadIdentity
+	}{
+		{
+			name:  "empty",
+			input: &WorkloadIdentity{Name: "vault"},
+			expected: &WorkloadIdentity{
+				Name:         "vault",
+				TTL:          pointerOf(time.Duration(0)),
+				Env:          pointerOf(false),
+				File:         pointerOf(true),
+				ChangeMode:   pointerOf("noop"),
+				ChangeSignal: pointerOf(""),
+			},
+		},
+		{
+			name: "set",
+			input: &WorkloadIdentity{
+				Name:         "aws",
+				Audience:     []string{"sts.amazonaws.com"},
+				TTL:          pointerOf(time.Hour),
+				Env:          pointerOf(true),
+				File:         pointerOf(false),
+				ChangeMode:   pointerOf("signal"),
+				ChangeSignal: pointerOf("SIGUSR1"),
+			},
+			expected: &WorkloadIdentity{
+				Name:         "aws",
+				Audience:     []string{"sts.amazonaws.com"},
+				TTL:          pointerOf(time.Hour),
+				Env:          pointerOf(true),
+				File:         pointerOf(false),
+				ChangeMode:   pointerOf("signal"),
+				ChangeSignal: pointerOf("SIGUSR1"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.input.Canonicalize()
+			require.Equal(t, tc.expected, tc.input)
+		})
+	}
+}
+
 // Ensures no regression on https://github.com/hashicorp/nomad/issues/3132
 func TestTaskGroup_Canonicalize_Update(t *testing.T) {
 	testutil.Parallel(t)
@@ -82,6 +82,37 @@ func (sv *Variables) Peek(path string, qo *QueryOptions) (*Variable, *QueryMeta,
 	return svar, qm, nil
 }
 
+// ReadVersion is used to query a version of a variable by path, including the
+// last version of a deleted variable. This will error if the version is not
+// found.
+func (sv *Variables) ReadVersion(path string, version uint64, qo *QueryOptions) (*Variable, *QueryMeta, error) {
+
+	path = cleanPathString(path)
+	var svar = new(Variable)
+	qm, err := sv.readInternal(fmt.Sprintf("/v1/var/%s?version=%d", path, version), &svar, qo)
+	if err != nil {
+		return nil, nil, err
+	}
+	if svar == nil {
+		return nil, qm, errors.New(ErrVariableNotFound)
+	}
+	return svar, qm, nil
+}
+
+// History is used to list the versions of a variable kept by the servers,
+// newest first. It includes the versions of a deleted variable until they
+// are garbage collected.
+func (sv *Variables) History(path string, qo *QueryOptions) ([]*VariableVersionMetadata, *QueryMeta, error) {
+
+	path = cleanPathString(path)
+	var resp []*VariableVersionMetadata
+	qm, err := sv.client.query("/v1/vars/history/"+path, &resp, qo)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
 // Update is used to update a variable.
 func (sv *Variables) Update(v *Variable, qo *WriteOptions) (*Variable, *WriteMeta, error) {
 
@@ -339,6 +370,9 @@ type Variable struct {
 	CreateTime int64 `hcl:"create_time"`
 	ModifyTime int64 `hcl:"modify_time"`
 
+	// Version is incremented every time the items of the variable change
+	Version uint64 `hcl:"version"`
+
 	Items VariableItems `hcl:"items"`
 }
 
@@ -357,6 +391,20 @@ type VariableMetadata struct {
 	// Times provided as a convenience for operators expressed time.UnixNanos
 	CreateTime int64 `hcl:"create_time"`
 	ModifyTime int64 `hcl:"modify_time"`
+
+	// Version is incremented every time the items of the variable change
+	Version uint64 `hcl:"version"`
+}
+
+// VariableVersionMetadata specifies the metadata for a version of a
+// variable and is used as the history list object
+type VariableVersionMetadata struct {
+	VariableMetadata
+
+	// DeleteIndex and DeleteTime are set on the last version of a deleted
+	// variable
+	DeleteIndex uint64 `hcl:"delete_index"`
+	DeleteTime  int64  `hcl:"delete_time"`
 }
 
 type VariableItems map[string]string
@@ -394,6 +442,7 @@ func (sv *Variable) Metadata() *VariableMetadata {
 		ModifyIndex: sv.ModifyIndex,
 		CreateTime:  sv.CreateTime,
 		ModifyTime:  sv.ModifyTime,
+		Version:     sv.Version,
 	}
 }
 
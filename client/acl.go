@@ -115,6 +115,9 @@ func (c *Client) resolveTokenAndACL(secretID string) (*acl.ACL, *structs.ACLToke
 	if token.IsExpired(time.Now().Add(2 * time.Second)) {
 		return nil, nil, structs.ErrTokenExpired
 	}
+	if token.Disabled {
+		return nil, nil, structs.ErrTokenDisabled
+	}
 
 	// Check if this is a management token
 	if token.Type == structs.ACLManagementToken {
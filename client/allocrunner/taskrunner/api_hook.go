@@ -0,0 +1,126 @@
+package taskrunner
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+)
+
+const (
+	// TaskAPISocketFile is the name of the Task API socket in the secrets
+	// directory of the task.
+	TaskAPISocketFile = "api.sock"
+
+	// maxSocketPathLen is the longest path of a unix socket that can be
+	// created portably; Linux allows 108 bytes and macOS 104.
+	maxSocketPathLen = 100
+)
+
+// apiHook serves the HTTP API of the agent to the task over a unix socket in
+// its secrets directory. Every request on the socket is authenticated with
+// the workload identity of the task.
+type apiHook struct {
+	registrar config.APIListenerRegistrar
+	authToken func() string
+	logger    hclog.Logger
+
+	// lock synchronizes ln and cancel which are mutated by Prestart, Stop
+	// and Shutdown.
+	lock   sync.Mutex
+	ln     net.Listener
+	cancel context.CancelFunc
+}
+
+func newAPIHook(tr *TaskRunner, logger hclog.Logger) *apiHook {
+	h := &apiHook{
+		registrar: tr.clientConfig.TaskAPI,
+		authToken: tr.getNomadToken,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*apiHook) Name() string {
+	return "api"
+}
+
+func (h *apiHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.registrar == nil || h.ln != nil {
+		// The Task API is disabled or already served across task restarts
+		return nil
+	}
+
+	path := filepath.Join(req.TaskDir.SecretsDir, TaskAPISocketFile)
+	if len(path) > maxSocketPathLen {
+		h.logger.Warn("path to the Task API socket is too long, the Task API is disabled",
+			"path", path, "max", maxSocketPathLen)
+		return nil
+	}
+
+	// Remove the socket left over by a previous run of the client
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+
+	// The task may run as any user, so the socket must be accessible by all
+	// of them. The secrets directory is only reachable from within the task.
+	if err := os.Chmod(path, os.ModePerm); err != nil {
+		ln.Close()
+		return err
+	}
+
+	serveCtx, cancel := context.WithCancel(context.Background())
+	h.ln = ln
+	h.cancel = cancel
+
+	go func() {
+		if err := h.registrar.Serve(serveCtx, ln, h.authToken); err != nil {
+			h.logger.Error("error serving the Task API", "error", err)
+		}
+	}()
+
+	h.logger.Trace("serving the Task API", "path", path)
+	return nil
+}
+
+// Stop stops serving the Task API once the task will not be restarted.
+func (h *apiHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest, resp *interfaces.TaskStopResponse) error {
+	h.stop()
+	return nil
+}
+
+// Shutdown stops serving the Task API when the client shuts down. The socket
+// is created again by Prestart when the client restores the task.
+func (h *apiHook) Shutdown() {
+	h.stop()
+}
+
+func (h *apiHook) stop() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.ln == nil {
+		return
+	}
+
+	h.cancel()
+	if err := h.ln.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		h.logger.Warn("error closing the Task API socket", "error", err)
+	}
+	h.ln = nil
+}
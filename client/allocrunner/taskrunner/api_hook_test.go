@@ -0,0 +1,99 @@
+package taskrunner
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the API hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*apiHook)(nil)
+var _ interfaces.TaskStopHook = (*apiHook)(nil)
+var _ interfaces.ShutdownHook = (*apiHook)(nil)
+
+// mockAPIRegistrar serves a handler that echoes the ACL token of the request
+type mockAPIRegistrar struct{}
+
+func (mockAPIRegistrar) Serve(ctx context.Context, ln net.Listener, authToken func() string) error {
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(authToken()))
+		}),
+	}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	srv.Serve(ln)
+	return nil
+}
+
+func TestAPIHook(t *testing.T) {
+	ci.Parallel(t)
+
+	h := &apiHook{
+		registrar: mockAPIRegistrar{},
+		authToken: func() string { return "workload-identity" },
+		logger:    testlog.HCLogger(t),
+	}
+	t.Cleanup(h.Shutdown)
+
+	// use a short path as the path of the socket has a length limit
+	dir, err := os.MkdirTemp("", "apihook")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	req := &interfaces.TaskPrestartRequest{
+		TaskDir: &allocdir.TaskDir{SecretsDir: dir},
+	}
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+
+	// restarting the task keeps serving the same socket
+	ln := h.ln
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+	require.Equal(t, ln, h.ln)
+
+	path := filepath.Join(dir, TaskAPISocketFile)
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", path)
+			},
+		},
+	}
+	httpResp, err := client.Get("http://localhost/v1/jobs")
+	require.NoError(t, err)
+	body, err := io.ReadAll(httpResp.Body)
+	httpResp.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, "workload-identity", string(body))
+
+	// stopping the task removes the socket
+	require.NoError(t, h.Stop(context.Background(), nil, nil))
+	require.NoFileExists(t, path)
+}
+
+func TestAPIHook_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	h := &apiHook{logger: testlog.HCLogger(t)}
+	req := &interfaces.TaskPrestartRequest{
+		TaskDir: &allocdir.TaskDir{SecretsDir: t.TempDir()},
+	}
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+	require.Nil(t, h.ln)
+	require.NoFileExists(t, filepath.Join(req.TaskDir.SecretsDir, TaskAPISocketFile))
+}
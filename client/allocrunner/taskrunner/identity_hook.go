@@ -2,26 +2,82 @@ package taskrunner
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/hashicorp/consul-template/signals"
 	log "github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
+const (
+	// identityBackoffBaseline is the baseline time for exponential backoff
+	// when attempting to renew workload identities
+	identityBackoffBaseline = 5 * time.Second
+
+	// identityBackoffLimit is the limit of the exponential backoff when
+	// attempting to renew workload identities
+	identityBackoffLimit = 3 * time.Minute
+
+	// identityTokenEnvPrefix is the prefix of the environment variables
+	// holding the named workload identities of the task
+	identityTokenEnvPrefix = "NOMAD_TOKEN_"
+)
+
+// identityTokenFile returns the name of the file holding the named workload
+// identity inside the task's secret directory
+func identityTokenFile(name string) string {
+	return "nomad_" + name + ".jwt"
+}
+
 // identityHook sets the task runner's Nomad workload identity token
-// based on the signed identity stored on the Allocation
+// based on the signed identity stored on the Allocation, and manages the
+// named workload identities of the task, renewing them before they expire
 type identityHook struct {
 	tr       *TaskRunner
 	logger   log.Logger
 	taskName string
 	lock     sync.Mutex
+
+	// lifecycle is used to signal and restart the task when its named
+	// identities are renewed
+	lifecycle ti.TaskLifecycle
+
+	// identities are the named workload identities of the task
+	identities []*structs.WorkloadIdentity
+
+	// tokens are the current named workload identities keyed by name
+	tokens map[string]string
+
+	// secretsDir is the task's secret directory
+	secretsDir string
+
+	// renewing is set once the renewal loop has been started
+	renewing bool
+
+	// ctx and cancel are used to stop the renewal loop
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func newIdentityHook(tr *TaskRunner, logger log.Logger) *identityHook {
+	ctx, cancel := context.WithCancel(context.Background())
 	h := &identityHook{
-		tr:       tr,
-		taskName: tr.taskName,
+		tr:         tr,
+		taskName:   tr.taskName,
+		lifecycle:  tr,
+		identities: tr.task.Identities,
+		ctx:        ctx,
+		cancel:     cancel,
 	}
 	h.logger = logger.Named(h.Name())
 	return h
@@ -37,6 +93,46 @@ func (h *identityHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 
 	token := h.tr.alloc.SignedIdentities[h.taskName]
 	h.tr.setNomadToken(token)
+
+	if len(h.identities) == 0 {
+		return nil
+	}
+
+	// Recover the named identities on the first run only, so that identities
+	// renewed while the task was running survive task restarts
+	if h.tokens == nil {
+		h.secretsDir = req.TaskDir.SecretsDir
+		h.tokens = make(map[string]string, len(h.identities))
+		for _, wi := range h.identities {
+			token, err := h.recoverToken(wi)
+			if err != nil {
+				return err
+			}
+			h.tokens[wi.Name] = token
+		}
+	}
+
+	for _, wi := range h.identities {
+		if err := h.writeToken(wi); err != nil {
+			return err
+		}
+		if wi.Env {
+			if resp.Env == nil {
+				resp.Env = make(map[string]string)
+			}
+			resp.Env[identityTokenEnvPrefix+wi.Name] = h.tokens[wi.Name]
+		}
+	}
+
+	if !h.renewing {
+		for _, wi := range h.identities {
+			if wi.TTL > 0 {
+				h.renewing = true
+				go h.run()
+				break
+			}
+		}
+	}
 	return nil
 }
 
@@ -48,3 +144,231 @@ func (h *identityHook) Update(_ context.Context, req *interfaces.TaskUpdateReque
 	h.tr.setNomadToken(token)
 	return nil
 }
+
+func (h *identityHook) Stop(context.Context, *interfaces.TaskStopRequest, *interfaces.TaskStopResponse) error {
+	h.cancel()
+	return nil
+}
+
+func (h *identityHook) Shutdown() {
+	h.cancel()
+}
+
+// recoverToken returns the token of the named identity. The token signed
+// with the allocation is used unless the token file holds one that was
+// renewed later, which is the case when the task runner is restored.
+func (h *identityHook) recoverToken(wi *structs.WorkloadIdentity) (string, error) {
+	token := h.tr.alloc.SignedIdentities[structs.WorkloadIdentityKey(h.taskName, wi.Name)]
+	if token == "" {
+		return "", fmt.Errorf("allocation has no signed identity %q", wi.Name)
+	}
+	if !wi.File {
+		return token, nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(h.secretsDir, identityTokenFile(wi.Name)))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to recover identity %q: %v", wi.Name, err)
+		}
+		return token, nil
+	}
+
+	_, signedExp, err := identityTokenTimes(token)
+	if err != nil {
+		return token, nil
+	}
+	_, recoveredExp, err := identityTokenTimes(string(data))
+	if err != nil || !recoveredExp.After(signedExp) {
+		return token, nil
+	}
+	return string(data), nil
+}
+
+// writeToken writes the named identity to the task's secret directory if
+// the identity asks for it. It must be called with the lock held.
+func (h *identityHook) writeToken(wi *structs.WorkloadIdentity) error {
+	if !wi.File {
+		return nil
+	}
+	path := filepath.Join(h.secretsDir, identityTokenFile(wi.Name))
+	if err := ioutil.WriteFile(path, []byte(h.tokens[wi.Name]), 0666); err != nil {
+		return fmt.Errorf("failed to write identity %q: %v", wi.Name, err)
+	}
+	return nil
+}
+
+// run should be called in a go-routine and renews the named identities of
+// the task that have a TTL, once half of their lifetime has passed.
+func (h *identityHook) run() {
+	attempts := 0
+	for {
+		timer, stop := helper.NewSafeTimer(h.nextRenewal())
+		select {
+		case <-h.ctx.Done():
+			stop()
+			return
+		case <-timer.C:
+		}
+		stop()
+
+		if err := h.renew(); err != nil {
+			backoff := (1 << (2 * uint64(attempts))) * identityBackoffBaseline
+			if backoff > identityBackoffLimit {
+				backoff = identityBackoffLimit
+			} else {
+				attempts++
+			}
+			h.logger.Error("failed to renew workload identities", "error", err, "backoff", backoff)
+
+			select {
+			case <-h.ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			continue
+		}
+		attempts = 0
+	}
+}
+
+// nextRenewal returns the time until the next named identity must be
+// renewed.
+func (h *identityHook) nextRenewal() time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var next time.Time
+	found := false
+	for _, wi := range h.identities {
+		if wi.TTL == 0 {
+			continue
+		}
+		renewAt := identityRenewalTime(h.tokens[wi.Name])
+		if !found || renewAt.Before(next) {
+			next = renewAt
+			found = true
+		}
+	}
+
+	if wait := time.Until(next); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// renew renews the named identities that are due and applies their change
+// mode.
+func (h *identityHook) renew() error {
+	h.lock.Lock()
+	now := time.Now()
+	var due []*structs.WorkloadIdentity
+	var names []string
+	for _, wi := range h.identities {
+		if wi.TTL > 0 && !identityRenewalTime(h.tokens[wi.Name]).After(now) {
+			due = append(due, wi)
+			names = append(names, wi.Name)
+		}
+	}
+	h.lock.Unlock()
+
+	if len(due) == 0 {
+		return nil
+	}
+
+	alloc := h.tr.Alloc()
+	req := &structs.AllocIdentitiesRequest{
+		AllocID:    alloc.ID,
+		TaskName:   h.taskName,
+		Identities: names,
+		QueryOptions: structs.QueryOptions{
+			Region:    alloc.Job.Region,
+			Namespace: alloc.Namespace,
+			AuthToken: h.tr.clientConfig.Node.SecretID,
+		},
+	}
+	var resp structs.AllocIdentitiesResponse
+	if err := h.tr.rpcClient.RPC("Alloc.SignIdentities", req, &resp); err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	for _, wi := range due {
+		token, ok := resp.SignedIdentities[wi.Name]
+		if !ok {
+			h.lock.Unlock()
+			return fmt.Errorf("server did not renew identity %q", wi.Name)
+		}
+		h.tokens[wi.Name] = token
+		if err := h.writeToken(wi); err != nil {
+			h.lock.Unlock()
+			return err
+		}
+	}
+	h.lock.Unlock()
+
+	h.logger.Debug("renewed workload identities", "identities", names)
+	h.handleChange(due)
+	return nil
+}
+
+// handleChange applies the change mode of the renewed identities. The task
+// is restarted at most once and each distinct signal is sent once.
+func (h *identityHook) handleChange(renewed []*structs.WorkloadIdentity) {
+	restart := false
+	sigs := map[string]struct{}{}
+	for _, wi := range renewed {
+		switch wi.ChangeMode {
+		case structs.WorkloadIdentityChangeModeRestart:
+			restart = true
+		case structs.WorkloadIdentityChangeModeSignal:
+			sigs[wi.ChangeSignal] = struct{}{}
+		}
+	}
+
+	if restart {
+		const noFailure = false
+		h.lifecycle.Restart(h.ctx,
+			structs.NewTaskEvent(structs.TaskRestartSignal).
+				SetDisplayMessage("Identity: workload identity renewed"), noFailure)
+		return
+	}
+
+	for sig := range sigs {
+		s, err := signals.Parse(sig)
+		if err != nil {
+			h.logger.Error("failed to parse signal", "signal", sig, "error", err)
+			continue
+		}
+		event := structs.NewTaskEvent(structs.TaskSignaling).
+			SetTaskSignal(s).
+			SetDisplayMessage("Identity: workload identity renewed")
+		if err := h.lifecycle.Signal(event, sig); err != nil {
+			h.logger.Error("failed to send signal", "signal", sig, "error", err)
+		}
+	}
+}
+
+// identityTokenTimes returns the issue and expiry times of a signed
+// identity without verifying it.
+func identityTokenTimes(token string) (time.Time, time.Time, error) {
+	var claims jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if claims.IssuedAt == nil || claims.ExpiresAt == nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("identity does not expire")
+	}
+	return claims.IssuedAt.Time, claims.ExpiresAt.Time, nil
+}
+
+// identityRenewalTime returns when a signed identity should be renewed,
+// which is once half of its lifetime has passed. Identities that can't be
+// parsed are renewed immediately.
+func identityRenewalTime(token string) time.Time {
+	iat, exp, err := identityTokenTimes(token)
+	if err != nil {
+		return time.Time{}
+	}
+	return iat.Add(exp.Sub(iat) / 2)
+}
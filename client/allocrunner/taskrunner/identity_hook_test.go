@@ -0,0 +1,215 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the identity hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*identityHook)(nil)
+var _ interfaces.TaskUpdateHook = (*identityHook)(nil)
+var _ interfaces.TaskStopHook = (*identityHook)(nil)
+var _ interfaces.ShutdownHook = (*identityHook)(nil)
+
+// mockIdentityRPC renews workload identities by returning unsigned tokens
+type mockIdentityRPC struct {
+	lock     sync.Mutex
+	requests []*structs.AllocIdentitiesRequest
+}
+
+func (m *mockIdentityRPC) RPC(method string, args interface{}, reply interface{}) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	req := args.(*structs.AllocIdentitiesRequest)
+	m.requests = append(m.requests, req)
+	resp := reply.(*structs.AllocIdentitiesResponse)
+	resp.SignedIdentities = map[string]string{}
+	for _, name := range req.Identities {
+		resp.SignedIdentities[name] = testIdentityToken(time.Now(), time.Hour)
+	}
+	return nil
+}
+
+func (m *mockIdentityRPC) numRequests() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.requests)
+}
+
+// mockIdentityLifecycle records the signals sent to the task
+type mockIdentityLifecycle struct {
+	lock    sync.Mutex
+	signals []string
+}
+
+func (m *mockIdentityLifecycle) Restart(context.Context, *structs.TaskEvent, bool) error {
+	return nil
+}
+
+func (m *mockIdentityLifecycle) Signal(_ *structs.TaskEvent, signal string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.signals = append(m.signals, signal)
+	return nil
+}
+
+func (m *mockIdentityLifecycle) Kill(context.Context, *structs.TaskEvent) error {
+	return nil
+}
+
+func (m *mockIdentityLifecycle) IsRunning() bool {
+	return true
+}
+
+func (m *mockIdentityLifecycle) numSignals() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.signals)
+}
+
+func testIdentityToken(iat time.Time, ttl time.Duration) string {
+	claims := jwt.RegisteredClaims{
+		IssuedAt:  jwt.NewNumericDate(iat),
+		ExpiresAt: jwt.NewNumericDate(iat.Add(ttl)),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test"))
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+func testIdentityHook(t *testing.T, identities []*structs.WorkloadIdentity, tokens map[string]string) (*identityHook, *interfaces.TaskPrestartRequest) {
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Identities = identities
+	alloc.SignedIdentities = tokens
+
+	tr := &TaskRunner{
+		alloc:        alloc,
+		allocID:      alloc.ID,
+		task:         task,
+		taskName:     task.Name,
+		clientConfig: &config.Config{Node: mock.Node()},
+		rpcClient:    &mockIdentityRPC{},
+	}
+	h := newIdentityHook(tr, testlog.HCLogger(t))
+	h.lifecycle = &mockIdentityLifecycle{}
+	t.Cleanup(h.Shutdown)
+
+	req := &interfaces.TaskPrestartRequest{
+		TaskDir: &allocdir.TaskDir{SecretsDir: t.TempDir()},
+	}
+	return h, req
+}
+
+func TestIdentityHook_Prestart(t *testing.T) {
+	ci.Parallel(t)
+
+	fileToken := testIdentityToken(time.Now(), 0)
+	envToken := testIdentityToken(time.Now(), 0)
+	h, req := testIdentityHook(t,
+		[]*structs.WorkloadIdentity{
+			{Name: "file", Audience: []string{"a"}, File: true},
+			{Name: "env", Audience: []string{"b"}, Env: true},
+		},
+		map[string]string{
+			"web":      "default",
+			"web/file": fileToken,
+			"web/env":  envToken,
+		})
+
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+
+	require.Equal(t, "default", h.tr.getNomadToken())
+	require.Equal(t, map[string]string{"NOMAD_TOKEN_env": envToken}, resp.Env)
+
+	data, err := ioutil.ReadFile(filepath.Join(req.TaskDir.SecretsDir, "nomad_file.jwt"))
+	require.NoError(t, err)
+	require.Equal(t, fileToken, string(data))
+	require.NoFileExists(t, filepath.Join(req.TaskDir.SecretsDir, "nomad_env.jwt"))
+
+	// identities without a TTL are never renewed
+	require.False(t, h.renewing)
+}
+
+func TestIdentityHook_Prestart_Recover(t *testing.T) {
+	ci.Parallel(t)
+
+	signed := testIdentityToken(time.Now().Add(-time.Minute), time.Hour)
+	renewed := testIdentityToken(time.Now(), time.Hour)
+	h, req := testIdentityHook(t,
+		[]*structs.WorkloadIdentity{
+			{Name: "file", Audience: []string{"a"}, File: true},
+		},
+		map[string]string{"web/file": signed})
+
+	// a token renewed before the client restarted is preferred over the one
+	// signed with the allocation
+	path := filepath.Join(req.TaskDir.SecretsDir, "nomad_file.jwt")
+	require.NoError(t, ioutil.WriteFile(path, []byte(renewed), 0666))
+
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+	require.Equal(t, renewed, h.tokens["file"])
+}
+
+func TestIdentityHook_Renew(t *testing.T) {
+	ci.Parallel(t)
+
+	// the token is past half of its lifetime and must be renewed right away
+	expiring := testIdentityToken(time.Now().Add(-40*time.Minute), time.Hour)
+	h, req := testIdentityHook(t,
+		[]*structs.WorkloadIdentity{{
+			Name:         "aws",
+			Audience:     []string{"sts.amazonaws.com"},
+			TTL:          time.Hour,
+			File:         true,
+			ChangeMode:   structs.WorkloadIdentityChangeModeSignal,
+			ChangeSignal: "SIGHUP",
+		}},
+		map[string]string{"web/aws": expiring})
+
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+	require.True(t, h.renewing)
+
+	rpc := h.tr.rpcClient.(*mockIdentityRPC)
+	lifecycle := h.lifecycle.(*mockIdentityLifecycle)
+	path := filepath.Join(req.TaskDir.SecretsDir, "nomad_aws.jwt")
+	testutil.WaitForResult(func() (bool, error) {
+		if lifecycle.numSignals() != 1 {
+			return false, nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		return string(data) != expiring, nil
+	}, func(err error) {
+		require.NoError(t, err, "identity was not renewed")
+	})
+
+	// the renewed token isn't due again for half an hour
+	require.Equal(t, 1, rpc.numRequests())
+	require.Equal(t, []string{"SIGHUP"}, lifecycle.signals)
+	require.Equal(t, h.tr.clientConfig.Node.SecretID, rpc.requests[0].AuthToken)
+	require.Equal(t, []string{"aws"}, rpc.requests[0].Identities)
+}
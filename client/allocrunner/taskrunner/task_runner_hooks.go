@@ -62,6 +62,7 @@ func (tr *TaskRunner) initHooks() {
 		newValidateHook(tr.clientConfig, hookLogger),
 		newTaskDirHook(tr, hookLogger),
 		newIdentityHook(tr, hookLogger),
+		newAPIHook(tr, hookLogger),
 		newLogMonHook(tr, hookLogger),
 		newDispatchHook(alloc, hookLogger),
 		newVolumeHook(tr, hookLogger),
@@ -97,6 +98,11 @@ func (tr *TaskRunner) initHooks() {
 		}))
 	}
 
+	// If the task consumes variables, add the hook
+	if len(task.EnvFromVariables) != 0 || len(task.FileFromVariables) != 0 {
+		tr.runnerHooks = append(tr.runnerHooks, newVariablesHook(tr, hookLogger))
+	}
+
 	// Get the consul namespace for the TG of the allocation.
 	consulNamespace := tr.alloc.ConsulNamespace()
 
@@ -0,0 +1,469 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-template/signals"
+	log "github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/escapingfs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// variablesBackoffBaseline is the baseline time for exponential backoff
+	// when attempting to read the variables consumed by a task
+	variablesBackoffBaseline = 5 * time.Second
+
+	// variablesBackoffLimit is the limit of the exponential backoff when
+	// attempting to read the variables consumed by a task
+	variablesBackoffLimit = 3 * time.Minute
+)
+
+// variablesHook exposes the variables consumed by a task through its
+// env_from_variable and file_from_variable blocks as environment variables
+// and files in the task's secrets directory. The variables are read with the
+// workload identity of the task and watched with blocking queries, applying
+// the change mode of the blocks when they are updated.
+type variablesHook struct {
+	tr     *TaskRunner
+	logger log.Logger
+	lock   sync.Mutex
+
+	// lifecycle is used to signal and restart the task when its variables
+	// are updated
+	lifecycle ti.TaskLifecycle
+
+	// events is used to emit the events of the hook
+	events ti.EventEmitter
+
+	// envs and files are the env_from_variable and file_from_variable
+	// blocks of the task
+	envs  []*structs.EnvFromVariable
+	files []*structs.FileFromVariable
+
+	// vars are the current variables keyed by path. It is nil until the
+	// variables have been read for the first time.
+	vars map[string]*structs.VariableDecrypted
+
+	// secretsDir is the task's secret directory
+	secretsDir string
+
+	// ctx and cancel are used to stop the watchers
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newVariablesHook(tr *TaskRunner, logger log.Logger) *variablesHook {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &variablesHook{
+		tr:        tr,
+		lifecycle: tr,
+		events:    tr,
+		envs:      tr.task.EnvFromVariables,
+		files:     tr.task.FileFromVariables,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*variablesHook) Name() string {
+	return "variables"
+}
+
+func (h *variablesHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	h.lock.Lock()
+	first := h.vars == nil
+	h.lock.Unlock()
+
+	// Read the variables on the first run only, the watchers keep them up to
+	// date across task restarts
+	if first {
+		vars := make(map[string]*structs.VariableDecrypted)
+		for _, path := range h.paths() {
+			sv, err := h.waitForVariable(ctx, path)
+			if err != nil {
+				return err
+			}
+			if sv == nil {
+				// the task is being killed or shut down
+				return nil
+			}
+			vars[path] = sv
+		}
+
+		h.lock.Lock()
+		h.vars = vars
+		h.secretsDir = req.TaskDir.SecretsDir
+		h.lock.Unlock()
+
+		for path := range vars {
+			go h.watch(path)
+		}
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for _, f := range h.files {
+		if err := h.writeFile(f); err != nil {
+			return err
+		}
+	}
+
+	resp.Env = h.env()
+	return nil
+}
+
+func (h *variablesHook) Stop(context.Context, *interfaces.TaskStopRequest, *interfaces.TaskStopResponse) error {
+	h.cancel()
+	return nil
+}
+
+func (h *variablesHook) Shutdown() {
+	h.cancel()
+}
+
+// paths returns the distinct paths of the variables consumed by the task.
+func (h *variablesHook) paths() []string {
+	seen := make(map[string]struct{})
+	var paths []string
+	for _, e := range h.envs {
+		if _, ok := seen[e.Path]; !ok {
+			seen[e.Path] = struct{}{}
+			paths = append(paths, e.Path)
+		}
+	}
+	for _, f := range h.files {
+		if _, ok := seen[f.Path]; !ok {
+			seen[f.Path] = struct{}{}
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// read reads the variable at path, blocking until its index is greater
+// than minIndex. The variable is nil if it doesn't exist.
+func (h *variablesHook) read(path string, minIndex uint64) (*structs.VariablesReadResponse, error) {
+	alloc := h.tr.Alloc()
+	req := &structs.VariablesReadRequest{
+		Path: path,
+		QueryOptions: structs.QueryOptions{
+			Region:        alloc.Job.Region,
+			Namespace:     alloc.Job.Namespace,
+			AuthToken:     h.tr.getNomadToken(),
+			AllowStale:    true,
+			MinQueryIndex: minIndex,
+		},
+	}
+	var resp structs.VariablesReadResponse
+	if err := h.tr.rpcClient.RPC(structs.VariablesReadRPCMethod, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// waitForVariable reads the variable at path, waiting for it to be created
+// if it doesn't exist. It returns a nil variable if ctx is done first.
+func (h *variablesHook) waitForVariable(ctx context.Context, path string) (*structs.VariableDecrypted, error) {
+	var index uint64
+	attempts := 0
+	waiting := false
+	for {
+		resp, err := h.read(path, index)
+		switch {
+		case err != nil && structs.IsErrPermissionDenied(err):
+			return nil, fmt.Errorf("failed to read variable %q: %v", path, err)
+		case err != nil:
+			backoff := (1 << (2 * uint64(attempts))) * variablesBackoffBaseline
+			if backoff > variablesBackoffLimit {
+				backoff = variablesBackoffLimit
+			} else {
+				attempts++
+			}
+			h.logger.Error("failed to read variable", "path", path, "error", err, "backoff", backoff)
+
+			select {
+			case <-ctx.Done():
+				return nil, nil
+			case <-time.After(backoff):
+			}
+			continue
+		case resp.Data != nil:
+			return resp.Data, nil
+		}
+
+		if !waiting {
+			waiting = true
+			h.events.EmitEvent(structs.NewTaskEvent(structs.TaskHookMessage).
+				SetDisplayMessage(fmt.Sprintf("Variables: waiting for variable %q to be created", path)))
+		}
+		attempts = 0
+		index = resp.Index
+
+		if ctx.Err() != nil {
+			return nil, nil
+		}
+	}
+}
+
+// watch should be called in a go-routine and watches the variable at path
+// with blocking queries, applying the change mode of the blocks consuming
+// it when it is updated.
+func (h *variablesHook) watch(path string) {
+	h.lock.Lock()
+	index := h.vars[path].ModifyIndex
+	h.lock.Unlock()
+
+	attempts := 0
+	for {
+		if h.ctx.Err() != nil {
+			return
+		}
+
+		resp, err := h.read(path, index)
+		if err != nil {
+			backoff := (1 << (2 * uint64(attempts))) * variablesBackoffBaseline
+			if backoff > variablesBackoffLimit {
+				backoff = variablesBackoffLimit
+			} else {
+				attempts++
+			}
+			h.logger.Error("failed to watch variable", "path", path, "error", err, "backoff", backoff)
+
+			select {
+			case <-h.ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			continue
+		}
+		attempts = 0
+		if resp.Index > index {
+			index = resp.Index
+		}
+
+		// The task keeps the last version of a deleted variable
+		if resp.Data == nil {
+			continue
+		}
+
+		// Rekeying the variable updates it without changing its items
+		h.lock.Lock()
+		changed := !resp.Data.Items.Equal(h.vars[path].Items)
+		h.vars[path] = resp.Data
+		if !changed {
+			h.lock.Unlock()
+			continue
+		}
+
+		var writeErr error
+		for _, f := range h.files {
+			if f.Path != path {
+				continue
+			}
+			if writeErr = h.writeFile(f); writeErr != nil {
+				break
+			}
+		}
+		h.lock.Unlock()
+
+		// The task keeps running with the files it has until the variable is
+		// fixed, as applying the change mode would only make it fail
+		if writeErr != nil {
+			h.logger.Error("failed to write variable", "path", path, "error", writeErr)
+			h.events.EmitEvent(structs.NewTaskEvent(structs.TaskHookMessage).
+				SetDisplayMessage(fmt.Sprintf("Variables: %v", writeErr)))
+			continue
+		}
+
+		h.logger.Debug("variable updated", "path", path, "index", resp.Data.ModifyIndex)
+		h.handleChange(path)
+	}
+}
+
+// handleChange applies the change mode of the blocks consuming the variable
+// at path. The task is restarted at most once and each distinct signal is
+// sent once.
+func (h *variablesHook) handleChange(path string) {
+	restart := false
+	sigs := map[string]struct{}{}
+	for _, e := range h.envs {
+		if e.Path == path && e.ChangeMode == structs.VariableChangeModeRestart {
+			restart = true
+		}
+	}
+	for _, f := range h.files {
+		if f.Path != path {
+			continue
+		}
+		switch f.ChangeMode {
+		case structs.VariableChangeModeRestart:
+			restart = true
+		case structs.VariableChangeModeSignal:
+			sigs[f.ChangeSignal] = struct{}{}
+		}
+	}
+
+	message := fmt.Sprintf("Variables: variable %q updated", path)
+	if restart {
+		const noFailure = false
+		h.lifecycle.Restart(h.ctx,
+			structs.NewTaskEvent(structs.TaskRestartSignal).
+				SetDisplayMessage(message), noFailure)
+		return
+	}
+
+	for sig := range sigs {
+		s, err := signals.Parse(sig)
+		if err != nil {
+			h.logger.Error("failed to parse signal", "signal", sig, "error", err)
+			continue
+		}
+		event := structs.NewTaskEvent(structs.TaskSignaling).
+			SetTaskSignal(s).
+			SetDisplayMessage(message)
+		if err := h.lifecycle.Signal(event, sig); err != nil {
+			h.logger.Error("failed to send signal", "signal", sig, "error", err)
+		}
+	}
+}
+
+// env returns the environment variables of the task's env_from_variable
+// blocks. Later blocks override the variables of earlier ones. It must be
+// called with the lock held.
+func (h *variablesHook) env() map[string]string {
+	if len(h.envs) == 0 {
+		return nil
+	}
+
+	env := make(map[string]string)
+	for _, e := range h.envs {
+		for k, v := range h.vars[e.Path].Items {
+			env[e.EnvName(k)] = v
+		}
+	}
+	return env
+}
+
+// writeFile writes the item of a file_from_variable block to the task's
+// secrets directory. The file is replaced atomically so the task never reads
+// a partial item. It must be called with the lock held.
+func (h *variablesHook) writeFile(f *structs.FileFromVariable) error {
+	value, ok := h.vars[f.Path].Items[f.Item]
+	if !ok {
+		return fmt.Errorf("variable %q has no item %q", f.Path, f.Item)
+	}
+
+	perms, err := strconv.ParseUint(f.Perms, 8, 12)
+	if err != nil {
+		return fmt.Errorf("failed to parse %q as octal: %v", f.Perms, err)
+	}
+
+	dest, err := h.secretsPath(f.Destination)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0777); err != nil {
+		return fmt.Errorf("failed to create directory for %q: %v", f.Destination, err)
+	}
+
+	// The task can replace the directories leading to the destination with
+	// symlinks at any time, so the file is written to the resolved directory,
+	// which is checked again just before the file is moved into place.
+	dir, err := h.resolveSecretsDir(f.Destination, filepath.Dir(dest))
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, filepath.Base(dest)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %q: %v", f.Destination, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(value); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %q: %v", f.Destination, err)
+	}
+	if err := tmp.Chmod(os.FileMode(perms)); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %q: %v", f.Destination, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %q: %v", f.Destination, err)
+	}
+
+	resolved, err := h.resolveSecretsDir(f.Destination, dir)
+	if err != nil {
+		return err
+	}
+	if resolved != dir {
+		return fmt.Errorf("destination %q escapes the secrets directory", f.Destination)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, filepath.Base(dest))); err != nil {
+		return fmt.Errorf("failed to write %q: %v", f.Destination, err)
+	}
+	return nil
+}
+
+// secretsPath returns the path of the destination in the task's secrets
+// directory. Since the task can write to the directory, the symlinks of the
+// directories leading to the destination are resolved and rejected if they
+// escape it.
+func (h *variablesHook) secretsPath(destination string) (string, error) {
+	root, err := filepath.EvalSymlinks(h.secretsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secrets directory: %v", err)
+	}
+
+	dest := filepath.Join(root, destination)
+	if escapingfs.PathEscapesSandbox(root, dest) {
+		return "", fmt.Errorf("destination %q escapes the secrets directory", destination)
+	}
+
+	// Resolve the nearest directory which exists, since the missing ones are
+	// created before writing the file
+	for dir := filepath.Dir(dest); ; dir = filepath.Dir(dir) {
+		_, err := h.resolveSecretsDir(destination, dir)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		return dest, nil
+	}
+}
+
+// resolveSecretsDir returns the directory with its symlinks resolved, or an
+// error if it's outside of the task's secrets directory.
+func (h *variablesHook) resolveSecretsDir(destination, dir string) (string, error) {
+	root, err := filepath.EvalSymlinks(h.secretsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secrets directory: %v", err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(dir)
+	if os.IsNotExist(err) {
+		return "", err
+	} else if err != nil {
+		return "", fmt.Errorf("failed to resolve %q: %v", destination, err)
+	}
+
+	if escapingfs.PathEscapesSandbox(root, resolved) {
+		return "", fmt.Errorf("destination %q escapes the secrets directory", destination)
+	}
+	return resolved, nil
+}
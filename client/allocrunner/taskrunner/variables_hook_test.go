@@ -0,0 +1,258 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the variables hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*variablesHook)(nil)
+var _ interfaces.TaskStopHook = (*variablesHook)(nil)
+var _ interfaces.ShutdownHook = (*variablesHook)(nil)
+
+// mockVariablesRPC serves variables from memory. Blocking queries return
+// when the variable is set or after a short wait.
+type mockVariablesRPC struct {
+	lock    sync.Mutex
+	index   uint64
+	vars    map[string]*structs.VariableDecrypted
+	updated chan struct{}
+	tokens  []string
+}
+
+func newMockVariablesRPC() *mockVariablesRPC {
+	return &mockVariablesRPC{
+		vars:    make(map[string]*structs.VariableDecrypted),
+		updated: make(chan struct{}),
+	}
+}
+
+func (m *mockVariablesRPC) set(path string, items structs.VariableItems) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.index++
+	m.vars[path] = &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Path:        path,
+			ModifyIndex: m.index,
+		},
+		Items: items,
+	}
+	close(m.updated)
+	m.updated = make(chan struct{})
+}
+
+func (m *mockVariablesRPC) RPC(method string, args interface{}, reply interface{}) error {
+	req := args.(*structs.VariablesReadRequest)
+	for {
+		m.lock.Lock()
+		m.tokens = append(m.tokens, req.AuthToken)
+		updated := m.updated
+		if m.index > req.MinQueryIndex {
+			resp := reply.(*structs.VariablesReadResponse)
+			if sv, ok := m.vars[req.Path]; ok {
+				copied := sv.Copy()
+				resp.Data = &copied
+			}
+			resp.Index = m.index
+			m.lock.Unlock()
+			return nil
+		}
+		m.lock.Unlock()
+
+		select {
+		case <-updated:
+		case <-time.After(50 * time.Millisecond):
+			reply.(*structs.VariablesReadResponse).Index = req.MinQueryIndex
+			return nil
+		}
+	}
+}
+
+func testVariablesHook(t *testing.T, envs []*structs.EnvFromVariable, files []*structs.FileFromVariable) (*variablesHook, *interfaces.TaskPrestartRequest) {
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.EnvFromVariables = envs
+	task.FileFromVariables = files
+
+	tr := &TaskRunner{
+		alloc:        alloc,
+		allocID:      alloc.ID,
+		task:         task,
+		taskName:     task.Name,
+		clientConfig: &config.Config{Node: mock.Node()},
+		rpcClient:    newMockVariablesRPC(),
+		nomadToken:   "workload-identity",
+	}
+	h := newVariablesHook(tr, testlog.HCLogger(t))
+	h.lifecycle = &mockIdentityLifecycle{}
+	h.events = &mockEmitter{}
+	t.Cleanup(h.Shutdown)
+
+	req := &interfaces.TaskPrestartRequest{
+		TaskDir: &allocdir.TaskDir{SecretsDir: t.TempDir()},
+	}
+	return h, req
+}
+
+func TestVariablesHook_Prestart(t *testing.T) {
+	ci.Parallel(t)
+
+	h, req := testVariablesHook(t,
+		[]*structs.EnvFromVariable{
+			{Path: "nomad/jobs/db", Prefix: "DB_", ChangeMode: structs.VariableChangeModeRestart},
+		},
+		[]*structs.FileFromVariable{{
+			Path:        "nomad/jobs/db",
+			Item:        "cert",
+			Destination: "db/cert.pem",
+			Perms:       "0600",
+			ChangeMode:  structs.VariableChangeModeRestart,
+		}})
+	rpc := h.tr.rpcClient.(*mockVariablesRPC)
+	rpc.set("nomad/jobs/db", structs.VariableItems{"user": "admin", "tls-cert": "x", "cert": "PEM"})
+
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+	require.Equal(t, map[string]string{
+		"DB_USER":     "admin",
+		"DB_TLS_CERT": "x",
+		"DB_CERT":     "PEM",
+	}, resp.Env)
+
+	path := filepath.Join(req.TaskDir.SecretsDir, "db", "cert.pem")
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "PEM", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// the variables are read with the workload identity of the task
+	rpc.lock.Lock()
+	require.Equal(t, "workload-identity", rpc.tokens[0])
+	rpc.lock.Unlock()
+}
+
+func TestVariablesHook_Prestart_Wait(t *testing.T) {
+	ci.Parallel(t)
+
+	h, req := testVariablesHook(t,
+		[]*structs.EnvFromVariable{
+			{Path: "nomad/jobs/db", ChangeMode: structs.VariableChangeModeNoop},
+		}, nil)
+	rpc := h.tr.rpcClient.(*mockVariablesRPC)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		rpc.set("nomad/jobs/db", structs.VariableItems{"user": "admin"})
+	}()
+
+	// the task waits for the variable to be created
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+	require.Equal(t, map[string]string{"USER": "admin"}, resp.Env)
+	require.Len(t, h.events.(*mockEmitter).events, 1)
+}
+
+func TestVariablesHook_Prestart_MissingItem(t *testing.T) {
+	ci.Parallel(t)
+
+	h, req := testVariablesHook(t, nil,
+		[]*structs.FileFromVariable{{
+			Path:        "nomad/jobs/db",
+			Item:        "cert",
+			Destination: "cert.pem",
+			Perms:       "0644",
+			ChangeMode:  structs.VariableChangeModeNoop,
+		}})
+	h.tr.rpcClient.(*mockVariablesRPC).set("nomad/jobs/db", structs.VariableItems{"user": "admin"})
+
+	var resp interfaces.TaskPrestartResponse
+	err := h.Prestart(context.Background(), req, &resp)
+	require.EqualError(t, err, `variable "nomad/jobs/db" has no item "cert"`)
+}
+
+func TestVariablesHook_Prestart_Symlink(t *testing.T) {
+	ci.Parallel(t)
+
+	h, req := testVariablesHook(t, nil,
+		[]*structs.FileFromVariable{{
+			Path:        "nomad/jobs/db",
+			Item:        "cert",
+			Destination: "db/certs/cert.pem",
+			Perms:       "0644",
+			ChangeMode:  structs.VariableChangeModeNoop,
+		}})
+	h.tr.rpcClient.(*mockVariablesRPC).set("nomad/jobs/db", structs.VariableItems{"cert": "PEM"})
+
+	// the task replaced a directory of the destination with a symlink
+	// escaping the secrets directory
+	outside := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(req.TaskDir.SecretsDir, "db")))
+
+	var resp interfaces.TaskPrestartResponse
+	err := h.Prestart(context.Background(), req, &resp)
+	require.EqualError(t, err, `destination "db/certs/cert.pem" escapes the secrets directory`)
+
+	entries, err := ioutil.ReadDir(outside)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestVariablesHook_Update(t *testing.T) {
+	ci.Parallel(t)
+
+	h, req := testVariablesHook(t, nil,
+		[]*structs.FileFromVariable{{
+			Path:         "nomad/jobs/db",
+			Item:         "cert",
+			Destination:  "cert.pem",
+			Perms:        "0644",
+			ChangeMode:   structs.VariableChangeModeSignal,
+			ChangeSignal: "SIGHUP",
+		}})
+	rpc := h.tr.rpcClient.(*mockVariablesRPC)
+	rpc.set("nomad/jobs/db", structs.VariableItems{"cert": "old"})
+
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(t, h.Prestart(context.Background(), req, &resp))
+
+	// updating the variable without changing its items doesn't signal the
+	// task
+	rpc.set("nomad/jobs/db", structs.VariableItems{"cert": "old"})
+	rpc.set("nomad/jobs/db", structs.VariableItems{"cert": "new"})
+
+	lifecycle := h.lifecycle.(*mockIdentityLifecycle)
+	path := filepath.Join(req.TaskDir.SecretsDir, "cert.pem")
+	testutil.WaitForResult(func() (bool, error) {
+		if lifecycle.numSignals() != 1 {
+			return false, nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		return string(data) == "new", nil
+	}, func(err error) {
+		require.NoError(t, err, "variable was not updated")
+	})
+	require.Equal(t, []string{"SIGHUP"}, lifecycle.signals)
+}
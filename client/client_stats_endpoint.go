@@ -20,7 +20,7 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	// Check node read permissions
 	if aclObj, err := s.c.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+	} else if aclObj != nil && !aclObj.AllowNodeReadFor(s.c.Node().ACLAttributes()) {
 		return nstructs.ErrPermissionDenied
 	}
 
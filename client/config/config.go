@@ -1,8 +1,10 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"reflect"
 	"strconv"
 	"strings"
@@ -289,6 +291,10 @@ type Config struct {
 	// used for template functions which require access to the Nomad API.
 	TemplateDialer *bufconndialer.BufConnWrapper
 
+	// TaskAPI serves the HTTP API of the agent to tasks over the Task API
+	// socket in their secrets directory. The Task API is disabled if nil.
+	TaskAPI APIListenerRegistrar
+
 	// Artifact configuration from the agent's config file.
 	Artifact *ArtifactConfig
 }
@@ -694,6 +700,15 @@ func (rc *RetryConfig) ToConsulTemplate() (*config.RetryConfig, error) {
 	return result, nil
 }
 
+// APIListenerRegistrar serves the HTTP API of the agent on listeners created
+// by the client at runtime, such as the Task API socket.
+type APIListenerRegistrar interface {
+	// Serve the HTTP API on the listener until the context is canceled,
+	// authenticating every request with the ACL token returned by authToken
+	// in place of any token given by the caller.
+	Serve(ctx context.Context, ln net.Listener, authToken func() string) error
+}
+
 func (c *Config) Copy() *Config {
 	if c == nil {
 		return nil
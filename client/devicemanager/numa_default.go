@@ -0,0 +1,9 @@
+//go:build !linux
+
+package devicemanager
+
+// pciNUMANode returns the NUMA node local to the PCI device, or nil if it is
+// unknown.
+func pciNUMANode(busID string) *uint16 {
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build linux
+
+package devicemanager
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sysfsPCIDevices is the sysfs directory describing the PCI devices.
+var sysfsPCIDevices = "/sys/bus/pci/devices"
+
+// pciNUMANode returns the NUMA node local to the PCI device, or nil if it is
+// unknown.
+func pciNUMANode(busID string) *uint16 {
+	if busID == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(filepath.Join(sysfsPCIDevices, normalizePciBusID(busID), "numa_node"))
+	if err != nil {
+		return nil
+	}
+
+	// The kernel reports -1 when the device has no NUMA affinity
+	id, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 16)
+	if err != nil {
+		return nil
+	}
+	node := uint16(id)
+	return &node
+}
+
+// normalizePciBusID converts a PCI bus ID, such as the 00000000:3B:00.0 format
+// reported by some device plugins, to the 0000:3b:00.0 format used by sysfs.
+func normalizePciBusID(busID string) string {
+	busID = strings.ToLower(busID)
+	domain, rest, ok := strings.Cut(busID, ":")
+	if ok && len(domain) > 4 {
+		busID = domain[len(domain)-4:] + ":" + rest
+	}
+	return busID
+}
@@ -89,5 +89,6 @@ func convertHwLocality(l *device.DeviceLocality) *structs.NodeDeviceLocality {
 
 	return &structs.NodeDeviceLocality{
 		PciBusID: l.PciBusID,
+		NUMANode: pciNUMANode(l.PciBusID),
 	}
 }
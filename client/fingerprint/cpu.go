@@ -33,7 +33,8 @@ func NewCPUFingerprint(logger log.Logger) Fingerprint {
 
 func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintResponse) error {
 	cfg := req.Config
-	setResourcesCPU := func(totalCompute int, totalCores uint16, reservableCores []uint16) {
+	setResourcesCPU := func(totalCompute int, totalCores uint16, reservableCores []uint16,
+		numaNodes []*structs.NodeNUMANode, threadSiblings [][]uint16) {
 		// COMPAT(0.10): Remove in 0.10
 		resp.Resources = &structs.Resources{
 			CPU: totalCompute,
@@ -44,6 +45,8 @@ func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintR
 				CpuShares:          int64(totalCompute),
 				TotalCpuCores:      totalCores,
 				ReservableCpuCores: reservableCores,
+				NUMANodes:          numaNodes,
+				ThreadSiblings:     threadSiblings,
 			},
 		}
 	}
@@ -83,6 +86,14 @@ func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintR
 	}
 	resp.AddAttribute("cpu.reservablecores", strconv.Itoa(len(reservableCores)))
 
+	numaNodes, threadSiblings, err := f.deriveTopology()
+	if err != nil {
+		f.logger.Warn("failed to detect cpu topology", "error", err)
+	} else if len(numaNodes) > 0 {
+		resp.AddAttribute("cpu.numanodes", strconv.Itoa(len(numaNodes)))
+		f.logger.Debug("detected numa nodes", "count", len(numaNodes))
+	}
+
 	tt := int(stats.TotalTicksAvailable())
 	if cfg.CpuCompute > 0 {
 		f.logger.Debug("using user specified cpu compute", "cpu_compute", cfg.CpuCompute)
@@ -99,7 +110,7 @@ func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintR
 	}
 
 	resp.AddAttribute("cpu.totalcompute", fmt.Sprintf("%d", tt))
-	setResourcesCPU(tt, uint16(numCores), reservableCores)
+	setResourcesCPU(tt, uint16(numCores), reservableCores, numaNodes, threadSiblings)
 	resp.Detected = true
 
 	return nil
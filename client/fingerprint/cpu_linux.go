@@ -1,12 +1,118 @@
 package fingerprint
 
 import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
+// sysfsSystem is the sysfs directory describing the NUMA nodes and cpus of
+// the machine.
+var sysfsSystem = "/sys/devices/system"
+
 func (f *CPUFingerprint) deriveReservableCores(req *FingerprintRequest) ([]uint16, error) {
 	// The cpuset cgroup manager is initialized (on linux), but not accessible
 	// from the finger-printer. So we reach in and grab the information manually.
 	// We may assume the hierarchy is already setup.
 	return cgutil.GetCPUsFromCgroup(req.Config.CgroupParent)
 }
+
+// deriveTopology returns the NUMA nodes of the machine and the groups of
+// cpus sharing a physical core, as reported by sysfs.
+func (f *CPUFingerprint) deriveTopology() ([]*structs.NodeNUMANode, [][]uint16, error) {
+	numaNodes, err := readNUMANodes(filepath.Join(sysfsSystem, "node"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	siblings, err := readThreadSiblings(filepath.Join(sysfsSystem, "cpu"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return numaNodes, siblings, nil
+}
+
+// readNUMANodes reads the cpus local to each NUMA node from the node<N>/cpulist
+// files of the directory.
+func readNUMANodes(dir string) ([]*structs.NodeNUMANode, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "node[0-9]*", "cpulist"))
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*structs.NodeNUMANode
+	for _, path := range paths {
+		id, err := strconv.ParseUint(strings.TrimPrefix(filepath.Base(filepath.Dir(path)), "node"), 10, 16)
+		if err != nil {
+			continue
+		}
+
+		cores, err := readCPUList(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Memory only NUMA nodes have no cpus to reserve
+		if len(cores) == 0 {
+			continue
+		}
+
+		nodes = append(nodes, &structs.NodeNUMANode{
+			ID:    uint16(id),
+			Cores: cores,
+		})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes, nil
+}
+
+// readThreadSiblings reads the groups of cpus sharing a physical core from the
+// cpu<N>/topology/thread_siblings_list files of the directory.
+func readThreadSiblings(dir string) ([][]uint16, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "cpu[0-9]*", "topology", "thread_siblings_list"))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var siblings [][]uint16
+	for _, path := range paths {
+		cores, err := readCPUList(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Every cpu of a physical core reports the same siblings
+		key := cpuset.New(cores...).String()
+		if _, ok := seen[key]; ok || len(cores) == 0 {
+			continue
+		}
+		seen[key] = struct{}{}
+		siblings = append(siblings, cores)
+	}
+
+	sort.Slice(siblings, func(i, j int) bool { return siblings[i][0] < siblings[j][0] })
+	return siblings, nil
+}
+
+// readCPUList reads a file in the cpu list format, such as 0-3,8-11.
+func readCPUList(path string) ([]uint16, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := cpuset.Parse(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, err
+	}
+	return set.ToSlice(), nil
+}
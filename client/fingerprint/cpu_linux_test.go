@@ -0,0 +1,52 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func writeSysfsFile(t *testing.T, path, content string) {
+	must.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	must.NoError(t, os.WriteFile(path, []byte(content+"\n"), 0644))
+}
+
+func TestCPUFingerprint_readNUMANodes(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	writeSysfsFile(t, filepath.Join(dir, "node1", "cpulist"), "4-7")
+	writeSysfsFile(t, filepath.Join(dir, "node0", "cpulist"), "0-3")
+
+	// memory only NUMA node
+	writeSysfsFile(t, filepath.Join(dir, "node2", "cpulist"), "")
+
+	nodes, err := readNUMANodes(dir)
+	must.NoError(t, err)
+	must.Eq(t, []*structs.NodeNUMANode{
+		{ID: 0, Cores: []uint16{0, 1, 2, 3}},
+		{ID: 1, Cores: []uint16{4, 5, 6, 7}},
+	}, nodes)
+}
+
+func TestCPUFingerprint_readThreadSiblings(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	for cpu, siblings := range map[string]string{
+		"cpu0": "0,2",
+		"cpu1": "1,3",
+		"cpu2": "0,2",
+		"cpu3": "1,3",
+	} {
+		writeSysfsFile(t, filepath.Join(dir, cpu, "topology", "thread_siblings_list"), siblings)
+	}
+
+	siblings, err := readThreadSiblings(dir)
+	must.NoError(t, err)
+	must.Eq(t, [][]uint16{{0, 2}, {1, 3}}, siblings)
+}
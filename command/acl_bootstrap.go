@@ -34,6 +34,8 @@ Bootstrap Options:
   -t
     Format and display the bootstrap response using a Go template.
 
+  ` + formatOptionsUsage + `
+
 `
 	return strings.TrimSpace(helpText)
 }
@@ -41,8 +43,11 @@ Bootstrap Options:
 func (c *ACLBootstrapCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -59,15 +64,13 @@ func (c *ACLBootstrapCommand) Name() string { return "acl bootstrap" }
 func (c *ACLBootstrapCommand) Run(args []string) int {
 
 	var (
-		json bool
-		tmpl string
-		file string
+		formatOpts FormatOpts
+		file       string
 	)
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -116,8 +119,8 @@ func (c *ACLBootstrapCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, token)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(token)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -175,6 +178,9 @@ func outputACLToken(ui cli.Ui, token *api.ACLToken) {
 		fmt.Sprintf("Global|%v", token.Global),
 		fmt.Sprintf("Create Time|%v", token.CreateTime),
 		fmt.Sprintf("Expiry Time |%s", expiryTimeString(token.ExpirationTime)),
+		fmt.Sprintf("Disabled|%v", token.Disabled),
+		fmt.Sprintf("Last Used|%s", lastUsedString(token.LastUsedTime)),
+		fmt.Sprintf("Last Used IP|%s", lastUsedIPString(token.LastUsedIP)),
 		fmt.Sprintf("Create Index|%d", token.CreateIndex),
 		fmt.Sprintf("Modify Index|%d", token.ModifyIndex),
 	}
@@ -219,3 +225,17 @@ func expiryTimeString(t *time.Time) string {
 	}
 	return t.String()
 }
+
+func lastUsedString(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "<never>"
+	}
+	return t.String()
+}
+
+func lastUsedIPString(ip string) string {
+	if ip == "" {
+		return "<none>"
+	}
+	return ip
+}
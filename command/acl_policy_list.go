@@ -32,6 +32,8 @@ List Options:
 
   -t
     Format and display the ACL policies using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 
 	return strings.TrimSpace(helpText)
@@ -40,8 +42,11 @@ List Options:
 func (c *ACLPolicyListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -56,13 +61,11 @@ func (c *ACLPolicyListCommand) Synopsis() string {
 func (c *ACLPolicyListCommand) Name() string { return "acl policy list" }
 
 func (c *ACLPolicyListCommand) Run(args []string) int {
-	var json bool
-	var tmpl string
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -90,8 +93,8 @@ func (c *ACLPolicyListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, policies)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(policies)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -19,8 +19,7 @@ type ACLRoleCreateCommand struct {
 	name        string
 	description string
 	policyNames []string
-	json        bool
-	tmpl        string
+	formatOpts  FormatOpts
 }
 
 // Help satisfies the cli.Command Help function.
@@ -53,6 +52,8 @@ ACL Create Options:
 
   -t
     Format and display the ACL role using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -65,6 +66,9 @@ func (a *ACLRoleCreateCommand) AutocompleteFlags() complete.Flags {
 			"-policy":      complete.PredictAnything,
 			"-json":        complete.PredictNothing,
 			"-t":           complete.PredictAnything,
+			"-format":      complete.PredictSet("json", "yaml", "csv"),
+			"-columns":     complete.PredictAnything,
+			"-query":       complete.PredictAnything,
 		})
 }
 
@@ -87,8 +91,7 @@ func (a *ACLRoleCreateCommand) Run(args []string) int {
 		a.policyNames = append(a.policyNames, s)
 		return nil
 	}), "policy", "")
-	flags.BoolVar(&a.json, "json", false, "")
-	flags.StringVar(&a.tmpl, "t", "", "")
+	a.formatOpts.SetFlags(flags)
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -132,8 +135,8 @@ func (a *ACLRoleCreateCommand) Run(args []string) int {
 		return 1
 	}
 
-	if a.json || len(a.tmpl) > 0 {
-		out, err := Format(a.json, a.tmpl, role)
+	if a.formatOpts.Enabled() {
+		out, err := a.formatOpts.Output(role)
 		if err != nil {
 			a.Ui.Error(err.Error())
 			return 1
@@ -16,9 +16,8 @@ var _ cli.Command = &ACLRoleInfoCommand{}
 type ACLRoleInfoCommand struct {
 	Meta
 
-	byName bool
-	json   bool
-	tmpl   string
+	byName     bool
+	formatOpts FormatOpts
 }
 
 // Help satisfies the cli.Command Help function.
@@ -44,6 +43,8 @@ ACL Info Options:
 
   -t
     Format and display the ACL role using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 
 	return strings.TrimSpace(helpText)
@@ -55,6 +56,9 @@ func (a *ACLRoleInfoCommand) AutocompleteFlags() complete.Flags {
 			"-by-name": complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -72,8 +76,7 @@ func (a *ACLRoleInfoCommand) Run(args []string) int {
 	flags := a.Meta.FlagSet(a.Name(), FlagSetClient)
 	flags.Usage = func() { a.Ui.Output(a.Help()) }
 	flags.BoolVar(&a.byName, "by-name", false, "")
-	flags.BoolVar(&a.json, "json", false, "")
-	flags.StringVar(&a.tmpl, "t", "", "")
+	a.formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -115,6 +118,17 @@ func (a *ACLRoleInfoCommand) Run(args []string) int {
 		return 1
 	}
 
+	if a.formatOpts.Enabled() {
+		out, err := a.formatOpts.Output(aclRole)
+		if err != nil {
+			a.Ui.Error(err.Error())
+			return 1
+		}
+
+		a.Ui.Output(out)
+		return 0
+	}
+
 	// Format the output.
 	a.Ui.Output(formatACLRole(aclRole))
 	return 0
@@ -35,6 +35,8 @@ ACL List Options:
 
   -t
     Format and display the ACL roles using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 
 	return strings.TrimSpace(helpText)
@@ -43,8 +45,11 @@ ACL List Options:
 func (a *ACLRoleListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(a.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -58,13 +63,10 @@ func (a *ACLRoleListCommand) Name() string { return "acl role list" }
 
 // Run satisfies the cli.Command Run function.
 func (a *ACLRoleListCommand) Run(args []string) int {
-	var json bool
-	var tmpl string
-
+	var formatOpts FormatOpts
 	flags := a.Meta.FlagSet(a.Name(), FlagSetClient)
 	flags.Usage = func() { a.Ui.Output(a.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -91,8 +93,8 @@ func (a *ACLRoleListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, roles)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(roles)
 		if err != nil {
 			a.Ui.Error(err.Error())
 			return 1
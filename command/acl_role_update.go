@@ -20,8 +20,7 @@ type ACLRoleUpdateCommand struct {
 	description string
 	policyNames []string
 	noMerge     bool
-	json        bool
-	tmpl        string
+	formatOpts  FormatOpts
 }
 
 // Help satisfies the cli.Command Help function.
@@ -59,6 +58,8 @@ Update Options:
 
   -t
     Format and display the ACL role using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 
 	return strings.TrimSpace(helpText)
@@ -73,6 +74,9 @@ func (a *ACLRoleUpdateCommand) AutocompleteFlags() complete.Flags {
 			"-policy":      complete.PredictAnything,
 			"-json":        complete.PredictNothing,
 			"-t":           complete.PredictAnything,
+			"-format":      complete.PredictSet("json", "yaml", "csv"),
+			"-columns":     complete.PredictAnything,
+			"-query":       complete.PredictAnything,
 		})
 }
 
@@ -96,8 +100,7 @@ func (a *ACLRoleUpdateCommand) Run(args []string) int {
 		return nil
 	}), "policy", "")
 	flags.BoolVar(&a.noMerge, "no-merge", false, "")
-	flags.BoolVar(&a.json, "json", false, "")
-	flags.StringVar(&a.tmpl, "t", "", "")
+	a.formatOpts.SetFlags(flags)
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -200,8 +203,8 @@ func (a *ACLRoleUpdateCommand) Run(args []string) int {
 		return 1
 	}
 
-	if a.json || len(a.tmpl) > 0 {
-		out, err := Format(a.json, a.tmpl, updatedACLRoleRead)
+	if a.formatOpts.Enabled() {
+		out, err := a.formatOpts.Output(updatedACLRoleRead)
 		if err != nil {
 			a.Ui.Error(err.Error())
 			return 1
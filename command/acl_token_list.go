@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,6 +31,13 @@ List Options:
 
   -t
     Format and display the ACL tokens using a Go template.
+
+  -unused-since=<duration>
+    Only list the tokens that were not used within the given duration, such
+    as "90d" or "720h". Tokens that were never used are listed once they are
+    older than the duration.
+
+  ` + formatOptionsUsage + `
 `
 
 	return strings.TrimSpace(helpText)
@@ -38,8 +46,12 @@ List Options:
 func (c *ACLTokenListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":         complete.PredictNothing,
+			"-t":            complete.PredictAnything,
+			"-format":       complete.PredictSet("json", "yaml", "csv"),
+			"-columns":      complete.PredictAnything,
+			"-query":        complete.PredictAnything,
+			"-unused-since": complete.PredictAnything,
 		})
 }
 
@@ -54,13 +66,13 @@ func (c *ACLTokenListCommand) Synopsis() string {
 func (c *ACLTokenListCommand) Name() string { return "acl token list" }
 
 func (c *ACLTokenListCommand) Run(args []string) int {
-	var json bool
-	var tmpl string
+	var formatOpts FormatOpts
+	var unusedSince string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
+	flags.StringVar(&unusedSince, "unused-since", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -74,6 +86,16 @@ func (c *ACLTokenListCommand) Run(args []string) int {
 		return 1
 	}
 
+	var unusedDuration time.Duration
+	if unusedSince != "" {
+		d, err := parseDurationWithDays(unusedSince)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing unused-since duration: %s", err))
+			return 1
+		}
+		unusedDuration = d
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -88,8 +110,12 @@ func (c *ACLTokenListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, tokens)
+	if unusedDuration > 0 {
+		tokens = filterUnusedTokens(tokens, time.Now().UTC().Add(-unusedDuration))
+	}
+
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(tokens)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -109,7 +135,7 @@ func formatTokens(tokens []*api.ACLTokenListStub) string {
 	}
 
 	output := make([]string, 0, len(tokens)+1)
-	output = append(output, "Name|Type|Global|Accessor ID|Expired")
+	output = append(output, "Name|Type|Global|Accessor ID|Expired|Last Used")
 	for _, p := range tokens {
 		expired := false
 		if p.ExpirationTime != nil && !p.ExpirationTime.IsZero() {
@@ -119,8 +145,39 @@ func formatTokens(tokens []*api.ACLTokenListStub) string {
 		}
 
 		output = append(output, fmt.Sprintf(
-			"%s|%s|%t|%s|%v", p.Name, p.Type, p.Global, p.AccessorID, expired))
+			"%s|%s|%t|%s|%v|%s", p.Name, p.Type, p.Global, p.AccessorID, expired,
+			lastUsedString(p.LastUsedTime)))
 	}
 
 	return formatList(output)
 }
+
+// filterUnusedTokens returns the tokens that were not used since the passed
+// time. Tokens that were never used are returned if they were created before
+// it.
+func filterUnusedTokens(tokens []*api.ACLTokenListStub, since time.Time) []*api.ACLTokenListStub {
+	unused := make([]*api.ACLTokenListStub, 0, len(tokens))
+	for _, token := range tokens {
+		lastUsed := token.CreateTime
+		if token.LastUsedTime != nil {
+			lastUsed = *token.LastUsedTime
+		}
+		if lastUsed.Before(since) {
+			unused = append(unused, token)
+		}
+	}
+	return unused
+}
+
+// parseDurationWithDays parses a duration which, in addition to the units
+// supported by time.ParseDuration, may be a number of days such as "90d".
+func parseDurationWithDays(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
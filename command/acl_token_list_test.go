@@ -2,6 +2,7 @@ package command
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
@@ -57,3 +58,84 @@ func TestACLTokenListCommand(t *testing.T) {
 	must.StrContains(t, out, "CreateIndex")
 	ui.OutputWriter.Reset()
 }
+
+func TestACLTokenListCommand_UnusedSince(t *testing.T) {
+	ci.Parallel(t)
+
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer stopTestAgent(srv)
+
+	state := srv.Agent.Server().State()
+	token := srv.RootToken
+	must.NotNil(t, token)
+
+	now := time.Now().UTC()
+
+	staleToken := mock.ACLToken()
+	staleToken.CreateTime = now.Add(-100 * 24 * time.Hour)
+
+	usedToken := mock.ACLToken()
+	usedToken.CreateTime = now.Add(-100 * 24 * time.Hour)
+
+	newToken := mock.ACLToken()
+
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1000,
+		[]*structs.ACLToken{staleToken, usedToken, newToken}))
+	must.NoError(t, state.UpsertACLTokenUsage(structs.MsgTypeTestSetup, 1001,
+		[]*structs.ACLTokenUsage{{
+			AccessorID:   usedToken.AccessorID,
+			LastUsedTime: now.Add(-time.Hour),
+			LastUsedIP:   "10.0.0.1",
+		}}))
+
+	ui := cli.NewMockUi()
+	cmd := &ACLTokenListCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-unused-since=90d"})
+	must.Zero(t, code)
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "Last Used")
+	must.StrContains(t, out, staleToken.AccessorID)
+	must.StrNotContains(t, out, usedToken.AccessorID)
+	must.StrNotContains(t, out, newToken.AccessorID)
+
+	// An invalid duration is rejected
+	must.One(t, cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-unused-since=ninety"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "Error parsing unused-since duration")
+}
+
+func TestParseDurationWithDays(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		input string
+		exp   time.Duration
+		err   bool
+	}{
+		{input: "90d", exp: 90 * 24 * time.Hour},
+		{input: "0d", exp: 0},
+		{input: "36h", exp: 36 * time.Hour},
+		{input: "1h30m", exp: 90 * time.Minute},
+		{input: "-1d", err: true},
+		{input: "1.5d", err: true},
+		{input: "d", err: true},
+		{input: "ninety", err: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			d, err := parseDurationWithDays(tc.input)
+			if tc.err {
+				must.Error(t, err)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.exp, d)
+		})
+	}
+}
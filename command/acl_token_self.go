@@ -2,8 +2,11 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
 )
 
@@ -15,7 +18,9 @@ func (c *ACLTokenSelfCommand) Help() string {
 	helpText := `
 Usage: nomad acl token self
 
-  Self is used to fetch information about the currently set ACL token.
+  Self is used to fetch information about the currently set ACL token. The
+  node rules granted to the token by its policies and roles are listed along
+  the token.
 
 General Options:
 
@@ -69,5 +74,69 @@ func (c *ACLTokenSelfCommand) Run(args []string) int {
 
 	// Format the output
 	outputACLToken(c.Ui, token)
+
+	// Management tokens are allowed any node operation
+	if token.Type == "management" {
+		return 0
+	}
+
+	rules, err := c.nodeRules(client, token)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error fetching node rules: %s", err))
+		return 1
+	}
+	c.Ui.Output("")
+	c.Ui.Output(fmt.Sprintf("Node Rules\n%s", formatList(rules)))
 	return 0
 }
+
+// nodeRules returns the rows of the node policies and node rules of the
+// policies linked to the token, directly or through its roles.
+func (c *ACLTokenSelfCommand) nodeRules(client *api.Client, token *api.ACLToken) ([]string, error) {
+	names := make(map[string]struct{})
+	for _, name := range token.Policies {
+		names[name] = struct{}{}
+	}
+	for _, link := range token.Roles {
+		role, _, err := client.ACLRoles().Get(link.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range role.Policies {
+			names[policy.Name] = struct{}{}
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	rows := []string{"Policy|Selector|Node Policy"}
+	for _, name := range sorted {
+		// Tokens can reference policies that don't exist yet
+		policy, _, err := client.ACLPolicies().Info(name, nil)
+		if err != nil && strings.Contains(err.Error(), "404") {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		parsed, err := acl.Parse(policy.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy %q: %v", name, err)
+		}
+		if parsed.Node != nil {
+			rows = append(rows, fmt.Sprintf("%s|<all>|%s", name, parsed.Node.Policy))
+		}
+		for _, rule := range parsed.NodeRules {
+			rows = append(rows, fmt.Sprintf("%s|%s|%s", name, rule.Selector, rule.Policy))
+		}
+	}
+
+	if len(rows) == 1 {
+		return []string{"<none>"}, nil
+	}
+	return rows, nil
+}
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/nomad/acl"
@@ -50,3 +51,32 @@ func TestACLTokenSelfCommand_ViaEnvVar(t *testing.T) {
 	out := ui.OutputWriter.String()
 	must.StrContains(t, out, mockToken.AccessorID)
 }
+
+func TestACLTokenSelfCommand_NodeRules(t *testing.T) {
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer stopTestAgent(srv)
+
+	state := srv.Agent.Server().State()
+
+	// Create a token with a policy granting node rules
+	token := mock.CreatePolicyAndToken(t, state, 1000, "gpu-oncall",
+		`node { policy = "read" }
+		node "class:gpu-*" { policy = "write" }`)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLTokenSelfCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	t.Setenv("NOMAD_TOKEN", token.SecretID)
+	code := cmd.Run([]string{"-address=" + url})
+	must.Zero(t, code)
+
+	// Check the output lists the node policy and the node rule
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "Node Rules")
+	must.RegexMatch(t, regexp.MustCompile(`gpu-oncall\s+<all>\s+read`), out)
+	must.RegexMatch(t, regexp.MustCompile(`gpu-oncall\s+class:gpu-\*\s+write`), out)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
 	"github.com/posener/complete"
 )
 
@@ -35,6 +36,10 @@ Update Options:
   -policy=""
     Specifies a policy to associate with the token. Can be specified multiple times,
     but only with client type tokens.
+
+  -disabled
+    Disables the token, or enables it again with -disabled=false. Tokens that
+    were disabled for being idle can be enabled again this way.
 `
 
 	return strings.TrimSpace(helpText)
@@ -43,10 +48,11 @@ Update Options:
 func (c *ACLTokenUpdateCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"name":   complete.PredictAnything,
-			"type":   complete.PredictAnything,
-			"global": complete.PredictNothing,
-			"policy": complete.PredictAnything,
+			"name":     complete.PredictAnything,
+			"type":     complete.PredictAnything,
+			"global":   complete.PredictNothing,
+			"policy":   complete.PredictAnything,
+			"disabled": complete.PredictNothing,
 		})
 }
 
@@ -64,6 +70,7 @@ func (c *ACLTokenUpdateCommand) Run(args []string) int {
 	var name, tokenType string
 	var global bool
 	var policies []string
+	var disabled flaghelper.BoolValue
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.StringVar(&name, "name", "", "")
@@ -73,6 +80,7 @@ func (c *ACLTokenUpdateCommand) Run(args []string) int {
 		policies = append(policies, s)
 		return nil
 	}), "policy", "")
+	flags.Var(&disabled, "disabled", "")
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -119,6 +127,8 @@ func (c *ACLTokenUpdateCommand) Run(args []string) int {
 		token.Policies = policies
 	}
 
+	disabled.Merge(&token.Disabled)
+
 	// Update the token
 	updatedToken, _, err := client.ACLTokens().Update(token, nil)
 	if err != nil {
@@ -50,3 +50,44 @@ func TestACLTokenUpdateCommand(t *testing.T) {
 	must.StrContains(t, out, mockToken.AccessorID)
 	must.StrContains(t, out, "bar")
 }
+
+func TestACLTokenUpdateCommand_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer stopTestAgent(srv)
+
+	token := srv.RootToken
+	must.NotNil(t, token)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLTokenUpdateCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	state := srv.Agent.Server().State()
+
+	// Create a token disabled for being idle
+	mockToken := mock.ACLToken()
+	mockToken.Policies = []string{acl.PolicyWrite}
+	mockToken.Disabled = true
+	mockToken.SetHash()
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{mockToken}))
+
+	// Updating the token keeps it disabled
+	code := cmd.Run([]string{"--token=" + token.SecretID, "-address=" + url, "-name=bar", mockToken.AccessorID})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), "Disabled     = true")
+	ui.OutputWriter.Reset()
+
+	// Enable the token again
+	code = cmd.Run([]string{"--token=" + token.SecretID, "-address=" + url, "-disabled=false", mockToken.AccessorID})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), "Disabled     = false")
+
+	updated, err := state.ACLTokenByAccessorID(nil, mockToken.AccessorID)
+	must.NoError(t, err)
+	must.False(t, updated.Disabled)
+	must.NotNil(t, updated.EnableTime)
+}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	golog "log"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -115,6 +116,10 @@ type Agent struct {
 	builtinListener net.Listener
 	builtinDialer   *bufconndialer.BufConnWrapper
 
+	// taskAPI serves the HTTP API to tasks over their Task API socket. In
+	// the event this agent is not running in client mode, it will be nil.
+	taskAPI *taskAPI
+
 	InmemSink *metrics.InmemSink
 }
 
@@ -152,6 +157,9 @@ func NewAgent(config *Config, logger log.InterceptLogger, logOutput io.Writer, i
 	if err := a.setupEnterpriseAgent(logger); err != nil {
 		return nil, err
 	}
+	if err := a.setupAuditor(config.Audit); err != nil {
+		return nil, fmt.Errorf("Failed to initialize audit logging: %v", err)
+	}
 	if a.client == nil && a.server == nil {
 		return nil, fmt.Errorf("must have at least client or server mode enabled")
 	}
@@ -247,6 +255,9 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.ACL.TokenMaxExpirationTTL != 0 {
 		conf.ACLTokenMaxExpirationTTL = agentConfig.ACL.TokenMaxExpirationTTL
 	}
+	if agentConfig.ACL.TokenIdleTimeout != 0 {
+		conf.ACLTokenIdleTimeout = agentConfig.ACL.TokenIdleTimeout
+	}
 	if agentConfig.Sentinel != nil {
 		conf.SentinelConfig = agentConfig.Sentinel
 	}
@@ -355,6 +366,15 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.JobGCThreshold = dur
 	}
+	if ttl := agentConfig.Server.DispatchIdempotencyTTL; ttl != "" {
+		dur, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dispatch_idempotency_ttl: %v", err)
+		} else if dur <= time.Duration(0) {
+			return nil, fmt.Errorf("dispatch_idempotency_ttl should be greater than 0s")
+		}
+		conf.DispatchIdempotencyTTL = dur
+	}
 	if gcThreshold := agentConfig.Server.EvalGCThreshold; gcThreshold != "" {
 		dur, err := time.ParseDuration(gcThreshold)
 		if err != nil {
@@ -411,6 +431,19 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.RootKeyRotationThreshold = dur
 	}
+	if maxVersions := agentConfig.Server.VariablesMaxVersions; maxVersions != nil {
+		if *maxVersions < 0 {
+			return nil, fmt.Errorf("variables_max_versions must be non-negative")
+		}
+		conf.VariablesMaxVersions = *maxVersions
+	}
+	if gcThreshold := agentConfig.Server.VariablesGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.VariablesGCThreshold = dur
+	}
 
 	if heartbeatGrace := agentConfig.Server.HeartbeatGrace; heartbeatGrace != 0 {
 		conf.HeartbeatGrace = heartbeatGrace
@@ -487,6 +520,16 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 	}
 
+	// Set admission webhook configuration.
+	for _, webhook := range agentConfig.Server.AdmissionWebhooks {
+		if err := webhook.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid admission_webhook %q: %v", webhook.Name, err)
+		}
+		webhook = webhook.Copy()
+		webhook.Canonicalize()
+		conf.AdmissionWebhooks = append(conf.AdmissionWebhooks, webhook)
+	}
+
 	// Add Enterprise license configs
 	conf.LicenseEnv = agentConfig.Server.LicenseEnv
 	conf.LicensePath = agentConfig.Server.LicensePath
@@ -507,6 +550,22 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.RaftBoltNoFreelistSync = bolt.NoFreelistSync
 	}
 
+	// Set the issuer of workload identities
+	if issuer := agentConfig.Server.OIDCIssuer; issuer != "" {
+		if u, err := url.Parse(issuer); err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("oidc_issuer must be an https URL: %q", issuer)
+		}
+		conf.OIDCIssuer = issuer
+	}
+
+	// Set the provider of the keyring's key encryption keys
+	if keyring := agentConfig.Server.Keyring; keyring != nil {
+		if err := keyring.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid keyring configuration: %v", err)
+		}
+		conf.KeyringConfig = keyring.Copy()
+	}
+
 	return conf, nil
 }
 
@@ -533,6 +592,7 @@ func (a *Agent) finalizeServerConfig(c *nomad.Config) {
 	c.PluginLoader = a.pluginLoader
 	c.PluginSingletonLoader = a.pluginSingletonLoader
 	c.AgentShutdown = func() error { return a.Shutdown() }
+	c.JobTemplateRenderer = &jobTemplateRenderer{region: c.Region}
 }
 
 // clientConfig is used to generate a new client configuration struct for
@@ -977,6 +1037,11 @@ func (a *Agent) setupClient() error {
 	a.builtinListener, a.builtinDialer = bufconndialer.New()
 	conf.TemplateDialer = a.builtinDialer
 
+	// The Task API serves the handlers of the builtin HTTP server, which
+	// is created along with the other HTTP servers after the client.
+	a.taskAPI = newTaskAPI(a.httpLogger)
+	conf.TaskAPI = a.taskAPI
+
 	nomadClient, err := client.NewClient(
 		conf, a.consulCatalog, a.consulProxies, a.consulService, nil)
 	if err != nil {
@@ -1198,13 +1263,12 @@ func (a *Agent) Reload(newConfig *Config) error {
 
 	// Update eventer config
 	if newConfig.Audit != nil {
-		if err := a.entReloadEventer(newConfig.Audit); err != nil {
+		if err := a.reloadAuditor(newConfig.Audit); err != nil {
 			return err
 		}
 	}
-	// Allow auditor to call reopen regardless of config changes
-	// This is primarily for enterprise audit logging to allow the underlying
-	// file to be reopened if necessary
+	// Allow auditor to call reopen regardless of config changes, so the
+	// audit log can be rotated by external tools
 	if err := a.auditor.Reopen(); err != nil {
 		return err
 	}
@@ -1294,6 +1358,26 @@ func (a *Agent) setupConsul(consulConfig *config.ConsulConfig) error {
 	return nil
 }
 
+// setupAuditor creates the auditor writing the audit log of the requests made
+// to the HTTP API of the agent.
+func (a *Agent) setupAuditor(cfg *config.AuditConfig) error {
+	auditor, err := newAuditor(cfg, a.config.DataDir, a.logger)
+	if err != nil {
+		return err
+	}
+	a.auditor = auditor
+	return nil
+}
+
+// reloadAuditor updates the configuration of the auditor.
+func (a *Agent) reloadAuditor(cfg *config.AuditConfig) error {
+	auditor, ok := a.auditor.(*auditor)
+	if !ok {
+		return nil
+	}
+	return auditor.setConfig(cfg, a.config.DataDir)
+}
+
 // noOpAuditor is a no-op Auditor that fulfills the
 // event.Auditor interface.
 type noOpAuditor struct{}
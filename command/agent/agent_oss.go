@@ -5,7 +5,6 @@ package agent
 
 import (
 	hclog "github.com/hashicorp/go-hclog"
-	"github.com/hashicorp/nomad/nomad/structs/config"
 )
 
 // EnterpriseAgent holds information and methods for enterprise functionality
@@ -13,12 +12,5 @@ import (
 type EnterpriseAgent struct{}
 
 func (a *Agent) setupEnterpriseAgent(log hclog.Logger) error {
-	// configure eventer
-	a.auditor = &noOpAuditor{}
-
-	return nil
-}
-
-func (a *Agent) entReloadEventer(cfg *config.AuditConfig) error {
 	return nil
 }
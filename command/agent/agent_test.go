@@ -444,6 +444,33 @@ func TestAgent_ServerConfig_PlanRejectionTracker(t *testing.T) {
 	}
 }
 
+func TestAgent_ServerConfig_AdmissionWebhooks(t *testing.T) {
+	ci.Parallel(t)
+
+	conf := DevConfig(nil)
+	require.NoError(t, conf.normalizeAddrs())
+
+	conf.Server.AdmissionWebhooks = []*config.AdmissionWebhookConfig{{
+		Name: "policy",
+		URL:  "http://localhost:8080/admit",
+	}}
+	serverConfig, err := convertServerConfig(conf)
+	require.NoError(t, err)
+	require.Equal(t, []*config.AdmissionWebhookConfig{{
+		Name:          "policy",
+		URL:           "http://localhost:8080/admit",
+		Timeout:       config.DefaultAdmissionWebhookTimeout,
+		FailurePolicy: config.AdmissionWebhookFailurePolicyFail,
+	}}, serverConfig.AdmissionWebhooks)
+
+	// The agent configuration is not modified
+	require.Zero(t, conf.Server.AdmissionWebhooks[0].Timeout)
+
+	conf.Server.AdmissionWebhooks[0].FailurePolicy = "retry"
+	_, err = convertServerConfig(conf)
+	require.ErrorContains(t, err, `invalid admission_webhook "policy"`)
+}
+
 func TestAgent_ServerConfig_RaftMultiplier_Ok(t *testing.T) {
 	ci.Parallel(t)
 
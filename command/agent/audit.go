@@ -0,0 +1,400 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	glob "github.com/ryanuber/go-glob"
+)
+
+const (
+	// auditStageReceived is the stage of the audit events emitted before a
+	// request is handled
+	auditStageReceived = "OperationReceived"
+
+	// auditStageComplete is the stage of the audit events emitted once a
+	// request is handled, but before its response is returned
+	auditStageComplete = "OperationComplete"
+
+	// auditEventType is the type of the audit events of requests
+	auditEventType = "audit"
+
+	// auditEventVersion is the version of the format of the audit events
+	auditEventVersion = 1
+
+	// auditFilterTypeHTTP is the type of the filters of HTTP requests, the
+	// only type of audit filters
+	auditFilterTypeHTTP = "HTTPEvent"
+
+	// auditSinkTypeFile is the type of the file sinks, the only type of
+	// audit sinks
+	auditSinkTypeFile = "file"
+
+	// auditSinkFormatJSON is the format of the JSON sinks, the only format of
+	// audit sinks
+	auditSinkFormatJSON = "json"
+
+	// auditDeliveryEnforced fails requests whose audit events can't be
+	// written to the sink
+	auditDeliveryEnforced = "enforced"
+
+	// auditDeliveryBestEffort logs the audit events that can't be written to
+	// the sink without failing their requests
+	auditDeliveryBestEffort = "best-effort"
+
+	// auditDefaultSinkName is the name of the sink used when audit logging is
+	// enabled without configuring a sink
+	auditDefaultSinkName = "audit"
+
+	// auditDefaultRotateDuration is the default duration after which the
+	// audit log is rotated
+	auditDefaultRotateDuration = 24 * time.Hour
+
+	// auditDefaultMode is the default permissions of the audit log files
+	auditDefaultMode = "0600"
+)
+
+// Ensure auditor is an Auditor
+var _ event.Auditor = &auditor{}
+
+// auditor writes the audit events of the requests made to the HTTP API of the
+// agent to its audit sink, unless the events are excluded by a filter. RPC
+// calls aren't audited.
+type auditor struct {
+	logger log.Logger
+
+	// lock protects the configuration of the auditor, which is replaced when
+	// the agent is reloaded
+	lock    sync.RWMutex
+	enabled bool
+	sink    *auditFileSink
+	filters []auditFilter
+}
+
+// newAuditor returns an auditor configured by cfg. The default sink writes
+// the audit log in the audit directory of dataDir.
+func newAuditor(cfg *config.AuditConfig, dataDir string, logger log.Logger) (*auditor, error) {
+	a := &auditor{
+		logger: logger.Named("audit"),
+	}
+	if err := a.setConfig(cfg, dataDir); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// setConfig replaces the configuration of the auditor, closing the audit log
+// of the previous sink.
+func (a *auditor) setConfig(cfg *config.AuditConfig, dataDir string) error {
+	enabled := cfg != nil && cfg.Enabled != nil && *cfg.Enabled
+
+	var sink *auditFileSink
+	var filters []auditFilter
+	if enabled {
+		sinks := cfg.Sinks
+		if len(sinks) == 0 {
+			sinks = []*config.AuditSink{{Name: auditDefaultSinkName}}
+		}
+		if len(sinks) > 1 {
+			return fmt.Errorf("only a single audit sink is supported")
+		}
+
+		var err error
+		sink, err = newAuditFileSink(sinks[0], dataDir)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range cfg.Filters {
+			filter, err := newHTTPAuditFilter(f)
+			if err != nil {
+				return err
+			}
+			filters = append(filters, filter)
+		}
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.sink != nil {
+		if err := a.sink.file.Close(); err != nil {
+			a.logger.Warn("failed to close audit log", "sink", a.sink.name, "error", err)
+		}
+	}
+	a.enabled = enabled
+	a.sink = sink
+	a.filters = filters
+	return nil
+}
+
+// auditEntry is the line of the audit log of an audit event
+type auditEntry struct {
+	CreatedAt time.Time   `json:"created_at"`
+	EventType string      `json:"event_type"`
+	Payload   interface{} `json:"payload"`
+}
+
+// Event writes the audit event to the sink, unless it is excluded by a
+// filter. An error is returned if the event can't be written to a sink with
+// enforced delivery.
+func (a *auditor) Event(ctx context.Context, eventType string, payload interface{}) error {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	if !a.enabled {
+		return nil
+	}
+
+	if ev, ok := payload.(*auditEvent); ok {
+		for _, filter := range a.filters {
+			if filter.Excludes(ev) {
+				return nil
+			}
+		}
+	}
+
+	line, err := json.Marshal(&auditEntry{
+		CreatedAt: time.Now(),
+		EventType: eventType,
+		Payload:   payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %v", err)
+	}
+	line = append(line, '\n')
+
+	if _, err := a.sink.file.Write(line); err != nil {
+		if a.sink.enforced {
+			return fmt.Errorf("failed to write audit event: %v", err)
+		}
+		a.logger.Error("failed to write audit event", "sink", a.sink.name, "error", err)
+	}
+	return nil
+}
+
+// Enabled returns whether audit logging is enabled.
+func (a *auditor) Enabled() bool {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.enabled
+}
+
+// Reopen closes the audit log, which is opened again by the next event. It
+// allows the audit log to be moved by external log rotation.
+func (a *auditor) Reopen() error {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	if a.sink == nil {
+		return nil
+	}
+	return a.sink.file.Close()
+}
+
+// SetEnabled enables or disables audit logging. Audit logging can only be
+// enabled if a sink is configured.
+func (a *auditor) SetEnabled(enabled bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.enabled = enabled && a.sink != nil
+}
+
+// DeliveryEnforced returns whether requests fail if their audit events can't
+// be written to the sink.
+func (a *auditor) DeliveryEnforced() bool {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.sink != nil && a.sink.enforced
+}
+
+// auditFileSink writes the audit log to a file, which is rotated by size or
+// time.
+type auditFileSink struct {
+	name     string
+	enforced bool
+	file     *logFile
+}
+
+func newAuditFileSink(cfg *config.AuditSink, dataDir string) (*auditFileSink, error) {
+	switch cfg.Type {
+	case "", auditSinkTypeFile:
+	default:
+		return nil, fmt.Errorf("audit sink %q: unsupported type %q", cfg.Name, cfg.Type)
+	}
+
+	switch cfg.Format {
+	case "", auditSinkFormatJSON:
+	default:
+		return nil, fmt.Errorf("audit sink %q: unsupported format %q", cfg.Name, cfg.Format)
+	}
+
+	enforced := true
+	switch cfg.DeliveryGuarantee {
+	case "", auditDeliveryEnforced:
+	case auditDeliveryBestEffort:
+		enforced = false
+	default:
+		return nil, fmt.Errorf("audit sink %q: unsupported delivery guarantee %q", cfg.Name, cfg.DeliveryGuarantee)
+	}
+
+	path := cfg.Path
+	if path == "" {
+		if dataDir == "" {
+			return nil, fmt.Errorf("audit sink %q: path is required without a data directory", cfg.Name)
+		}
+		path = filepath.Join(dataDir, "audit", "audit.log")
+	}
+
+	modeStr := cfg.Mode
+	if modeStr == "" {
+		modeStr = auditDefaultMode
+	}
+	mode, err := strconv.ParseUint(modeStr, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("audit sink %q: failed to parse mode %q as octal: %v", cfg.Name, modeStr, err)
+	}
+
+	duration := cfg.RotateDuration
+	if duration == 0 {
+		duration = auditDefaultRotateDuration
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("audit sink %q: failed to create directory: %v", cfg.Name, err)
+	}
+
+	return &auditFileSink{
+		name:     cfg.Name,
+		enforced: enforced,
+		file: &logFile{
+			fileName: filepath.Base(path),
+			logPath:  filepath.Dir(path),
+			duration: duration,
+			MaxBytes: cfg.RotateBytes,
+			MaxFiles: cfg.RotateMaxFiles,
+			Mode:     os.FileMode(mode),
+		},
+	}, nil
+}
+
+// auditFilter excludes audit events from the audit log. Filters are
+// evaluated in order and an event is excluded if any filter excludes it.
+type auditFilter interface {
+	// Excludes returns true if the event must not be written to the audit
+	// log.
+	Excludes(ev *auditEvent) bool
+}
+
+// httpAuditFilter excludes the audit events of HTTP requests whose endpoint,
+// stage and operation each match one of the glob patterns of the filter.
+type httpAuditFilter struct {
+	endpoints  []string
+	stages     []string
+	operations []string
+}
+
+func newHTTPAuditFilter(cfg *config.AuditFilter) (*httpAuditFilter, error) {
+	switch cfg.Type {
+	case "", auditFilterTypeHTTP:
+	default:
+		return nil, fmt.Errorf("audit filter %q: unsupported type %q", cfg.Name, cfg.Type)
+	}
+	return &httpAuditFilter{
+		endpoints:  cfg.Endpoints,
+		stages:     cfg.Stages,
+		operations: cfg.Operations,
+	}, nil
+}
+
+// Excludes implements auditFilter. The query parameters of the endpoint are
+// ignored.
+func (f *httpAuditFilter) Excludes(ev *auditEvent) bool {
+	return ev.Request != nil &&
+		matchesAnyGlob(f.endpoints, ev.Request.path) &&
+		matchesAnyGlob(f.stages, ev.Stage) &&
+		matchesAnyGlob(f.operations, ev.Request.Operation)
+}
+
+func matchesAnyGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if glob.Glob(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// auditEvent is the payload of the audit event of a stage of a request
+type auditEvent struct {
+	ID        string         `json:"id"`
+	Stage     string         `json:"stage"`
+	Type      string         `json:"type"`
+	Timestamp time.Time      `json:"timestamp"`
+	Version   int            `json:"version"`
+	Auth      *auditAuth     `json:"auth,omitempty"`
+	Request   *auditRequest  `json:"request"`
+	Response  *auditResponse `json:"response,omitempty"`
+}
+
+// auditAuth is the identity a request is made with, either an ACL token or
+// the workload identity of a task. It is omitted if ACLs are disabled.
+type auditAuth struct {
+	AccessorID       string                 `json:"accessor_id,omitempty"`
+	Name             string                 `json:"name,omitempty"`
+	Policies         []string               `json:"policies,omitempty"`
+	Roles            []string               `json:"roles,omitempty"`
+	Global           bool                   `json:"global,omitempty"`
+	CreateTime       *time.Time             `json:"create_time,omitempty"`
+	WorkloadIdentity *auditWorkloadIdentity `json:"workload_identity,omitempty"`
+}
+
+// auditWorkloadIdentity are the claims of the workload identity of a task.
+// Verified is false when the claims couldn't be verified by the agent, in
+// which case they are only what the request presented.
+type auditWorkloadIdentity struct {
+	Namespace    string `json:"namespace"`
+	JobID        string `json:"job_id"`
+	AllocationID string `json:"allocation_id"`
+	TaskName     string `json:"task"`
+	Verified     bool   `json:"verified"`
+}
+
+type auditRequest struct {
+	ID          string            `json:"id"`
+	Operation   string            `json:"operation"`
+	Endpoint    string            `json:"endpoint"`
+	Namespace   *auditNamespace   `json:"namespace"`
+	RequestMeta *auditRequestMeta `json:"request_meta"`
+	NodeMeta    *auditNodeMeta    `json:"node_meta"`
+
+	// path is the endpoint without its query parameters, matched by filters
+	path string
+}
+
+type auditNamespace struct {
+	ID string `json:"id"`
+}
+
+type auditRequestMeta struct {
+	RemoteAddress string `json:"remote_address"`
+	UserAgent     string `json:"user_agent"`
+}
+
+type auditNodeMeta struct {
+	IP string `json:"ip"`
+}
+
+type auditResponse struct {
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,243 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+// readAuditLog returns the payloads of the events of the audit log at path
+func readAuditLog(t *testing.T, path string) []*auditEvent {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []*auditEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry struct {
+			EventType string      `json:"event_type"`
+			Payload   *auditEvent `json:"payload"`
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		require.Equal(t, auditEventType, entry.EventType)
+		events = append(events, entry.Payload)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestAuditor_DefaultSink(t *testing.T) {
+	ci.Parallel(t)
+
+	dataDir := t.TempDir()
+	a, err := newAuditor(&config.AuditConfig{Enabled: pointer.Of(true)}, dataDir, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.True(t, a.Enabled())
+	require.True(t, a.DeliveryEnforced())
+
+	ev := &auditEvent{
+		ID:      "1",
+		Stage:   auditStageReceived,
+		Request: &auditRequest{Operation: "GET", Endpoint: "/v1/jobs", path: "/v1/jobs"},
+	}
+	require.NoError(t, a.Event(context.Background(), auditEventType, ev))
+
+	path := filepath.Join(dataDir, "audit", "audit.log")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	events := readAuditLog(t, path)
+	require.Len(t, events, 1)
+	require.Equal(t, "/v1/jobs", events[0].Request.Endpoint)
+
+	// Disabling audit logging on reload stops writing events
+	require.NoError(t, a.setConfig(&config.AuditConfig{}, dataDir))
+	require.False(t, a.Enabled())
+	require.NoError(t, a.Event(context.Background(), auditEventType, ev))
+	require.Len(t, readAuditLog(t, path), 1)
+}
+
+func TestAuditor_InvalidConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name string
+		cfg  *config.AuditConfig
+		err  string
+	}{
+		{
+			name: "multiple sinks",
+			cfg: &config.AuditConfig{
+				Enabled: pointer.Of(true),
+				Sinks:   []*config.AuditSink{{Name: "a"}, {Name: "b"}},
+			},
+			err: "only a single audit sink is supported",
+		},
+		{
+			name: "delivery guarantee",
+			cfg: &config.AuditConfig{
+				Enabled: pointer.Of(true),
+				Sinks:   []*config.AuditSink{{Name: "a", DeliveryGuarantee: "maybe"}},
+			},
+			err: `audit sink "a": unsupported delivery guarantee "maybe"`,
+		},
+		{
+			name: "filter type",
+			cfg: &config.AuditConfig{
+				Enabled: pointer.Of(true),
+				Filters: []*config.AuditFilter{{Name: "f", Type: "RPCEvent"}},
+			},
+			err: `audit filter "f": unsupported type "RPCEvent"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newAuditor(tc.cfg, t.TempDir(), testlog.HCLogger(t))
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestAuditor_Filters(t *testing.T) {
+	ci.Parallel(t)
+
+	filter, err := newHTTPAuditFilter(&config.AuditFilter{
+		Name:       "received GETs",
+		Type:       auditFilterTypeHTTP,
+		Endpoints:  []string{"/v1/evaluation/*/allocations", "/v1/metrics"},
+		Stages:     []string{auditStageReceived},
+		Operations: []string{"GET"},
+	})
+	require.NoError(t, err)
+
+	event := func(stage, method, path string) *auditEvent {
+		return &auditEvent{
+			Stage: stage,
+			Request: &auditRequest{
+				Operation: method,
+				Endpoint:  path + "?index=1",
+				path:      path,
+			},
+		}
+	}
+
+	require.True(t, filter.Excludes(event(auditStageReceived, "GET", "/v1/metrics")))
+	require.True(t, filter.Excludes(event(auditStageReceived, "GET", "/v1/evaluation/123/allocations")))
+	require.False(t, filter.Excludes(event(auditStageComplete, "GET", "/v1/metrics")))
+	require.False(t, filter.Excludes(event(auditStageReceived, "PUT", "/v1/metrics")))
+	require.False(t, filter.Excludes(event(auditStageReceived, "GET", "/v1/jobs")))
+}
+
+func TestAuditEndpoint_Redaction(t *testing.T) {
+	ci.Parallel(t)
+
+	req := httptest.NewRequest("GET", "/v1/jobs?prefix=web&token=secret&Secret_ID=x", nil)
+	require.Equal(t, "/v1/jobs?Secret_ID=redacted&prefix=web&token=redacted", auditEndpoint(req.URL))
+
+	req = httptest.NewRequest("GET", "/v1/jobs", nil)
+	require.Equal(t, "/v1/jobs", auditEndpoint(req.URL))
+}
+
+func TestHTTP_Audit(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	httpACLTest(t, func(c *Config) {
+		c.Audit = &config.AuditConfig{
+			Enabled: pointer.Of(true),
+			Sinks: []*config.AuditSink{{
+				Name:              "audit",
+				DeliveryGuarantee: auditDeliveryEnforced,
+				Path:              path,
+			}},
+			Filters: []*config.AuditFilter{{
+				Name:       "metrics",
+				Type:       auditFilterTypeHTTP,
+				Endpoints:  []string{"/v1/metrics"},
+				Stages:     []string{"*"},
+				Operations: []string{"*"},
+			}},
+		}
+	}, func(s *TestAgent) {
+		state := s.Agent.server.State()
+		token := mock.CreatePolicyAndToken(t, state, 1000, "read-jobs",
+			mock.NamespacePolicy(structs.DefaultNamespace, "read", nil))
+
+		// An allowed request
+		req := httptest.NewRequest("GET", "/v1/jobs?namespace=default", nil)
+		setToken(req, token)
+		resp := httptest.NewRecorder()
+		s.Server.mux.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		// A denied request
+		req = httptest.NewRequest("GET", "/v1/nodes", nil)
+		setToken(req, token)
+		resp = httptest.NewRecorder()
+		s.Server.mux.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusForbidden, resp.Code)
+
+		// A filtered request
+		req = httptest.NewRequest("GET", "/v1/metrics", nil)
+		setToken(req, token)
+		s.Server.mux.ServeHTTP(httptest.NewRecorder(), req)
+
+		// A request with a workload identity
+		alloc := mock.Alloc()
+		claims := &structs.IdentityClaims{
+			Namespace:    alloc.Namespace,
+			JobID:        alloc.JobID,
+			AllocationID: alloc.ID,
+			TaskName:     "web",
+		}
+		jwtToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("key"))
+		require.NoError(t, err)
+		req = httptest.NewRequest("GET", "/v1/jobs", nil)
+		req.Header.Set("X-Nomad-Token", jwtToken)
+		s.Server.mux.ServeHTTP(httptest.NewRecorder(), req)
+
+		events := readAuditLog(t, path)
+		require.Len(t, events, 6)
+
+		received, complete := events[0], events[1]
+		require.Equal(t, auditStageReceived, received.Stage)
+		require.Equal(t, auditStageComplete, complete.Stage)
+		require.Equal(t, received.ID, complete.ID)
+		require.Nil(t, received.Response)
+		require.Equal(t, token.AccessorID, received.Auth.AccessorID)
+		require.Equal(t, []string{"read-jobs"}, received.Auth.Policies)
+		require.Equal(t, "GET", received.Request.Operation)
+		require.Equal(t, "/v1/jobs?namespace=default", received.Request.Endpoint)
+		require.Equal(t, structs.DefaultNamespace, received.Request.Namespace.ID)
+		require.Equal(t, http.StatusOK, complete.Response.StatusCode)
+
+		denied := events[3]
+		require.Equal(t, "/v1/nodes", denied.Request.Endpoint)
+		require.Equal(t, http.StatusForbidden, denied.Response.StatusCode)
+		require.Equal(t, structs.ErrPermissionDenied.Error(), denied.Response.Error)
+
+		identity := events[4]
+		require.Equal(t, "/v1/jobs", identity.Request.Endpoint)
+		require.Empty(t, identity.Auth.AccessorID)
+		require.Equal(t, alloc.ID, identity.Auth.WorkloadIdentity.AllocationID)
+		require.Equal(t, "web", identity.Auth.WorkloadIdentity.TaskName)
+		require.False(t, identity.Auth.WorkloadIdentity.Verified)
+	})
+}
@@ -399,6 +399,12 @@ type ACLConfig struct {
 	TokenMaxExpirationTTL    time.Duration
 	TokenMaxExpirationTTLHCL string `hcl:"token_max_expiration_ttl" json:"-"`
 
+	// TokenIdleTimeout is how long a local client ACL token can go unused
+	// before the leader disables it. Idle tokens are not disabled unless
+	// this is set.
+	TokenIdleTimeout    time.Duration
+	TokenIdleTimeoutHCL string `hcl:"token_idle_timeout" json:"-"`
+
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -465,6 +471,10 @@ type ServerConfig struct {
 	// can be used to filter by age.
 	JobGCThreshold string `hcl:"job_gc_threshold"`
 
+	// DispatchIdempotencyTTL controls how long the idempotency tokens of
+	// dispatched jobs are remembered.
+	DispatchIdempotencyTTL string `hcl:"dispatch_idempotency_ttl"`
+
 	// EvalGCThreshold controls how "old" an eval must be to be collected by GC.
 	// Age is not the only requirement for a eval to be GCed but the threshold
 	// can be used to filter by age.
@@ -502,6 +512,14 @@ type ServerConfig struct {
 	// collection interval.
 	RootKeyRotationThreshold string `hcl:"root_key_rotation_threshold"`
 
+	// VariablesMaxVersions is the number of previous versions of each
+	// variable that are kept.
+	VariablesMaxVersions *int `hcl:"variables_max_versions"`
+
+	// VariablesGCThreshold is how long the versions of a deleted variable
+	// are kept, so it can be restored, before they're eligible for GC.
+	VariablesGCThreshold string `hcl:"variables_gc_threshold"`
+
 	// HeartbeatGrace is the grace period beyond the TTL to account for network,
 	// processing delays and clock skew before marking a node as "down".
 	HeartbeatGrace    time.Duration
@@ -578,6 +596,10 @@ type ServerConfig struct {
 	// detects potentially bad nodes.
 	PlanRejectionTracker *PlanRejectionTracker `hcl:"plan_rejection_tracker"`
 
+	// AdmissionWebhooks configures the external webhooks which can mutate or
+	// reject jobs when they are registered or planned.
+	AdmissionWebhooks []*config.AdmissionWebhookConfig `hcl:"admission_webhook"`
+
 	// EnableEventBroker configures whether this server's state store
 	// will generate events for its event stream.
 	EnableEventBroker *bool `hcl:"enable_event_broker"`
@@ -610,6 +632,14 @@ type ServerConfig struct {
 
 	// RaftBoltConfig configures boltdb as used by raft.
 	RaftBoltConfig *RaftBoltConfig `hcl:"raft_boltdb"`
+
+	// OIDCIssuer is the URL of the issuer of workload identities, published
+	// in the OIDC discovery configuration of the agents.
+	OIDCIssuer string `hcl:"oidc_issuer"`
+
+	// Keyring configures the provider of the key encryption keys that wrap
+	// the root keys of the keyring in the on-disk keystore.
+	Keyring *config.KeyringConfig `hcl:"keyring"`
 }
 
 func (s *ServerConfig) Copy() *ServerConfig {
@@ -620,18 +650,21 @@ func (s *ServerConfig) Copy() *ServerConfig {
 	ns := *s
 	ns.RaftMultiplier = pointer.Copy(s.RaftMultiplier)
 	ns.NumSchedulers = pointer.Copy(s.NumSchedulers)
+	ns.VariablesMaxVersions = pointer.Copy(s.VariablesMaxVersions)
 	ns.EnabledSchedulers = slices.Clone(s.EnabledSchedulers)
 	ns.StartJoin = slices.Clone(s.StartJoin)
 	ns.RetryJoin = slices.Clone(s.RetryJoin)
 	ns.ServerJoin = s.ServerJoin.Copy()
 	ns.DefaultSchedulerConfig = s.DefaultSchedulerConfig.Copy()
 	ns.PlanRejectionTracker = s.PlanRejectionTracker.Copy()
+	ns.AdmissionWebhooks = config.CopySliceAdmissionWebhookConfig(s.AdmissionWebhooks)
 	ns.EnableEventBroker = pointer.Copy(s.EnableEventBroker)
 	ns.EventBufferSize = pointer.Copy(s.EventBufferSize)
 	ns.licenseAdditionalPublicKeys = slices.Clone(s.licenseAdditionalPublicKeys)
 	ns.ExtraKeysHCL = slices.Clone(s.ExtraKeysHCL)
 	ns.Search = s.Search.Copy()
 	ns.RaftBoltConfig = s.RaftBoltConfig.Copy()
+	ns.Keyring = s.Keyring.Copy()
 	return &ns
 }
 
@@ -1780,6 +1813,12 @@ func (a *ACLConfig) Merge(b *ACLConfig) *ACLConfig {
 	if b.TokenMaxExpirationTTLHCL != "" {
 		result.TokenMaxExpirationTTLHCL = b.TokenMaxExpirationTTLHCL
 	}
+	if b.TokenIdleTimeout != 0 {
+		result.TokenIdleTimeout = b.TokenIdleTimeout
+	}
+	if b.TokenIdleTimeoutHCL != "" {
+		result.TokenIdleTimeoutHCL = b.TokenIdleTimeoutHCL
+	}
 	if b.ReplicationToken != "" {
 		result.ReplicationToken = b.ReplicationToken
 	}
@@ -1824,6 +1863,9 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.JobGCThreshold != "" {
 		result.JobGCThreshold = b.JobGCThreshold
 	}
+	if b.DispatchIdempotencyTTL != "" {
+		result.DispatchIdempotencyTTL = b.DispatchIdempotencyTTL
+	}
 	if b.EvalGCThreshold != "" {
 		result.EvalGCThreshold = b.EvalGCThreshold
 	}
@@ -1848,6 +1890,12 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.RootKeyRotationThreshold != "" {
 		result.RootKeyRotationThreshold = b.RootKeyRotationThreshold
 	}
+	if b.VariablesMaxVersions != nil {
+		result.VariablesMaxVersions = pointer.Of(*b.VariablesMaxVersions)
+	}
+	if b.VariablesGCThreshold != "" {
+		result.VariablesGCThreshold = b.VariablesGCThreshold
+	}
 	if b.HeartbeatGrace != 0 {
 		result.HeartbeatGrace = b.HeartbeatGrace
 	}
@@ -1912,6 +1960,12 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		result.PlanRejectionTracker = result.PlanRejectionTracker.Merge(b.PlanRejectionTracker)
 	}
 
+	if len(s.AdmissionWebhooks) == 0 && len(b.AdmissionWebhooks) != 0 {
+		result.AdmissionWebhooks = config.CopySliceAdmissionWebhookConfig(b.AdmissionWebhooks)
+	} else if len(b.AdmissionWebhooks) != 0 {
+		result.AdmissionWebhooks = config.AdmissionWebhookSliceMerge(s.AdmissionWebhooks, b.AdmissionWebhooks)
+	}
+
 	if b.DefaultSchedulerConfig != nil {
 		c := *b.DefaultSchedulerConfig
 		result.DefaultSchedulerConfig = &c
@@ -1940,6 +1994,14 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		}
 	}
 
+	if b.OIDCIssuer != "" {
+		result.OIDCIssuer = b.OIDCIssuer
+	}
+
+	if b.Keyring != nil {
+		result.Keyring = s.Keyring.Merge(b.Keyring)
+	}
+
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
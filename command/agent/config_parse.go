@@ -69,6 +69,7 @@ func ParseConfigFile(path string) (*Config, error) {
 		{"acl.policy_ttl", &c.ACL.RoleTTL, &c.ACL.RoleTTLHCL, nil},
 		{"acl.token_min_expiration_ttl", &c.ACL.TokenMinExpirationTTL, &c.ACL.TokenMinExpirationTTLHCL, nil},
 		{"acl.token_max_expiration_ttl", &c.ACL.TokenMaxExpirationTTL, &c.ACL.TokenMaxExpirationTTLHCL, nil},
+		{"acl.token_idle_timeout", &c.ACL.TokenIdleTimeout, &c.ACL.TokenIdleTimeoutHCL, nil},
 		{"client.server_join.retry_interval", &c.Client.ServerJoin.RetryInterval, &c.Client.ServerJoin.RetryIntervalHCL, nil},
 		{"server.heartbeat_grace", &c.Server.HeartbeatGrace, &c.Server.HeartbeatGraceHCL, nil},
 		{"server.min_heartbeat_ttl", &c.Server.MinHeartbeatTTL, &c.Server.MinHeartbeatTTLHCL, nil},
@@ -147,6 +148,12 @@ func ParseConfigFile(path string) (*Config, error) {
 			fmt.Sprintf("audit.sink.%d", i), &sink.RotateDuration, &sink.RotateDurationHCL, nil})
 	}
 
+	// Add admission webhooks for time.Duration parsing
+	for i, webhook := range c.Server.AdmissionWebhooks {
+		tds = append(tds, durationConversionMap{
+			fmt.Sprintf("server.admission_webhook.%d", i), &webhook.Timeout, &webhook.TimeoutHCL, nil})
+	}
+
 	// convert strings to time.Durations
 	err = convertDurations(tds)
 	if err != nil {
@@ -244,6 +251,11 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Audit.ExtraKeysHCL, "sink")
 	}
 
+	for _, w := range c.Server.AdmissionWebhooks {
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, w.Name)
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "admission_webhook")
+	}
+
 	for _, k := range []string{"enabled_schedulers", "start_join", "retry_join", "server_join"} {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, k)
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "server")
@@ -104,10 +104,13 @@ var basicConfig = &Config{
 		EvalGCThreshold:           "12h",
 		JobGCInterval:             "3m",
 		JobGCThreshold:            "12h",
+		DispatchIdempotencyTTL:    "48h",
 		DeploymentGCThreshold:     "12h",
 		CSIVolumeClaimGCThreshold: "12h",
 		CSIPluginGCThreshold:      "12h",
 		ACLTokenGCThreshold:       "12h",
+		VariablesGCThreshold:      "96h",
+		VariablesMaxVersions:      pointer.Of(5),
 		HeartbeatGrace:            30 * time.Second,
 		HeartbeatGraceHCL:         "30s",
 		MinHeartbeatTTL:           33 * time.Second,
@@ -127,12 +130,28 @@ var basicConfig = &Config{
 		EncryptKey:                "abc",
 		EnableEventBroker:         pointer.Of(false),
 		EventBufferSize:           pointer.Of(200),
+		OIDCIssuer:                "https://nomad.example.com",
+		Keyring: &config.KeyringConfig{
+			Provider: "vault_transit",
+			Config: map[string]string{
+				"address":  "https://vault.example.com:8200",
+				"key_name": "nomad-keyring",
+			},
+		},
 		PlanRejectionTracker: &PlanRejectionTracker{
 			Enabled:       pointer.Of(true),
 			NodeThreshold: 100,
 			NodeWindow:    41 * time.Minute,
 			NodeWindowHCL: "41m",
 		},
+		AdmissionWebhooks: []*config.AdmissionWebhookConfig{{
+			Name:          "policy",
+			URL:           "https://policy.example.com/admit",
+			Timeout:       3 * time.Second,
+			TimeoutHCL:    "3s",
+			FailurePolicy: "ignore",
+			Namespaces:    []string{"prod-*"},
+		}},
 		ServerJoin: &ServerJoin{
 			RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 			RetryInterval:    time.Duration(15) * time.Second,
@@ -161,6 +180,8 @@ var basicConfig = &Config{
 		TokenMinExpirationTTL:    1 * time.Hour,
 		TokenMaxExpirationTTLHCL: "100h",
 		TokenMaxExpirationTTL:    100 * time.Hour,
+		TokenIdleTimeoutHCL:      "2160h",
+		TokenIdleTimeout:         2160 * time.Hour,
 		ReplicationToken:         "foobar",
 	},
 	Audit: &config.AuditConfig{
@@ -364,6 +364,7 @@ func TestConfig_Merge(t *testing.T) {
 			RoleTTL:               20 * time.Second,
 			TokenMinExpirationTTL: 20 * time.Second,
 			TokenMaxExpirationTTL: 20 * time.Second,
+			TokenIdleTimeout:      20 * time.Second,
 			ReplicationToken:      "foobar",
 		},
 		Ports: &Ports{
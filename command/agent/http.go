@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"bufio"
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
@@ -9,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/armon/go-metrics"
 	assetfs "github.com/elazarl/go-bindata-assetfs"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-connlimit"
@@ -26,8 +29,10 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/noxssrw"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -184,6 +189,9 @@ func NewHTTPServers(agent *Agent, config *Config) ([]*HTTPServer, error) {
 		}
 
 		srv.registerHandlers(config.EnableDebug)
+		if agent.taskAPI != nil {
+			agent.taskAPI.setServer(srv)
+		}
 
 		httpServer := http.Server{
 			Addr:     srv.Addr,
@@ -351,6 +359,8 @@ func (s HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/jobs", s.wrap(s.JobsRequest))
 	s.mux.HandleFunc("/v1/jobs/parse", s.wrap(s.JobsParseRequest))
 	s.mux.HandleFunc("/v1/job/", s.wrap(s.JobSpecificRequest))
+	s.mux.HandleFunc("/v1/job-templates", s.wrap(s.JobTemplatesRequest))
+	s.mux.HandleFunc("/v1/job-template/", s.wrap(s.JobTemplateSpecificRequest))
 
 	s.mux.HandleFunc("/v1/nodes", s.wrap(s.NodesRequest))
 	s.mux.HandleFunc("/v1/node/", s.wrap(s.NodeSpecificRequest))
@@ -448,8 +458,14 @@ func (s HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/namespace/", s.wrap(s.NamespaceSpecificRequest))
 
 	s.mux.Handle("/v1/vars", wrapCORS(s.wrap(s.VariablesListRequest)))
+	s.mux.Handle("/v1/vars/history/", wrapCORS(s.wrap(s.VariableHistoryRequest)))
 	s.mux.Handle("/v1/var/", wrapCORSWithAllowedMethods(s.wrap(s.VariableSpecificRequest), "HEAD", "GET", "PUT", "DELETE"))
 
+	// JWKS and OIDC discovery endpoints for verifying workload identities,
+	// served at well-known paths outside of the versioned API
+	s.mux.Handle("/.well-known/jwks.json", wrapCORS(s.wrap(s.JWKSRequest)))
+	s.mux.Handle("/.well-known/openid-configuration", wrapCORS(s.wrap(s.OIDCDiscoveryRequest)))
+
 	uiConfigEnabled := s.agent.config.UI != nil && s.agent.config.UI.Enabled
 
 	if uiEnabled && uiConfigEnabled {
@@ -688,6 +704,251 @@ func (s *HTTPServer) wrapNonJSON(handler func(resp http.ResponseWriter, req *htt
 	return f
 }
 
+// auditRedactedParams are the substrings of the names of the query parameters
+// whose values are redacted from audit events
+var auditRedactedParams = []string{"token", "secret", "password"}
+
+// auditHandler wraps the passed handlerFn
+func (s *HTTPServer) auditHandler(h handlerFn) handlerFn {
+	return func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		var obj interface{}
+		err := s.audit(resp, req, func(resp http.ResponseWriter) error {
+			var err error
+			obj, err = h(resp, req)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+}
+
+// auditNonJSONHandler wraps the passed handlerByteFn
+func (s *HTTPServer) auditNonJSONHandler(h handlerByteFn) handlerByteFn {
+	return func(resp http.ResponseWriter, req *http.Request) ([]byte, error) {
+		var obj []byte
+		err := s.audit(resp, req, func(resp http.ResponseWriter) error {
+			var err error
+			obj, err = h(resp, req)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+}
+
+// auditHTTPHandler wraps the passed http.Handler
+func (s *HTTPServer) auditHTTPHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		handled := false
+		err := s.audit(resp, req, func(resp http.ResponseWriter) error {
+			handled = true
+			h.ServeHTTP(resp, req)
+			return nil
+		})
+
+		// The response is already written once the request is handled
+		if err != nil && !handled {
+			code, errMsg := errCodeFromHandler(err)
+			resp.WriteHeader(code)
+			resp.Write([]byte(errMsg))
+		}
+	})
+}
+
+// audit emits the audit events of the request before and after calling
+// handle, returning the error of handle. If an audit event can't be written
+// to a sink with enforced delivery, the request fails: handle isn't called if
+// the request can't be audited before it is handled, and its result is
+// replaced by an error if it can't be audited after.
+func (s *HTTPServer) audit(resp http.ResponseWriter, req *http.Request, handle func(http.ResponseWriter) error) error {
+	auditor := s.agent.auditor
+	if auditor == nil || !auditor.Enabled() {
+		return handle(resp)
+	}
+
+	ev := s.newAuditEvent(req)
+	if err := auditor.Event(req.Context(), auditEventType, ev); err != nil {
+		s.logger.Error("failed to audit request", "method", req.Method, "path", req.URL.Path, "error", err)
+		return CodedError(http.StatusInternalServerError, "failed to audit request")
+	}
+
+	rw := &auditResponseWriter{ResponseWriter: resp}
+	err := handle(rw)
+
+	// The complete event has the same ID and timestamp as the received event
+	complete := *ev
+	complete.Stage = auditStageComplete
+	complete.Response = &auditResponse{StatusCode: rw.status}
+	if err != nil {
+		complete.Response.StatusCode, complete.Response.Error = errCodeFromHandler(err)
+	} else if rw.status == 0 {
+		complete.Response.StatusCode = http.StatusOK
+	}
+
+	if auditErr := auditor.Event(req.Context(), auditEventType, &complete); auditErr != nil {
+		s.logger.Error("failed to audit request", "method", req.Method, "path", req.URL.Path, "error", auditErr)
+		if err == nil {
+			return CodedError(http.StatusInternalServerError, "failed to audit request")
+		}
+	}
+	return err
+}
+
+// newAuditEvent returns the audit event of the received stage of the request
+func (s *HTTPServer) newAuditEvent(req *http.Request) *auditEvent {
+	var namespace string
+	parseNamespace(req, &namespace)
+
+	return &auditEvent{
+		ID:        uuid.Generate(),
+		Stage:     auditStageReceived,
+		Type:      auditEventType,
+		Timestamp: time.Now(),
+		Version:   auditEventVersion,
+		Auth:      s.auditAuth(req),
+		Request: &auditRequest{
+			ID:        uuid.Generate(),
+			Operation: req.Method,
+			Endpoint:  auditEndpoint(req.URL),
+			Namespace: &auditNamespace{ID: namespace},
+			RequestMeta: &auditRequestMeta{
+				RemoteAddress: req.RemoteAddr,
+				UserAgent:     req.UserAgent(),
+			},
+			NodeMeta: &auditNodeMeta{IP: s.Addr},
+			path:     req.URL.Path,
+		},
+	}
+}
+
+// auditAuth returns the identity the request is made with. It returns nil if
+// ACLs are disabled or if the ACL token of the request can't be resolved,
+// which the response of the request records.
+func (s *HTTPServer) auditAuth(req *http.Request) *auditAuth {
+	if !s.agent.config.ACL.Enabled {
+		return nil
+	}
+
+	var secret string
+	s.parseToken(req, &secret)
+
+	// Workload identities are JWTs rather than secret IDs. Servers verify
+	// them with the keyring, while clients can only record the claims as
+	// presented, which is marked in the event.
+	if secret != "" && !helper.IsUUID(secret) {
+		var claims *structs.IdentityClaims
+		var verified bool
+		if srv := s.agent.Server(); srv != nil {
+			claims, _ = srv.VerifyClaim(secret)
+			verified = claims != nil
+		}
+		if claims == nil {
+			claims = &structs.IdentityClaims{}
+			if _, _, err := jwt.NewParser().ParseUnverified(secret, claims); err != nil {
+				return nil
+			}
+		}
+		return &auditAuth{
+			WorkloadIdentity: &auditWorkloadIdentity{
+				Namespace:    claims.Namespace,
+				JobID:        claims.JobID,
+				AllocationID: claims.AllocationID,
+				TaskName:     claims.TaskName,
+				Verified:     verified,
+			},
+		}
+	}
+
+	var token *structs.ACLToken
+	var err error
+	if srv := s.agent.Server(); srv != nil {
+		token, err = srv.ResolveSecretToken(secret)
+	} else {
+		token, err = s.agent.Client().ResolveSecretToken(secret)
+	}
+	if err != nil || token == nil {
+		return nil
+	}
+
+	auth := &auditAuth{
+		AccessorID: token.AccessorID,
+		Name:       token.Name,
+		Policies:   token.Policies,
+		Global:     token.Global,
+		CreateTime: &token.CreateTime,
+	}
+	for _, role := range token.Roles {
+		auth.Roles = append(auth.Roles, role.Name)
+	}
+	return auth
+}
+
+// auditEndpoint returns the endpoint of the request with its query
+// parameters, redacting the values of the parameters that may be secrets.
+func auditEndpoint(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+
+	query := u.Query()
+	for key, values := range query {
+		lower := strings.ToLower(key)
+		for _, param := range auditRedactedParams {
+			if strings.Contains(lower, param) {
+				for i := range values {
+					values[i] = "redacted"
+				}
+				break
+			}
+		}
+	}
+	return u.Path + "?" + query.Encode()
+}
+
+// auditResponseWriter records the status code of the response of an audited
+// request.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher for the streaming endpoints.
+func (w *auditResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker for the websocket endpoints.
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
 // isAPIClientError returns true if the passed http code represents a client error
 func isAPIClientError(code int) bool {
 	return 400 <= code && code <= 499
@@ -868,11 +1129,21 @@ func (s *HTTPServer) parseToken(req *http.Request, token *string) {
 	}
 }
 
+// parseSourceIP is used to parse the IP address of the HTTP client, which
+// servers record as the last source of the ACL token of the request. It is
+// left empty for requests made over a unix socket.
+func parseSourceIP(req *http.Request, ip *string) {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		*ip = host
+	}
+}
+
 // parse is a convenience method for endpoints that need to parse multiple flags
 // It sets r to the region and b to the QueryOptions in req
 func (s *HTTPServer) parse(resp http.ResponseWriter, req *http.Request, r *string, b *structs.QueryOptions) bool {
 	s.parseRegion(req, r)
 	s.parseToken(req, &b.AuthToken)
+	parseSourceIP(req, &b.SourceIP)
 	parseConsistency(req, b)
 	parsePrefix(req, b)
 	parseNamespace(req, &b.Namespace)
@@ -915,6 +1186,7 @@ func parseReverse(req *http.Request, b *structs.QueryOptions) {
 func (s *HTTPServer) parseWriteRequest(req *http.Request, w *structs.WriteRequest) {
 	parseNamespace(req, &w.Namespace)
 	s.parseToken(req, &w.AuthToken)
+	parseSourceIP(req, &w.SourceIP)
 	s.parseRegion(req, &w.Region)
 	parseIdempotencyToken(req, &w.IdempotencyToken)
 }
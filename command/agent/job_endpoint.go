@@ -89,6 +89,12 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/revert"):
 		jobName := strings.TrimSuffix(path, "/revert")
 		return s.jobRevert(resp, req, jobName)
+	case strings.HasSuffix(path, "/submission"):
+		jobName := strings.TrimSuffix(path, "/submission")
+		return s.jobSubmission(resp, req, jobName)
+	case strings.HasSuffix(path, "/diff"):
+		jobName := strings.TrimSuffix(path, "/diff")
+		return s.jobDiff(resp, req, jobName)
 	case strings.HasSuffix(path, "/deployments"):
 		jobName := strings.TrimSuffix(path, "/deployments")
 		return s.jobDeployments(resp, req, jobName)
@@ -426,6 +432,7 @@ func (s *HTTPServer) jobUpdate(resp http.ResponseWriter, req *http.Request,
 		PolicyOverride: args.PolicyOverride,
 		PreserveCounts: args.PreserveCounts,
 		EvalPriority:   args.EvalPriority,
+		Submission:     apiJobSubmissionToStructs(args.Submission),
 		WriteRequest:   *writeReq,
 	}
 
@@ -612,6 +619,96 @@ func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobSubmission(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	versionStr := req.URL.Query().Get("version")
+	if versionStr == "" {
+		return nil, CodedError(400, "missing version parameter")
+	}
+	version, err := strconv.ParseUint(versionStr, 10, 64)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("Failed to parse value of %q (%v) as a uint64: %v", "version", versionStr, err))
+	}
+
+	args := structs.JobSubmissionRequest{
+		JobID:   jobName,
+		Version: version,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobSubmissionResponse
+	if err := s.agent.RPC(structs.JobGetSubmissionRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Submission == nil {
+		return nil, CodedError(404, "job submission not found")
+	}
+	return out.Submission, nil
+}
+
+func (s *HTTPServer) jobDiff(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	args := structs.JobDiffRequest{
+		JobID: jobName,
+	}
+	for param, version := range map[string]**uint64{"from": &args.FromVersion, "to": &args.ToVersion} {
+		versionStr := req.URL.Query().Get(param)
+		if versionStr == "" {
+			continue
+		}
+		v, err := strconv.ParseUint(versionStr, 10, 64)
+		if err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Failed to parse value of %q (%v) as a uint64: %v", param, versionStr, err))
+		}
+		*version = &v
+	}
+
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	switch req.Method {
+	case "GET":
+	case "PUT", "POST":
+		// Diff to the job of the request, which is placed like a job being
+		// planned
+		var diffRequest api.JobDiffRequest
+		if err := decodeBody(req, &diffRequest); err != nil {
+			return nil, CodedError(400, err.Error())
+		}
+		if diffRequest.Job == nil {
+			return nil, CodedError(400, "Job must be specified")
+		}
+		if diffRequest.Job.ID == nil || *diffRequest.Job.ID != jobName {
+			return nil, CodedError(400, "Job ID does not match")
+		}
+		sJob, writeReq := s.apiJobAndRequestToStructs(diffRequest.Job, req, api.WriteRequest{})
+		args.Job = sJob
+		args.Region = writeReq.Region
+		args.Namespace = writeReq.Namespace
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var out structs.JobDiffResponse
+	if err := s.agent.RPC(structs.JobDiffRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out, nil
+}
+
 func (s *HTTPServer) jobRevert(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 
@@ -905,6 +1002,18 @@ func namespaceForJob(jobNamespace *string, queryNamespace, apiNamespace string)
 	return structs.DefaultNamespace
 }
 
+func apiJobSubmissionToStructs(sub *api.JobSubmission) *structs.JobSubmission {
+	if sub == nil {
+		return nil
+	}
+	return &structs.JobSubmission{
+		Source:    sub.Source,
+		Format:    sub.Format,
+		Variables: sub.Variables,
+		Includes:  sub.Includes,
+	}
+}
+
 func ApiJobToStructJob(job *api.Job) *structs.Job {
 	job.Canonicalize()
 
@@ -969,6 +1078,14 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if job.Preemption != nil {
+		j.Preemption = &structs.PreemptionPolicy{
+			NeverPreempt:       job.Preemption.NeverPreempt,
+			MaxPerHour:         job.Preemption.MaxPerHour,
+			ProtectDeployments: job.Preemption.ProtectDeployments,
+		}
+	}
+
 	if job.Multiregion != nil {
 		j.Multiregion = &structs.Multiregion{}
 		j.Multiregion.Strategy = &structs.MultiregionStrategy{
@@ -1027,6 +1144,10 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		tg.MaxClientDisconnect = taskGroup.MaxClientDisconnect
 	}
 
+	if taskGroup.Gang != nil {
+		tg.Gang = *taskGroup.Gang
+	}
+
 	if taskGroup.ReschedulePolicy != nil {
 		tg.ReschedulePolicy = &structs.ReschedulePolicy{
 			Attempts:      *taskGroup.ReschedulePolicy.Attempts,
@@ -1241,6 +1362,50 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 		}
 	}
 
+	if len(apiTask.Identities) > 0 {
+		structsTask.Identities = []*structs.WorkloadIdentity{}
+		for _, wi := range apiTask.Identities {
+			structsTask.Identities = append(structsTask.Identities,
+				&structs.WorkloadIdentity{
+					Name:         wi.Name,
+					Audience:     wi.Audience,
+					TTL:          *wi.TTL,
+					Env:          *wi.Env,
+					File:         *wi.File,
+					ChangeMode:   *wi.ChangeMode,
+					ChangeSignal: *wi.ChangeSignal,
+					Claims:       wi.Claims,
+				})
+		}
+	}
+
+	if len(apiTask.EnvFromVariables) > 0 {
+		structsTask.EnvFromVariables = []*structs.EnvFromVariable{}
+		for _, e := range apiTask.EnvFromVariables {
+			structsTask.EnvFromVariables = append(structsTask.EnvFromVariables,
+				&structs.EnvFromVariable{
+					Path:       *e.Path,
+					Prefix:     *e.Prefix,
+					ChangeMode: *e.ChangeMode,
+				})
+		}
+	}
+
+	if len(apiTask.FileFromVariables) > 0 {
+		structsTask.FileFromVariables = []*structs.FileFromVariable{}
+		for _, f := range apiTask.FileFromVariables {
+			structsTask.FileFromVariables = append(structsTask.FileFromVariables,
+				&structs.FileFromVariable{
+					Path:         *f.Path,
+					Item:         *f.Item,
+					Destination:  *f.Destination,
+					Perms:        *f.Perms,
+					ChangeMode:   *f.ChangeMode,
+					ChangeSignal: *f.ChangeSignal,
+				})
+		}
+	}
+
 	if apiTask.DispatchPayload != nil {
 		structsTask.DispatchPayload = &structs.DispatchPayloadConfig{
 			File: apiTask.DispatchPayload.File,
@@ -1334,6 +1499,12 @@ func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
 		}
 	}
 
+	if in.NUMA != nil {
+		out.NUMA = &structs.NUMAResource{
+			Affinity: in.NUMA.Affinity,
+		}
+	}
+
 	return out
 }
 
@@ -1817,6 +1817,119 @@ func TestHTTP_JobDispatch(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobSubmission(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the job along with its source
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job: job,
+			Submission: &structs.JobSubmission{
+				Source: "{}",
+				Format: structs.JobSubmissionFormatJSON,
+			},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(t, s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/job/"+job.ID+"/submission?version=0", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		sub := obj.(*structs.JobSubmission)
+		require.Equal(t, "{}", sub.Source)
+		require.Equal(t, structs.JobSubmissionFormatJSON, sub.Format)
+		require.Equal(t, job.ID, sub.JobID)
+		require.NotZero(t, respW.Header().Get("X-Nomad-Index"))
+
+		// Unknown versions are not found
+		req, err = http.NewRequest("GET", "/v1/job/"+job.ID+"/submission?version=1", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Equal(t, 404, err.(HTTPCodedError).Code())
+
+		// The version is required
+		req, err = http.NewRequest("GET", "/v1/job/"+job.ID+"/submission", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Equal(t, 400, err.(HTTPCodedError).Code())
+	})
+}
+
+func TestHTTP_JobDiff(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Register two versions of the job
+		job := MockJob()
+		args := api.JobRegisterRequest{
+			Job: job,
+			WriteRequest: api.WriteRequest{
+				Region:    "global",
+				Namespace: api.DefaultNamespace,
+			},
+		}
+		for _, priority := range []int{50, 60} {
+			job.Priority = pointer.Of(priority)
+			req, err := http.NewRequest("PUT", "/v1/jobs", encodeReq(args))
+			require.NoError(t, err)
+			_, err = s.Server.JobsRequest(httptest.NewRecorder(), req)
+			require.NoError(t, err)
+		}
+
+		// Diff the versions
+		req, err := http.NewRequest("GET", "/v1/job/"+*job.ID+"/diff?from=0&to=1", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotZero(t, respW.Header().Get("X-Nomad-Index"))
+
+		out := obj.(structs.JobDiffResponse)
+		require.Equal(t, pointer.Of(uint64(0)), out.FromVersion)
+		require.Equal(t, pointer.Of(uint64(1)), out.ToVersion)
+		require.Len(t, out.Diff.Fields, 1)
+		require.Equal(t, "Priority", out.Diff.Fields[0].Name)
+
+		// Diff the latest version to a job which isn't registered
+		job.Priority = pointer.Of(70)
+		req, err = http.NewRequest("POST", "/v1/job/"+*job.ID+"/diff", encodeReq(api.JobDiffRequest{Job: job}))
+		require.NoError(t, err)
+		obj, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+
+		out = obj.(structs.JobDiffResponse)
+		require.Equal(t, pointer.Of(uint64(1)), out.FromVersion)
+		require.Nil(t, out.ToVersion)
+		require.Len(t, out.Diff.Fields, 1)
+		require.Equal(t, "60", out.Diff.Fields[0].Old)
+		require.Equal(t, "70", out.Diff.Fields[0].New)
+
+		// The job must match the path
+		req, err = http.NewRequest("POST", "/v1/job/other/diff", encodeReq(api.JobDiffRequest{Job: job}))
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Equal(t, 400, err.(HTTPCodedError).Code())
+
+		// The versions must be valid
+		req, err = http.NewRequest("GET", "/v1/job/"+*job.ID+"/diff?from=latest", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Equal(t, 400, err.(HTTPCodedError).Code())
+	})
+}
+
 func TestHTTP_JobRevert(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
@@ -2723,6 +2836,35 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							ChangeMode:   pointer.Of("c"),
 							ChangeSignal: pointer.Of("sighup"),
 						},
+						Identities: []*api.WorkloadIdentity{
+							{
+								Name:         "vault",
+								Audience:     []string{"vault.io"},
+								TTL:          pointer.Of(time.Hour),
+								Env:          pointer.Of(true),
+								File:         pointer.Of(false),
+								ChangeMode:   pointer.Of("signal"),
+								ChangeSignal: pointer.Of("SIGHUP"),
+								Claims:       map[string]string{"dc": "${node.datacenter}"},
+							},
+						},
+						EnvFromVariables: []*api.EnvFromVariable{
+							{
+								Path:       pointer.Of("nomad/jobs/db"),
+								Prefix:     pointer.Of("DB_"),
+								ChangeMode: pointer.Of("noop"),
+							},
+						},
+						FileFromVariables: []*api.FileFromVariable{
+							{
+								Path:         pointer.Of("nomad/jobs/db"),
+								Item:         pointer.Of("cert"),
+								Destination:  pointer.Of("db/cert.pem"),
+								Perms:        pointer.Of("0600"),
+								ChangeMode:   pointer.Of("signal"),
+								ChangeSignal: pointer.Of("SIGHUP"),
+							},
+						},
 						Templates: []*api.Template{
 							{
 								SourcePath:   pointer.Of("source"),
@@ -3136,6 +3278,35 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							ChangeMode:   "c",
 							ChangeSignal: "sighup",
 						},
+						Identities: []*structs.WorkloadIdentity{
+							{
+								Name:         "vault",
+								Audience:     []string{"vault.io"},
+								TTL:          time.Hour,
+								Env:          true,
+								File:         false,
+								ChangeMode:   "signal",
+								ChangeSignal: "SIGHUP",
+								Claims:       map[string]string{"dc": "${node.datacenter}"},
+							},
+						},
+						EnvFromVariables: []*structs.EnvFromVariable{
+							{
+								Path:       "nomad/jobs/db",
+								Prefix:     "DB_",
+								ChangeMode: "noop",
+							},
+						},
+						FileFromVariables: []*structs.FileFromVariable{
+							{
+								Path:         "nomad/jobs/db",
+								Item:         "cert",
+								Destination:  "db/cert.pem",
+								Perms:        "0600",
+								ChangeMode:   "signal",
+								ChangeSignal: "SIGHUP",
+							},
+						},
 						Templates: []*structs.Template{
 							{
 								SourcePath:   "source",
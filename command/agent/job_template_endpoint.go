@@ -0,0 +1,201 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/jobspec2"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// JobTemplatesRequest lists the job templates using the
+// structs.JobTemplateListRPCMethod RPC endpoint and is callable via the
+// /v1/job-templates HTTP API.
+func (s *HTTPServer) JobTemplatesRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.JobTemplateListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobTemplateListResponse
+	if err := s.agent.RPC(structs.JobTemplateListRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+
+	if out.Templates == nil {
+		out.Templates = make([]*structs.JobTemplateListStub, 0)
+	}
+	return out.Templates, nil
+}
+
+// JobTemplateSpecificRequest is callable via the /v1/job-template/ HTTP API
+// and handles the reads, registrations, deletions and runs of job templates.
+func (s *HTTPServer) JobTemplateSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/job-template/")
+	name, action, _ := strings.Cut(path, "/")
+	if name == "" {
+		return nil, CodedError(http.StatusBadRequest, "missing job template name")
+	}
+
+	switch action {
+	case "":
+		switch req.Method {
+		case http.MethodGet:
+			return s.jobTemplateQuery(resp, req, name)
+		case http.MethodPut, http.MethodPost:
+			return s.jobTemplateUpsert(resp, req, name)
+		case http.MethodDelete:
+			return s.jobTemplateDelete(resp, req, name)
+		default:
+			return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+		}
+	case "run":
+		if req.Method != http.MethodPut && req.Method != http.MethodPost {
+			return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+		}
+		return s.jobTemplateRun(resp, req, name)
+	default:
+		return nil, CodedError(http.StatusNotFound, "invalid URI")
+	}
+}
+
+func (s *HTTPServer) jobTemplateQuery(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
+	args := structs.JobTemplateGetRequest{Name: name}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobTemplateGetResponse
+	if err := s.agent.RPC(structs.JobTemplateGetRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+
+	if out.Template == nil {
+		return nil, CodedError(http.StatusNotFound, "job template not found")
+	}
+	return out.Template, nil
+}
+
+func (s *HTTPServer) jobTemplateUpsert(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
+	var tmpl structs.JobTemplate
+	if err := decodeBody(req, &tmpl); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+	if tmpl.Name != "" && tmpl.Name != name {
+		return nil, CodedError(http.StatusBadRequest, "job template name does not match request path")
+	}
+	tmpl.Name = name
+
+	args := structs.JobTemplateUpsertRequest{Template: &tmpl}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.JobTemplateUpsertResponse
+	if err := s.agent.RPC(structs.JobTemplateUpsertRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out.Template, nil
+}
+
+func (s *HTTPServer) jobTemplateDelete(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
+	args := structs.JobTemplateDeleteRequest{Name: name}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.JobTemplateDeleteResponse
+	if err := s.agent.RPC(structs.JobTemplateDeleteRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) jobTemplateRun(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
+	var args structs.JobTemplateRunRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+	args.Name = name
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.JobTemplateRunResponse
+	if err := s.agent.RPC(structs.JobTemplateRunRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+// jobTemplateRenderer renders job templates with the HCL2 jobspec parser. The
+// templates can't access the file system or the environment of the server.
+type jobTemplateRenderer struct {
+	// region is the region of the jobs which don't set one
+	region string
+}
+
+func (r *jobTemplateRenderer) Variables(source string) ([]*structs.JobTemplateVariable, error) {
+	vars, err := jobspec2.ParseVariables(&jobspec2.ParseConfig{
+		Path: "template.hcl",
+		Body: []byte(source),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*structs.JobTemplateVariable, 0, len(vars))
+	for _, v := range vars {
+		out = append(out, &structs.JobTemplateVariable{
+			Name:        v.Name,
+			Description: v.Description,
+			Required:    len(v.Values) == 0,
+			Sensitive:   v.Sensitive,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (r *jobTemplateRenderer) Render(tmpl *structs.JobTemplate, vars map[string]string) (*structs.Job, *structs.JobSubmission, error) {
+	argVars := make([]string, 0, len(vars))
+	for k, v := range vars {
+		argVars = append(argVars, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(argVars)
+
+	job, submitted, err := jobspec2.ParseWithVariables(&jobspec2.ParseConfig{
+		Path:    "template.hcl",
+		Body:    []byte(tmpl.Source),
+		ArgVars: argVars,
+		AllowFS: false,
+		Strict:  true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.Namespace == nil {
+		job.Namespace = &tmpl.Namespace
+	}
+	if job.Region == nil {
+		job.Region = &r.region
+	}
+
+	sub := &structs.JobSubmission{
+		Source:    tmpl.Source,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Variables: submitted,
+	}
+	return ApiJobToStructJob(job), sub, nil
+}
@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+const testJobTemplateSource = `
+variable "image" {
+  type        = string
+  description = "The image of the web task"
+}
+
+variable "count" {
+  default = 1
+}
+
+variable "password" {
+  default   = "secret"
+  sensitive = true
+}
+
+job "web" {
+  datacenters = ["dc1"]
+
+  group "web" {
+    count = var.count
+
+    task "web" {
+      driver = "docker"
+
+      config {
+        image = var.image
+      }
+
+      env {
+        PASSWORD = var.password
+      }
+    }
+  }
+}
+`
+
+func TestHTTP_JobTemplate_CRUD(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Register the template
+		tmpl := &structs.JobTemplate{
+			Description: "web service",
+			Source:      testJobTemplateSource,
+		}
+		req, err := http.NewRequest(http.MethodPut, "/v1/job-template/web", encodeReq(tmpl))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobTemplateSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.Header().Get("X-Nomad-Index"))
+
+		out := obj.(*structs.JobTemplate)
+		require.Equal(t, "web", out.Name)
+		require.Equal(t, structs.DefaultNamespace, out.Namespace)
+		require.Equal(t, []*structs.JobTemplateVariable{
+			{Name: "count"},
+			{Name: "image", Description: "The image of the web task", Required: true},
+			{Name: "password", Sensitive: true},
+		}, out.Variables)
+
+		// Templates which don't parse are rejected
+		tmpl.Source = `job "web" {`
+		req, err = http.NewRequest(http.MethodPut, "/v1/job-template/broken", encodeReq(tmpl))
+		require.NoError(t, err)
+		_, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		require.ErrorContains(t, err, "invalid job template source")
+
+		// List the templates
+		req, err = http.NewRequest(http.MethodGet, "/v1/job-templates", nil)
+		require.NoError(t, err)
+		obj, err = s.Server.JobTemplatesRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		stubs := obj.([]*structs.JobTemplateListStub)
+		require.Len(t, stubs, 1)
+		require.Equal(t, "web service", stubs[0].Description)
+
+		// Run the template
+		runReq := &structs.JobTemplateRunRequest{
+			Variables: map[string]string{"image": "nginx:1.23", "count": "3", "password": "hunter2"},
+		}
+		req, err = http.NewRequest(http.MethodPost, "/v1/job-template/web/run", encodeReq(runReq))
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.JobTemplateSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		runResp := obj.(structs.JobTemplateRunResponse)
+		require.Equal(t, "web", runResp.JobID)
+		require.NotEmpty(t, runResp.EvalID)
+
+		job, err := s.Agent.server.State().JobByID(nil, structs.DefaultNamespace, "web")
+		require.NoError(t, err)
+		require.NotNil(t, job)
+		require.Equal(t, 3, job.TaskGroups[0].Count)
+		require.Equal(t, "nginx:1.23", job.TaskGroups[0].Tasks[0].Config["image"])
+		require.Equal(t, "hunter2", job.TaskGroups[0].Tasks[0].Env["PASSWORD"])
+
+		// The values of sensitive variables are not stored in the submission
+		sub, err := s.Agent.server.State().JobSubmission(nil, job.Namespace, job.ID, job.Version)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"image":    `"nginx:1.23"`,
+			"count":    "3",
+			"password": structs.JobSubmissionSensitiveValue,
+		}, sub.Variables)
+
+		// Undeclared variables are rejected
+		runReq.Variables["port"] = "8080"
+		req, err = http.NewRequest(http.MethodPost, "/v1/job-template/web/run", encodeReq(runReq))
+		require.NoError(t, err)
+		_, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		require.ErrorContains(t, err, "Undefined -var variable")
+
+		// Delete the template
+		req, err = http.NewRequest(http.MethodDelete, "/v1/job-template/web", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/job-template/web", nil)
+		require.NoError(t, err)
+		_, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(t, err, "job template not found")
+	})
+}
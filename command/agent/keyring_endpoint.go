@@ -1,9 +1,13 @@
 package agent
 
 import (
+	"crypto/x509"
+	"fmt"
 	"net/http"
 	"strings"
 
+	jose "gopkg.in/square/go-jose.v2"
+
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -92,3 +96,57 @@ func (s *HTTPServer) keyringDeleteRequest(resp http.ResponseWriter, req *http.Re
 	setIndex(resp, out.Index)
 	return out, nil
 }
+
+// JWKSRequest is used to publish the public keys of the keyring as a JSON
+// Web Key Set, so third parties can verify workload identities.
+func (s *HTTPServer) JWKSRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringListPublicResponse
+	if err := s.agent.RPC("Keyring.ListPublic", &args, &out); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &out.QueryMeta)
+
+	jwks := jose.JSONWebKeySet{Keys: make([]jose.JSONWebKey, 0, len(out.PublicKeys))}
+	for _, pubKey := range out.PublicKeys {
+		key, err := x509.ParsePKIXPublicKey(pubKey.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key %q: %v", pubKey.KeyID, err)
+		}
+		jwks.Keys = append(jwks.Keys, jose.JSONWebKey{
+			Key:       key,
+			KeyID:     pubKey.KeyID,
+			Algorithm: pubKey.Algorithm,
+			Use:       pubKey.Use,
+		})
+	}
+	return jwks, nil
+}
+
+// OIDCDiscoveryRequest is used to publish the OIDC discovery configuration
+// of workload identities. It's only available when the servers are
+// configured with an oidc_issuer.
+func (s *HTTPServer) OIDCDiscoveryRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringGetConfigResponse
+	if err := s.agent.RPC("Keyring.GetConfig", &args, &out); err != nil {
+		return nil, err
+	}
+	return out.OIDCDiscovery, nil
+}
@@ -6,15 +6,18 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
 )
 
 func TestHTTP_Keyring_CRUD(t *testing.T) {
 	ci.Parallel(t)
 
 	httpTest(t, nil, func(s *TestAgent) {
+		testutil.WaitForKeyring(t, s.Agent.RPC, s.Config.Region)
 
 		respW := httptest.NewRecorder()
 
@@ -74,3 +77,44 @@ func TestHTTP_Keyring_CRUD(t *testing.T) {
 		require.Len(t, listResp, 1)
 	})
 }
+
+func TestHTTP_Keyring_JWKS(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		testutil.WaitForKeyring(t, s.Agent.RPC, s.Config.Region)
+
+		respW := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+		require.NoError(t, err)
+		obj, err := s.Server.JWKSRequest(respW, req)
+		require.NoError(t, err)
+		require.NotZero(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		jwks := obj.(jose.JSONWebKeySet)
+		require.Len(t, jwks.Keys, 1)
+		require.Equal(t, structs.PubKeyAlgRS256, jwks.Keys[0].Algorithm)
+		require.Equal(t, structs.PubKeyUseSig, jwks.Keys[0].Use)
+		require.True(t, jwks.Keys[0].IsPublic())
+		require.True(t, jwks.Keys[0].Valid())
+	})
+}
+
+func TestHTTP_Keyring_OIDCDiscovery(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, func(c *Config) {
+		c.Server.OIDCIssuer = "https://nomad.example.com"
+	}, func(s *TestAgent) {
+		respW := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)
+		require.NoError(t, err)
+		obj, err := s.Server.OIDCDiscoveryRequest(respW, req)
+		require.NoError(t, err)
+
+		config := obj.(*structs.OIDCDiscoveryConfig)
+		require.Equal(t, "https://nomad.example.com", config.Issuer)
+		require.Equal(t, "https://nomad.example.com/.well-known/jwks.json", config.JWKS)
+		require.Contains(t, config.IDTokenAlgs, structs.PubKeyAlgRS256)
+	})
+}
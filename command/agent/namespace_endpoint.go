@@ -34,6 +34,12 @@ func (s *HTTPServer) NamespaceSpecificRequest(resp http.ResponseWriter, req *htt
 	if len(name) == 0 {
 		return nil, CodedError(400, "Missing Namespace Name")
 	}
+	if strings.HasSuffix(name, "/usage") {
+		if req.Method != "GET" {
+			return nil, CodedError(405, ErrInvalidMethod)
+		}
+		return s.namespaceUsage(resp, req, strings.TrimSuffix(name, "/usage"))
+	}
 	switch req.Method {
 	case "GET":
 		return s.namespaceQuery(resp, req, name)
@@ -75,6 +81,24 @@ func (s *HTTPServer) namespaceQuery(resp http.ResponseWriter, req *http.Request,
 	return out.Namespace, nil
 }
 
+func (s *HTTPServer) namespaceUsage(resp http.ResponseWriter, req *http.Request,
+	namespaceName string) (interface{}, error) {
+	args := structs.NamespaceSpecificRequest{
+		Name: namespaceName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.NamespaceUsageResponse
+	if err := s.agent.RPC("Namespace.GetNamespaceUsage", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out.Usage, nil
+}
+
 func (s *HTTPServer) namespaceUpdate(resp http.ResponseWriter, req *http.Request,
 	namespaceName string) (interface{}, error) {
 	// Parse the namespace
@@ -0,0 +1,131 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	log "github.com/hashicorp/go-hclog"
+)
+
+// taskAPIEndpoints are the prefixes of the HTTP API endpoints that tasks can
+// reach over the Task API socket. Agent, client, operator, and ACL endpoints
+// are excluded, as they are meant for operators rather than workloads.
+var taskAPIEndpoints = []string{
+	"/v1/jobs",
+	"/v1/job/",
+	"/v1/allocations",
+	"/v1/allocation/",
+	"/v1/evaluations",
+	"/v1/evaluation/",
+	"/v1/deployments",
+	"/v1/deployment/",
+	"/v1/services",
+	"/v1/service/",
+	"/v1/vars",
+	"/v1/vars/history/",
+	"/v1/var/",
+	"/v1/namespaces",
+	"/v1/namespace/",
+	"/v1/regions",
+	"/v1/status/leader",
+}
+
+// taskAPI serves the HTTP API of the agent to tasks over the Task API sockets
+// created by the client. Every request is authenticated with the workload
+// identity of the task the socket belongs to.
+type taskAPI struct {
+	logger log.Logger
+
+	// srv is the HTTP server whose handlers are served to tasks. It is set
+	// once the HTTP servers of the agent are created, after the client.
+	srv     *HTTPServer
+	srvLock sync.RWMutex
+	readyCh chan struct{}
+}
+
+func newTaskAPI(logger log.Logger) *taskAPI {
+	return &taskAPI{
+		logger:  logger.Named("task_api"),
+		readyCh: make(chan struct{}),
+	}
+}
+
+// setServer sets the HTTP server whose handlers are served to tasks. It is
+// called again when the HTTP servers are reloaded.
+func (t *taskAPI) setServer(srv *HTTPServer) {
+	t.srvLock.Lock()
+	defer t.srvLock.Unlock()
+
+	if t.srv == nil {
+		close(t.readyCh)
+	}
+	t.srv = srv
+}
+
+func (t *taskAPI) server() *HTTPServer {
+	t.srvLock.RLock()
+	defer t.srvLock.RUnlock()
+	return t.srv
+}
+
+// Serve implements the client's APIListenerRegistrar interface.
+func (t *taskAPI) Serve(ctx context.Context, ln net.Listener, authToken func() string) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-t.readyCh:
+	}
+
+	httpServer := &http.Server{
+		Handler:  t.handler(authToken),
+		ErrorLog: newHTTPServerLogger(t.logger),
+	}
+
+	go func() {
+		<-ctx.Done()
+		httpServer.Close()
+	}()
+
+	err := httpServer.Serve(ln)
+	if errors.Is(err, http.ErrServerClosed) || errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// handler returns the handler of the requests on the Task API socket of a
+// task, which only allows the endpoints tasks can reach and replaces any ACL
+// token of the caller with the workload identity of the task.
+func (t *taskAPI) handler(authToken func() string) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if !taskAPIAllowed(req.URL.Path) {
+			resp.WriteHeader(http.StatusForbidden)
+			resp.Write([]byte("endpoint is not available over the Task API"))
+			return
+		}
+
+		req.Header.Del("Authorization")
+		req.Header.Set("X-Nomad-Token", authToken())
+
+		t.server().mux.ServeHTTP(resp, req)
+	})
+}
+
+// taskAPIAllowed returns true if tasks can reach the endpoint at path over the
+// Task API socket.
+func taskAPIAllowed(path string) bool {
+	for _, prefix := range taskAPIEndpoints {
+		if strings.HasSuffix(prefix, "/") {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == prefix {
+			return true
+		}
+	}
+	return false
+}
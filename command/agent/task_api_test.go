@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskAPI_Serve(t *testing.T) {
+	ci.Parallel(t)
+
+	httpACLTest(t, nil, func(s *TestAgent) {
+		require.NotNil(t, s.Agent.taskAPI)
+
+		path := filepath.Join(t.TempDir(), "api.sock")
+		ln, err := net.Listen("unix", path)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		token := s.RootToken.SecretID
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- s.Agent.taskAPI.Serve(ctx, ln, func() string { return token })
+		}()
+
+		client := &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", path)
+				},
+			},
+		}
+		get := func(path, callerToken string) (int, string) {
+			req, err := http.NewRequest("GET", "http://localhost"+path, nil)
+			require.NoError(t, err)
+			if callerToken != "" {
+				req.Header.Set("X-Nomad-Token", callerToken)
+			}
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			return resp.StatusCode, string(body)
+		}
+
+		// requests are authenticated with the token of the task, whatever
+		// the token of the caller
+		code, _ := get("/v1/jobs", uuid.Generate())
+		require.Equal(t, http.StatusOK, code)
+
+		// endpoints outside of the Task API are rejected
+		code, body := get("/v1/agent/self", "")
+		require.Equal(t, http.StatusForbidden, code)
+		require.Contains(t, body, "not available over the Task API")
+
+		code, _ = get("/v1/acl/tokens", "")
+		require.Equal(t, http.StatusForbidden, code)
+
+		// an invalid token of the task is rejected
+		token = uuid.Generate()
+		code, _ = get("/v1/jobs", "")
+		require.Equal(t, http.StatusForbidden, code)
+
+		cancel()
+		require.NoError(t, <-errCh)
+	})
+}
+
+func TestTaskAPI_Allowed(t *testing.T) {
+	ci.Parallel(t)
+
+	require.True(t, taskAPIAllowed("/v1/jobs"))
+	require.True(t, taskAPIAllowed("/v1/job/example/dispatch"))
+	require.True(t, taskAPIAllowed("/v1/var/nomad/jobs/example"))
+	require.True(t, taskAPIAllowed("/v1/status/leader"))
+	require.False(t, taskAPIAllowed("/v1/jobs/parse"))
+	require.False(t, taskAPIAllowed("/v1/status/peers"))
+	require.False(t, taskAPIAllowed("/v1/agent/self"))
+	require.False(t, taskAPIAllowed("/v1/client/fs/cat/123"))
+	require.False(t, taskAPIAllowed("/v1/acl/token/self"))
+}
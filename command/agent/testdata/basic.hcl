@@ -112,11 +112,14 @@ server {
   node_gc_threshold             = "12h"
   job_gc_interval               = "3m"
   job_gc_threshold              = "12h"
+  dispatch_idempotency_ttl      = "48h"
   eval_gc_threshold             = "12h"
   deployment_gc_threshold       = "12h"
   csi_volume_claim_gc_threshold = "12h"
   csi_plugin_gc_threshold       = "12h"
   acl_token_gc_threshold        = "12h"
+  variables_gc_threshold        = "96h"
+  variables_max_versions        = 5
   heartbeat_grace               = "30s"
   min_heartbeat_ttl             = "33s"
   max_heartbeats_per_second     = 11.0
@@ -133,6 +136,7 @@ server {
   raft_multiplier               = 4
   enable_event_broker           = false
   event_buffer_size             = 200
+  oidc_issuer                   = "https://nomad.example.com"
 
   plan_rejection_tracker {
     enabled        = true
@@ -140,12 +144,28 @@ server {
     node_window    = "41m"
   }
 
+  admission_webhook "policy" {
+    url            = "https://policy.example.com/admit"
+    timeout        = "3s"
+    failure_policy = "ignore"
+    namespaces     = ["prod-*"]
+  }
+
   server_join {
     retry_join     = ["1.1.1.1", "2.2.2.2"]
     retry_max      = 3
     retry_interval = "15s"
   }
 
+  keyring {
+    provider = "vault_transit"
+
+    config {
+      address  = "https://vault.example.com:8200"
+      key_name = "nomad-keyring"
+    }
+  }
+
   default_scheduler_config {
     scheduler_algorithm = "spread"
 
@@ -166,6 +186,7 @@ acl {
   role_ttl                 = "60s"
   token_min_expiration_ttl = "1h"
   token_max_expiration_ttl = "100h"
+  token_idle_timeout       = "2160h"
   replication_token        = "foobar"
 }
 
@@ -32,6 +32,35 @@ func (s *HTTPServer) VariablesListRequest(resp http.ResponseWriter, req *http.Re
 	return out.Data, nil
 }
 
+func (s *HTTPServer) VariableHistoryRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/v1/vars/history/")
+	if len(path) == 0 {
+		return nil, CodedError(http.StatusBadRequest, "missing variable path")
+	}
+
+	args := structs.VariablesHistoryRequest{
+		Path: path,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VariablesHistoryResponse
+	if err := s.agent.RPC(structs.VariablesHistoryRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+
+	if len(out.Data) == 0 {
+		return nil, CodedError(http.StatusNotFound, "variable not found")
+	}
+	return out.Data, nil
+}
+
 func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	path := strings.TrimPrefix(req.URL.Path, "/v1/var/")
 	if len(path) == 0 {
@@ -57,6 +86,13 @@ func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, nil
 	}
+	if v := req.URL.Query().Get("version"); v != "" {
+		version, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("can not parse version: %v", err))
+		}
+		args.Version = version
+	}
 	var out structs.VariablesReadResponse
 	if err := s.agent.RPC(structs.VariablesReadRPCMethod, &args, &out); err != nil {
 		return nil, err
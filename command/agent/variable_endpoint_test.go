@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,6 +30,8 @@ func TestHTTP_Variables(t *testing.T) {
 	ci.Parallel(t)
 
 	httpTest(t, cb, func(s *TestAgent) {
+		testutil.WaitForKeyring(t, s.Agent.RPC, s.Config.Region)
+
 		// These tests are run against the same running server in order to reduce
 		// the costs of server startup and allow as much parallelization as possible
 		// given the port reuse issue that we have seen with the current freeport
@@ -290,6 +293,7 @@ func TestHTTP_Variables(t *testing.T) {
 				// can use a simple equality check
 				svU.ModifyIndex = out.ModifyIndex
 				svU.ModifyTime = out.ModifyTime
+				svU.Version = out.Version
 				require.Equal(t, &svU, out)
 			}
 		})
@@ -368,6 +372,7 @@ func TestHTTP_Variables(t *testing.T) {
 				// can use a simple equality check
 				svU.CreateIndex, svU.ModifyIndex = out.CreateIndex, out.ModifyIndex
 				svU.CreateTime, svU.ModifyTime = out.CreateTime, out.ModifyTime
+				svU.Version = out.Version
 				require.Equal(t, svU.VariableMetadata, out.VariableMetadata)
 
 				// fmt writes sorted output of maps for testability.
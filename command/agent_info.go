@@ -32,6 +32,8 @@ Agent Info Options:
 
   -t
     Format and display node using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -43,8 +45,11 @@ func (c *AgentInfoCommand) Synopsis() string {
 func (c *AgentInfoCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -55,13 +60,11 @@ func (c *AgentInfoCommand) AutocompleteArgs() complete.Predictor {
 func (c *AgentInfoCommand) Name() string { return "agent-info" }
 
 func (c *AgentInfoCommand) Run(args []string) int {
-	var json bool
-	var tmpl string
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing flags: %s", err))
@@ -91,8 +94,8 @@ func (c *AgentInfoCommand) Run(args []string) int {
 	}
 
 	// If output format is specified, format and output the agent info
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, info)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(info)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error formatting output: %s", err))
 			return 1
@@ -52,6 +52,8 @@ Alloc Status Options:
 
   -t
     Format and display allocation using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 
 	return strings.TrimSpace(helpText)
@@ -68,6 +70,9 @@ func (c *AllocStatusCommand) AutocompleteFlags() complete.Flags {
 			"-verbose": complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -89,16 +94,15 @@ func (c *AllocStatusCommand) AutocompleteArgs() complete.Predictor {
 func (c *AllocStatusCommand) Name() string { return "alloc status" }
 
 func (c *AllocStatusCommand) Run(args []string) int {
-	var short, displayStats, verbose, json bool
-	var tmpl string
+	var short, displayStats, verbose bool
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&short, "short", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&displayStats, "stats", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -115,14 +119,14 @@ func (c *AllocStatusCommand) Run(args []string) int {
 	}
 
 	// If args not specified but output format is specified, format and output the allocations data list
-	if len(args) == 0 && (json || len(tmpl) > 0) {
+	if len(args) == 0 && formatOpts.Enabled() {
 		allocs, _, err := client.Allocations().List(nil)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying allocations: %v", err))
 			return 1
 		}
 
-		out, err := Format(json, tmpl, allocs)
+		out, err := formatOpts.Output(allocs)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -177,8 +181,8 @@ func (c *AllocStatusCommand) Run(args []string) int {
 	}
 
 	// If output format is specified, format and output the data
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, alloc)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(alloc)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -0,0 +1,443 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ApplyCommand struct {
+	Meta
+}
+
+func (c *ApplyCommand) Help() string {
+	helpText := `
+Usage: nomad apply [options]
+
+  Apply the cluster configuration defined by a directory of HCL files. The
+  files define namespaces, ACL policies, ACL roles, the scheduler and
+  autopilot configurations, CSI volumes and variables:
+
+      namespace "prod" {
+        description = "Production workloads"
+      }
+
+      acl_policy "readonly" {
+        rules_file = "policies/readonly.hcl"
+      }
+
+      acl_role "operators" {
+        policies = ["readonly"]
+      }
+
+      scheduler_config {
+        memory_oversubscription_enabled = true
+      }
+
+      var "nomad/jobs/web" {
+        namespace = "prod"
+        items     = { db_host = "db.example.com" }
+      }
+
+  The apply command compares the definitions with the current state of the
+  cluster, prints the plan of the changes, and creates and updates the
+  resources in dependency order. The fields of the scheduler and autopilot
+  configurations which are not set keep their current value.
+
+  When ACLs are enabled, this command requires a token with the capabilities
+  needed to manage each kind of resource defined in the directory.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Apply Options:
+
+  -dir
+    The directory of the HCL files (*.hcl) defining the cluster
+    configuration. Defaults to the current directory.
+
+  -prune
+    Delete the resources which exist in the cluster but are not defined in
+    the directory. Only the kinds of resources defined in the directory are
+    pruned, and for CSI volumes and variables only in the namespaces where
+    the directory defines resources of that kind.
+
+  -dry-run
+    Print the plan without applying it.
+
+  -yes
+    Automatically answer "yes" to the confirmation prompt when the plan
+    deletes resources.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-dir":     complete.PredictDirs("*"),
+			"-prune":   complete.PredictNothing,
+			"-dry-run": complete.PredictNothing,
+			"-yes":     complete.PredictNothing,
+		})
+}
+
+func (c *ApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ApplyCommand) Synopsis() string {
+	return "Apply the cluster configuration defined by a directory"
+}
+
+func (c *ApplyCommand) Name() string { return "apply" }
+
+func (c *ApplyCommand) Run(args []string) int {
+	var dir string
+	var prune, dryRun, autoYes bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&dir, "dir", ".", "")
+	flags.BoolVar(&prune, "prune", false, "")
+	flags.BoolVar(&dryRun, "dry-run", false, "")
+	flags.BoolVar(&autoYes, "yes", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	resources, err := parseApplyDir(dir)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing the configuration: %s", err))
+		return 1
+	}
+	if len(resources) == 0 {
+		c.Ui.Error(fmt.Sprintf("No resources are defined in %q", dir))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	changes, err := planApply(client, resources, prune)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error computing the plan: %s", err))
+		return 1
+	}
+
+	if len(changes) == 0 {
+		c.Ui.Output("No changes. The cluster matches the configuration.")
+		return 0
+	}
+
+	c.Ui.Output(formatApplyPlan(changes))
+	if dryRun {
+		return 0
+	}
+
+	deletes := 0
+	for _, change := range changes {
+		if change.Action == applyDelete {
+			deletes++
+		}
+	}
+	if deletes > 0 && !autoYes {
+		question := fmt.Sprintf("\nAre you sure you want to delete %d resource(s)? [y/N]", deletes)
+		answer, err := c.Ui.Ask(question)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse answer: %v", err))
+			return 1
+		}
+		if answer != "y" {
+			c.Ui.Output("Cancelling apply")
+			return 0
+		}
+	}
+
+	c.Ui.Output("")
+	for _, change := range changes {
+		if err := change.apply(client); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error applying the plan: failed to %s %s: %s",
+				change.Action, change.resource(), err))
+			return 1
+		}
+		c.Ui.Output(fmt.Sprintf("%s %s", change.Action.done(), change.resource()))
+	}
+
+	return 0
+}
+
+// parseApplyDir parses the resources defined by the HCL files of the
+// directory.
+func parseApplyDir(dir string) ([]*applyResource, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.hcl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var resources []*applyResource
+	seen := map[string]*applyResource{}
+	for _, path := range paths {
+		input, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fileResources, err := parseApplyFile(path, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range fileResources {
+			if prev, ok := seen[r.key()]; ok {
+				return nil, fmt.Errorf("%s: %s is already defined at %s", r.Pos, r, prev.Pos)
+			}
+			seen[r.key()] = r
+			resources = append(resources, r)
+		}
+	}
+
+	return resources, nil
+}
+
+// parseApplyFile parses the resources defined by a file.
+func parseApplyFile(path string, input []byte) ([]*applyResource, error) {
+	root, err := hcl.ParseBytes(input)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	// Top-level item should be a list
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%s: root should be an object", path)
+	}
+
+	var resources []*applyResource
+	for _, item := range list.Items {
+		pos := fmt.Sprintf("%s:%d", path, item.Pos().Line)
+		blockType := item.Keys[0].Token.Value().(string)
+
+		kind := lookupApplyKind(blockType)
+		if kind == nil {
+			return nil, fmt.Errorf("%s: unknown resource type %q", pos, blockType)
+		}
+
+		labels := len(item.Keys) - 1
+		switch {
+		case kind.Singleton && labels != 0:
+			return nil, fmt.Errorf("%s: %s blocks take no labels", pos, blockType)
+		case !kind.Singleton && labels != 1:
+			return nil, fmt.Errorf("%s: %s blocks take exactly one label", pos, blockType)
+		}
+
+		ot, ok := item.Val.(*ast.ObjectType)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s should be a block", pos, blockType)
+		}
+
+		r := &applyResource{Kind: kind, Pos: pos}
+		if !kind.Singleton {
+			r.Name = item.Keys[1].Token.Value().(string)
+		}
+		if kind.Namespaced {
+			r.Namespace = api.DefaultNamespace
+		}
+		if err := kind.Decode(r, ot.List, filepath.Dir(path)); err != nil {
+			return nil, fmt.Errorf("%s: invalid %s: %v", pos, r, err)
+		}
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
+
+type applyAction string
+
+const (
+	applyCreate applyAction = "create"
+	applyUpdate applyAction = "update"
+	applyDelete applyAction = "delete"
+)
+
+// done returns the message reporting the action was applied.
+func (a applyAction) done() string {
+	switch a {
+	case applyCreate:
+		return "Created"
+	case applyUpdate:
+		return "Updated"
+	default:
+		return "Deleted"
+	}
+}
+
+// applyChange is a change of the plan computed by the apply command.
+type applyChange struct {
+	Action applyAction
+
+	// Desired is the definition of the resource, nil for deletions.
+	Desired *applyResource
+
+	// Current is the resource in the cluster, nil for creations.
+	Current *applyResource
+
+	// Fields are the names of the fields modified by updates.
+	Fields []string
+}
+
+func (c *applyChange) resource() *applyResource {
+	if c.Desired != nil {
+		return c.Desired
+	}
+	return c.Current
+}
+
+func (c *applyChange) apply(client *api.Client) error {
+	kind := c.resource().Kind
+	if c.Action == applyDelete {
+		return kind.Delete(client, c.Current)
+	}
+	return kind.Put(client, c.Desired, c.Current)
+}
+
+// planApply compares the resources with the current state of the cluster
+// and returns the changes to apply, in dependency order. Only the kinds of
+// resources defined are read from the cluster.
+func planApply(client *api.Client, resources []*applyResource, prune bool) ([]*applyChange, error) {
+	var changes, deletes []*applyChange
+
+	for _, kind := range applyKinds {
+		var desired []*applyResource
+		namespaces := map[string]bool{}
+		for _, r := range resources {
+			if r.Kind == kind {
+				desired = append(desired, r)
+				namespaces[r.Namespace] = true
+			}
+		}
+		if len(desired) == 0 {
+			continue
+		}
+
+		current, err := kind.List(client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s resources: %v", kind.Name, err)
+		}
+		currentByKey := make(map[string]*applyResource, len(current))
+		for _, r := range current {
+			r.Kind = kind
+			currentByKey[r.key()] = r
+		}
+
+		for _, r := range desired {
+			cur, ok := currentByKey[r.key()]
+			if !ok {
+				changes = append(changes, &applyChange{Action: applyCreate, Desired: r})
+				continue
+			}
+			delete(currentByKey, r.key())
+
+			if kind.Fetch != nil {
+				if err := kind.Fetch(client, cur); err != nil {
+					return nil, fmt.Errorf("failed to read %s: %v", cur, err)
+				}
+			}
+			if kind.Merge != nil {
+				merged, err := kind.Merge(r.Value, cur.Value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: invalid %s: %v", r.Pos, r, err)
+				}
+				r.Value = merged
+			}
+
+			fields := diffApplyFields(kind.Fields(r.Value), kind.Fields(cur.Value))
+			if len(fields) > 0 {
+				changes = append(changes, &applyChange{
+					Action:  applyUpdate,
+					Desired: r,
+					Current: cur,
+					Fields:  fields,
+				})
+			}
+		}
+
+		if !prune || kind.Delete == nil {
+			continue
+		}
+		var pruned []*applyChange
+		for _, cur := range currentByKey {
+			if kind.Namespaced && !namespaces[cur.Namespace] {
+				continue
+			}
+			if kind == applyNamespaceKind && cur.Name == api.DefaultNamespace {
+				continue
+			}
+			pruned = append(pruned, &applyChange{Action: applyDelete, Current: cur})
+		}
+		sort.Slice(pruned, func(i, j int) bool {
+			return pruned[i].Current.key() < pruned[j].Current.key()
+		})
+		deletes = append(pruned, deletes...)
+	}
+
+	return append(changes, deletes...), nil
+}
+
+// diffApplyFields returns the sorted names of the fields which differ.
+func diffApplyFields(desired, current map[string]interface{}) []string {
+	var fields []string
+	for name, value := range desired {
+		if !reflect.DeepEqual(value, current[name]) {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// formatApplyPlan formats the plan for the user.
+func formatApplyPlan(changes []*applyChange) string {
+	var b strings.Builder
+	counts := map[applyAction]int{}
+
+	b.WriteString("Plan:\n")
+	for _, change := range changes {
+		counts[change.Action]++
+		switch change.Action {
+		case applyCreate:
+			fmt.Fprintf(&b, "  + %s\n", change.resource())
+		case applyUpdate:
+			fmt.Fprintf(&b, "  ~ %s (%s)\n", change.resource(), strings.Join(change.Fields, ", "))
+		case applyDelete:
+			fmt.Fprintf(&b, "  - %s\n", change.resource())
+		}
+	}
+	fmt.Fprintf(&b, "\n%d to create, %d to update, %d to delete.",
+		counts[applyCreate], counts[applyUpdate], counts[applyDelete])
+
+	return b.String()
+}
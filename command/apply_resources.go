@@ -0,0 +1,713 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+)
+
+// applyResource is a resource of the cluster, either defined in the
+// configuration directory or read from the servers.
+type applyResource struct {
+	Kind *applyKind
+
+	// Name identifies the resource within its kind: the name of namespaces,
+	// ACL policies and ACL roles, the ID of CSI volumes or the path of
+	// variables. It is empty for the singleton configurations.
+	Name string
+
+	// Namespace is the namespace of the namespaced resources.
+	Namespace string
+
+	// Pos is the position of the definition of the resource in the
+	// configuration directory.
+	Pos string
+
+	// Value is the API object of the resource, or the fields to set for the
+	// singleton configurations.
+	Value interface{}
+}
+
+// key uniquely identifies the resource in the cluster.
+func (r *applyResource) key() string {
+	return r.Kind.Name + "\x00" + r.Namespace + "\x00" + r.Name
+}
+
+func (r *applyResource) String() string {
+	switch {
+	case r.Kind.Singleton:
+		return r.Kind.Name
+	case r.Kind.Namespaced:
+		return fmt.Sprintf("%s %q (namespace %q)", r.Kind.Name, r.Name, r.Namespace)
+	default:
+		return fmt.Sprintf("%s %q", r.Kind.Name, r.Name)
+	}
+}
+
+// applyKind describes how a kind of resource is defined in the configuration
+// directory and how it is read, compared and written through the API.
+type applyKind struct {
+	// Name is the type of the blocks defining the resources of this kind.
+	Name string
+
+	// Singleton kinds have a single instance, defined by a block without
+	// labels. They are never deleted.
+	Singleton bool
+
+	// Namespaced kinds have a namespace attribute, which defaults to the
+	// default namespace.
+	Namespaced bool
+
+	// Decode decodes the body of a block into the value of the resource.
+	Decode func(r *applyResource, list *ast.ObjectList, dir string) error
+
+	// List returns the resources of the cluster. The values may be partial,
+	// in which case Fetch completes them.
+	List func(client *api.Client) ([]*applyResource, error)
+
+	// Fetch completes the value of a resource returned by List, before it's
+	// compared with its definition.
+	Fetch func(client *api.Client, r *applyResource) error
+
+	// Merge returns the value of a singleton resource once the fields set in
+	// its definition are applied to the current value.
+	Merge func(desired, current interface{}) (interface{}, error)
+
+	// Fields returns the fields of a value which are compared to decide if
+	// the resource needs to be updated.
+	Fields func(v interface{}) map[string]interface{}
+
+	// Put creates the resource, or updates it when current is not nil.
+	Put func(client *api.Client, desired, current *applyResource) error
+
+	// Delete deletes the resource. It is nil for the singleton kinds.
+	Delete func(client *api.Client, current *applyResource) error
+}
+
+// applyKinds are the kinds of resources supported by the apply command, in
+// the order in which they are created and updated. Resources are deleted in
+// the reverse order, so that the resources they depend on still exist.
+var applyKinds = []*applyKind{
+	applyNamespaceKind,
+	applyACLPolicyKind,
+	applyACLRoleKind,
+	applySchedulerConfigKind,
+	applyAutopilotConfigKind,
+	applyCSIVolumeKind,
+	applyVariableKind,
+}
+
+func lookupApplyKind(name string) *applyKind {
+	for _, kind := range applyKinds {
+		if kind.Name == name {
+			return kind
+		}
+	}
+	return nil
+}
+
+var applyNamespaceKind = &applyKind{
+	Name: "namespace",
+	Decode: func(r *applyResource, list *ast.ObjectList, _ string) error {
+		valid := []string{"description", "quota", "capabilities", "limits", "meta"}
+		if err := helper.CheckHCLKeys(list, valid); err != nil {
+			return err
+		}
+		ns := &api.Namespace{}
+		if err := parseNamespaceSpecImpl(ns, list); err != nil {
+			return err
+		}
+		ns.Name = r.Name
+		r.Value = ns
+		return nil
+	},
+	List: func(client *api.Client) ([]*applyResource, error) {
+		namespaces, _, err := client.Namespaces().List(nil)
+		if err != nil {
+			return nil, err
+		}
+		resources := make([]*applyResource, 0, len(namespaces))
+		for _, ns := range namespaces {
+			resources = append(resources, &applyResource{Name: ns.Name, Value: ns})
+		}
+		return resources, nil
+	},
+	Fields: func(v interface{}) map[string]interface{} {
+		ns := v.(*api.Namespace)
+		return map[string]interface{}{
+			"description":  ns.Description,
+			"quota":        ns.Quota,
+			"capabilities": ns.Capabilities,
+			"limits":       ns.Limits,
+			"meta":         emptyMapToNil(ns.Meta),
+		}
+	},
+	Put: func(client *api.Client, desired, _ *applyResource) error {
+		_, err := client.Namespaces().Register(desired.Value.(*api.Namespace), nil)
+		return err
+	},
+	Delete: func(client *api.Client, current *applyResource) error {
+		if current.Name == api.DefaultNamespace {
+			return fmt.Errorf("the default namespace cannot be deleted")
+		}
+		_, err := client.Namespaces().Delete(current.Name, nil)
+		return err
+	},
+}
+
+// applyACLPolicy is the definition of an ACL policy. The rules are either
+// inlined or read from a file relative to the configuration directory.
+type applyACLPolicy struct {
+	Description string `hcl:"description"`
+	Rules       string `hcl:"rules"`
+	RulesFile   string `hcl:"rules_file"`
+}
+
+var applyACLPolicyKind = &applyKind{
+	Name: "acl_policy",
+	Decode: func(r *applyResource, list *ast.ObjectList, dir string) error {
+		if err := helper.CheckHCLKeys(list, []string{"description", "rules", "rules_file"}); err != nil {
+			return err
+		}
+		var def applyACLPolicy
+		if err := hcl.DecodeObject(&def, list); err != nil {
+			return err
+		}
+
+		switch {
+		case def.Rules != "" && def.RulesFile != "":
+			return fmt.Errorf("only one of rules and rules_file may be set")
+		case def.RulesFile != "":
+			path := def.RulesFile
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			rules, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read rules file: %v", err)
+			}
+			def.Rules = string(rules)
+		case def.Rules == "":
+			return fmt.Errorf("one of rules or rules_file must be set")
+		}
+
+		r.Value = &api.ACLPolicy{
+			Name:        r.Name,
+			Description: def.Description,
+			Rules:       def.Rules,
+		}
+		return nil
+	},
+	List: func(client *api.Client) ([]*applyResource, error) {
+		policies, _, err := client.ACLPolicies().List(nil)
+		if err != nil {
+			return nil, err
+		}
+		resources := make([]*applyResource, 0, len(policies))
+		for _, p := range policies {
+			resources = append(resources, &applyResource{
+				Name:  p.Name,
+				Value: &api.ACLPolicy{Name: p.Name, Description: p.Description},
+			})
+		}
+		return resources, nil
+	},
+	Fetch: func(client *api.Client, r *applyResource) error {
+		policy, _, err := client.ACLPolicies().Info(r.Name, nil)
+		if err != nil {
+			return err
+		}
+		r.Value = policy
+		return nil
+	},
+	Fields: func(v interface{}) map[string]interface{} {
+		p := v.(*api.ACLPolicy)
+		return map[string]interface{}{
+			"description": p.Description,
+			"rules":       strings.TrimSpace(p.Rules),
+		}
+	},
+	Put: func(client *api.Client, desired, _ *applyResource) error {
+		_, err := client.ACLPolicies().Upsert(desired.Value.(*api.ACLPolicy), nil)
+		return err
+	},
+	Delete: func(client *api.Client, current *applyResource) error {
+		_, err := client.ACLPolicies().Delete(current.Name, nil)
+		return err
+	},
+}
+
+// applyACLRole is the definition of an ACL role, which links policies by
+// name.
+type applyACLRole struct {
+	Description string   `hcl:"description"`
+	Policies    []string `hcl:"policies"`
+}
+
+var applyACLRoleKind = &applyKind{
+	Name: "acl_role",
+	Decode: func(r *applyResource, list *ast.ObjectList, _ string) error {
+		if err := helper.CheckHCLKeys(list, []string{"description", "policies"}); err != nil {
+			return err
+		}
+		var def applyACLRole
+		if err := hcl.DecodeObject(&def, list); err != nil {
+			return err
+		}
+		if len(def.Policies) == 0 {
+			return fmt.Errorf("at least one policy must be set")
+		}
+
+		role := &api.ACLRole{Name: r.Name, Description: def.Description}
+		for _, name := range def.Policies {
+			role.Policies = append(role.Policies, &api.ACLRolePolicyLink{Name: name})
+		}
+		r.Value = role
+		return nil
+	},
+	List: func(client *api.Client) ([]*applyResource, error) {
+		roles, _, err := client.ACLRoles().List(nil)
+		if err != nil {
+			return nil, err
+		}
+		resources := make([]*applyResource, 0, len(roles))
+		for _, role := range roles {
+			resources = append(resources, &applyResource{
+				Name: role.Name,
+				Value: &api.ACLRole{
+					ID:          role.ID,
+					Name:        role.Name,
+					Description: role.Description,
+					Policies:    role.Policies,
+				},
+			})
+		}
+		return resources, nil
+	},
+	Fields: func(v interface{}) map[string]interface{} {
+		role := v.(*api.ACLRole)
+		policies := make([]string, 0, len(role.Policies))
+		for _, link := range role.Policies {
+			policies = append(policies, link.Name)
+		}
+		sort.Strings(policies)
+		return map[string]interface{}{
+			"description": role.Description,
+			"policies":    policies,
+		}
+	},
+	Put: func(client *api.Client, desired, current *applyResource) error {
+		role := *desired.Value.(*api.ACLRole)
+		if current == nil {
+			_, _, err := client.ACLRoles().Create(&role, nil)
+			return err
+		}
+		role.ID = current.Value.(*api.ACLRole).ID
+		_, _, err := client.ACLRoles().Update(&role, nil)
+		return err
+	},
+	Delete: func(client *api.Client, current *applyResource) error {
+		_, err := client.ACLRoles().Delete(current.Value.(*api.ACLRole).ID, nil)
+		return err
+	},
+}
+
+// applySchedulerConfig holds the fields of the scheduler configuration set by
+// its definition. The fields left unset keep their current value.
+type applySchedulerConfig struct {
+	SchedulerAlgorithm            *string                `hcl:"scheduler_algorithm"`
+	MemoryOversubscriptionEnabled *bool                  `hcl:"memory_oversubscription_enabled"`
+	RejectJobRegistration         *bool                  `hcl:"reject_job_registration"`
+	PauseEvalBroker               *bool                  `hcl:"pause_eval_broker"`
+	PreemptionConfig              *applyPreemptionConfig `hcl:"preemption_config"`
+}
+
+type applyPreemptionConfig struct {
+	SystemSchedulerEnabled   *bool `hcl:"system_scheduler_enabled"`
+	SysBatchSchedulerEnabled *bool `hcl:"sysbatch_scheduler_enabled"`
+	BatchSchedulerEnabled    *bool `hcl:"batch_scheduler_enabled"`
+	ServiceSchedulerEnabled  *bool `hcl:"service_scheduler_enabled"`
+}
+
+var applySchedulerConfigKind = &applyKind{
+	Name:      "scheduler_config",
+	Singleton: true,
+	Decode: func(r *applyResource, list *ast.ObjectList, _ string) error {
+		valid := []string{
+			"scheduler_algorithm",
+			"memory_oversubscription_enabled",
+			"reject_job_registration",
+			"pause_eval_broker",
+			"preemption_config",
+		}
+		if err := helper.CheckHCLKeys(list, valid); err != nil {
+			return err
+		}
+		if o := list.Filter("preemption_config"); len(o.Items) > 0 {
+			valid := []string{
+				"system_scheduler_enabled",
+				"sysbatch_scheduler_enabled",
+				"batch_scheduler_enabled",
+				"service_scheduler_enabled",
+			}
+			for _, item := range o.Items {
+				if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+					return fmt.Errorf("preemption_config: %v", err)
+				}
+			}
+		}
+
+		var def applySchedulerConfig
+		if err := hcl.DecodeObject(&def, list); err != nil {
+			return err
+		}
+		if def.SchedulerAlgorithm != nil {
+			switch api.SchedulerAlgorithm(*def.SchedulerAlgorithm) {
+			case api.SchedulerAlgorithmBinpack, api.SchedulerAlgorithmSpread:
+			default:
+				return fmt.Errorf("invalid scheduler_algorithm %q: must be %q or %q",
+					*def.SchedulerAlgorithm, api.SchedulerAlgorithmBinpack, api.SchedulerAlgorithmSpread)
+			}
+		}
+		r.Value = &def
+		return nil
+	},
+	List: func(client *api.Client) ([]*applyResource, error) {
+		resp, _, err := client.Operator().SchedulerGetConfiguration(nil)
+		if err != nil {
+			return nil, err
+		}
+		return []*applyResource{{Value: resp.SchedulerConfig}}, nil
+	},
+	Merge: func(desired, current interface{}) (interface{}, error) {
+		def := desired.(*applySchedulerConfig)
+		conf := *current.(*api.SchedulerConfiguration)
+		if def.SchedulerAlgorithm != nil {
+			conf.SchedulerAlgorithm = api.SchedulerAlgorithm(*def.SchedulerAlgorithm)
+		}
+		setBool(&conf.MemoryOversubscriptionEnabled, def.MemoryOversubscriptionEnabled)
+		setBool(&conf.RejectJobRegistration, def.RejectJobRegistration)
+		setBool(&conf.PauseEvalBroker, def.PauseEvalBroker)
+		if p := def.PreemptionConfig; p != nil {
+			setBool(&conf.PreemptionConfig.SystemSchedulerEnabled, p.SystemSchedulerEnabled)
+			setBool(&conf.PreemptionConfig.SysBatchSchedulerEnabled, p.SysBatchSchedulerEnabled)
+			setBool(&conf.PreemptionConfig.BatchSchedulerEnabled, p.BatchSchedulerEnabled)
+			setBool(&conf.PreemptionConfig.ServiceSchedulerEnabled, p.ServiceSchedulerEnabled)
+		}
+		return &conf, nil
+	},
+	Fields: func(v interface{}) map[string]interface{} {
+		conf := v.(*api.SchedulerConfiguration)
+		return map[string]interface{}{
+			"scheduler_algorithm":             conf.SchedulerAlgorithm,
+			"memory_oversubscription_enabled": conf.MemoryOversubscriptionEnabled,
+			"reject_job_registration":         conf.RejectJobRegistration,
+			"pause_eval_broker":               conf.PauseEvalBroker,
+			"preemption_config":               conf.PreemptionConfig,
+		}
+	},
+	Put: func(client *api.Client, desired, _ *applyResource) error {
+		resp, _, err := client.Operator().SchedulerCASConfiguration(desired.Value.(*api.SchedulerConfiguration), nil)
+		if err != nil {
+			return err
+		}
+		if !resp.Updated {
+			return fmt.Errorf("the configuration was modified concurrently")
+		}
+		return nil
+	},
+}
+
+// applyAutopilotConfig holds the fields of the autopilot configuration set by
+// its definition. The fields left unset keep their current value.
+type applyAutopilotConfig struct {
+	CleanupDeadServers      *bool   `hcl:"cleanup_dead_servers"`
+	LastContactThreshold    *string `hcl:"last_contact_threshold"`
+	MaxTrailingLogs         *int    `hcl:"max_trailing_logs"`
+	MinQuorum               *int    `hcl:"min_quorum"`
+	ServerStabilizationTime *string `hcl:"server_stabilization_time"`
+	EnableRedundancyZones   *bool   `hcl:"enable_redundancy_zones"`
+	DisableUpgradeMigration *bool   `hcl:"disable_upgrade_migration"`
+	EnableCustomUpgrades    *bool   `hcl:"enable_custom_upgrades"`
+}
+
+var applyAutopilotConfigKind = &applyKind{
+	Name:      "autopilot_config",
+	Singleton: true,
+	Decode: func(r *applyResource, list *ast.ObjectList, _ string) error {
+		valid := []string{
+			"cleanup_dead_servers",
+			"last_contact_threshold",
+			"max_trailing_logs",
+			"min_quorum",
+			"server_stabilization_time",
+			"enable_redundancy_zones",
+			"disable_upgrade_migration",
+			"enable_custom_upgrades",
+		}
+		if err := helper.CheckHCLKeys(list, valid); err != nil {
+			return err
+		}
+
+		var def applyAutopilotConfig
+		if err := hcl.DecodeObject(&def, list); err != nil {
+			return err
+		}
+		for name, n := range map[string]*int{
+			"max_trailing_logs": def.MaxTrailingLogs,
+			"min_quorum":        def.MinQuorum,
+		} {
+			if n != nil && *n < 0 {
+				return fmt.Errorf("invalid %s: must not be negative", name)
+			}
+		}
+		for name, d := range map[string]*string{
+			"last_contact_threshold":    def.LastContactThreshold,
+			"server_stabilization_time": def.ServerStabilizationTime,
+		} {
+			if d == nil {
+				continue
+			}
+			if _, err := time.ParseDuration(*d); err != nil {
+				return fmt.Errorf("invalid %s: %v", name, err)
+			}
+		}
+		r.Value = &def
+		return nil
+	},
+	List: func(client *api.Client) ([]*applyResource, error) {
+		conf, _, err := client.Operator().AutopilotGetConfiguration(nil)
+		if err != nil {
+			return nil, err
+		}
+		return []*applyResource{{Value: conf}}, nil
+	},
+	Merge: func(desired, current interface{}) (interface{}, error) {
+		def := desired.(*applyAutopilotConfig)
+		conf := *current.(*api.AutopilotConfiguration)
+		setBool(&conf.CleanupDeadServers, def.CleanupDeadServers)
+		setBool(&conf.EnableRedundancyZones, def.EnableRedundancyZones)
+		setBool(&conf.DisableUpgradeMigration, def.DisableUpgradeMigration)
+		setBool(&conf.EnableCustomUpgrades, def.EnableCustomUpgrades)
+		if def.MaxTrailingLogs != nil {
+			conf.MaxTrailingLogs = uint64(*def.MaxTrailingLogs)
+		}
+		if def.MinQuorum != nil {
+			conf.MinQuorum = uint(*def.MinQuorum)
+		}
+		if def.LastContactThreshold != nil {
+			d, err := time.ParseDuration(*def.LastContactThreshold)
+			if err != nil {
+				return nil, err
+			}
+			conf.LastContactThreshold = d
+		}
+		if def.ServerStabilizationTime != nil {
+			d, err := time.ParseDuration(*def.ServerStabilizationTime)
+			if err != nil {
+				return nil, err
+			}
+			conf.ServerStabilizationTime = d
+		}
+		return &conf, nil
+	},
+	Fields: func(v interface{}) map[string]interface{} {
+		conf := v.(*api.AutopilotConfiguration)
+		return map[string]interface{}{
+			"cleanup_dead_servers":      conf.CleanupDeadServers,
+			"last_contact_threshold":    conf.LastContactThreshold,
+			"max_trailing_logs":         conf.MaxTrailingLogs,
+			"min_quorum":                conf.MinQuorum,
+			"server_stabilization_time": conf.ServerStabilizationTime,
+			"enable_redundancy_zones":   conf.EnableRedundancyZones,
+			"disable_upgrade_migration": conf.DisableUpgradeMigration,
+			"enable_custom_upgrades":    conf.EnableCustomUpgrades,
+		}
+	},
+	Put: func(client *api.Client, desired, _ *applyResource) error {
+		updated, _, err := client.Operator().AutopilotCASConfiguration(desired.Value.(*api.AutopilotConfiguration), nil)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return fmt.Errorf("the configuration was modified concurrently")
+		}
+		return nil
+	},
+}
+
+var applyCSIVolumeKind = &applyKind{
+	Name:       "csi_volume",
+	Namespaced: true,
+	Decode: func(r *applyResource, list *ast.ObjectList, _ string) error {
+		vol, err := csiDecodeVolume(&ast.File{Node: list})
+		if err != nil {
+			return err
+		}
+		if vol.Namespace != "" {
+			r.Namespace = vol.Namespace
+		}
+		vol.ID = r.Name
+		vol.Namespace = r.Namespace
+		r.Value = vol
+		return nil
+	},
+	List: func(client *api.Client) ([]*applyResource, error) {
+		vols, _, err := client.CSIVolumes().List(&api.QueryOptions{Namespace: api.AllNamespacesNamespace})
+		if err != nil {
+			return nil, err
+		}
+		resources := make([]*applyResource, 0, len(vols))
+		for _, vol := range vols {
+			resources = append(resources, &applyResource{
+				Name:      vol.ID,
+				Namespace: vol.Namespace,
+			})
+		}
+		return resources, nil
+	},
+	Fetch: func(client *api.Client, r *applyResource) error {
+		vol, _, err := client.CSIVolumes().Info(r.Name, &api.QueryOptions{Namespace: r.Namespace})
+		if err != nil {
+			return err
+		}
+		r.Value = vol
+		return nil
+	},
+	Fields: func(v interface{}) map[string]interface{} {
+		vol := v.(*api.CSIVolume)
+		capabilities := make([]string, 0, len(vol.RequestedCapabilities))
+		for _, c := range vol.RequestedCapabilities {
+			capabilities = append(capabilities, fmt.Sprintf("%s/%s", c.AccessMode, c.AttachmentMode))
+		}
+		sort.Strings(capabilities)
+
+		// The secrets and mount flags are redacted by the servers, so
+		// they can't be compared.
+		fsType := ""
+		if vol.MountOptions != nil {
+			fsType = vol.MountOptions.FSType
+		}
+		return map[string]interface{}{
+			"name":          vol.Name,
+			"external_id":   vol.ExternalID,
+			"plugin_id":     vol.PluginID,
+			"capability":    capabilities,
+			"mount_options": fsType,
+			"parameters":    emptyMapToNil(vol.Parameters),
+			"context":       emptyMapToNil(vol.Context),
+		}
+	},
+	Put: func(client *api.Client, desired, _ *applyResource) error {
+		_, err := client.CSIVolumes().Register(desired.Value.(*api.CSIVolume), &api.WriteOptions{Namespace: desired.Namespace})
+		return err
+	},
+	Delete: func(client *api.Client, current *applyResource) error {
+		return client.CSIVolumes().Deregister(current.Name, false, &api.WriteOptions{Namespace: current.Namespace})
+	},
+}
+
+// applyVariable is the definition of a variable, whose path is the label of
+// its block.
+type applyVariable struct {
+	Namespace string            `hcl:"namespace"`
+	Items     map[string]string `hcl:"items"`
+}
+
+var applyVariableKind = &applyKind{
+	Name:       "var",
+	Namespaced: true,
+	Decode: func(r *applyResource, list *ast.ObjectList, _ string) error {
+		if err := helper.CheckHCLKeys(list, []string{"namespace", "items"}); err != nil {
+			return err
+		}
+		var def applyVariable
+		if err := hcl.DecodeObject(&def, list); err != nil {
+			return err
+		}
+		if len(def.Items) == 0 {
+			return fmt.Errorf("variables must have at least one item")
+		}
+		if def.Namespace != "" {
+			r.Namespace = def.Namespace
+		}
+		r.Value = &api.Variable{
+			Namespace: r.Namespace,
+			Path:      r.Name,
+			Items:     def.Items,
+		}
+		return nil
+	},
+	List: func(client *api.Client) ([]*applyResource, error) {
+		vars, _, err := client.Variables().List(&api.QueryOptions{Namespace: api.AllNamespacesNamespace})
+		if err != nil {
+			return nil, err
+		}
+		resources := make([]*applyResource, 0, len(vars))
+		for _, v := range vars {
+			resources = append(resources, &applyResource{
+				Name:      v.Path,
+				Namespace: v.Namespace,
+				Value: &api.Variable{
+					Namespace:   v.Namespace,
+					Path:        v.Path,
+					ModifyIndex: v.ModifyIndex,
+				},
+			})
+		}
+		return resources, nil
+	},
+	Fetch: func(client *api.Client, r *applyResource) error {
+		v, _, err := client.Variables().Read(r.Name, &api.QueryOptions{Namespace: r.Namespace})
+		if err != nil {
+			return err
+		}
+		r.Value = v
+		return nil
+	},
+	Fields: func(v interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"items": map[string]string(v.(*api.Variable).Items),
+		}
+	},
+	Put: func(client *api.Client, desired, current *applyResource) error {
+		v := *desired.Value.(*api.Variable)
+		opts := &api.WriteOptions{Namespace: desired.Namespace}
+		if current == nil {
+			_, _, err := client.Variables().CheckedCreate(&v, opts)
+			return err
+		}
+		v.ModifyIndex = current.Value.(*api.Variable).ModifyIndex
+		_, _, err := client.Variables().CheckedUpdate(&v, opts)
+		return err
+	},
+	Delete: func(client *api.Client, current *applyResource) error {
+		index := current.Value.(*api.Variable).ModifyIndex
+		_, err := client.Variables().CheckedDelete(current.Name, index, &api.WriteOptions{Namespace: current.Namespace})
+		return err
+	},
+}
+
+// setBool sets the value pointed to by dst when src is set.
+func setBool(dst *bool, src *bool) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// emptyMapToNil returns nil for empty maps, so that maps omitted in
+// definitions compare equal to the empty maps returned by the servers.
+func emptyMapToNil(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,292 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestApplyCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &ApplyCommand{}
+}
+
+func writeApplyFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		must.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		must.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestApplyCommand_Parse(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := writeApplyFiles(t, map[string]string{
+		"namespaces.hcl": `
+namespace "prod" {
+  description = "Production"
+  meta {
+    owner = "ops"
+  }
+}
+`,
+		"acl.hcl": `
+acl_policy "readonly" {
+  description = "Read only"
+  rules_file  = "policies/readonly.hcl"
+}
+
+acl_role "operators" {
+  policies = ["readonly"]
+}
+`,
+		"policies/readonly.hcl": `namespace "*" { policy = "read" }`,
+		"operator.hcl": `
+scheduler_config {
+  scheduler_algorithm = "spread"
+  preemption_config {
+    batch_scheduler_enabled = true
+  }
+}
+
+autopilot_config {
+  last_contact_threshold = "500ms"
+  min_quorum             = 3
+}
+`,
+		"storage.hcl": `
+csi_volume "db" {
+  namespace   = "prod"
+  name        = "database"
+  external_id = "vol-1234"
+  plugin_id   = "ebs"
+  capability {
+    access_mode     = "single-node-writer"
+    attachment_mode = "file-system"
+  }
+}
+
+var "nomad/jobs/web" {
+  items = {
+    db_host = "db.example.com"
+  }
+}
+`,
+		"README.md": "not a resource file",
+	})
+
+	resources, err := parseApplyDir(dir)
+	must.NoError(t, err)
+	must.Len(t, 7, resources)
+
+	byKind := map[string]*applyResource{}
+	for _, r := range resources {
+		byKind[r.Kind.Name] = r
+	}
+
+	ns := byKind["namespace"].Value.(*api.Namespace)
+	must.Eq(t, "prod", ns.Name)
+	must.Eq(t, "Production", ns.Description)
+	must.Eq(t, map[string]string{"owner": "ops"}, ns.Meta)
+
+	policy := byKind["acl_policy"].Value.(*api.ACLPolicy)
+	must.Eq(t, "readonly", policy.Name)
+	must.Eq(t, `namespace "*" { policy = "read" }`, policy.Rules)
+
+	role := byKind["acl_role"].Value.(*api.ACLRole)
+	must.Eq(t, "operators", role.Name)
+	must.Len(t, 1, role.Policies)
+	must.Eq(t, "readonly", role.Policies[0].Name)
+
+	// Only the fields set are applied to the current configuration
+	merged, err := applySchedulerConfigKind.Merge(byKind["scheduler_config"].Value, &api.SchedulerConfiguration{
+		MemoryOversubscriptionEnabled: true,
+		PreemptionConfig:              api.PreemptionConfig{SystemSchedulerEnabled: true},
+	})
+	must.NoError(t, err)
+	must.Eq(t, &api.SchedulerConfiguration{
+		SchedulerAlgorithm:            api.SchedulerAlgorithmSpread,
+		MemoryOversubscriptionEnabled: true,
+		PreemptionConfig: api.PreemptionConfig{
+			SystemSchedulerEnabled: true,
+			BatchSchedulerEnabled:  true,
+		},
+	}, merged.(*api.SchedulerConfiguration))
+
+	merged, err = applyAutopilotConfigKind.Merge(byKind["autopilot_config"].Value, &api.AutopilotConfiguration{
+		CleanupDeadServers: true,
+		MinQuorum:          1,
+	})
+	must.NoError(t, err)
+	must.Eq(t, &api.AutopilotConfiguration{
+		CleanupDeadServers:   true,
+		LastContactThreshold: 500 * time.Millisecond,
+		MinQuorum:            3,
+	}, merged.(*api.AutopilotConfiguration))
+
+	vol := byKind["csi_volume"]
+	must.Eq(t, "prod", vol.Namespace)
+	must.Eq(t, "db", vol.Value.(*api.CSIVolume).ID)
+	must.Eq(t, "vol-1234", vol.Value.(*api.CSIVolume).ExternalID)
+	must.Len(t, 1, vol.Value.(*api.CSIVolume).RequestedCapabilities)
+
+	v := byKind["var"]
+	must.Eq(t, api.DefaultNamespace, v.Namespace)
+	must.Eq(t, api.VariableItems{"db_host": "db.example.com"}, v.Value.(*api.Variable).Items)
+}
+
+func TestApplyCommand_Parse_Errors(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{
+			name:   "unknown type",
+			input:  `job "example" {}`,
+			expect: `unknown resource type "job"`,
+		},
+		{
+			name:   "missing label",
+			input:  `namespace {}`,
+			expect: "namespace blocks take exactly one label",
+		},
+		{
+			name:   "singleton label",
+			input:  `scheduler_config "foo" {}`,
+			expect: "scheduler_config blocks take no labels",
+		},
+		{
+			name:   "invalid key",
+			input:  `namespace "prod" { descr = "typo" }`,
+			expect: "invalid key: descr",
+		},
+		{
+			name:   "missing rules",
+			input:  `acl_policy "readonly" {}`,
+			expect: "one of rules or rules_file must be set",
+		},
+		{
+			name:   "bad algorithm",
+			input:  `scheduler_config { scheduler_algorithm = "random" }`,
+			expect: `invalid scheduler_algorithm "random"`,
+		},
+		{
+			name:   "bad duration",
+			input:  `autopilot_config { last_contact_threshold = "soon" }`,
+			expect: "invalid last_contact_threshold",
+		},
+		{
+			name: "duplicate",
+			input: `
+var "foo" { items = { a = "b" } }
+var "foo" { items = { a = "c" } }
+`,
+			expect: `var "foo" (namespace "default") is already defined at`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeApplyFiles(t, map[string]string{"cluster.hcl": tc.input})
+			_, err := parseApplyDir(dir)
+			must.Error(t, err)
+			must.StrContains(t, err.Error(), tc.expect)
+		})
+	}
+}
+
+func TestApplyCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, _, err := client.Variables().Create(&api.Variable{
+		Namespace: api.DefaultNamespace,
+		Path:      "nomad/jobs/old",
+		Items:     api.VariableItems{"foo": "bar"},
+	}, nil)
+	must.NoError(t, err)
+
+	dir := writeApplyFiles(t, map[string]string{
+		"cluster.hcl": `
+namespace "prod" {
+  description = "Production"
+}
+
+scheduler_config {
+  memory_oversubscription_enabled = true
+}
+
+var "nomad/jobs/web" {
+  namespace = "prod"
+  items = {
+    db_host = "db.example.com"
+  }
+}
+
+var "nomad/jobs/api" {
+  items = {
+    port = "8080"
+  }
+}
+`,
+	})
+
+	// A dry run only prints the plan
+	ui := cli.NewMockUi()
+	cmd := &ApplyCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-dir=" + dir, "-prune", "-dry-run"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, `+ namespace "prod"`)
+	must.StrContains(t, out, "~ scheduler_config (memory_oversubscription_enabled)")
+	must.StrContains(t, out, `+ var "nomad/jobs/web" (namespace "prod")`)
+	must.StrContains(t, out, `- var "nomad/jobs/old" (namespace "default")`)
+	must.StrContains(t, out, "3 to create, 1 to update, 1 to delete.")
+
+	ns, _, err := client.Namespaces().Info("prod", nil)
+	must.Error(t, err)
+	must.Nil(t, ns)
+
+	// Apply the plan
+	ui = cli.NewMockUi()
+	cmd = &ApplyCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "-dir=" + dir, "-prune", "-yes"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), `Deleted var "nomad/jobs/old" (namespace "default")`)
+
+	ns, _, err = client.Namespaces().Info("prod", nil)
+	must.NoError(t, err)
+	must.Eq(t, "Production", ns.Description)
+
+	schedConfig, _, err := client.Operator().SchedulerGetConfiguration(nil)
+	must.NoError(t, err)
+	must.True(t, schedConfig.SchedulerConfig.MemoryOversubscriptionEnabled)
+
+	v, _, err := client.Variables().Read("nomad/jobs/web", &api.QueryOptions{Namespace: "prod"})
+	must.NoError(t, err)
+	must.Eq(t, "db.example.com", v.Items["db_host"])
+
+	_, _, err = client.Variables().Read("nomad/jobs/old", nil)
+	must.Error(t, err)
+	must.StrContains(t, err.Error(), api.ErrVariableNotFound)
+
+	// Applying again is a no-op
+	ui = cli.NewMockUi()
+	cmd = &ApplyCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "-dir=" + dir, "-prune"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), "No changes.")
+}
@@ -234,6 +234,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"apply": func() (cli.Command, error) {
+			return &ApplyCommand{
+				Meta: meta,
+			}, nil
+		},
 		"check": func() (cli.Command, error) {
 			return &AgentCheckCommand{
 				Meta: meta,
@@ -305,6 +310,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"eval explain": func() (cli.Command, error) {
+			return &EvalExplainCommand{
+				Meta: meta,
+			}, nil
+		},
 		"eval list": func() (cli.Command, error) {
 			return &EvalListCommand{
 				Meta: meta,
@@ -360,6 +370,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job diff": func() (cli.Command, error) {
+			return &JobDiffCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job dispatch": func() (cli.Command, error) {
 			return &JobDispatchCommand{
 				Meta: meta,
@@ -385,6 +400,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job lint": func() (cli.Command, error) {
+			return &JobLintCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job periodic": func() (cli.Command, error) {
 			return &JobPeriodicCommand{
 				Meta: meta,
@@ -435,6 +455,31 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job template": func() (cli.Command, error) {
+			return &JobTemplateCommand{
+				Meta: meta,
+			}, nil
+		},
+		"job template delete": func() (cli.Command, error) {
+			return &JobTemplateDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"job template list": func() (cli.Command, error) {
+			return &JobTemplateListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"job template register": func() (cli.Command, error) {
+			return &JobTemplateRegisterCommand{
+				Meta: meta,
+			}, nil
+		},
+		"job template run": func() (cli.Command, error) {
+			return &JobTemplateRunCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job validate": func() (cli.Command, error) {
 			return &JobValidateCommand{
 				Meta: meta,
@@ -946,6 +991,16 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"var history": func() (cli.Command, error) {
+			return &VarHistoryCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var rollback": func() (cli.Command, error) {
+			return &VarRollbackCommand{
+				Meta: meta,
+			}, nil
+		},
 		"var init": func() (cli.Command, error) {
 			return &VarInitCommand{
 				Meta: meta,
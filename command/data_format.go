@@ -2,11 +2,17 @@ package command
 
 import (
 	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"text/template"
 
 	"github.com/hashicorp/go-msgpack/codec"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -14,6 +20,18 @@ var (
 		HTMLCharsAsIs: true,
 		Indent:        4,
 	}
+
+	// jsonHandleCanonical is used for the results of -query, whose objects
+	// are maps that would otherwise be output in a random order.
+	jsonHandleCanonical = &codec.JsonHandle{
+		HTMLCharsAsIs: true,
+		Indent:        4,
+		BasicHandle: codec.BasicHandle{
+			EncodeOptions: codec.EncodeOptions{
+				Canonical: true,
+			},
+		},
+	}
 )
 
 // DataFormatter is a transformer of the data.
@@ -32,17 +50,34 @@ func DataFormat(format, tmpl string) (DataFormatter, error) {
 		return &JSONFormat{}, nil
 	case "template":
 		return &TemplateFormat{tmpl}, nil
+	case "yaml":
+		if len(tmpl) > 0 {
+			return nil, fmt.Errorf("yaml format does not support template option.")
+		}
+		return &YAMLFormat{}, nil
+	case "csv":
+		if len(tmpl) > 0 {
+			return nil, fmt.Errorf("csv format does not support template option.")
+		}
+		return &CSVFormat{}, nil
 	}
 	return nil, fmt.Errorf("Unsupported format is specified.")
 }
 
 type JSONFormat struct {
+	// Canonical sorts the keys of maps.
+	Canonical bool
 }
 
 // TransformData returns JSON format string data.
 func (p *JSONFormat) TransformData(data interface{}) (string, error) {
+	handle := jsonHandlePretty
+	if p.Canonical {
+		handle = jsonHandleCanonical
+	}
+
 	var buf bytes.Buffer
-	enc := codec.NewEncoder(&buf, jsonHandlePretty)
+	enc := codec.NewEncoder(&buf, handle)
 	err := enc.Encode(data)
 	if err != nil {
 		return "", err
@@ -74,6 +109,292 @@ func (p *TemplateFormat) TransformData(data interface{}) (string, error) {
 	return fmt.Sprint(out), nil
 }
 
+type YAMLFormat struct {
+}
+
+// TransformData returns YAML format string data.
+func (p *YAMLFormat) TransformData(data interface{}) (string, error) {
+	generic, err := toGeneric(data)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// CSVFormat outputs a row per element of a list, or a single row for any
+// other value. Columns are paths into each row, as accepted by -query.
+type CSVFormat struct {
+	Columns []string
+}
+
+// TransformData returns CSV format string data.
+func (p *CSVFormat) TransformData(data interface{}) (string, error) {
+	generic, err := toGeneric(data)
+	if err != nil {
+		return "", err
+	}
+
+	rows, ok := generic.([]interface{})
+	if !ok {
+		rows = []interface{}{generic}
+	}
+
+	columns := p.Columns
+	if len(columns) == 0 {
+		columns = csvColumns(rows)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return "", err
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			value := row
+			if column != csvValueColumn {
+				value, _ = queryData(row, column)
+			}
+			cell, err := csvCell(value)
+			if err != nil {
+				return "", err
+			}
+			record[i] = cell
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// csvValueColumn is the column of the rows that aren't objects.
+const csvValueColumn = "Value"
+
+// csvColumns returns the sorted keys of the rows, which are the default
+// columns of the CSV output.
+func csvColumns(rows []interface{}) []string {
+	keys := map[string]struct{}{}
+	for _, row := range rows {
+		m, ok := row.(map[string]interface{})
+		if !ok {
+			keys[csvValueColumn] = struct{}{}
+			continue
+		}
+		for k := range m {
+			keys[k] = struct{}{}
+		}
+	}
+
+	columns := make([]string, 0, len(keys))
+	for k := range keys {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// csvCell formats a value as a CSV cell. Objects and lists are formatted as
+// compact JSON.
+func csvCell(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// toGeneric converts the data into the maps, lists and scalars of its JSON
+// representation, so that it can be queried and formatted independently of
+// its Go type.
+func toGeneric(data interface{}) (interface{}, error) {
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, jsonHandlePretty).Encode(data); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(&buf)
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return normalizeNumbers(generic), nil
+}
+
+// normalizeNumbers replaces the json.Number values with integers or floats.
+func normalizeNumbers(data interface{}) interface{} {
+	switch v := data.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = normalizeNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalizeNumbers(e)
+		}
+	}
+	return data
+}
+
+// FormatOpts are the values of the flags selecting the machine-readable
+// output of a command.
+type FormatOpts struct {
+	// JSON is the -json flag, a shorthand for -format=json
+	JSON bool
+
+	// Template is the -t flag, a Go template to render the data with
+	Template string
+
+	// Format is the -format flag: json, yaml or csv
+	Format string
+
+	// Columns is the -columns flag, the comma-separated columns of the csv
+	// format
+	Columns string
+
+	// Query is the -query flag, a JSONPath-style expression selecting part
+	// of the data to output
+	Query string
+}
+
+// formatOptionsUsage is the help of the flags registered by
+// FormatOpts.SetFlags, other than -json and -t which are documented by each
+// command.
+const formatOptionsUsage = `-format <json|yaml|csv>
+    Output the data in the given format. The csv format outputs a row for
+    each element of a list.
+
+  -columns <path,...>
+    Comma-separated paths of the fields to output as columns with the csv
+    format. Defaults to all the top-level fields.
+
+  -query <path>
+    Output only the part of the data selected by a JSONPath-style expression,
+    such as "$.TaskGroups[0].Name" or "[*].ID". Defaults to the json format
+    when no other format is selected.`
+
+// SetFlags registers the -json, -t, -format, -columns and -query flags.
+func (o *FormatOpts) SetFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&o.JSON, "json", false, "")
+	flags.StringVar(&o.Template, "t", "", "")
+	flags.StringVar(&o.Format, "format", "", "")
+	flags.StringVar(&o.Columns, "columns", "", "")
+	flags.StringVar(&o.Query, "query", "", "")
+}
+
+// Enabled returns whether any of the flags selecting a machine-readable
+// output was set.
+func (o *FormatOpts) Enabled() bool {
+	return o.JSON || o.Template != "" || o.Format != "" || o.Query != ""
+}
+
+// Validate returns an error if the flags select conflicting formats, so
+// commands can report it before querying the API.
+func (o *FormatOpts) Validate() error {
+	_, err := o.format()
+	return err
+}
+
+// format returns the name of the format selected by the flags.
+func (o *FormatOpts) format() (string, error) {
+	format := o.Format
+	switch {
+	case o.JSON && o.Template != "":
+		return "", fmt.Errorf("Both json and template formatting are not allowed")
+	case o.JSON:
+		if format != "" && format != "json" {
+			return "", fmt.Errorf("Both json and %s formatting are not allowed", format)
+		}
+		format = "json"
+	case o.Template != "":
+		if format != "" {
+			return "", fmt.Errorf("Both %s and template formatting are not allowed", format)
+		}
+		format = "template"
+	case format == "" && o.Query != "":
+		format = "json"
+	case format == "":
+		return "", fmt.Errorf("no formatting option given")
+	}
+
+	if o.Columns != "" && format != "csv" {
+		return "", fmt.Errorf("The -columns flag is only valid with the csv format")
+	}
+	return format, nil
+}
+
+// Output formats the data according to the flags.
+func (o *FormatOpts) Output(data interface{}) (string, error) {
+	format, err := o.format()
+	if err != nil {
+		return "", err
+	}
+
+	f, err := DataFormat(format, o.Template)
+	if err != nil {
+		return "", err
+	}
+	if csvFormat, ok := f.(*CSVFormat); ok && o.Columns != "" {
+		for _, column := range strings.Split(o.Columns, ",") {
+			csvFormat.Columns = append(csvFormat.Columns, strings.TrimSpace(column))
+		}
+	}
+
+	if o.Query != "" {
+		generic, err := toGeneric(data)
+		if err != nil {
+			return "", fmt.Errorf("Error formatting the data: %s", err)
+		}
+		data, err = queryData(generic, o.Query)
+		if err != nil {
+			return "", err
+		}
+		if jsonFormat, ok := f.(*JSONFormat); ok {
+			jsonFormat.Canonical = true
+		}
+	}
+
+	out, err := f.TransformData(data)
+	if err != nil {
+		return "", fmt.Errorf("Error formatting the data: %s", err)
+	}
+
+	return out, nil
+}
+
 func Format(json bool, template string, data interface{}) (string, error) {
 	var format string
 	if json && len(template) > 0 {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
 )
 
 type testData struct {
@@ -66,3 +67,125 @@ func TestInvalidJSONTemplate(t *testing.T) {
 		t.Fatalf("expected not specified template error, got: %s", err.Error())
 	}
 }
+
+type testNestedData struct {
+	ID    string
+	Count int
+	Tags  []string
+	Meta  map[string]string
+}
+
+var tNestedData = []testNestedData{
+	{ID: "1", Count: 2, Tags: []string{"a", "b"}, Meta: map[string]string{"owner": "team-a"}},
+	{ID: "2", Count: 3, Meta: map[string]string{"owner": "team-b"}},
+}
+
+func TestYAMLFormat(t *testing.T) {
+	ci.Parallel(t)
+
+	out, err := (&YAMLFormat{}).TransformData(tData)
+	must.NoError(t, err)
+	must.Eq(t, "ID: \"1\"\nName: example\nRegion: global", out)
+
+	out, err = (&YAMLFormat{}).TransformData(tNestedData[:1])
+	must.NoError(t, err)
+	must.Eq(t, `- Count: 2
+  ID: "1"
+  Meta:
+    owner: team-a
+  Tags:
+    - a
+    - b`, out)
+}
+
+func TestCSVFormat(t *testing.T) {
+	ci.Parallel(t)
+
+	out, err := (&CSVFormat{}).TransformData(tNestedData)
+	must.NoError(t, err)
+	must.Eq(t, `Count,ID,Meta,Tags
+2,1,"{""owner"":""team-a""}","[""a"",""b""]"
+3,2,"{""owner"":""team-b""}",`, out)
+
+	out, err = (&CSVFormat{Columns: []string{"ID", "Meta.owner", "Tags[0]"}}).TransformData(tNestedData)
+	must.NoError(t, err)
+	must.Eq(t, "ID,Meta.owner,Tags[0]\n1,team-a,a\n2,team-b,", out)
+
+	// Single values are output as a single row
+	out, err = (&CSVFormat{}).TransformData(tData)
+	must.NoError(t, err)
+	must.Eq(t, "ID,Name,Region\n1,example,global", out)
+}
+
+func TestQueryData(t *testing.T) {
+	ci.Parallel(t)
+
+	data, err := toGeneric(tNestedData)
+	must.NoError(t, err)
+
+	cases := []struct {
+		query  string
+		expect interface{}
+		err    string
+	}{
+		{query: "$[0].ID", expect: "1"},
+		{query: "[1].Count", expect: int64(3)},
+		{query: "[-1].Meta.owner", expect: "team-b"},
+		{query: "[*].ID", expect: []interface{}{"1", "2"}},
+		{query: "$[*].Tags[0]", expect: []interface{}{"a"}},
+		{query: "[0].Meta['owner']", expect: "team-a"},
+		{query: "[0].Meta.*", expect: []interface{}{"team-a"}},
+		{query: "[0].Nope", err: `field "Nope" not found`},
+		{query: "[2]", err: "index 2 out of range"},
+		{query: "[0", err: "missing closing bracket"},
+		{query: "[x]", err: `invalid index "x"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			out, err := queryData(data, tc.query)
+			if tc.err != "" {
+				must.Error(t, err)
+				must.StrContains(t, err.Error(), tc.err)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.expect, out)
+		})
+	}
+}
+
+func TestFormatOpts_Output(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		opts   FormatOpts
+		expect string
+		err    string
+	}{
+		{name: "json", opts: FormatOpts{JSON: true}, expect: expectJSON},
+		{name: "format json", opts: FormatOpts{Format: "json"}, expect: expectJSON},
+		{name: "template", opts: FormatOpts{Template: "{{.Region}}"}, expect: "global"},
+		{name: "yaml", opts: FormatOpts{Format: "yaml", Query: "$.Name"}, expect: "example"},
+		{name: "csv", opts: FormatOpts{Format: "csv", Columns: "Name, Region"}, expect: "Name,Region\nexample,global"},
+		{name: "query", opts: FormatOpts{Query: "Region"}, expect: `"global"`},
+		{name: "query template", opts: FormatOpts{Template: "{{.}}", Query: "ID"}, expect: "1"},
+		{name: "json and template", opts: FormatOpts{JSON: true, Template: "{{.}}"}, err: "Both json and template formatting are not allowed"},
+		{name: "json and yaml", opts: FormatOpts{JSON: true, Format: "yaml"}, err: "Both json and yaml formatting are not allowed"},
+		{name: "columns", opts: FormatOpts{Format: "yaml", Columns: "ID"}, err: "only valid with the csv format"},
+		{name: "unknown", opts: FormatOpts{Format: "xml"}, err: "Unsupported format is specified."},
+		{name: "none", opts: FormatOpts{}, err: "no formatting option given"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := tc.opts.Output(tData)
+			if tc.err != "" {
+				must.Error(t, err)
+				must.StrContains(t, err.Error(), tc.err)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.expect, out)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// queryStep is a step of a query: the field of an object, the element of a
+// list at an index, or all the elements of a list or object.
+type queryStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseQuery parses a JSONPath-style query, such as "$.TaskGroups[0].Name",
+// "TaskGroups[*].Name" or "[*].ID". The leading "$" is optional.
+func parseQuery(query string) ([]queryStep, error) {
+	q := strings.TrimSpace(query)
+	q = strings.TrimPrefix(q, "$")
+
+	var steps []queryStep
+	for len(q) > 0 {
+		switch q[0] {
+		case '.':
+			q = q[1:]
+			if len(q) > 0 && q[0] == '*' {
+				steps = append(steps, queryStep{wildcard: true})
+				q = q[1:]
+				continue
+			}
+			fallthrough
+		default:
+			end := strings.IndexAny(q, ".[")
+			if end == -1 {
+				end = len(q)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("Invalid query %q: expected a field name", query)
+			}
+			steps = append(steps, queryStep{field: q[:end]})
+			q = q[end:]
+		case '[':
+			end := strings.IndexByte(q, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("Invalid query %q: missing closing bracket", query)
+			}
+			inner := strings.TrimSpace(q[1:end])
+			q = q[end+1:]
+
+			switch {
+			case inner == "*":
+				steps = append(steps, queryStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, queryStep{field: inner[1 : len(inner)-1]})
+			default:
+				i, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid query %q: invalid index %q", query, inner)
+				}
+				steps = append(steps, queryStep{index: i, isIndex: true})
+			}
+		}
+	}
+	return steps, nil
+}
+
+// queryData returns the part of the data selected by the query. The data
+// must be made of the maps, lists and scalars returned by toGeneric. Once a
+// wildcard is applied, the result is the list of the values selected from
+// each element, skipping the elements that don't have them.
+func queryData(data interface{}, query string) (interface{}, error) {
+	steps, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	current := []interface{}{data}
+	multi := false
+	for _, step := range steps {
+		var next []interface{}
+		for _, value := range current {
+			selected, err := step.apply(value)
+			if err != nil {
+				if multi {
+					continue
+				}
+				return nil, fmt.Errorf("Error querying %q: %v", query, err)
+			}
+			next = append(next, selected...)
+		}
+		current = next
+		if step.wildcard {
+			multi = true
+		}
+	}
+
+	if multi {
+		if current == nil {
+			current = []interface{}{}
+		}
+		return current, nil
+	}
+	return current[0], nil
+}
+
+// apply returns the values selected by the step from the value.
+func (s queryStep) apply(value interface{}) ([]interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch {
+		case s.wildcard:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			values := make([]interface{}, 0, len(v))
+			for _, k := range keys {
+				values = append(values, v[k])
+			}
+			return values, nil
+		case s.isIndex:
+			return nil, fmt.Errorf("cannot index an object with [%d]", s.index)
+		}
+
+		e, ok := v[s.field]
+		if !ok {
+			return nil, fmt.Errorf("field %q not found", s.field)
+		}
+		return []interface{}{e}, nil
+
+	case []interface{}:
+		switch {
+		case s.wildcard:
+			return v, nil
+		case s.isIndex:
+			i := s.index
+			if i < 0 {
+				i += len(v)
+			}
+			if i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("index %d out of range", s.index)
+			}
+			return []interface{}{v[i]}, nil
+		}
+		return nil, fmt.Errorf("cannot select field %q of a list", s.field)
+	}
+
+	if s.wildcard || s.isIndex {
+		return nil, fmt.Errorf("cannot index a %T", value)
+	}
+	return nil, fmt.Errorf("cannot select field %q of a %T", s.field, value)
+}
@@ -36,6 +36,8 @@ List Options:
   -t
     Format and display the deployments using a Go template.
 
+  ` + formatOptionsUsage + `
+
   -verbose
     Display full information.
 `
@@ -48,6 +50,9 @@ func (c *DeploymentListCommand) AutocompleteFlags() complete.Flags {
 			"-json":    complete.PredictNothing,
 			"-filter":  complete.PredictAnything,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 			"-verbose": complete.PredictNothing,
 		})
 }
@@ -63,15 +68,15 @@ func (c *DeploymentListCommand) Synopsis() string {
 func (c *DeploymentListCommand) Name() string { return "deployment list" }
 
 func (c *DeploymentListCommand) Run(args []string) int {
-	var json, verbose bool
-	var filter, tmpl string
+	var verbose bool
+	var filter string
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&filter, "filter", "", "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -107,8 +112,8 @@ func (c *DeploymentListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploys)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(deploys)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -50,6 +50,8 @@ Status Options:
 
   -t
     Format and display deployment using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -65,6 +67,9 @@ func (c *DeploymentStatusCommand) AutocompleteFlags() complete.Flags {
 			"-json":    complete.PredictNothing,
 			"-monitor": complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -86,22 +91,21 @@ func (c *DeploymentStatusCommand) AutocompleteArgs() complete.Predictor {
 func (c *DeploymentStatusCommand) Name() string { return "deployment status" }
 
 func (c *DeploymentStatusCommand) Run(args []string) int {
-	var json, verbose, monitor bool
-	var tmpl string
+	var verbose, monitor bool
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.BoolVar(&json, "json", false, "")
 	flags.BoolVar(&monitor, "monitor", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
 	// Check that json or tmpl isn't set with monitor
-	if monitor && (json || len(tmpl) > 0) {
+	if monitor && formatOpts.Enabled() {
 		c.Ui.Error("The monitor flag cannot be used with the '-json' or '-t' flags")
 		return 1
 	}
@@ -152,8 +156,8 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploy)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(deploy)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
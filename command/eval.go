@@ -27,6 +27,10 @@ Usage: nomad eval <subcommand> [options] [args]
 
       $ nomad eval status <eval-id>
 
+  Explain why an evaluation's task groups can not be placed:
+
+      $ nomad eval explain <eval-id>
+
   Delete evaluations:
 
       $ nomad eval delete <eval-id>
@@ -0,0 +1,267 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+// evalExplainDefaultNodes is the number of nodes displayed for each task group
+// unless the -verbose flag is set.
+const evalExplainDefaultNodes = 10
+
+type EvalExplainCommand struct {
+	Meta
+}
+
+func (c *EvalExplainCommand) Help() string {
+	helpText := `
+Usage: nomad eval explain [options] <evaluation>
+
+  Explain why the task groups of an evaluation can not be placed. Feasibility
+  checking is run again for each task group against the current state of the
+  cluster, without creating a plan, and the result is broken down per node:
+  which constraint, resource, device or port filtered the node out, and how
+  close the best nodes came to fitting the task group.
+
+  This command is most useful on blocked evaluations, which only retain the
+  placement metrics of their last scheduling attempt.
+
+  When ACLs are enabled, this command requires a token with the 'read-job'
+  capability for the evaluation's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Eval Explain Options:
+
+  -verbose
+    Show full IDs and every node instead of the closest ones.
+
+  -json
+    Output the explanation in its JSON format.
+
+  -t
+    Format and display the explanation using a Go template.
+
+  ` + formatOptionsUsage + `
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EvalExplainCommand) Synopsis() string {
+	return "Explain why an evaluation's task groups can not be placed"
+}
+
+func (c *EvalExplainCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *EvalExplainCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Evals, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Evals]
+	})
+}
+
+func (c *EvalExplainCommand) Name() string { return "eval explain" }
+
+func (c *EvalExplainCommand) Run(args []string) int {
+	var verbose bool
+	var formatOpts FormatOpts
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	formatOpts.SetFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one evaluation ID
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <evaluation>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	evalID := args[0]
+	if len(evalID) == 1 {
+		c.Ui.Error("Identifier must contain at least two characters.")
+		return 1
+	}
+
+	if formatOpts.Enabled() {
+		if err := formatOpts.Validate(); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	evalID = sanitizeUUIDPrefix(evalID)
+	evals, _, err := client.Evaluations().PrefixList(evalID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying evaluation: %v", err))
+		return 1
+	}
+	if len(evals) == 0 {
+		c.Ui.Error(fmt.Sprintf("No evaluation(s) with prefix or id %q found", evalID))
+		return 1
+	}
+	if len(evals) > 1 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple evaluations\n\n%s", formatEvalList(evals, verbose)))
+		return 1
+	}
+
+	explain, _, err := client.Evaluations().Explain(evals[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error explaining evaluation: %s", err))
+		return 1
+	}
+
+	// If output format is specified, format and output the data
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(explain)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Evaluation ID|%s", limit(explain.EvalID, length)),
+		fmt.Sprintf("Job ID|%s", explain.JobID),
+		fmt.Sprintf("Status|%s", evals[0].Status),
+	}))
+
+	for _, tg := range sortedTaskGroupFromExplanations(explain.TaskGroups) {
+		c.Ui.Output(c.formatTaskGroupExplanation(explain.TaskGroups[tg], verbose, length))
+	}
+
+	return 0
+}
+
+func (c *EvalExplainCommand) formatTaskGroupExplanation(tg *api.TaskGroupExplanation, verbose bool, length int) string {
+	var feasible, exhausted, filtered int
+	for _, node := range tg.Nodes {
+		switch {
+		case node.Feasible:
+			feasible++
+		case node.ExhaustedDimension != "":
+			exhausted++
+		default:
+			filtered++
+		}
+	}
+
+	out := c.Colorize().Color(fmt.Sprintf(
+		"\n[bold]Task Group %q (%d feasible, %d exhausted, %d filtered nodes)[reset]\n",
+		tg.TaskGroup, feasible, exhausted, filtered))
+
+	if len(tg.Nodes) == 0 {
+		out += "No ready nodes in the job's datacenters\n"
+		return strings.TrimSuffix(out, "\n")
+	}
+
+	best := tg.Nodes[0]
+	out += fmt.Sprintf("Best Node: %s (%s) %s\n",
+		limit(best.NodeID, length), best.NodeName, formatNodeExplanationResult(best))
+
+	if tg.Metrics != nil {
+		out += formatAllocMetrics(tg.Metrics, false, "  ") + "\n"
+	}
+
+	nodes := tg.Nodes
+	if !verbose && len(nodes) > evalExplainDefaultNodes {
+		nodes = nodes[:evalExplainDefaultNodes]
+	}
+
+	rows := make([]string, len(nodes)+1)
+	rows[0] = "Node ID|Node Name|Datacenter|Class|Result"
+	for i, node := range nodes {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s",
+			limit(node.NodeID, length),
+			node.NodeName,
+			node.Datacenter,
+			node.NodeClass,
+			formatNodeExplanationResult(node))
+	}
+	out += "\n" + formatList(rows)
+
+	if len(nodes) < len(tg.Nodes) {
+		out += fmt.Sprintf("\n%d more nodes not shown, use -verbose to show all nodes", len(tg.Nodes)-len(nodes))
+	}
+	return out
+}
+
+// formatNodeExplanationResult returns a short description of whether and why
+// a task group fits the node.
+func formatNodeExplanationResult(node *api.NodeExplanation) string {
+	switch {
+	case node.Feasible:
+		return fmt.Sprintf("feasible (score %.3g)", node.Score)
+	case node.ExhaustedDimension != "":
+		var short []string
+		if node.CPUShortfall > 0 {
+			short = append(short, fmt.Sprintf("%d MHz CPU", node.CPUShortfall))
+		}
+		if node.MemoryShortfall > 0 {
+			short = append(short, fmt.Sprintf("%d MB memory", node.MemoryShortfall))
+		}
+		if len(short) == 0 {
+			return fmt.Sprintf("exhausted %q", node.ExhaustedDimension)
+		}
+		return fmt.Sprintf("exhausted %q (short %s)", node.ExhaustedDimension, strings.Join(short, ", "))
+	default:
+		return fmt.Sprintf("filtered by %q", node.FilteredBy)
+	}
+}
+
+func sortedTaskGroupFromExplanations(groups map[string]*api.TaskGroupExplanation) []string {
+	tgs := make([]string, 0, len(groups))
+	for tg := range groups {
+		tgs = append(tgs, tg)
+	}
+	sort.Strings(tgs)
+	return tgs
+}
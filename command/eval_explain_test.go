@@ -0,0 +1,80 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestEvalExplainCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &EvalExplainCommand{}
+}
+
+func TestEvalExplainCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &EvalExplainCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on eval lookup failure
+	code = cmd.Run([]string{"-address=" + url, "3E55C771-76FC-423B-BCED-3E5314F433B1"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "No evaluation(s) with prefix or id")
+	ui.ErrorWriter.Reset()
+
+	// Fails on both -json and -t options are specified
+	code = cmd.Run([]string{"-address=" + url, "-json", "-t", "{{.EvalID}}",
+		"12345678-abcd-efab-cdef-123456789abc"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Both json and template formatting are not allowed")
+}
+
+func TestEvalExplainCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+
+	node := mock.Node()
+	node.Name = "too-small"
+	node.NodeResources.Memory.MemoryMB = 300
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	job := mock.Job()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	eval.Status = structs.EvalStatusBlocked
+	eval.FailedTGAllocs = map[string]*structs.AllocMetric{"web": {}}
+	must.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1002, []*structs.Evaluation{eval}))
+
+	ui := cli.NewMockUi()
+	cmd := &EvalExplainCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, eval.ID})
+	must.Zero(t, code)
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, `Task Group "web" (0 feasible, 1 exhausted, 0 filtered nodes)`)
+	must.StrContains(t, out, `too-small`)
+	must.StrContains(t, out, `exhausted "memory" (short 212 MB memory)`)
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-t", "{{.JobID}}", eval.ID})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), job.ID)
+}
@@ -49,6 +49,8 @@ Eval List Options:
 
   -t
     Format and display evaluation using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 
 	return strings.TrimSpace(helpText)
@@ -63,6 +65,9 @@ func (c *EvalListCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-json":       complete.PredictNothing,
 			"-t":          complete.PredictAnything,
+			"-format":     complete.PredictSet("json", "yaml", "csv"),
+			"-columns":    complete.PredictAnything,
+			"-query":      complete.PredictAnything,
 			"-verbose":    complete.PredictNothing,
 			"-filter":     complete.PredictAnything,
 			"-job":        complete.PredictAnything,
@@ -90,16 +95,16 @@ func (c *EvalListCommand) AutocompleteArgs() complete.Predictor {
 func (c *EvalListCommand) Name() string { return "eval list" }
 
 func (c *EvalListCommand) Run(args []string) int {
-	var monitor, verbose, json bool
+	var monitor, verbose bool
 	var perPage int
-	var tmpl, pageToken, filter, filterJobID, filterStatus string
+	var pageToken, filter, filterJobID, filterStatus string
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&monitor, "monitor", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 	flags.IntVar(&perPage, "per-page", 0, "")
 	flags.StringVar(&pageToken, "page-token", "", "")
 	flags.StringVar(&filter, "filter", "", "")
@@ -145,8 +150,8 @@ func (c *EvalListCommand) Run(args []string) int {
 
 	// If args not specified but output format is specified, format
 	// and output the evaluations data list
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, evals)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(evals)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -40,6 +40,8 @@ Eval Status Options:
 
   -t
     Format and display evaluation using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 
 	return strings.TrimSpace(helpText)
@@ -55,6 +57,9 @@ func (c *EvalStatusCommand) AutocompleteFlags() complete.Flags {
 			"-json":    complete.PredictNothing,
 			"-monitor": complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 			"-verbose": complete.PredictNothing,
 		})
 }
@@ -81,15 +86,14 @@ func (c *EvalStatusCommand) AutocompleteArgs() complete.Predictor {
 func (c *EvalStatusCommand) Name() string { return "eval status" }
 
 func (c *EvalStatusCommand) Run(args []string) int {
-	var monitor, verbose, json bool
-	var tmpl string
+	var monitor, verbose bool
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&monitor, "monitor", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -125,9 +129,11 @@ func (c *EvalStatusCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json && len(tmpl) > 0 {
-		c.Ui.Error("Both json and template formatting are not allowed")
-		return 1
+	if formatOpts.Enabled() {
+		if err := formatOpts.Validate(); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
 	}
 
 	evalID = sanitizeUUIDPrefix(evalID)
@@ -160,8 +166,8 @@ func (c *EvalStatusCommand) Run(args []string) int {
 	}
 
 	// If output format is specified, format and output the data
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, eval)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(eval)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -415,11 +415,11 @@ func (j *JobGetter) Validate() error {
 }
 
 // ApiJob returns the Job struct from jobfile.
-func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
+func (j *JobGetter) ApiJob(jpath string) (*api.JobSubmission, *api.Job, error) {
 	return j.ApiJobWithArgs(jpath, nil, nil, true)
 }
 
-func (j *JobGetter) ApiJobWithArgs(jpath string, vars []string, varfiles []string, strict bool) (*api.Job, error) {
+func (j *JobGetter) ApiJobWithArgs(jpath string, vars []string, varfiles []string, strict bool) (*api.JobSubmission, *api.Job, error) {
 	j.Vars = vars
 	j.VarFiles = varfiles
 	j.Strict = strict
@@ -427,7 +427,9 @@ func (j *JobGetter) ApiJobWithArgs(jpath string, vars []string, varfiles []strin
 	return j.Get(jpath)
 }
 
-func (j *JobGetter) Get(jpath string) (*api.Job, error) {
+// Get returns the job parsed from the jobfile, along with the submission
+// holding the source and variables it was parsed from.
+func (j *JobGetter) Get(jpath string) (*api.JobSubmission, *api.Job, error) {
 	var jobfile io.Reader
 	pathName := filepath.Base(jpath)
 	switch jpath {
@@ -440,23 +442,23 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 		pathName = "stdin"
 	default:
 		if len(jpath) == 0 {
-			return nil, fmt.Errorf("Error jobfile path has to be specified.")
+			return nil, nil, fmt.Errorf("Error jobfile path has to be specified.")
 		}
 
 		jobFile, err := os.CreateTemp("", "jobfile")
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		defer os.Remove(jobFile.Name())
 
 		if err := jobFile.Close(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// Get the pwd
 		pwd, err := os.Getwd()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		client := &gg.Client{
@@ -469,24 +471,40 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 		}
 
 		if err := client.Get(); err != nil {
-			return nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
+			return nil, nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
 		} else {
 			file, err := os.Open(jobFile.Name())
 			if err != nil {
-				return nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
+				return nil, nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
 			}
 			defer file.Close()
 			jobfile = file
 		}
+
+		// Parse local files with their path, so that the files they include
+		// are resolved from their directory
+		if info, err := os.Stat(jpath); err == nil && info.Mode().IsRegular() {
+			pathName = jpath
+		}
 	}
 
+	// Read the JobFile, so that its source can be submitted along the job
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, jobfile); err != nil {
+		return nil, nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
+	}
+	sub := &api.JobSubmission{Source: buf.String()}
+
 	// Parse the JobFile
 	var jobStruct *api.Job
 	var err error
 	switch {
 	case j.HCL1:
-		jobStruct, err = jobspec.Parse(jobfile)
+		sub.Format = api.JobSubmissionFormatHCL1
+		jobStruct, err = jobspec.Parse(&buf)
 	case j.JSON:
+		sub.Format = api.JobSubmissionFormatJSON
+
 		// Support JSON files with both a top-level Job key as well as
 		// ones without.
 		eitherJob := struct {
@@ -494,8 +512,8 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 			api.Job
 		}{}
 
-		if err := json.NewDecoder(jobfile).Decode(&eitherJob); err != nil {
-			return nil, fmt.Errorf("Failed to parse JSON job: %w", err)
+		if err := json.NewDecoder(&buf).Decode(&eitherJob); err != nil {
+			return nil, nil, fmt.Errorf("Failed to parse JSON job: %w", err)
 		}
 
 		if eitherJob.NestedJob != nil {
@@ -504,13 +522,13 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 			jobStruct = &eitherJob.Job
 		}
 	default:
-		var buf bytes.Buffer
-		_, err = io.Copy(&buf, jobfile)
-		if err != nil {
-			return nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
-		}
-		jobStruct, err = jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
-			Path:     pathName,
+		var parsed *api.JobSubmission
+		jobStruct, parsed, err = jobspec2.ParseWithSubmission(&jobspec2.ParseConfig{
+			Path: pathName,
+
+			// HCL functions such as file() resolve relative paths from the
+			// working directory
+			BaseDir:  ".",
 			Body:     buf.Bytes(),
 			ArgVars:  j.Vars,
 			AllowFS:  true,
@@ -518,19 +536,22 @@ func (j *JobGetter) Get(jpath string) (*api.Job, error) {
 			Envs:     os.Environ(),
 			Strict:   j.Strict,
 		})
+		if parsed != nil {
+			sub = parsed
+		}
 
 		if err != nil {
 			if _, merr := jobspec.Parse(&buf); merr == nil {
-				return nil, fmt.Errorf("Failed to parse using HCL 2. Use the HCL 1 parser with `nomad run -hcl1`, or address the following issues:\n%v", err)
+				return nil, nil, fmt.Errorf("Failed to parse using HCL 2. Use the HCL 1 parser with `nomad run -hcl1`, or address the following issues:\n%v", err)
 			}
 		}
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("Error parsing job file from %s:\n%v", jpath, err)
+		return nil, nil, fmt.Errorf("Error parsing job file from %s:\n%v", jpath, err)
 	}
 
-	return jobStruct, nil
+	return sub, jobStruct, nil
 }
 
 // mergeAutocompleteFlags is used to join multiple flag completion sets.
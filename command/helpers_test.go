@@ -269,7 +269,7 @@ func TestJobGetter_LocalFile(t *testing.T) {
 	}
 
 	j := &JobGetter{}
-	aj, err := j.ApiJob(fh.Name())
+	_, aj, err := j.ApiJob(fh.Name())
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
@@ -281,6 +281,26 @@ func TestJobGetter_LocalFile(t *testing.T) {
 	}
 }
 
+// TestJobGetter_LocalFile_Include asserts that included files are resolved
+// relative to the jobspec and not to the working directory
+func TestJobGetter_LocalFile_Include(t *testing.T) {
+	ci.Parallel(t)
+
+	j := &JobGetter{}
+	sub, aj, err := j.ApiJob("../jobspec2/test-fixtures/include/job.nomad.hcl")
+	require.NoError(t, err)
+
+	require.Len(t, aj.TaskGroups, 2)
+	require.Equal(t, "web", *aj.TaskGroups[0].Name)
+	require.Equal(t, "cache", *aj.TaskGroups[1].Name)
+
+	// The included files are submitted along the job
+	require.Equal(t, api.JobSubmissionFormatHCL2, sub.Format)
+	require.Len(t, sub.Includes, 2)
+	require.Contains(t, sub.Includes, "fragments/logging.nomad.hcl")
+	require.Contains(t, sub.Includes, "fragments/cache.nomad.hcl")
+}
+
 // TestJobGetter_LocalFile_InvalidHCL2 asserts that a custom message is emited
 // if the file is a valid HCL1 but not HCL2
 func TestJobGetter_LocalFile_InvalidHCL2(t *testing.T) {
@@ -316,7 +336,7 @@ func TestJobGetter_LocalFile_InvalidHCL2(t *testing.T) {
 			require.NoError(t, err)
 
 			j := &JobGetter{}
-			_, err = j.ApiJob(fh.Name())
+			_, _, err = j.ApiJob(fh.Name())
 			require.Error(t, err)
 
 			exptMessage := "Failed to parse using HCL 2. Use the HCL 1"
@@ -367,7 +387,7 @@ job "example" {
 	_, err = vf.WriteString(fileVars + "\n")
 	require.NoError(t, err)
 
-	j, err := (&JobGetter{}).ApiJobWithArgs(hclf.Name(), cliArgs, []string{vf.Name()}, true)
+	_, j, err := (&JobGetter{}).ApiJobWithArgs(hclf.Name(), cliArgs, []string{vf.Name()}, true)
 	require.NoError(t, err)
 
 	require.NotNil(t, j)
@@ -416,7 +436,7 @@ unsedVar2 = "from-varfile"
 	_, err = vf.WriteString(fileVars + "\n")
 	require.NoError(t, err)
 
-	j, err := (&JobGetter{}).ApiJobWithArgs(hclf.Name(), cliArgs, []string{vf.Name()}, false)
+	_, j, err := (&JobGetter{}).ApiJobWithArgs(hclf.Name(), cliArgs, []string{vf.Name()}, false)
 	require.NoError(t, err)
 
 	require.NotNil(t, j)
@@ -435,7 +455,7 @@ func TestJobGetter_HTTPServer(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	j := &JobGetter{}
-	aj, err := j.ApiJob("http://127.0.0.1:12345/")
+	_, aj, err := j.ApiJob("http://127.0.0.1:12345/")
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
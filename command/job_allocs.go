@@ -38,6 +38,8 @@ Allocs Options:
   -t
     Format and display allocations using a Go template.
 
+  ` + formatOptionsUsage + `
+
   -verbose
     Display full information.
 `
@@ -53,6 +55,9 @@ func (c *JobAllocsCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 			"-verbose": complete.PredictNothing,
 			"-all":     complete.PredictNothing,
 		})
@@ -76,15 +81,14 @@ func (c *JobAllocsCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobAllocsCommand) Name() string { return "job allocs" }
 
 func (c *JobAllocsCommand) Run(args []string) int {
-	var json, verbose, all bool
-	var tmpl string
+	var verbose, all bool
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&all, "all", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -133,8 +137,8 @@ func (c *JobAllocsCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, allocs)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(allocs)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -34,6 +34,8 @@ Deployments Options:
   -t
     Format and display deployments using a Go template.
 
+  ` + formatOptionsUsage + `
+
   -latest
     Display the latest deployment only.
 
@@ -56,6 +58,9 @@ func (c *JobDeploymentsCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 			"-latest":  complete.PredictNothing,
 			"-verbose": complete.PredictNothing,
 			"-all":     complete.PredictNothing,
@@ -80,16 +85,15 @@ func (c *JobDeploymentsCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobDeploymentsCommand) Name() string { return "job deployments" }
 
 func (c *JobDeploymentsCommand) Run(args []string) int {
-	var json, latest, verbose, all bool
-	var tmpl string
+	var latest, verbose, all bool
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&latest, "latest", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&all, "all", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -145,8 +149,8 @@ func (c *JobDeploymentsCommand) Run(args []string) int {
 			return 1
 		}
 
-		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, deploy)
+		if formatOpts.Enabled() {
+			out, err := formatOpts.Output(deploy)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -166,8 +170,8 @@ func (c *JobDeploymentsCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploys)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(deploys)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -0,0 +1,254 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/posener/complete"
+)
+
+type JobDiffCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *JobDiffCommand) Help() string {
+	helpText := `
+Usage: nomad job diff [options] <job>
+       nomad job diff [options] -file=<path> [<job>]
+
+  Diff is used to display the differences between two versions of a job, or
+  between a version of a job and a local jobspec.
+
+  Without the -file flag, the version given by -to is diffed from the version
+  given by -from. The -to version defaults to the latest version of the job,
+  and the -from version to the version before it.
+
+  With the -file flag, the jobspec at the path is diffed from the version given
+  by -from, which defaults to the latest version of the job. The jobspec goes
+  through the same defaults as the jobs being registered, so the diff only
+  shows the changes registering it would make. The job argument defaults to the
+  ID of the job of the jobspec.
+
+  When ACLs are enabled, this command requires a token with the 'read-job'
+  capability for the job's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Diff Options:
+
+  -from <version>
+    The version of the job to diff from.
+
+  -to <version>
+    The version of the job to diff to. Can't be used with -file.
+
+  -file <path>
+    The jobspec to diff to, or "-" to read it from stdin.
+
+  -hcl1
+    Parses the jobspec as HCLv1. Use this if the jobspec uses HCLv1.
+
+  -hcl2-strict
+    Whether an error should be produced from the HCL2 parser where a variable
+    has been supplied which is not defined within the root variables. Defaults
+    to true.
+
+  -var 'key=value'
+    Variable for the jobspec. This can be specified multiple times.
+
+  -var-file=path
+    Path to an HCL2 file containing user variables.
+
+  -verbose
+    Expand the added and deleted task groups and tasks of the diff.
+
+  -json
+    Output the diff in a JSON format.
+
+  -t
+    Format and display the diff using a Go template.
+
+  ` + formatOptionsUsage + `
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobDiffCommand) Synopsis() string {
+	return "Display the differences between versions of a job"
+}
+
+func (c *JobDiffCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-from":        complete.PredictAnything,
+			"-to":          complete.PredictAnything,
+			"-file":        complete.PredictOr(complete.PredictFiles("*.nomad"), complete.PredictFiles("*.hcl")),
+			"-hcl1":        complete.PredictNothing,
+			"-hcl2-strict": complete.PredictNothing,
+			"-var":         complete.PredictAnything,
+			"-var-file":    complete.PredictFiles("*.var"),
+			"-verbose":     complete.PredictNothing,
+			"-json":        complete.PredictNothing,
+			"-t":           complete.PredictAnything,
+			"-format":      complete.PredictSet("json", "yaml", "csv"),
+			"-columns":     complete.PredictAnything,
+			"-query":       complete.PredictAnything,
+		})
+}
+
+func (c *JobDiffCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobDiffCommand) Name() string { return "job diff" }
+
+func (c *JobDiffCommand) Run(args []string) int {
+	var verbose bool
+	var from, to int64
+	var file string
+	var formatOpts FormatOpts
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Int64Var(&from, "from", -1, "")
+	flags.Int64Var(&to, "to", -1, "")
+	flags.StringVar(&file, "file", "", "")
+	flags.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
+	flags.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
+	flags.Var(&c.JobGetter.Vars, "var", "")
+	flags.Var(&c.JobGetter.VarFiles, "var-file", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	formatOpts.SetFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got the job, which is optional with a file
+	args = flags.Args()
+	if l := len(args); l > 1 || (l == 0 && file == "") {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if file != "" && to >= 0 {
+		c.Ui.Error("The -to flag can't be used with -file")
+		return 1
+	}
+
+	var fromVersion, toVersion *uint64
+	if from >= 0 {
+		fromVersion = pointer.Of(uint64(from))
+	}
+	if to >= 0 {
+		toVersion = pointer.Of(uint64(to))
+	}
+
+	// Get the job from the jobspec
+	var job *api.Job
+	if file != "" {
+		if c.JobGetter.HCL1 {
+			c.JobGetter.Strict = false
+		}
+		if err := c.JobGetter.Validate(); err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid job options: %s", err))
+			return 1
+		}
+
+		var err error
+		_, job, err = c.JobGetter.Get(file)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+			return 1
+		}
+		if len(args) == 1 && args[0] != *job.ID {
+			c.Ui.Error(fmt.Sprintf("Job ID %q of the jobspec does not match %q", *job.ID, args[0]))
+			return 1
+		}
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var resp *api.JobDiffResponse
+	if job != nil {
+		// Force the region and the namespace to be those of the job
+		if r := job.Region; r != nil {
+			client.SetRegion(*r)
+		}
+		if n := job.Namespace; n != nil {
+			client.SetNamespace(*n)
+		}
+
+		resp, _, err = client.Jobs().DiffJob(job, fromVersion, nil)
+	} else {
+		jobID := strings.TrimSpace(args[0])
+		resp, _, err = client.Jobs().DiffVersions(jobID, fromVersion, toVersion, nil)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error diffing job: %s", err))
+		return 1
+	}
+
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(resp)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if resp.Warnings != "" {
+		c.Ui.Output(
+			c.Colorize().Color(fmt.Sprintf("[bold][yellow]Job Warnings:\n%s[reset]\n", resp.Warnings)))
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatJobDiffHeader(resp, file)))
+	if resp.Diff.Type == "None" {
+		c.Ui.Output("No differences")
+		return 0
+	}
+	c.Ui.Output(c.Colorize().Color(strings.TrimSpace(formatJobDiff(resp.Diff, verbose))))
+	return 0
+}
+
+// formatJobDiffHeader returns the unified diff header naming the versions or
+// the file the diff is between.
+func formatJobDiffHeader(resp *api.JobDiffResponse, file string) string {
+	from := "/dev/null"
+	if resp.FromVersion != nil {
+		from = fmt.Sprintf("Version %d", *resp.FromVersion)
+	}
+
+	to := file
+	if resp.ToVersion != nil {
+		to = fmt.Sprintf("Version %d", *resp.ToVersion)
+	}
+
+	return fmt.Sprintf("[bold]--- %s\n+++ %s[reset]", from, to)
+}
@@ -0,0 +1,158 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobDiffCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobDiffCommand{}
+}
+
+func TestJobDiffCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobDiffCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	must.One(t, cmd.Run([]string{"some", "bad", "args"}))
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	must.One(t, cmd.Run([]string{}))
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	must.One(t, cmd.Run([]string{"-file", "job.nomad", "-to", "3"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "-to flag can't be used with -file")
+	ui.ErrorWriter.Reset()
+
+	must.One(t, cmd.Run([]string{"-address=nope", "example"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "Error diffing job")
+	ui.ErrorWriter.Reset()
+}
+
+func TestJobDiffCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	// Register three versions of the job
+	job := testJob("job_diff")
+	for _, priority := range []int{50, 60, 70} {
+		job.Priority = pointer.Of(priority)
+		_, _, err := client.Jobs().Register(job, nil)
+		must.NoError(t, err)
+	}
+
+	ui := cli.NewMockUi()
+	cmd := &JobDiffCommand{Meta: Meta{Ui: ui}}
+
+	// The latest version is diffed from the one before it by default
+	code := cmd.Run([]string{"-address=" + url, "job_diff"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "--- Version 1\n+++ Version 2")
+	must.RegexMatch(t, regexp.MustCompile(`Priority: +"60" => "70"`), out)
+	ui.OutputWriter.Reset()
+
+	// Any two versions can be diffed
+	code = cmd.Run([]string{"-address=" + url, "-from", "2", "-to", "0", "job_diff"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	must.RegexMatch(t, regexp.MustCompile(`Priority: +"70" => "50"`), ui.OutputWriter.String())
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-from", "1", "-to", "1", "job_diff"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), "No differences")
+	ui.OutputWriter.Reset()
+
+	// The diff can be output as JSON
+	code = cmd.Run([]string{"-address=" + url, "-json", "-from", "0", "job_diff"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	var resp api.JobDiffResponse
+	must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &resp))
+	must.Eq(t, pointer.Of(uint64(0)), resp.FromVersion)
+	must.Eq(t, pointer.Of(uint64(2)), resp.ToVersion)
+	must.Eq(t, "Edited", resp.Diff.Type)
+	ui.OutputWriter.Reset()
+
+	// Missing versions are reported
+	must.One(t, cmd.Run([]string{"-address=" + url, "-from", "9", "job_diff"}))
+	must.StrContains(t, ui.ErrorWriter.String(), `job "job_diff" version 9 not found`)
+	ui.ErrorWriter.Reset()
+}
+
+func TestJobDiffCommand_File(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	path := filepath.Join(t.TempDir(), "job.nomad.hcl")
+	must.NoError(t, os.WriteFile(path, []byte(`
+variable "priority" {
+  default = 50
+}
+
+job "job_diff" {
+  type        = "service"
+  datacenters = ["dc1"]
+  priority    = var.priority
+
+  group "group1" {
+    count = 1
+
+    task "task1" {
+      driver = "exec"
+
+      config {
+        command = "/bin/sleep"
+      }
+
+      resources {
+        cpu    = 100
+        memory = 256
+      }
+    }
+  }
+}
+`), 0o644))
+
+	// Register the job from the jobspec with its default variables
+	_, job, err := (&JobGetter{}).ApiJobWithArgs(path, nil, nil, true)
+	must.NoError(t, err)
+	_, _, err = client.Jobs().Register(job, nil)
+	must.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &JobDiffCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "-file", path})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), "No differences")
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-file", path, "-var", "priority=80"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "--- Version 0\n+++ "+path)
+	must.RegexMatch(t, regexp.MustCompile(`Priority: +"50" => "80"`), out)
+	ui.OutputWriter.Reset()
+
+	// The job argument must match the jobspec
+	must.One(t, cmd.Run([]string{"-address=" + url, "-file", path, "other"}))
+	must.StrContains(t, ui.ErrorWriter.String(), `does not match "other"`)
+	ui.ErrorWriter.Reset()
+}
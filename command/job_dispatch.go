@@ -21,19 +21,22 @@ Usage: nomad job dispatch [options] <parameterized job> [input source]
 
   Dispatch creates an instance of a parameterized job. A data payload to the
   dispatched instance can be provided via stdin by using "-" or by specifying a
-  path to a file. Metadata can be supplied by using the meta flag one or more
-  times.
+  path to a file, or read from a variable with the -payload-variable flag.
+  Metadata can be supplied by using the meta flag one or more times.
 
   An optional idempotency token can be used to prevent more than one instance
-  of the job to be dispatched. If an instance with the same token already
-  exists, the command returns without any action.
+  of the job to be dispatched. If an instance was already dispatched with the
+  same token, the command prints its ID and returns without any action. Tokens
+  are remembered for the dispatch_idempotency_ttl of the servers, even once
+  the instance has been garbage collected.
 
   Upon successful creation, the dispatched job ID will be printed and the
   triggered evaluation will be monitored. This can be disabled by supplying the
   detach flag.
 
   When ACLs are enabled, this command requires a token with the 'dispatch-job'
-  capability for the job's namespace.
+  capability for the job's namespace. Reading the payload from a variable also
+  requires the 'read' capability for the variable.
 
 General Options:
 
@@ -60,6 +63,15 @@ Dispatch Options:
   -id-prefix-template
     Optional prefix template for dispatched job IDs.
 
+  -payload-variable <path>
+    Path of the variable to read the payload from, in the namespace of the
+    job. The payload is the JSON object of the items of the variable, unless
+    -payload-variable-item is set. Can't be used with an input source.
+
+  -payload-variable-item <key>
+    Item of the variable given by -payload-variable whose value is used as the
+    payload.
+
   -verbose
     Display full information.
 `
@@ -73,10 +85,13 @@ func (c *JobDispatchCommand) Synopsis() string {
 func (c *JobDispatchCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-meta":              complete.PredictAnything,
-			"-detach":            complete.PredictNothing,
-			"-idempotency-token": complete.PredictAnything,
-			"-verbose":           complete.PredictNothing,
+			"-meta":                  complete.PredictAnything,
+			"-detach":                complete.PredictNothing,
+			"-idempotency-token":     complete.PredictAnything,
+			"-id-prefix-template":    complete.PredictAnything,
+			"-payload-variable":      complete.PredictAnything,
+			"-payload-variable-item": complete.PredictAnything,
+			"-verbose":               complete.PredictNothing,
 		})
 }
 
@@ -111,6 +126,7 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	var idempotencyToken string
 	var meta []string
 	var idPrefixTemplate string
+	var payloadVariable, payloadVariableItem string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -119,6 +135,8 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	flags.StringVar(&idempotencyToken, "idempotency-token", "", "")
 	flags.Var((*flaghelper.StringFlag)(&meta), "meta", "")
 	flags.StringVar(&idPrefixTemplate, "id-prefix-template", "", "")
+	flags.StringVar(&payloadVariable, "payload-variable", "", "")
+	flags.StringVar(&payloadVariableItem, "payload-variable-item", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -138,6 +156,15 @@ func (c *JobDispatchCommand) Run(args []string) int {
 		return 1
 	}
 
+	if payloadVariable != "" && len(args) == 2 {
+		c.Ui.Error("The -payload-variable flag can't be used with an input source")
+		return 1
+	}
+	if payloadVariableItem != "" && payloadVariable == "" {
+		c.Ui.Error("The -payload-variable-item flag requires -payload-variable")
+		return 1
+	}
+
 	job := args[0]
 	var payload []byte
 	var readErr error
@@ -179,12 +206,29 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	w := &api.WriteOptions{
 		IdempotencyToken: idempotencyToken,
 	}
-	resp, _, err := client.Jobs().Dispatch(job, metaMap, payload, idPrefixTemplate, w)
+	opts := &api.DispatchOptions{
+		Meta:             metaMap,
+		Payload:          payload,
+		IdPrefixTemplate: idPrefixTemplate,
+	}
+	if payloadVariable != "" {
+		opts.PayloadVariable = &api.DispatchPayloadVariable{
+			Path: payloadVariable,
+			Item: payloadVariableItem,
+		}
+	}
+	resp, _, err := client.Jobs().DispatchOpts(job, opts, w)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to dispatch job: %s", err))
 		return 1
 	}
 
+	if resp.AlreadyDispatched {
+		c.Ui.Output(fmt.Sprintf("Job %q already dispatched with idempotency token %q.",
+			resp.DispatchedJobID, idempotencyToken))
+		return 0
+	}
+
 	// See if an evaluation was created. If the job is periodic there will be no
 	// eval.
 	evalCreated := resp.EvalID != ""
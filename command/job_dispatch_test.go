@@ -47,6 +47,47 @@ func TestJobDispatchCommand_Fails(t *testing.T) {
 		t.Fatalf("expected failed query error, got: %s", out)
 	}
 	ui.ErrorWriter.Reset()
+
+	// Fails when the payload is given twice
+	if code := cmd.Run([]string{"-payload-variable=foo", "foo", "-"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "can't be used with an input source") {
+		t.Fatalf("expected payload variable error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-payload-variable-item=bar", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "requires -payload-variable") {
+		t.Fatalf("expected payload variable item error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestJobDispatchCommand_IdempotencyToken(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	// Create a parameterized job
+	state := srv.Agent.Server().State()
+	j := mock.BatchJob()
+	j.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.Nil(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, j))
+
+	ui := cli.NewMockUi()
+	cmd := &JobDispatchCommand{Meta: Meta{Ui: ui}}
+	args := []string{"-address=" + url, "-detach", "-idempotency-token=prod", j.ID}
+	require.Equal(t, 0, cmd.Run(args), ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Dispatched Job ID")
+	ui.OutputWriter.Reset()
+
+	// Dispatching again with the same token returns the dispatched job
+	require.Equal(t, 0, cmd.Run(args), ui.ErrorWriter.String())
+	require.Regexp(t, `Job ".+" already dispatched with idempotency token "prod".`, ui.OutputWriter.String())
 }
 
 func TestJobDispatchCommand_AutocompleteArgs(t *testing.T) {
@@ -49,6 +49,8 @@ History Options:
 
   -t
     Format and display the job versions using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -65,6 +67,9 @@ func (c *JobHistoryCommand) AutocompleteFlags() complete.Flags {
 			"-version": complete.PredictAnything,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -86,16 +91,16 @@ func (c *JobHistoryCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobHistoryCommand) Name() string { return "job history" }
 
 func (c *JobHistoryCommand) Run(args []string) int {
-	var json, diff, full bool
-	var tmpl, versionStr string
+	var diff, full bool
+	var versionStr string
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&diff, "p", false, "")
 	flags.BoolVar(&full, "full", false, "")
-	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&versionStr, "version", "", "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -109,8 +114,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 		return 1
 	}
 
-	if (json || len(tmpl) != 0) && (diff || full) {
-		c.Ui.Error("-json and -t are exclusive with -p and -full")
+	if formatOpts.Enabled() && (diff || full) {
+		c.Ui.Error("-json, -t, -format and -query are exclusive with -p and -full")
 		return 1
 	}
 
@@ -178,8 +183,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 			}
 		}
 
-		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, job)
+		if formatOpts.Enabled() {
+			out, err := formatOpts.Output(job)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -195,8 +200,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 		}
 
 	} else {
-		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, versions)
+		if formatOpts.Enabled() {
+			out, err := formatOpts.Output(versions)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
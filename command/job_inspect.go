@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
@@ -32,11 +33,22 @@ Inspect Options:
   -version <job version>
     Display the job at the given job version.
 
+  -hcl
+    Display the jobspec the job was submitted from, in its original format.
+    Only available for jobs submitted with the Nomad CLI.
+
+  -vars
+    Display the HCL2 variable values the job was submitted with, as a
+    variable file. Values of sensitive variables are redacted. Only valid
+    with -hcl.
+
   -json
     Output the job in its JSON format.
 
   -t
     Format and display job using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -49,8 +61,13 @@ func (c *JobInspectCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-version": complete.PredictAnything,
+			"-hcl":     complete.PredictNothing,
+			"-vars":    complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -72,13 +89,15 @@ func (c *JobInspectCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobInspectCommand) Name() string { return "job inspect" }
 
 func (c *JobInspectCommand) Run(args []string) int {
-	var json bool
-	var tmpl, versionStr string
+	var hcl, vars bool
+	var versionStr string
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&hcl, "hcl", false, "")
+	flags.BoolVar(&vars, "vars", false, "")
+	formatOpts.SetFlags(flags)
 	flags.StringVar(&versionStr, "version", "", "")
 
 	if err := flags.Parse(args); err != nil {
@@ -86,6 +105,15 @@ func (c *JobInspectCommand) Run(args []string) int {
 	}
 	args = flags.Args()
 
+	if hcl && formatOpts.Enabled() {
+		c.Ui.Error("The -hcl flag cannot be used with -json, -t, -format or -query")
+		return 1
+	}
+	if vars && !hcl {
+		c.Ui.Error("The -vars flag is only valid with -hcl")
+		return 1
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -94,14 +122,14 @@ func (c *JobInspectCommand) Run(args []string) int {
 	}
 
 	// If args not specified but output format is specified, format and output the jobs data list
-	if len(args) == 0 && (json || len(tmpl) > 0) {
+	if len(args) == 0 && formatOpts.Enabled() {
 		jobs, _, err := client.Jobs().List(nil)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying jobs: %v", err))
 			return 1
 		}
 
-		out, err := Format(json, tmpl, jobs)
+		out, err := formatOpts.Output(jobs)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -154,9 +182,29 @@ func (c *JobInspectCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Print the jobspec the job was submitted from
+	if hcl {
+		var q *api.QueryOptions
+		if ns := jobs[0].JobSummary.Namespace; ns != "" {
+			q = &api.QueryOptions{Namespace: ns}
+		}
+		sub, _, err := client.Jobs().Submission(*job.ID, int(*job.Version), q)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving job submission: %s", err))
+			return 1
+		}
+
+		if vars {
+			c.Ui.Output(formatSubmissionVariables(sub))
+			return 0
+		}
+		c.Ui.Output(strings.TrimSpace(sub.Source))
+		return 0
+	}
+
 	// If output format is specified, format and output the data
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, job)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(job)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -212,3 +260,25 @@ func getJob(client *api.Client, namespace, jobID string, version *uint64) (*api.
 
 	return nil, fmt.Errorf("job %q with version %d couldn't be found", jobID, *version)
 }
+
+// formatSubmissionVariables formats the variables of the job submission as a
+// variable file. Sensitive variables are commented out, since their value
+// was not stored.
+func formatSubmissionVariables(sub *api.JobSubmission) string {
+	names := make([]string, 0, len(sub.Variables))
+	for name := range sub.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		value := sub.Variables[name]
+		if value == api.JobSubmissionSensitiveValue {
+			lines = append(lines, fmt.Sprintf("# %s = %s", name, value))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s = %s", name, value))
+	}
+	return strings.Join(lines, "\n")
+}
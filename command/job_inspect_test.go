@@ -4,11 +4,13 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,6 +63,45 @@ func TestInspectCommand_Fails(t *testing.T) {
 	}
 }
 
+func TestInspectCommand_HCL(t *testing.T) {
+	ci.Parallel(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	source := `variable "image" {}
+
+job "job1" {}
+`
+	_, _, err := client.Jobs().RegisterOpts(testJob("job1"), &api.RegisterOptions{
+		Submission: &api.JobSubmission{
+			Source: source,
+			Format: api.JobSubmissionFormatHCL2,
+			Variables: map[string]string{
+				"image":    `"redis:7"`,
+				"password": api.JobSubmissionSensitiveValue,
+			},
+		},
+	}, nil)
+	must.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &JobInspectCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "-hcl", "job1"})
+	must.Zero(t, code)
+	must.Eq(t, strings.TrimSpace(source), strings.TrimSpace(ui.OutputWriter.String()))
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-hcl", "-vars", "job1"})
+	must.Zero(t, code)
+	must.Eq(t, "image = \"redis:7\"\n# password = <sensitive>", strings.TrimSpace(ui.OutputWriter.String()))
+
+	// Fails when combined with another output format
+	code = cmd.Run([]string{"-address=" + url, "-hcl", "-json", "job1"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "cannot be used with -json, -t, -format or -query")
+}
+
 func TestInspectCommand_AutocompleteArgs(t *testing.T) {
 	ci.Parallel(t)
 	assert := assert.New(t)
@@ -0,0 +1,405 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type JobLintCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *JobLintCommand) Help() string {
+	helpText := `
+Usage: nomad job lint [options] <path>
+
+  Checks a job file against a set of rules reporting practices which are
+  valid but likely to cause problems, such as tasks without resources or
+  Docker images using the latest tag. Unlike "nomad job validate", the job is
+  only checked locally and no connection to a Nomad agent is needed.
+
+  If the supplied path is "-", the jobfile is read from stdin. Otherwise
+  it is read from the file at the supplied path or downloaded and
+  read from URL specified.
+
+  The built-in rules are:
+
+    missing-resources      Tasks without a resources block.
+    missing-update         Service groups without an update block.
+    docker-latest-tag      Docker images without a tag or with the latest tag.
+    raw-exec               Tasks using the raw_exec driver.
+    missing-health-checks  Service groups without health checks.
+    unsafe-network-mode    Tasks sharing the network namespace of the host.
+
+  Additional rules are defined in HCL files, with a condition which must be
+  true for the job, each group or each task:
+
+    rule "owner-meta" {
+      description = "Jobs must declare their owner"
+      severity    = "error"
+      scope       = "job"
+      condition   = can(job.Meta.owner)
+    }
+
+  The lint command exits with status 1 when a rule with the error severity
+  fails, or any rule fails with -strict.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Lint Options:
+
+  -rules=path
+    Path to an HCL file of additional rules. Can be used multiple times.
+
+  -disable=<rule,...>
+    Comma-separated list of the rules to skip.
+
+  -format=<text|json|sarif>
+    The format of the findings. The sarif format can be uploaded to code
+    scanning tools. Defaults to text.
+
+  -strict
+    Exit with status 1 when any rule fails, including warnings.
+
+  -json
+    Parses the job file as JSON. If the outer object has a Job field, such as
+    from "nomad job inspect" or "nomad run -output", the value of the field is
+    used as the job.
+
+  -hcl1
+    Parses the job file as HCLv1. Takes precedence over "-hcl2-strict".
+
+  -hcl2-strict
+    Whether an error should be produced from the HCL2 parser where a variable
+    has been supplied which is not defined within the root variables. Defaults
+    to true, but ignored if "-hcl1" is also defined.
+
+  -var 'key=value'
+    Variable for template, can be used multiple times.
+
+  -var-file=path
+    Path to HCL2 file containing user variables.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobLintCommand) Synopsis() string {
+	return "Check a job specification against lint rules"
+}
+
+func (c *JobLintCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-rules":       complete.PredictFiles("*.hcl"),
+		"-disable":     complete.PredictAnything,
+		"-format":      complete.PredictSet("text", "json", "sarif"),
+		"-strict":      complete.PredictNothing,
+		"-hcl1":        complete.PredictNothing,
+		"-hcl2-strict": complete.PredictNothing,
+		"-var":         complete.PredictAnything,
+		"-var-file":    complete.PredictFiles("*.var"),
+	}
+}
+
+func (c *JobLintCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictFiles("*.nomad"),
+		complete.PredictFiles("*.hcl"),
+		complete.PredictFiles("*.json"),
+	)
+}
+
+func (c *JobLintCommand) Name() string { return "job lint" }
+
+func (c *JobLintCommand) Run(args []string) int {
+	var rulesFiles flaghelper.StringFlag
+	var disable, format string
+	var strict bool
+
+	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
+	flagSet.Var(&rulesFiles, "rules", "")
+	flagSet.StringVar(&disable, "disable", "", "")
+	flagSet.StringVar(&format, "format", "text", "")
+	flagSet.BoolVar(&strict, "strict", false, "")
+	flagSet.BoolVar(&c.JobGetter.JSON, "json", false, "")
+	flagSet.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
+	flagSet.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
+	flagSet.Var(&c.JobGetter.Vars, "var", "")
+	flagSet.Var(&c.JobGetter.VarFiles, "var-file", "")
+
+	if err := flagSet.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job file
+	args = flagSet.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	switch format {
+	case "text", "json", "sarif":
+	default:
+		c.Ui.Error(fmt.Sprintf("Invalid format %q: must be one of text, json or sarif", format))
+		return 1
+	}
+
+	if c.JobGetter.HCL1 {
+		c.JobGetter.Strict = false
+	}
+
+	if err := c.JobGetter.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid job options: %s", err))
+		return 1
+	}
+
+	rules, err := lintRules(rulesFiles, disable)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading lint rules: %s", err))
+		return 1
+	}
+
+	// Get Job struct from Jobfile
+	_, job, err := c.JobGetter.Get(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+		return 1
+	}
+
+	findings, err := lintJob(job, rules)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error linting job: %s", err))
+		return 1
+	}
+
+	var out string
+	switch format {
+	case "json":
+		out, err = formatLintJSON(args[0], findings)
+	case "sarif":
+		out, err = formatLintSARIF(args[0], rules, findings)
+	default:
+		out = formatLintText(findings)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error formatting the findings: %s", err))
+		return 1
+	}
+	c.Ui.Output(out)
+
+	for _, f := range findings {
+		if strict || f.Severity == lintSeverityError {
+			return 1
+		}
+	}
+	return 0
+}
+
+// lintRules returns the built-in rules and the rules of the files, without
+// the disabled rules.
+func lintRules(files []string, disable string) ([]*lintRule, error) {
+	rules := append([]*lintRule{}, builtinLintRules...)
+	for _, path := range files {
+		fileRules, err := parseLintRules(path)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, fileRules...)
+	}
+
+	ids := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if ids[rule.ID] {
+			return nil, fmt.Errorf("duplicate rule %q", rule.ID)
+		}
+		ids[rule.ID] = true
+	}
+
+	disabled := map[string]bool{}
+	for _, id := range strings.Split(disable, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if !ids[id] {
+			return nil, fmt.Errorf("unknown rule %q", id)
+		}
+		disabled[id] = true
+	}
+
+	enabled := make([]*lintRule, 0, len(rules))
+	for _, rule := range rules {
+		if !disabled[rule.ID] {
+			enabled = append(enabled, rule)
+		}
+	}
+	return enabled, nil
+}
+
+// lintJob checks the job against the rules and returns the findings, sorted
+// by location.
+func lintJob(job *api.Job, rules []*lintRule) ([]*lintFinding, error) {
+	var findings []*lintFinding
+	for _, rule := range rules {
+		ruleFindings, err := rule.Check(job)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %v", rule.ID, err)
+		}
+		for _, f := range ruleFindings {
+			f.RuleID = rule.ID
+			f.Severity = rule.Severity
+		}
+		findings = append(findings, ruleFindings...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Group != findings[j].Group {
+			return findings[i].Group < findings[j].Group
+		}
+		return findings[i].Task < findings[j].Task
+	})
+	return findings, nil
+}
+
+func formatLintText(findings []*lintFinding) string {
+	if len(findings) == 0 {
+		return "No lint findings"
+	}
+
+	counts := map[string]int{}
+	lines := make([]string, 0, len(findings)+2)
+	for _, f := range findings {
+		counts[f.Severity]++
+		lines = append(lines, fmt.Sprintf("%s: %s: [%s] %s", f.Severity, f.location(), f.RuleID, f.Message))
+	}
+	lines = append(lines, "", fmt.Sprintf("%d error(s), %d warning(s), %d info",
+		counts[lintSeverityError], counts[lintSeverityWarning], counts[lintSeverityInfo]))
+	return strings.Join(lines, "\n")
+}
+
+func formatLintJSON(path string, findings []*lintFinding) (string, error) {
+	if findings == nil {
+		findings = []*lintFinding{}
+	}
+	out, err := json.MarshalIndent(struct {
+		Path     string
+		Findings []*lintFinding
+	}{path, findings}, "", "    ")
+	return string(out), err
+}
+
+// The SARIF 2.1.0 types used to report the findings.
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifLevel converts a severity to a SARIF level.
+func sarifLevel(severity string) string {
+	if severity == lintSeverityInfo {
+		return "note"
+	}
+	return severity
+}
+
+func formatLintSARIF(path string, rules []*lintRule, findings []*lintFinding) (string, error) {
+	driver := sarifDriver{
+		Name:           "nomad-job-lint",
+		InformationURI: "https://developer.hashicorp.com/nomad/docs/commands/job/lint",
+		Rules:          make([]sarifRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		r := sarifRule{ID: rule.ID, ShortDescription: sarifMessage{Text: rule.Description}}
+		r.DefaultConfiguration.Level = sarifLevel(rule.Severity)
+		driver.Rules = append(driver.Rules, r)
+	}
+
+	if path == "-" {
+		path = "stdin"
+	}
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		var loc sarifLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = path
+		if f.Group != "" {
+			name := f.Group
+			if f.Task != "" {
+				name += "." + f.Task
+			}
+			loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: name}}
+		}
+		results = append(results, sarifResult{
+			RuleID:    f.RuleID,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+
+	out, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}, "", "  ")
+	return string(out), err
+}
@@ -0,0 +1,383 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/jobspec2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+const (
+	lintSeverityError   = "error"
+	lintSeverityWarning = "warning"
+	lintSeverityInfo    = "info"
+
+	lintScopeJob   = "job"
+	lintScopeGroup = "group"
+	lintScopeTask  = "task"
+)
+
+// lintFinding is a problem of a job reported by a lint rule.
+type lintFinding struct {
+	RuleID   string
+	Severity string
+	Message  string
+	Group    string `json:",omitempty"`
+	Task     string `json:",omitempty"`
+}
+
+// location returns the part of the job the finding is about.
+func (f *lintFinding) location() string {
+	switch {
+	case f.Task != "":
+		return fmt.Sprintf("group %q task %q", f.Group, f.Task)
+	case f.Group != "":
+		return fmt.Sprintf("group %q", f.Group)
+	default:
+		return "job"
+	}
+}
+
+// lintRule is a check of a job, either built in or defined by the user.
+type lintRule struct {
+	ID          string
+	Description string
+	Severity    string
+
+	// Check returns the findings of the rule for the job. Errors are only
+	// returned for user-defined rules which can't be evaluated.
+	Check func(job *api.Job) ([]*lintFinding, error)
+}
+
+// builtinLintRules are the rules checked unless they are disabled.
+var builtinLintRules = []*lintRule{
+	{
+		ID:          "missing-resources",
+		Description: "Tasks should declare the resources they need.",
+		Severity:    lintSeverityWarning,
+		Check: forEachLintTask(func(job *api.Job, tg *api.TaskGroup, task *api.Task) string {
+			if task.Resources == nil {
+				return "task has no resources block, so the default resources are used"
+			}
+			return ""
+		}),
+	},
+	{
+		ID:          "missing-update",
+		Description: "Service jobs should declare an update block.",
+		Severity:    lintSeverityWarning,
+		Check: forEachLintGroup(func(job *api.Job, tg *api.TaskGroup) string {
+			if lintJobType(job) == api.JobTypeService && job.Update == nil && tg.Update == nil {
+				return "service group has no update block, so the default update strategy is used"
+			}
+			return ""
+		}),
+	},
+	{
+		ID:          "docker-latest-tag",
+		Description: "Docker images should be pinned to a tag other than latest.",
+		Severity:    lintSeverityWarning,
+		Check: forEachLintTask(func(job *api.Job, tg *api.TaskGroup, task *api.Task) string {
+			if task.Driver != "docker" {
+				return ""
+			}
+			image, ok := task.Config["image"].(string)
+			if !ok || strings.Contains(image, "${") {
+				return ""
+			}
+			if tag := dockerImageTag(image); tag == "" || tag == "latest" {
+				return fmt.Sprintf("image %q uses the latest tag", image)
+			}
+			return ""
+		}),
+	},
+	{
+		ID:          "raw-exec",
+		Description: "Tasks should not use the raw_exec driver, which runs without isolation.",
+		Severity:    lintSeverityWarning,
+		Check: forEachLintTask(func(job *api.Job, tg *api.TaskGroup, task *api.Task) string {
+			if task.Driver == "raw_exec" {
+				return "task uses the raw_exec driver, which runs without isolation"
+			}
+			return ""
+		}),
+	},
+	{
+		ID:          "missing-health-checks",
+		Description: "Service jobs should register services with health checks.",
+		Severity:    lintSeverityWarning,
+		Check: forEachLintGroup(func(job *api.Job, tg *api.TaskGroup) string {
+			if lintJobType(job) != api.JobTypeService {
+				return ""
+			}
+			services := tg.Services
+			for _, task := range tg.Tasks {
+				services = append(services, task.Services...)
+			}
+			for _, service := range services {
+				if len(service.Checks) > 0 {
+					return ""
+				}
+			}
+			return "service group has no health checks"
+		}),
+	},
+	{
+		ID:          "unsafe-network-mode",
+		Description: "Tasks should not share the network namespace of the host.",
+		Severity:    lintSeverityError,
+		Check: forEachLintTask(func(job *api.Job, tg *api.TaskGroup, task *api.Task) string {
+			if task.Driver != "docker" && task.Driver != "podman" {
+				return ""
+			}
+			if mode, ok := task.Config["network_mode"].(string); ok && mode == "host" {
+				return fmt.Sprintf("task uses the %q network mode, which shares the network namespace of the host", mode)
+			}
+			return ""
+		}),
+	},
+}
+
+// forEachLintGroup returns a check reporting the messages returned by fn for
+// the groups of the job.
+func forEachLintGroup(fn func(job *api.Job, tg *api.TaskGroup) string) func(*api.Job) ([]*lintFinding, error) {
+	return func(job *api.Job) ([]*lintFinding, error) {
+		var findings []*lintFinding
+		for _, tg := range job.TaskGroups {
+			if msg := fn(job, tg); msg != "" {
+				findings = append(findings, &lintFinding{Message: msg, Group: lintName(tg.Name)})
+			}
+		}
+		return findings, nil
+	}
+}
+
+// forEachLintTask returns a check reporting the messages returned by fn for
+// the tasks of the job.
+func forEachLintTask(fn func(job *api.Job, tg *api.TaskGroup, task *api.Task) string) func(*api.Job) ([]*lintFinding, error) {
+	return func(job *api.Job) ([]*lintFinding, error) {
+		var findings []*lintFinding
+		for _, tg := range job.TaskGroups {
+			for _, task := range tg.Tasks {
+				if msg := fn(job, tg, task); msg != "" {
+					findings = append(findings, &lintFinding{
+						Message: msg,
+						Group:   lintName(tg.Name),
+						Task:    task.Name,
+					})
+				}
+			}
+		}
+		return findings, nil
+	}
+}
+
+func lintJobType(job *api.Job) string {
+	if job.Type == nil || *job.Type == "" {
+		return api.JobTypeService
+	}
+	return *job.Type
+}
+
+func lintName(name *string) string {
+	if name == nil {
+		return ""
+	}
+	return *name
+}
+
+// dockerImageTag returns the tag of a Docker image reference, or "" when the
+// image has no tag. Images pinned by digest are reported as tagged.
+func dockerImageTag(image string) string {
+	if i := strings.Index(image, "@"); i != -1 {
+		return image[i+1:]
+	}
+	name := image
+	if i := strings.LastIndex(name, "/"); i != -1 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i != -1 {
+		return name[i+1:]
+	}
+	return ""
+}
+
+// lintRulesFile is a file of user-defined lint rules.
+type lintRulesFile struct {
+	Rules []*lintRuleConfig `hcl:"rule,block"`
+}
+
+// lintRuleConfig is a user-defined lint rule. The condition is evaluated for
+// the job, each group or each task depending on the scope, and the rule
+// reports a finding when it is false.
+type lintRuleConfig struct {
+	ID          string         `hcl:"id,label"`
+	Description string         `hcl:"description,optional"`
+	Severity    string         `hcl:"severity,optional"`
+	Scope       string         `hcl:"scope,optional"`
+	Condition   hcl.Expression `hcl:"condition"`
+	Message     hcl.Expression `hcl:"message,optional"`
+}
+
+// parseLintRules parses the user-defined rules of a file.
+func parseLintRules(path string) ([]*lintRule, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %v", err)
+	}
+
+	parser := hclparse.NewParser()
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(path, ".json") {
+		file, diags = parser.ParseJSON(src, path)
+	} else {
+		file, diags = parser.ParseHCL(src, path)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var config lintRulesFile
+	if diags := gohcl.DecodeBody(file.Body, nil, &config); diags.HasErrors() {
+		return nil, diags
+	}
+
+	functions := jobspec2.Functions(filepath.Dir(path), false)
+	rules := make([]*lintRule, 0, len(config.Rules))
+	for _, rc := range config.Rules {
+		if rc.Severity == "" {
+			rc.Severity = lintSeverityError
+		}
+		switch rc.Severity {
+		case lintSeverityError, lintSeverityWarning, lintSeverityInfo:
+		default:
+			return nil, fmt.Errorf("%s: rule %q: invalid severity %q", path, rc.ID, rc.Severity)
+		}
+		if rc.Scope == "" {
+			rc.Scope = lintScopeJob
+		}
+		switch rc.Scope {
+		case lintScopeJob, lintScopeGroup, lintScopeTask:
+		default:
+			return nil, fmt.Errorf("%s: rule %q: invalid scope %q", path, rc.ID, rc.Scope)
+		}
+
+		rules = append(rules, &lintRule{
+			ID:          rc.ID,
+			Description: rc.Description,
+			Severity:    rc.Severity,
+			Check:       rc.check(functions),
+		})
+	}
+
+	return rules, nil
+}
+
+// check returns the check of a user-defined rule. The job, group and task
+// are exposed to the expressions with the structure of their JSON encoding,
+// such as job.Meta or task.Config.image.
+func (rc *lintRuleConfig) check(functions map[string]function.Function) func(*api.Job) ([]*lintFinding, error) {
+	return func(job *api.Job) ([]*lintFinding, error) {
+		jobVal, err := lintValue(job)
+		if err != nil {
+			return nil, err
+		}
+
+		var findings []*lintFinding
+		evaluate := func(vars map[string]cty.Value, group, task string) error {
+			ctx := &hcl.EvalContext{Variables: vars, Functions: functions}
+			ok, err := rc.evalCondition(ctx)
+			if err != nil || ok {
+				return err
+			}
+			msg, err := rc.evalMessage(ctx)
+			if err != nil {
+				return err
+			}
+			findings = append(findings, &lintFinding{Message: msg, Group: group, Task: task})
+			return nil
+		}
+
+		if rc.Scope == lintScopeJob {
+			return findings, evaluate(map[string]cty.Value{"job": jobVal}, "", "")
+		}
+		for _, tg := range job.TaskGroups {
+			groupVal, err := lintValue(tg)
+			if err != nil {
+				return nil, err
+			}
+			if rc.Scope == lintScopeGroup {
+				vars := map[string]cty.Value{"job": jobVal, "group": groupVal}
+				if err := evaluate(vars, lintName(tg.Name), ""); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			for _, task := range tg.Tasks {
+				taskVal, err := lintValue(task)
+				if err != nil {
+					return nil, err
+				}
+				vars := map[string]cty.Value{"job": jobVal, "group": groupVal, "task": taskVal}
+				if err := evaluate(vars, lintName(tg.Name), task.Name); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return findings, nil
+	}
+}
+
+func (rc *lintRuleConfig) evalCondition(ctx *hcl.EvalContext) (bool, error) {
+	val, diags := rc.Condition.Value(ctx)
+	if diags.HasErrors() {
+		return false, diags
+	}
+	if val.IsNull() || !val.IsKnown() || val.Type() != cty.Bool {
+		return false, fmt.Errorf("%s: condition of rule %q must be a boolean",
+			rc.Condition.Range(), rc.ID)
+	}
+	return val.True(), nil
+}
+
+func (rc *lintRuleConfig) evalMessage(ctx *hcl.EvalContext) (string, error) {
+	val, diags := rc.Message.Value(ctx)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	switch {
+	case val.IsNull() && rc.Description != "":
+		return rc.Description, nil
+	case val.IsNull():
+		return "condition is false", nil
+	case !val.IsKnown() || val.Type() != cty.String:
+		return "", fmt.Errorf("%s: message of rule %q must be a string",
+			rc.Message.Range(), rc.ID)
+	}
+	return val.AsString(), nil
+}
+
+// lintValue converts an API object to a cty value with the structure of its
+// JSON encoding.
+func lintValue(v interface{}) (cty.Value, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	ty, err := ctyjson.ImpliedType(buf)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return ctyjson.Unmarshal(buf, ty)
+}
@@ -0,0 +1,325 @@
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+const testLintJob = `
+job "example" {
+  meta {
+    team = "web"
+  }
+
+  group "web" {
+    network {
+      port "http" {}
+    }
+
+    service {
+      name = "web"
+      port = "http"
+    }
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image        = "nginx"
+        network_mode = "host"
+      }
+    }
+
+    task "sidecar" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/true"
+      }
+
+      resources {
+        cpu = 100
+      }
+    }
+  }
+
+  group "cache" {
+    update {
+      max_parallel = 1
+    }
+
+    service {
+      name = "cache"
+
+      check {
+        type     = "tcp"
+        port     = 6379
+        interval = "10s"
+        timeout  = "2s"
+      }
+    }
+
+    task "redis" {
+      driver = "docker"
+
+      config {
+        image = "redis:7.0@sha256:d8f5a8c3"
+      }
+
+      resources {
+        cpu = 500
+      }
+    }
+  }
+}
+`
+
+const testLintRules = `
+rule "owner-meta" {
+  description = "Jobs must declare their owner"
+  condition   = can(job.Meta.owner)
+}
+
+rule "small-tasks" {
+  severity  = "info"
+  scope     = "task"
+  condition = try(task.Resources.CPU, 0) <= 200
+  message   = "task ${task.Name} uses more than 200 MHz"
+}
+`
+
+func writeLintFiles(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	jobPath := filepath.Join(dir, "example.nomad.hcl")
+	must.NoError(t, os.WriteFile(jobPath, []byte(testLintJob), 0o644))
+	rulesPath := filepath.Join(dir, "rules.hcl")
+	must.NoError(t, os.WriteFile(rulesPath, []byte(testLintRules), 0o644))
+	return jobPath, rulesPath
+}
+
+func TestJobLintCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobLintCommand{}
+}
+
+func TestJobLintCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	jobPath, _ := writeLintFiles(t)
+
+	cases := []struct {
+		name   string
+		args   []string
+		expect string
+	}{
+		{
+			name:   "bad args",
+			args:   []string{"some", "bad", "args"},
+			expect: commandErrorText(&JobLintCommand{}),
+		},
+		{
+			name:   "bad format",
+			args:   []string{"-format=xml", jobPath},
+			expect: `Invalid format "xml"`,
+		},
+		{
+			name:   "unknown rule",
+			args:   []string{"-disable=nope", jobPath},
+			expect: `unknown rule "nope"`,
+		},
+		{
+			name:   "missing rules file",
+			args:   []string{"-rules=/nonexistent.hcl", jobPath},
+			expect: "failed to read rules file",
+		},
+		{
+			name:   "missing job file",
+			args:   []string{"/nonexistent.nomad.hcl"},
+			expect: "Error getting job struct",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := &JobLintCommand{Meta: Meta{Ui: ui}}
+			must.One(t, cmd.Run(tc.args))
+			must.StrContains(t, ui.ErrorWriter.String(), tc.expect)
+		})
+	}
+}
+
+func TestJobLintCommand_Text(t *testing.T) {
+	ci.Parallel(t)
+	jobPath, rulesPath := writeLintFiles(t)
+
+	ui := cli.NewMockUi()
+	cmd := &JobLintCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-rules=" + rulesPath, jobPath})
+
+	// The unsafe network mode and the custom rule are errors
+	must.One(t, code)
+
+	out := ui.OutputWriter.String()
+	for _, expect := range []string{
+		`error: job: [owner-meta] Jobs must declare their owner`,
+		`info: group "cache" task "redis": [small-tasks] task redis uses more than 200 MHz`,
+		`warning: group "web": [missing-update] service group has no update block`,
+		`warning: group "web": [missing-health-checks] service group has no health checks`,
+		`warning: group "web" task "server": [missing-resources] task has no resources block`,
+		`warning: group "web" task "server": [docker-latest-tag] image "nginx" uses the latest tag`,
+		`error: group "web" task "server": [unsafe-network-mode] task uses the "host" network mode`,
+		`warning: group "web" task "sidecar": [raw-exec] task uses the raw_exec driver`,
+		"2 error(s), 5 warning(s), 1 info",
+	} {
+		must.StrContains(t, out, expect)
+	}
+	must.StrNotContains(t, out, `group "cache": [missing-update]`)
+	must.StrNotContains(t, out, `group "cache": [missing-health-checks]`)
+	must.StrNotContains(t, out, `task "redis": [docker-latest-tag]`)
+
+	// Warnings alone don't fail unless -strict is set
+	ui = cli.NewMockUi()
+	cmd = &JobLintCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-disable=unsafe-network-mode", jobPath})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrNotContains(t, ui.OutputWriter.String(), "unsafe-network-mode")
+
+	ui = cli.NewMockUi()
+	cmd = &JobLintCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-disable=unsafe-network-mode", "-strict", jobPath})
+	must.One(t, code)
+}
+
+func TestJobLintCommand_JSON(t *testing.T) {
+	ci.Parallel(t)
+	jobPath, _ := writeLintFiles(t)
+
+	ui := cli.NewMockUi()
+	cmd := &JobLintCommand{Meta: Meta{Ui: ui}}
+	disable := "-disable=missing-resources,missing-update,docker-latest-tag,missing-health-checks,unsafe-network-mode"
+	code := cmd.Run([]string{"-format=json", disable, jobPath})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+	var out struct {
+		Path     string
+		Findings []*lintFinding
+	}
+	must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+	must.Eq(t, jobPath, out.Path)
+	must.Eq(t, []*lintFinding{{
+		RuleID:   "raw-exec",
+		Severity: lintSeverityWarning,
+		Message:  "task uses the raw_exec driver, which runs without isolation",
+		Group:    "web",
+		Task:     "sidecar",
+	}}, out.Findings)
+}
+
+func TestJobLintCommand_SARIF(t *testing.T) {
+	ci.Parallel(t)
+	jobPath, rulesPath := writeLintFiles(t)
+
+	ui := cli.NewMockUi()
+	cmd := &JobLintCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-format=sarif", "-rules=" + rulesPath, jobPath})
+	must.One(t, code)
+
+	var out sarifLog
+	must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+	must.Eq(t, "2.1.0", out.Version)
+	must.Len(t, 1, out.Runs)
+
+	run := out.Runs[0]
+	must.Len(t, len(builtinLintRules)+2, run.Tool.Driver.Rules)
+	must.Len(t, 8, run.Results)
+
+	levels := map[string]string{}
+	for _, result := range run.Results {
+		levels[result.RuleID] = result.Level
+		must.Eq(t, jobPath, result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		if result.RuleID == "raw-exec" {
+			must.Eq(t, "web.sidecar", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+		}
+	}
+	must.Eq(t, "error", levels["owner-meta"])
+	must.Eq(t, "note", levels["small-tasks"])
+	must.Eq(t, "warning", levels["raw-exec"])
+}
+
+func TestJobLintCommand_RuleErrors(t *testing.T) {
+	ci.Parallel(t)
+	jobPath, _ := writeLintFiles(t)
+
+	cases := []struct {
+		name   string
+		rules  string
+		expect string
+	}{
+		{
+			name: "invalid severity",
+			rules: `
+rule "foo" {
+  severity  = "fatal"
+  condition = true
+}`,
+			expect: `invalid severity "fatal"`,
+		},
+		{
+			name: "invalid scope",
+			rules: `
+rule "foo" {
+  scope     = "node"
+  condition = true
+}`,
+			expect: `invalid scope "node"`,
+		},
+		{
+			name:   "duplicate",
+			rules:  `rule "raw-exec" { condition = true }`,
+			expect: `duplicate rule "raw-exec"`,
+		},
+		{
+			name:   "not a boolean",
+			rules:  `rule "foo" { condition = job.ID }`,
+			expect: `condition of rule "foo" must be a boolean`,
+		},
+		{
+			name:   "evaluation error",
+			rules:  `rule "foo" { condition = job.Meta.owner == "me" }`,
+			expect: `rule "foo"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rulesPath := filepath.Join(t.TempDir(), "rules.hcl")
+			must.NoError(t, os.WriteFile(rulesPath, []byte(tc.rules), 0o644))
+
+			ui := cli.NewMockUi()
+			cmd := &JobLintCommand{Meta: Meta{Ui: ui}}
+			must.One(t, cmd.Run([]string{"-rules=" + rulesPath, jobPath}))
+			must.StrContains(t, ui.ErrorWriter.String(), tc.expect)
+		})
+	}
+}
+
+func TestDockerImageTag(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := map[string]string{
+		"redis":                          "",
+		"redis:latest":                   "latest",
+		"redis:7.0":                      "7.0",
+		"localhost:5000/redis":           "",
+		"localhost:5000/redis:7.0":       "7.0",
+		"redis@sha256:d8f5a8c3":          "sha256:d8f5a8c3",
+		"ghcr.io/org/app:v1@sha256:abcd": "sha256:abcd",
+	}
+	for image, expect := range cases {
+		must.Eq(t, expect, dockerImageTag(image), must.Sprint(image))
+	}
+}
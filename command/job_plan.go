@@ -194,7 +194,7 @@ func (c *JobPlanCommand) Run(args []string) int {
 
 	path := args[0]
 	// Get Job struct from Jobfile
-	job, err := c.JobGetter.Get(path)
+	_, job, err := c.JobGetter.Get(path)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
 		return 255
@@ -332,9 +332,18 @@ func (c *JobPlanCommand) addPreemptions(resp *api.JobPlanResponse) {
 	c.Ui.Output(c.Colorize().Color("[bold][yellow]Preemptions:\n[reset]"))
 	if len(resp.Annotations.PreemptedAllocs) < preemptionDisplayThreshold {
 		var allocs []string
-		allocs = append(allocs, "Alloc ID|Job ID|Task Group")
-		for _, alloc := range resp.Annotations.PreemptedAllocs {
-			allocs = append(allocs, fmt.Sprintf("%s|%s|%s", alloc.ID, alloc.JobID, alloc.TaskGroup))
+		reasons := resp.Annotations.PreemptionReasons
+		if len(reasons) == 0 {
+			allocs = append(allocs, "Alloc ID|Job ID|Task Group")
+			for _, alloc := range resp.Annotations.PreemptedAllocs {
+				allocs = append(allocs, fmt.Sprintf("%s|%s|%s", alloc.ID, alloc.JobID, alloc.TaskGroup))
+			}
+		} else {
+			allocs = append(allocs, "Alloc ID|Job ID|Task Group|Node ID|Reason")
+			for _, alloc := range resp.Annotations.PreemptedAllocs {
+				allocs = append(allocs, fmt.Sprintf("%s|%s|%s|%s|%s",
+					alloc.ID, alloc.JobID, alloc.TaskGroup, limit(alloc.NodeID, shortId), reasons[alloc.ID]))
+			}
 		}
 		c.Ui.Output(formatList(allocs))
 		return
@@ -271,6 +271,18 @@ func TestPlanCommad_Preemptions(t *testing.T) {
 	require.Contains(out, "Alloc ID")
 	require.Contains(out, "alloc1")
 
+	// Preemption reasons are shown when the plan includes them
+	resp1.Annotations.PreemptedAllocs[0].NodeID = "8d3e4f4c-3a6b-4d33-9a0f-1e4b8d5f6a7c"
+	resp1.Annotations.PreemptionReasons = map[string]string{
+		"alloc1": `evicted for "example.cache[0]": memory exhausted, job priority 20 < 70`,
+	}
+	ui.OutputWriter.Reset()
+	cmd.addPreemptions(resp1)
+	out = ui.OutputWriter.String()
+	require.Contains(out, "Reason")
+	require.Contains(out, "8d3e4f4c")
+	require.Contains(out, "memory exhausted, job priority 20 < 70")
+
 	// Less than 10 unique job ids
 	var preemptedAllocs []*api.AllocationListStub
 	for i := 0; i < 12; i++ {
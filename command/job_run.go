@@ -49,6 +49,11 @@ Alias: nomad run
   exit code will be 2. Any other errors, including client connection
   issues or internal errors, are indicated by exit code 1.
 
+  With -output=json-lines, the progress of the job is reported as one JSON
+  event per line until the evaluation and the deployment of the job complete.
+  In this mode, a failed deployment is indicated by exit code 3 and reaching
+  the -timeout by exit code 4.
+
   If the job has specified the region, the -region flag and NOMAD_REGION
   environment variable are overridden and the job's region is used.
 
@@ -102,9 +107,12 @@ Run Options:
     has been supplied which is not defined within the root variables. Defaults
     to true, but ignored if "-hcl1" is also defined.
 
-  -output
+  -output[=json-lines]
     Output the JSON that would be submitted to the HTTP API without submitting
-    the job.
+    the job. If set to json-lines, the job is submitted and its progress is
+    reported as JSON events, one per line, with the types JobRegistered,
+    EvalStatus, PlacementFailed, AllocStatus, DeploymentStatus,
+    DeploymentGroup and Done.
 
   -policy-override
     Sets the flag to force override any soft mandatory Sentinel policies.
@@ -112,6 +120,10 @@ Run Options:
   -preserve-counts
     If set, the existing task group counts will be preserved when updating a job.
 
+  -timeout
+    The maximum time to wait for the job with -output=json-lines, such as
+    "10m". Defaults to waiting until the deployment is terminal.
+
   -consul-token
     If set, the passed Consul token is stored in the job before sending to the
     Nomad servers. This allows passing the Consul token without storing it in
@@ -159,7 +171,7 @@ func (c *JobRunCommand) AutocompleteFlags() complete.Flags {
 			"-consul-token":    complete.PredictNothing,
 			"-vault-token":     complete.PredictAnything,
 			"-vault-namespace": complete.PredictAnything,
-			"-output":          complete.PredictNothing,
+			"-output":          complete.PredictSet("json-lines"),
 			"-policy-override": complete.PredictNothing,
 			"-preserve-counts": complete.PredictNothing,
 			"-json":            complete.PredictNothing,
@@ -168,6 +180,7 @@ func (c *JobRunCommand) AutocompleteFlags() complete.Flags {
 			"-var":             complete.PredictAnything,
 			"-var-file":        complete.PredictFiles("*.var"),
 			"-eval-priority":   complete.PredictNothing,
+			"-timeout":         complete.PredictAnything,
 		})
 }
 
@@ -182,7 +195,9 @@ func (c *JobRunCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobRunCommand) Name() string { return "job run" }
 
 func (c *JobRunCommand) Run(args []string) int {
-	var detach, verbose, output, override, preserveCounts bool
+	var detach, verbose, override, preserveCounts bool
+	var output runOutputFlag
+	var timeout time.Duration
 	var checkIndexStr, consulToken, consulNamespace, vaultToken, vaultNamespace string
 	var evalPriority int
 
@@ -190,7 +205,8 @@ func (c *JobRunCommand) Run(args []string) int {
 	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
 	flagSet.BoolVar(&detach, "detach", false, "")
 	flagSet.BoolVar(&verbose, "verbose", false, "")
-	flagSet.BoolVar(&output, "output", false, "")
+	flagSet.Var(&output, "output", "")
+	flagSet.DurationVar(&timeout, "timeout", 0, "")
 	flagSet.BoolVar(&override, "policy-override", false, "")
 	flagSet.BoolVar(&preserveCounts, "preserve-counts", false, "")
 	flagSet.BoolVar(&c.JobGetter.JSON, "json", false, "")
@@ -233,7 +249,7 @@ func (c *JobRunCommand) Run(args []string) int {
 	}
 
 	// Get Job struct from Jobfile
-	job, err := c.JobGetter.Get(args[0])
+	sub, job, err := c.JobGetter.Get(args[0])
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
 		return 1
@@ -289,7 +305,7 @@ func (c *JobRunCommand) Run(args []string) int {
 		job.VaultNamespace = pointer.Of(vaultNamespace)
 	}
 
-	if output {
+	if output == runOutputJSON {
 		req := struct {
 			Job *api.Job
 		}{
@@ -317,6 +333,7 @@ func (c *JobRunCommand) Run(args []string) int {
 		PolicyOverride: override,
 		PreserveCounts: preserveCounts,
 		EvalPriority:   evalPriority,
+		Submission:     sub,
 	}
 	if enforce {
 		opts.EnforceIndex = true
@@ -341,14 +358,28 @@ func (c *JobRunCommand) Run(args []string) int {
 		return 1
 	}
 
+	evalID := resp.EvalID
+
+	if output == runOutputJSONLines {
+		mon := newRunEventMonitor(c.Ui, client, timeout)
+		mon.emit(&runEvent{
+			Type:     runEventJobRegistered,
+			JobID:    *job.ID,
+			EvalID:   evalID,
+			Warnings: resp.Warnings,
+		})
+		if detach || periodic || paramjob || multiregion || evalID == "" {
+			return mon.done(runResultSuccess, 0, nil)
+		}
+		return mon.monitor(evalID)
+	}
+
 	// Print any warnings if there are any
 	if resp.Warnings != "" {
 		c.Ui.Output(
 			c.Colorize().Color(fmt.Sprintf("[bold][yellow]Job Warnings:\n%s[reset]\n", resp.Warnings)))
 	}
 
-	evalID := resp.EvalID
-
 	// Check if we should enter monitor mode
 	if detach || periodic || paramjob || multiregion {
 		c.Ui.Output("Job registration successful")
@@ -0,0 +1,358 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+const (
+	// runOutputJSON outputs the JSON of the job without submitting it.
+	runOutputJSON = "json"
+
+	// runOutputJSONLines submits the job and reports its progress as one
+	// JSON event per line.
+	runOutputJSONLines = "json-lines"
+)
+
+// The exit codes of "job run -output=json-lines". Other errors, including
+// client connection issues or internal errors, are indicated by exit code 1.
+const (
+	runExitPlacementFailure  = 2
+	runExitDeploymentFailure = 3
+	runExitTimeout           = 4
+)
+
+// The types of the events reported by "job run -output=json-lines".
+const (
+	runEventJobRegistered    = "JobRegistered"
+	runEventEvalStatus       = "EvalStatus"
+	runEventPlacementFailed  = "PlacementFailed"
+	runEventAllocStatus      = "AllocStatus"
+	runEventDeploymentStatus = "DeploymentStatus"
+	runEventDeploymentGroup  = "DeploymentGroup"
+	runEventDone             = "Done"
+)
+
+// The results of the Done event.
+const (
+	runResultSuccess          = "success"
+	runResultPlacementFailure = "placement-failure"
+	runResultDeploymentFailed = "deployment-failure"
+	runResultTimeout          = "timeout"
+	runResultError            = "error"
+)
+
+// runOutputFlag is the -output flag of "job run". It can be used as a boolean
+// to output the JSON of the job, or set to json-lines to report the progress
+// of the job as JSON events.
+type runOutputFlag string
+
+func (f *runOutputFlag) String() string { return string(*f) }
+
+func (f *runOutputFlag) IsBoolFlag() bool { return true }
+
+func (f *runOutputFlag) Set(v string) error {
+	switch v {
+	case "true", runOutputJSON:
+		*f = runOutputJSON
+	case "false":
+		*f = ""
+	case runOutputJSONLines:
+		*f = runOutputJSONLines
+	default:
+		return fmt.Errorf("must be %q or %q", runOutputJSON, runOutputJSONLines)
+	}
+	return nil
+}
+
+// runEvent is a progress event of "job run -output=json-lines". Only the
+// fields relevant to the type of the event are set.
+type runEvent struct {
+	Time time.Time
+	Type string
+
+	JobID        string `json:",omitempty"`
+	Namespace    string `json:",omitempty"`
+	EvalID       string `json:",omitempty"`
+	AllocID      string `json:",omitempty"`
+	DeploymentID string `json:",omitempty"`
+	NodeID       string `json:",omitempty"`
+	Group        string `json:",omitempty"`
+
+	Status            string   `json:",omitempty"`
+	StatusDescription string   `json:",omitempty"`
+	DesiredStatus     string   `json:",omitempty"`
+	Healthy           *bool    `json:",omitempty"`
+	Warnings          string   `json:",omitempty"`
+	BlockedEvalID     string   `json:",omitempty"`
+	Reasons           []string `json:",omitempty"`
+
+	// Failed is the number of allocations which failed to be placed.
+	Failed int `json:",omitempty"`
+
+	// The progress of a group of a deployment.
+	DesiredTotal    *int `json:",omitempty"`
+	PlacedAllocs    *int `json:",omitempty"`
+	HealthyAllocs   *int `json:",omitempty"`
+	UnhealthyAllocs *int `json:",omitempty"`
+
+	// The result of the Done event.
+	Result   string `json:",omitempty"`
+	ExitCode *int   `json:",omitempty"`
+	Error    string `json:",omitempty"`
+}
+
+// runEventMonitor reports the progress of a job registration as JSON events,
+// from the evaluation until the deployment of the job completes.
+type runEventMonitor struct {
+	ui      cli.Ui
+	client  *api.Client
+	timeout time.Duration
+
+	deadline time.Time
+	allocs   map[string]*runEvent
+	groups   map[string]api.DeploymentState
+}
+
+func newRunEventMonitor(ui cli.Ui, client *api.Client, timeout time.Duration) *runEventMonitor {
+	return &runEventMonitor{
+		ui:      ui,
+		client:  client,
+		timeout: timeout,
+		allocs:  make(map[string]*runEvent),
+		groups:  make(map[string]api.DeploymentState),
+	}
+}
+
+// emit outputs the event as a line of JSON.
+func (m *runEventMonitor) emit(e *runEvent) {
+	e.Time = time.Now().UTC()
+	buf, err := json.Marshal(e)
+	if err != nil {
+		// Events only hold values which can be encoded
+		panic(err)
+	}
+	m.ui.Output(string(buf))
+}
+
+// done emits the Done event and returns the exit code.
+func (m *runEventMonitor) done(result string, code int, err error) int {
+	e := &runEvent{Type: runEventDone, Result: result, ExitCode: &code}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	m.emit(e)
+	return code
+}
+
+// wait sleeps before the next poll, and returns false if the timeout is
+// reached instead.
+func (m *runEventMonitor) wait(d time.Duration) bool {
+	if m.timeout > 0 && time.Now().Add(d).After(m.deadline) {
+		return false
+	}
+	time.Sleep(d)
+	return true
+}
+
+// monitor reports the progress of the evaluation and of the deployment it
+// creates, and returns the exit code of the command.
+func (m *runEventMonitor) monitor(evalID string) int {
+	m.deadline = time.Now().Add(m.timeout)
+
+	var deploymentID string
+	var evalStatus string
+	for {
+		eval, _, err := m.client.Evaluations().Info(evalID, nil)
+		if err != nil {
+			return m.done(runResultError, 1, fmt.Errorf("error reading evaluation %q: %v", evalID, err))
+		}
+		if eval.Status != evalStatus {
+			evalStatus = eval.Status
+			m.emit(&runEvent{
+				Type:              runEventEvalStatus,
+				JobID:             eval.JobID,
+				Namespace:         eval.Namespace,
+				EvalID:            eval.ID,
+				Status:            eval.Status,
+				StatusDescription: eval.StatusDescription,
+			})
+		}
+		if eval.DeploymentID != "" {
+			deploymentID = eval.DeploymentID
+		}
+
+		allocs, _, err := m.client.Evaluations().Allocations(eval.ID, nil)
+		if err != nil {
+			return m.done(runResultError, 1, fmt.Errorf("error reading allocations: %v", err))
+		}
+		m.updateAllocs(allocs)
+
+		switch eval.Status {
+		case api.EvalStatusComplete, api.EvalStatusFailed, api.EvalStatusCancelled:
+		default:
+			if !m.wait(updateWait) {
+				return m.done(runResultTimeout, runExitTimeout, nil)
+			}
+			continue
+		}
+
+		if len(eval.FailedTGAllocs) > 0 {
+			groups := make([]string, 0, len(eval.FailedTGAllocs))
+			for group := range eval.FailedTGAllocs {
+				groups = append(groups, group)
+			}
+			sort.Strings(groups)
+
+			for _, group := range groups {
+				metrics := eval.FailedTGAllocs[group]
+				m.emit(&runEvent{
+					Type:          runEventPlacementFailed,
+					EvalID:        eval.ID,
+					Group:         group,
+					Failed:        metrics.CoalescedFailures + 1,
+					BlockedEvalID: eval.BlockedEval,
+					Reasons:       placementFailureReasons(metrics),
+				})
+			}
+			return m.done(runResultPlacementFailure, runExitPlacementFailure, nil)
+		}
+
+		if eval.NextEval == "" {
+			break
+		}
+		if eval.Wait > 0 && !m.wait(eval.Wait) {
+			return m.done(runResultTimeout, runExitTimeout, nil)
+		}
+		evalID, evalStatus = eval.NextEval, ""
+	}
+
+	if deploymentID == "" {
+		return m.done(runResultSuccess, 0, nil)
+	}
+	return m.monitorDeployment(deploymentID)
+}
+
+// monitorDeployment reports the progress of the deployment until it is
+// terminal.
+func (m *runEventMonitor) monitorDeployment(deploymentID string) int {
+	var status, desc string
+	for {
+		deploy, _, err := m.client.Deployments().Info(deploymentID, nil)
+		if err != nil {
+			return m.done(runResultError, 1, fmt.Errorf("error reading deployment %q: %v", deploymentID, err))
+		}
+		if deploy.Status != status || deploy.StatusDescription != desc {
+			status, desc = deploy.Status, deploy.StatusDescription
+			m.emit(&runEvent{
+				Type:              runEventDeploymentStatus,
+				JobID:             deploy.JobID,
+				Namespace:         deploy.Namespace,
+				DeploymentID:      deploy.ID,
+				Status:            deploy.Status,
+				StatusDescription: deploy.StatusDescription,
+			})
+		}
+
+		groups := make([]string, 0, len(deploy.TaskGroups))
+		for group := range deploy.TaskGroups {
+			groups = append(groups, group)
+		}
+		sort.Strings(groups)
+		for _, group := range groups {
+			state := *deploy.TaskGroups[group]
+			state.PlacedCanaries = nil
+			if prev, ok := m.groups[group]; ok &&
+				prev.DesiredTotal == state.DesiredTotal &&
+				prev.PlacedAllocs == state.PlacedAllocs &&
+				prev.HealthyAllocs == state.HealthyAllocs &&
+				prev.UnhealthyAllocs == state.UnhealthyAllocs {
+				continue
+			}
+			m.groups[group] = state
+			m.emit(&runEvent{
+				Type:            runEventDeploymentGroup,
+				DeploymentID:    deploy.ID,
+				Group:           group,
+				DesiredTotal:    &state.DesiredTotal,
+				PlacedAllocs:    &state.PlacedAllocs,
+				HealthyAllocs:   &state.HealthyAllocs,
+				UnhealthyAllocs: &state.UnhealthyAllocs,
+			})
+		}
+
+		allocs, _, err := m.client.Deployments().Allocations(deploymentID, nil)
+		if err != nil {
+			return m.done(runResultError, 1, fmt.Errorf("error reading allocations: %v", err))
+		}
+		m.updateAllocs(allocs)
+
+		switch deploy.Status {
+		case api.DeploymentStatusSuccessful:
+			return m.done(runResultSuccess, 0, nil)
+		case api.DeploymentStatusFailed, api.DeploymentStatusCancelled:
+			return m.done(runResultDeploymentFailed, runExitDeploymentFailure, nil)
+		}
+
+		if !m.wait(updateWait) {
+			return m.done(runResultTimeout, runExitTimeout, nil)
+		}
+	}
+}
+
+// updateAllocs emits an AllocStatus event for the allocations which are new
+// or whose status or health changed.
+func (m *runEventMonitor) updateAllocs(allocs []*api.AllocationListStub) {
+	sort.Slice(allocs, func(i, j int) bool { return allocs[i].CreateIndex < allocs[j].CreateIndex })
+	for _, alloc := range allocs {
+		e := &runEvent{
+			Type:              runEventAllocStatus,
+			AllocID:           alloc.ID,
+			EvalID:            alloc.EvalID,
+			NodeID:            alloc.NodeID,
+			Group:             alloc.TaskGroup,
+			Status:            alloc.ClientStatus,
+			StatusDescription: alloc.ClientDescription,
+			DesiredStatus:     alloc.DesiredStatus,
+		}
+		if alloc.DeploymentStatus != nil {
+			e.Healthy = alloc.DeploymentStatus.Healthy
+		}
+
+		if prev, ok := m.allocs[alloc.ID]; ok &&
+			prev.Status == e.Status &&
+			prev.DesiredStatus == e.DesiredStatus &&
+			healthEqual(prev.Healthy, e.Healthy) {
+			continue
+		}
+		m.allocs[alloc.ID] = e
+		m.emit(e)
+	}
+}
+
+// placementFailureReasons returns the reasons of the placement failures of
+// the metrics.
+func placementFailureReasons(metrics *api.AllocationMetric) []string {
+	var reasons []string
+	for _, line := range strings.Split(formatAllocMetrics(metrics, false, ""), "\n") {
+		if line = strings.TrimPrefix(line, "* "); line != "" {
+			reasons = append(reasons, line)
+		}
+	}
+	return reasons
+}
+
+// healthEqual returns whether the deployment health of two allocations is the
+// same, where nil is unset.
+func healthEqual(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
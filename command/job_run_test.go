@@ -1,6 +1,8 @@
 package command
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -297,3 +299,149 @@ func TestRunCommand_JSON(t *testing.T) {
 	require.Empty(t, stderr)
 	require.NotEmpty(t, stdout)
 }
+
+func TestRunCommand_OutputFlag(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &JobRunCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-output=xml", "assets/example-short.nomad"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), `must be "json" or "json-lines"`)
+
+	// The JSON of the job is output with -output and -output=json
+	for _, flag := range []string{"-output", "-output=json"} {
+		ui := cli.NewMockUi()
+		cmd := &JobRunCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{flag, "assets/example-short.nomad"})
+		require.Zero(t, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), `"ID": "example",`)
+	}
+}
+
+func TestRunCommand_JSONLines(t *testing.T) {
+	ci.Parallel(t)
+
+	_, client, url := testServer(t, true, nil)
+	waitForNodes(t, client)
+
+	writeJob := func(name, group string) string {
+		path := filepath.Join(t.TempDir(), name+".nomad")
+		job := fmt.Sprintf(`
+job %q {
+  datacenters = ["dc1"]
+
+  update {
+    min_healthy_time  = "100ms"
+    healthy_deadline  = "2s"
+    progress_deadline = "5s"
+  }
+
+  group "web" {
+    %s
+
+    restart {
+      attempts = 0
+      mode     = "fail"
+    }
+
+    reschedule {
+      attempts  = 0
+      unlimited = false
+    }
+
+    task "web" {
+      driver = "mock_driver"
+
+      config {
+        run_for = "1m"
+      }
+
+      resources {
+        cpu    = 50
+        memory = 32
+      }
+    }
+  }
+}`, name, group)
+		require.NoError(t, os.WriteFile(path, []byte(job), 0o644))
+		return path
+	}
+
+	run := func(args ...string) ([]*runEvent, int) {
+		ui := cli.NewMockUi()
+		cmd := &JobRunCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run(append([]string{"-address=" + url, "-output=json-lines"}, args...))
+
+		var events []*runEvent
+		for _, line := range strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n") {
+			var e runEvent
+			require.NoError(t, json.Unmarshal([]byte(line), &e), line)
+			events = append(events, &e)
+		}
+		require.NotEmpty(t, events)
+		require.Equal(t, runEventJobRegistered, events[0].Type)
+		require.Equal(t, runEventDone, events[len(events)-1].Type)
+		require.Equal(t, code, *events[len(events)-1].ExitCode)
+		return events, code
+	}
+
+	eventTypes := func(events []*runEvent) map[string]bool {
+		types := map[string]bool{}
+		for _, e := range events {
+			types[e.Type] = true
+		}
+		return types
+	}
+
+	// Successful deployment
+	events, code := run(writeJob("success", ""))
+	require.Zero(t, code)
+	require.Equal(t, runResultSuccess, events[len(events)-1].Result)
+	types := eventTypes(events)
+	require.True(t, types[runEventEvalStatus])
+	require.True(t, types[runEventAllocStatus])
+	require.True(t, types[runEventDeploymentStatus])
+	require.True(t, types[runEventDeploymentGroup])
+
+	// Placement failure
+	events, code = run(writeJob("unplaceable", `
+    constraint {
+      attribute = "${attr.kernel.name}"
+      value     = "plan9"
+    }`))
+	require.Equal(t, runExitPlacementFailure, code)
+	require.Equal(t, runResultPlacementFailure, events[len(events)-1].Result)
+	failed := events[len(events)-2]
+	require.Equal(t, runEventPlacementFailed, failed.Type)
+	require.Equal(t, "web", failed.Group)
+	require.Equal(t, 1, failed.Failed)
+	require.NotEmpty(t, failed.Reasons)
+
+	// Deployment failure
+	events, code = run(writeJob("failing", `
+    task "init" {
+      driver = "mock_driver"
+
+      config {
+        start_error = "failed to start"
+      }
+
+      resources {
+        cpu    = 50
+        memory = 32
+      }
+    }`))
+	require.Equal(t, runExitDeploymentFailure, code)
+	require.Equal(t, runResultDeploymentFailed, events[len(events)-1].Result)
+
+	// Timeout
+	events, code = run("-timeout=2s", writeJob("slow", `
+    update {
+      min_healthy_time  = "1m"
+      healthy_deadline  = "2m"
+      progress_deadline = "3m"
+    }`))
+	require.Equal(t, runExitTimeout, code)
+	require.Equal(t, runResultTimeout, events[len(events)-1].Result)
+}
@@ -23,6 +23,8 @@ type JobStatusCommand struct {
 	evals     bool
 	allAllocs bool
 	verbose   bool
+
+	formatOpts FormatOpts
 }
 
 func (c *JobStatusCommand) Help() string {
@@ -54,6 +56,16 @@ Status Options:
 
   -verbose
     Display full information.
+
+  -json
+    Output the jobs, or the job along with its summary and allocations, in
+    their JSON format.
+
+  -t
+    Format and display the jobs, or the job along with its summary and
+    allocations, using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -69,6 +81,11 @@ func (c *JobStatusCommand) AutocompleteFlags() complete.Flags {
 			"-evals":      complete.PredictNothing,
 			"-short":      complete.PredictNothing,
 			"-verbose":    complete.PredictNothing,
+			"-json":       complete.PredictNothing,
+			"-t":          complete.PredictAnything,
+			"-format":     complete.PredictSet("json", "yaml", "csv"),
+			"-columns":    complete.PredictAnything,
+			"-query":      complete.PredictAnything,
 		})
 }
 
@@ -98,6 +115,7 @@ func (c *JobStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.evals, "evals", false, "")
 	flags.BoolVar(&c.allAllocs, "all-allocs", false, "")
 	flags.BoolVar(&c.verbose, "verbose", false, "")
+	c.formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -135,6 +153,17 @@ func (c *JobStatusCommand) Run(args []string) int {
 			return 1
 		}
 
+		if c.formatOpts.Enabled() {
+			out, err := c.formatOpts.Output(jobs)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+
+			c.Ui.Output(out)
+			return 0
+		}
+
 		if len(jobs) == 0 {
 			// No output if we have no jobs
 			c.Ui.Output("No running jobs")
@@ -171,6 +200,10 @@ func (c *JobStatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.formatOpts.Enabled() {
+		return c.outputFormattedJob(client, job, q)
+	}
+
 	periodic := job.IsPeriodic()
 	parameterized := job.IsParameterized()
 
@@ -237,6 +270,39 @@ func (c *JobStatusCommand) Run(args []string) int {
 	return 0
 }
 
+// outputFormattedJob outputs the job along with its summary and allocations
+// in the format selected by the flags.
+func (c *JobStatusCommand) outputFormattedJob(client *api.Client, job *api.Job, q *api.QueryOptions) int {
+	summary, _, err := client.Jobs().Summary(*job.ID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job summary: %s", err))
+		return 1
+	}
+
+	allocs, _, err := client.Jobs().Allocations(*job.ID, c.allAllocs, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job allocations: %s", err))
+		return 1
+	}
+
+	out, err := c.formatOpts.Output(struct {
+		Job         *api.Job
+		Summary     *api.JobSummary
+		Allocations []*api.AllocationListStub
+	}{
+		Job:         job,
+		Summary:     summary,
+		Allocations: allocs,
+	})
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	c.Ui.Output(out)
+	return 0
+}
+
 // outputPeriodicInfo prints information about the passed periodic job. If a
 // request fails, an error is returned.
 func (c *JobStatusCommand) outputPeriodicInfo(client *api.Client, job *api.Job) error {
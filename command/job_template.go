@@ -0,0 +1,46 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type JobTemplateCommand struct {
+	Meta
+}
+
+func (c *JobTemplateCommand) Name() string { return "job template" }
+
+func (c *JobTemplateCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *JobTemplateCommand) Synopsis() string {
+	return "Interact with job templates"
+}
+
+func (c *JobTemplateCommand) Help() string {
+	helpText := `
+Usage: nomad job template <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with job templates. Job
+  templates are HCL2 jobspecs stored on the servers, which can be run with the
+  values of their input variables without access to the jobspec.
+
+  Register a job template:
+
+      $ nomad job template register web.nomad.hcl
+
+  List the job templates:
+
+      $ nomad job template list
+
+  Run a job template:
+
+      $ nomad job template run -var image=nginx:1.23 web
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type JobTemplateDeleteCommand struct {
+	Meta
+}
+
+func (c *JobTemplateDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad job template delete [options] <template>
+
+  Delete is used to remove a job template. The jobs run from the template are
+  not affected.
+
+  When ACLs are enabled, this command requires a token with the
+  'register-job-template' capability for the namespace of the template.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobTemplateDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *JobTemplateDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.JobTemplates().PrefixList(a.Last, nil)
+		if err != nil {
+			return []string{}
+		}
+
+		matches := make([]string, 0, len(resp))
+		for _, tmpl := range resp {
+			matches = append(matches, tmpl.Name)
+		}
+		return matches
+	})
+}
+
+func (c *JobTemplateDeleteCommand) Synopsis() string {
+	return "Delete a job template"
+}
+
+func (c *JobTemplateDeleteCommand) Name() string { return "job template delete" }
+
+func (c *JobTemplateDeleteCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <template>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.JobTemplates().Delete(name, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting job template: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted job template %q!", name))
+	return 0
+}
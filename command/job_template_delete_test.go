@@ -0,0 +1,48 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobTemplateDeleteCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobTemplateDeleteCommand{}
+}
+
+func TestJobTemplateDeleteCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobTemplateDeleteCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	must.One(t, cmd.Run([]string{"-address=" + url, "web"}))
+	must.StrContains(t, ui.ErrorWriter.String(), `job template "web" not found`)
+	ui.ErrorWriter.Reset()
+
+	_, _, err := client.JobTemplates().Register(&api.JobTemplate{
+		Name:   "web",
+		Source: testJobTemplate,
+	}, nil)
+	must.NoError(t, err)
+
+	// The templates are completed by name
+	res := cmd.AutocompleteArgs().Predict(complete.Args{Last: "w"})
+	must.Eq(t, []string{"web"}, res)
+
+	code := cmd.Run([]string{"-address=" + url, "web"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), `Successfully deleted job template "web"!`)
+
+	templates, _, err := client.JobTemplates().List(nil)
+	must.NoError(t, err)
+	must.SliceEmpty(t, templates)
+}
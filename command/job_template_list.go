@@ -0,0 +1,131 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type JobTemplateListCommand struct {
+	Meta
+}
+
+func (c *JobTemplateListCommand) Help() string {
+	helpText := `
+Usage: nomad job template list [options]
+
+  List is used to list the job templates of a namespace. Use the "*" namespace
+  to list the templates of all the namespaces.
+
+  When ACLs are enabled, this command requires a token with the 'read-job',
+  'register-job-template' or 'run-job-template' capability for the namespaces
+  of the templates.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+List Options:
+
+  -prefix <prefix>
+    Only list the templates whose name starts with the prefix.
+
+  -json
+    Output the job templates in a JSON format.
+
+  -t
+    Format and display the job templates using a Go template.
+
+  ` + formatOptionsUsage + `
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobTemplateListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-prefix":  complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
+		})
+}
+
+func (c *JobTemplateListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *JobTemplateListCommand) Synopsis() string {
+	return "List job templates"
+}
+
+func (c *JobTemplateListCommand) Name() string { return "job template list" }
+
+func (c *JobTemplateListCommand) Run(args []string) int {
+	var prefix string
+	var formatOpts FormatOpts
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&prefix, "prefix", "", "")
+	formatOpts.SetFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	templates, _, err := client.JobTemplates().PrefixList(prefix, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving job templates: %s", err))
+		return 1
+	}
+
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(templates)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatJobTemplates(templates))
+	return 0
+}
+
+func formatJobTemplates(templates []*api.JobTemplateListStub) string {
+	if len(templates) == 0 {
+		return "No job templates found"
+	}
+
+	rows := make([]string, len(templates)+1)
+	rows[0] = "Name|Namespace|Description"
+	for i, tmpl := range templates {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s",
+			tmpl.Name,
+			tmpl.Namespace,
+			tmpl.Description)
+	}
+	return formatList(rows)
+}
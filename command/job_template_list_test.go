@@ -0,0 +1,47 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobTemplateListCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobTemplateListCommand{}
+}
+
+func TestJobTemplateListCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobTemplateListCommand{Meta: Meta{Ui: ui}}
+
+	must.One(t, cmd.Run([]string{"-address=" + url, "extra"}))
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	must.Zero(t, cmd.Run([]string{"-address=" + url}))
+	must.StrContains(t, ui.OutputWriter.String(), "No job templates found")
+	ui.OutputWriter.Reset()
+
+	for _, name := range []string{"web", "worker"} {
+		_, _, err := client.JobTemplates().Register(&api.JobTemplate{
+			Name:        name,
+			Description: name + " service",
+			Source:      testJobTemplate,
+		}, nil)
+		must.NoError(t, err)
+	}
+
+	must.Zero(t, cmd.Run([]string{"-address=" + url, "-prefix", "wor"}))
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "worker service")
+	must.StrNotContains(t, out, "web service")
+}
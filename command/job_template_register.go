@@ -0,0 +1,157 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type JobTemplateRegisterCommand struct {
+	Meta
+}
+
+func (c *JobTemplateRegisterCommand) Help() string {
+	helpText := `
+Usage: nomad job template register [options] <path>
+
+  Register is used to register a job template, or to update an existing one.
+  The template is the HCL2 jobspec at the given path, or read from stdin when
+  the path is "-". Its input variables are the values to set when running the
+  template.
+
+  When ACLs are enabled, this command requires a token with the
+  'register-job-template' and 'submit-job' capabilities for the namespace of
+  the template.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Register Options:
+
+  -name <name>
+    The name of the template. Defaults to the file name of the path, without
+    its ".nomad.hcl", ".nomad" or ".hcl" extension. Required when reading the
+    template from stdin.
+
+  -description <description>
+    A human readable description of the template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobTemplateRegisterCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-name":        complete.PredictAnything,
+			"-description": complete.PredictAnything,
+		})
+}
+
+func (c *JobTemplateRegisterCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictFiles("*.nomad"),
+		complete.PredictFiles("*.hcl"),
+	)
+}
+
+func (c *JobTemplateRegisterCommand) Synopsis() string {
+	return "Register or update a job template"
+}
+
+func (c *JobTemplateRegisterCommand) Name() string { return "job template register" }
+
+func (c *JobTemplateRegisterCommand) Run(args []string) int {
+	var name, description string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&name, "name", "", "")
+	flags.StringVar(&description, "description", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	path := args[0]
+
+	var source []byte
+	var err error
+	if path == "-" {
+		if name == "" {
+			c.Ui.Error("The -name flag is required when reading the template from stdin")
+			return 1
+		}
+		source, err = io.ReadAll(os.Stdin)
+	} else {
+		if name == "" {
+			name = jobTemplateName(path)
+		}
+		source, err = os.ReadFile(path)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading job template: %s", err))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	tmpl := &api.JobTemplate{
+		Name:        name,
+		Description: description,
+		Source:      string(source),
+	}
+	out, _, err := client.JobTemplates().Register(tmpl, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error registering job template: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully registered job template %q!", out.Name))
+	if len(out.Variables) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Variables[reset]"))
+		c.Ui.Output(formatJobTemplateVariables(out.Variables))
+	}
+	return 0
+}
+
+// jobTemplateName returns the default name of the template at the path.
+func jobTemplateName(path string) string {
+	name := filepath.Base(path)
+	for _, ext := range []string{".nomad.hcl", ".nomad", ".hcl"} {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext)
+		}
+	}
+	return name
+}
+
+func formatJobTemplateVariables(vars []*api.JobTemplateVariable) string {
+	rows := make([]string, len(vars)+1)
+	rows[0] = "Name|Required|Sensitive|Description"
+	for i, v := range vars {
+		rows[i+1] = fmt.Sprintf("%s|%t|%t|%s",
+			v.Name,
+			v.Required,
+			v.Sensitive,
+			v.Description)
+	}
+	return formatList(rows)
+}
@@ -0,0 +1,97 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+const testJobTemplate = `
+variable "image" {
+  type        = string
+  description = "The image of the web task"
+}
+
+job "web" {
+  datacenters = ["dc1"]
+
+  group "web" {
+    task "web" {
+      driver = "docker"
+
+      config {
+        image = var.image
+      }
+    }
+  }
+}
+`
+
+func TestJobTemplateRegisterCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobTemplateRegisterCommand{}
+}
+
+func TestJobTemplateRegisterCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobTemplateRegisterCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	must.One(t, cmd.Run([]string{"some", "bad", "args"}))
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Templates read from stdin need a name
+	must.One(t, cmd.Run([]string{"-"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "-name flag is required")
+	ui.ErrorWriter.Reset()
+
+	must.One(t, cmd.Run([]string{"/unicorns/leprechauns.nomad.hcl"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "Error reading job template")
+	ui.ErrorWriter.Reset()
+}
+
+func TestJobTemplateRegisterCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	path := filepath.Join(t.TempDir(), "web.nomad.hcl")
+	must.NoError(t, os.WriteFile(path, []byte(testJobTemplate), 0o644))
+
+	ui := cli.NewMockUi()
+	cmd := &JobTemplateRegisterCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-description", "web service", path})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, `Successfully registered job template "web"!`)
+	must.StrContains(t, out, "The image of the web task")
+
+	tmpl, _, err := client.JobTemplates().Info("web", nil)
+	must.NoError(t, err)
+	must.Eq(t, "web service", tmpl.Description)
+	must.Eq(t, testJobTemplate, tmpl.Source)
+	must.Len(t, 1, tmpl.Variables)
+	must.True(t, tmpl.Variables[0].Required)
+}
+
+func TestJobTemplateRegisterCommand_Name(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := map[string]string{
+		"web.nomad.hcl":       "web",
+		"/jobs/web.nomad":     "web",
+		"templates/batch.hcl": "batch",
+		"web":                 "web",
+	}
+	for path, name := range cases {
+		must.Eq(t, name, jobTemplateName(path))
+	}
+}
@@ -0,0 +1,162 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type JobTemplateRunCommand struct {
+	Meta
+}
+
+func (c *JobTemplateRunCommand) Help() string {
+	helpText := `
+Usage: nomad job template run [options] <template>
+
+  Run is used to render a job template on the servers with the values of its
+  input variables, and to register the resulting job in the namespace of the
+  template.
+
+  Upon successful registration, the evaluation of the job will be monitored.
+  This can be disabled by supplying the detach flag.
+
+  When ACLs are enabled, this command requires a token with the
+  'run-job-template' capability for the namespace of the template.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Run Options:
+
+  -var <key>=<value>
+    Variable for the template. The flag can be provided more than once to set
+    multiple variables. Variables without a default value must be set.
+
+  -detach
+    Return immediately instead of entering monitor mode. After the job is
+    registered, the evaluation ID will be printed to the screen, which can be
+    used to examine the evaluation using the eval status command.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobTemplateRunCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-var":     complete.PredictAnything,
+			"-detach":  complete.PredictNothing,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *JobTemplateRunCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.JobTemplates().PrefixList(a.Last, nil)
+		if err != nil {
+			return []string{}
+		}
+
+		matches := make([]string, 0, len(resp))
+		for _, tmpl := range resp {
+			matches = append(matches, tmpl.Name)
+		}
+		return matches
+	})
+}
+
+func (c *JobTemplateRunCommand) Synopsis() string {
+	return "Run a job from a job template"
+}
+
+func (c *JobTemplateRunCommand) Name() string { return "job template run" }
+
+func (c *JobTemplateRunCommand) Run(args []string) int {
+	var detach, verbose bool
+	var vars []string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.Var((*flaghelper.StringFlag)(&vars), "var", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <template>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	// Build the variables
+	varMap := make(map[string]string, len(vars))
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			c.Ui.Error(fmt.Sprintf("Error parsing variable value: %v", v))
+			return 1
+		}
+		varMap[key] = value
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.JobTemplates().Run(name, varMap, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running job template: %s", err))
+		return 1
+	}
+
+	if resp.Warnings != "" {
+		c.Ui.Output(
+			c.Colorize().Color(fmt.Sprintf("[bold][yellow]Job Warnings:\n%s[reset]\n", resp.Warnings)))
+	}
+
+	// Periodic and parameterized jobs have no evaluation
+	evalCreated := resp.EvalID != ""
+
+	basic := []string{
+		fmt.Sprintf("Job ID|%s", resp.JobID),
+	}
+	if evalCreated {
+		basic = append(basic, fmt.Sprintf("Evaluation ID|%s", limit(resp.EvalID, length)))
+	}
+	c.Ui.Output(formatKV(basic))
+
+	// Nothing to do
+	if detach || !evalCreated {
+		return 0
+	}
+
+	c.Ui.Output("")
+	mon := newMonitor(c.Ui, client, length)
+	return mon.monitor(resp.EvalID)
+}
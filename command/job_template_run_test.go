@@ -0,0 +1,63 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobTemplateRunCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobTemplateRunCommand{}
+}
+
+func TestJobTemplateRunCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobTemplateRunCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	must.One(t, cmd.Run([]string{"some", "bad", "args"}))
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	must.One(t, cmd.Run([]string{"-var", "image", "web"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "Error parsing variable value")
+	ui.ErrorWriter.Reset()
+
+	must.One(t, cmd.Run([]string{"-address=nope", "web"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "Error running job template")
+	ui.ErrorWriter.Reset()
+}
+
+func TestJobTemplateRunCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, _, err := client.JobTemplates().Register(&api.JobTemplate{
+		Name:   "web",
+		Source: testJobTemplate,
+	}, nil)
+	must.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &JobTemplateRunCommand{Meta: Meta{Ui: ui}}
+
+	// Required variables must be set
+	must.One(t, cmd.Run([]string{"-address=" + url, "-detach", "web"}))
+	must.StrContains(t, ui.ErrorWriter.String(), `failed to render job template "web"`)
+	ui.ErrorWriter.Reset()
+
+	code := cmd.Run([]string{"-address=" + url, "-detach", "-var", "image=nginx:1.23", "web"})
+	must.Zero(t, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), "Evaluation ID")
+
+	job, _, err := client.Jobs().Info("web", nil)
+	must.NoError(t, err)
+	must.Eq(t, "nginx:1.23", job.TaskGroups[0].Tasks[0].Config["image"])
+}
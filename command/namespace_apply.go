@@ -31,6 +31,15 @@ Usage: nomad namespace apply [options] <input>
   Instead of a file, you may instead pass the namespace name to create
   or update as the only argument.
 
+  The specification may limit the resources used by the allocations of the
+  namespace, where a zero value means the resource is not limited:
+
+      limits {
+        cpu    = 10000
+        memory = 8192
+        allocs = 20
+      }
+
   If ACLs are enabled, this command requires a management ACL token.
 
 General Options:
@@ -220,6 +229,7 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 	}
 
 	delete(m, "capabilities")
+	delete(m, "limits")
 	delete(m, "meta")
 
 	// Decode the rest
@@ -243,6 +253,22 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 		}
 	}
 
+	lObj := list.Filter("limits")
+	if len(lObj.Items) > 0 {
+		for _, o := range lObj.Elem().Items {
+			ot, ok := o.Val.(*ast.ObjectType)
+			if !ok {
+				break
+			}
+			var limits *api.NamespaceLimits
+			if err := hcl.DecodeObject(&limits, ot.List); err != nil {
+				return err
+			}
+			result.Limits = limits
+			break
+		}
+	}
+
 	if metaO := list.Filter("meta"); len(metaO.Items) > 0 {
 		for _, o := range metaO.Elem().Items {
 			var m map[string]interface{}
@@ -92,6 +92,14 @@ func (c *NamespaceStatusCommand) Run(args []string) int {
 		c.Ui.Output(formatKV(meta))
 	}
 
+	usage, _, err := client.Namespaces().Usage(ns.Name, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving namespace usage: %s", err))
+		return 1
+	}
+	c.Ui.Output(c.Colorize().Color("\n[bold]Resource Usage[reset]"))
+	c.Ui.Output(formatNamespaceUsage(ns.Limits, usage))
+
 	if ns.Quota != "" {
 		quotas := client.Quotas()
 		spec, _, err := quotas.Info(ns.Quota, nil)
@@ -143,6 +151,25 @@ func formatNamespaceBasics(ns *api.Namespace) string {
 	return formatKV(basic)
 }
 
+// formatNamespaceUsage formats the usage of the namespace against its limits
+func formatNamespaceUsage(limits *api.NamespaceLimits, usage *api.NamespaceUsage) string {
+	if limits == nil {
+		limits = &api.NamespaceLimits{}
+	}
+	format := func(used, limit int) string {
+		return fmt.Sprintf("%d / %s", used, formatQuotaLimitInt(&limit))
+	}
+
+	rows := []string{
+		"CPU Usage|Memory Usage|Allocations",
+		fmt.Sprintf("%s|%s|%s",
+			format(usage.CPU, limits.CPU),
+			format(usage.MemoryMB, limits.MemoryMB),
+			format(usage.Allocs, limits.Allocs)),
+	}
+	return formatList(rows)
+}
+
 func getNamespace(client *api.Namespaces, ns string) (match *api.Namespace, possible []*api.Namespace, err error) {
 	// Do a prefix lookup
 	namespaces, _, err := client.PrefixList(ns, nil)
@@ -51,7 +51,8 @@ func TestNamespaceStatusCommand_Good(t *testing.T) {
 
 	// Create a namespace
 	ns := &api.Namespace{
-		Name: "foo",
+		Name:   "foo",
+		Limits: &api.NamespaceLimits{CPU: 1000},
 	}
 	_, err := client.Namespaces().Register(ns, nil)
 	assert.Nil(t, err)
@@ -66,6 +67,11 @@ func TestNamespaceStatusCommand_Good(t *testing.T) {
 	if !strings.Contains(out, "= foo") {
 		t.Fatalf("expected quota, got: %s", out)
 	}
+
+	// Check for the usage against the limits
+	if !strings.Contains(out, "0 / 1000") || !strings.Contains(out, "0 / inf") {
+		t.Fatalf("expected usage, got: %s", out)
+	}
 }
 
 func TestNamespaceStatusCommand_Good_Quota(t *testing.T) {
@@ -34,11 +34,10 @@ type NodeStatusCommand struct {
 	list_allocs bool
 	self        bool
 	stats       bool
-	json        bool
 	perPage     int
 	pageToken   string
 	filter      string
-	tmpl        string
+	formatOpts  FormatOpts
 }
 
 func (c *NodeStatusCommand) Help() string {
@@ -99,6 +98,8 @@ Node Status Options:
 
   -t
     Format and display node using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -119,6 +120,9 @@ func (c *NodeStatusCommand) AutocompleteFlags() complete.Flags {
 			"-short":      complete.PredictNothing,
 			"-stats":      complete.PredictNothing,
 			"-t":          complete.PredictAnything,
+			"-format":     complete.PredictSet("json", "yaml", "csv"),
+			"-columns":    complete.PredictAnything,
+			"-query":      complete.PredictAnything,
 			"-os":         complete.PredictAnything,
 			"-quiet":      complete.PredictAnything,
 			"-verbose":    complete.PredictNothing,
@@ -153,8 +157,7 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.list_allocs, "allocs", false, "")
 	flags.BoolVar(&c.self, "self", false, "")
 	flags.BoolVar(&c.stats, "stats", false, "")
-	flags.BoolVar(&c.json, "json", false, "")
-	flags.StringVar(&c.tmpl, "t", "", "")
+	c.formatOpts.SetFlags(flags)
 	flags.StringVar(&c.filter, "filter", "", "")
 	flags.IntVar(&c.perPage, "per-page", 0, "")
 	flags.StringVar(&c.pageToken, "page-token", "", "")
@@ -186,8 +189,8 @@ func (c *NodeStatusCommand) Run(args []string) int {
 
 	// Use list mode if no node name was provided
 	if len(args) == 0 && !c.self {
-		if c.quiet && (c.verbose || c.json) {
-			c.Ui.Error("-quiet cannot be used with -verbose or -json")
+		if c.quiet && (c.verbose || c.formatOpts.Enabled()) {
+			c.Ui.Error("-quiet cannot be used with -verbose, -json, -t, -format or -query")
 			return 1
 		}
 
@@ -213,8 +216,8 @@ func (c *NodeStatusCommand) Run(args []string) int {
 		}
 
 		// If output format is specified, format and output the node data list
-		if c.json || len(c.tmpl) > 0 {
-			out, err := Format(c.json, c.tmpl, nodes)
+		if c.formatOpts.Enabled() {
+			out, err := c.formatOpts.Output(nodes)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -347,8 +350,8 @@ Results have been paginated. To get the next page run:
 	}
 
 	// If output format is specified, format and output the data
-	if c.json || len(c.tmpl) > 0 {
-		out, err := Format(c.json, c.tmpl, node)
+	if c.formatOpts.Enabled() {
+		out, err := c.formatOpts.Output(node)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
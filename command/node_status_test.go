@@ -221,7 +221,7 @@ func TestNodeStatusCommand_Fails(t *testing.T) {
 		t.Fatalf("expected exit 1, got: %d", code)
 	}
 
-	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-quiet cannot be used with -verbose or -json") {
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-quiet cannot be used with -verbose, -json, -t, -format or -query") {
 		t.Fatalf("expected getting formatter error, got: %s", out)
 	}
 	ui.ErrorWriter.Reset()
@@ -231,7 +231,17 @@ func TestNodeStatusCommand_Fails(t *testing.T) {
 		t.Fatalf("expected exit 1, got: %d", code)
 	}
 
-	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-quiet cannot be used with -verbose or -json") {
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-quiet cannot be used with -verbose, -json, -t, -format or -query") {
+		t.Fatalf("expected getting formatter error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fail if -quiet is passed with -format
+	if code := cmd.Run([]string{"-address=" + url, "-quiet", "-format=csv"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-quiet cannot be used with -verbose, -json, -t, -format or -query") {
 		t.Fatalf("expected getting formatter error, got: %s", out)
 	}
 }
@@ -7,7 +7,6 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -41,6 +40,7 @@ type testCases []testCase
 
 func runTestCases(t *testing.T, cases testCases) {
 	t.Helper()
+	testArchiveDir(t)
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			ui := cli.NewMockUi()
@@ -570,6 +570,7 @@ func TestDebug_Fail_Pprof(t *testing.T) {
 	cmd := &OperatorDebugCommand{Meta: Meta{Ui: ui}}
 
 	// Debug on server with endpoints disabled
+	testArchiveDir(t)
 	code := cmd.Run([]string{"-address", url, "-duration", "250ms", "-interval", "250ms", "-server-id", "all"})
 
 	assert.Equal(t, 0, code) // Pprof failure isn't fatal
@@ -972,7 +973,6 @@ func TestDebug_EventStream_TopicsFromString(t *testing.T) {
 func TestDebug_EventStream(t *testing.T) {
 	ci.Parallel(t)
 
-	// TODO dmay: specify output directory to allow inspection of eventstream.json
 	// TODO dmay: require specific events in the eventstream.json file(s)
 	// TODO dmay: scenario where no events are expected, verify "No events captured"
 	// TODO dmay: verify event topic filtering only includes expected events
@@ -991,6 +991,9 @@ func TestDebug_EventStream(t *testing.T) {
 	ui := cli.NewMockUi()
 	cmd := &OperatorDebugCommand{Meta: Meta{Ui: ui}}
 
+	// Write the archive to a temporary directory
+	outputDir := t.TempDir()
+
 	// Return command output back to the main test goroutine
 	chOutput := make(chan testOutput)
 
@@ -1002,7 +1005,7 @@ func TestDebug_EventStream(t *testing.T) {
 	// Run debug in a goroutine so we can start the capture before we run the test job
 	t.Logf("%s: Starting nomad operator debug in goroutine\n", time.Since(start))
 	go func() {
-		code := cmd.Run([]string{"-address", url, "-duration", duration.String(), "-interval", "5s", "-event-topic", "Job:*"})
+		code := cmd.Run([]string{"-address", url, "-duration", duration.String(), "-interval", "5s", "-event-topic", "Job:*", "-output", outputDir})
 		assert.Equal(t, 0, code)
 
 		chOutput <- testOutput{
@@ -1048,23 +1051,23 @@ func TestDebug_EventStream(t *testing.T) {
 
 	require.Empty(t, testOut.error)
 
-	archive := extractArchiveName(testOut.output)
-	require.NotEmpty(t, archive)
-	fmt.Println(archive)
+	require.Contains(t, testOut.output, "Created debug directory")
+	eventstream := filepath.Join(outputDir, "*", clusterDir, "eventstream.json")
+	files, err := filepath.Glob(eventstream)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
 
 	// TODO dmay: verify evenstream.json output file contains expected content
 }
 
-// extractArchiveName searches string s for the archive filename
-func extractArchiveName(captureOutput string) string {
-	file := ""
-
-	r := regexp.MustCompile(`Created debug archive: (.+)?\n`)
-	res := r.FindStringSubmatch(captureOutput)
-	// If found, there will be 2 elements, where element [1] is the desired text from the submatch
-	if len(res) == 2 {
-		file = res[1]
-	}
-
-	return file
+// testArchiveDir changes the working directory to a temporary directory until
+// the end of the test, as the debug archives are written to the working
+// directory. Tests calling it can't run in parallel.
+func testArchiveDir(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(wd))
+	})
 }
@@ -14,15 +14,17 @@ var _ cli.Command = &OperatorSchedulerGetConfig{}
 type OperatorSchedulerGetConfig struct {
 	Meta
 
-	json bool
-	tmpl string
+	formatOpts FormatOpts
 }
 
 func (o *OperatorSchedulerGetConfig) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(o.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		},
 	)
 }
@@ -36,8 +38,7 @@ func (o *OperatorSchedulerGetConfig) Name() string { return "operator scheduler
 func (o *OperatorSchedulerGetConfig) Run(args []string) int {
 
 	flags := o.Meta.FlagSet("get-config", FlagSetClient)
-	flags.BoolVar(&o.json, "json", false, "")
-	flags.StringVar(&o.tmpl, "t", "", "")
+	o.formatOpts.SetFlags(flags)
 	flags.Usage = func() { o.Ui.Output(o.Help()) }
 
 	if err := flags.Parse(args); err != nil {
@@ -61,8 +62,8 @@ func (o *OperatorSchedulerGetConfig) Run(args []string) int {
 	// If the user has specified to output the scheduler config as JSON or
 	// using a template, perform this action for the entire object and exit the
 	// command.
-	if o.json || len(o.tmpl) > 0 {
-		out, err := Format(o.json, o.tmpl, resp)
+	if o.formatOpts.Enabled() {
+		out, err := o.formatOpts.Output(resp)
 		if err != nil {
 			o.Ui.Error(err.Error())
 			return 1
@@ -112,6 +113,8 @@ Scheduler Get Config Options:
 
   -t
     Format and display the scheduler config using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 
 	return strings.TrimSpace(helpText)
@@ -9,7 +9,7 @@ import (
 )
 
 func (c *PluginStatusCommand) csiBanner() {
-	if !(c.json || len(c.template) > 0) {
+	if !c.formatOpts.Enabled() {
 		c.Ui.Output(c.Colorize().Color("[bold]Container Storage Interface[reset]"))
 	}
 }
@@ -81,8 +81,8 @@ func (c *PluginStatusCommand) csiFormatPlugins(plugs []*api.CSIPluginListStub) (
 	// Sort the output by quota name
 	sort.Slice(plugs, func(i, j int) bool { return plugs[i].ID < plugs[j].ID })
 
-	if c.json || len(c.template) > 0 {
-		out, err := Format(c.json, c.template, plugs)
+	if c.formatOpts.Enabled() {
+		out, err := c.formatOpts.Output(plugs)
 		if err != nil {
 			return "", fmt.Errorf("format error: %v", err)
 		}
@@ -105,8 +105,8 @@ func (c *PluginStatusCommand) csiFormatPlugins(plugs []*api.CSIPluginListStub) (
 }
 
 func (c *PluginStatusCommand) csiFormatPlugin(plug *api.CSIPlugin) (string, error) {
-	if c.json || len(c.template) > 0 {
-		out, err := Format(c.json, c.template, plug)
+	if c.formatOpts.Enabled() {
+		out, err := c.formatOpts.Output(plug)
 		if err != nil {
 			return "", fmt.Errorf("format error: %v", err)
 		}
@@ -34,6 +34,8 @@ List Options:
 
   -t
     Format and display the quota specifications using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -41,8 +43,11 @@ List Options:
 func (c *QuotaListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -56,13 +61,11 @@ func (c *QuotaListCommand) Synopsis() string {
 
 func (c *QuotaListCommand) Name() string { return "quota list" }
 func (c *QuotaListCommand) Run(args []string) int {
-	var json bool
-	var tmpl string
+	var formatOpts FormatOpts
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -89,8 +92,8 @@ func (c *QuotaListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, quotas)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(quotas)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
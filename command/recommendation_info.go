@@ -38,6 +38,8 @@ Recommendation Info Options:
 
   -t
     Format and display the recommendation using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -50,8 +52,11 @@ func (r *RecommendationInfoCommand) Synopsis() string {
 func (r *RecommendationInfoCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(r.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -60,13 +65,10 @@ func (r *RecommendationInfoCommand) Name() string { return "recommendation info"
 
 // Run satisfies the cli.Command Run function.
 func (r *RecommendationInfoCommand) Run(args []string) int {
-	var json bool
-	var tmpl string
-
+	var formatOpts FormatOpts
 	flags := r.Meta.FlagSet(r.Name(), FlagSetClient)
 	flags.Usage = func() { r.Ui.Output(r.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -96,8 +98,8 @@ func (r *RecommendationInfoCommand) Run(args []string) int {
 	// If the user has specified to output the recommendation as JSON or using
 	// a template then perform this action for the entire object and exit the
 	// command.
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, rec)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(rec)
 		if err != nil {
 			r.Ui.Error(err.Error())
 			return 1
@@ -51,6 +51,8 @@ Recommendation List Options:
 
   -t
     Format and display the recommendations using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -63,11 +65,14 @@ func (r *RecommendationListCommand) Synopsis() string {
 func (r *RecommendationListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(r.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-job":   complete.PredictNothing,
-			"-group": complete.PredictNothing,
-			"-task":  complete.PredictNothing,
-			"-json":  complete.PredictNothing,
-			"-t":     complete.PredictAnything,
+			"-job":     complete.PredictNothing,
+			"-group":   complete.PredictNothing,
+			"-task":    complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -76,13 +81,11 @@ func (r *RecommendationListCommand) Name() string { return "recommendation list"
 
 // Run satisfies the cli.Command Run function.
 func (r *RecommendationListCommand) Run(args []string) int {
-	var json bool
-	var tmpl, job, group, task string
-
+	var job, group, task string
+	var formatOpts FormatOpts
 	flags := r.Meta.FlagSet(r.Name(), FlagSetClient)
 	flags.Usage = func() { r.Ui.Output(r.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 	flags.StringVar(&job, "job", "", "")
 	flags.StringVar(&group, "group", "", "")
 	flags.StringVar(&task, "task", "", "")
@@ -139,8 +142,8 @@ func (r *RecommendationListCommand) Run(args []string) int {
 		return 0
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, recommendations)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(recommendations)
 		if err != nil {
 			r.Ui.Error(err.Error())
 			return 1
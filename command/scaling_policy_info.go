@@ -43,6 +43,8 @@ Policy Info Options:
 
   -t
     Format and display the scaling policy using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -58,6 +60,9 @@ func (s *ScalingPolicyInfoCommand) AutocompleteFlags() complete.Flags {
 			"-verbose": complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -81,14 +86,12 @@ func (s *ScalingPolicyInfoCommand) Name() string { return "scaling policy info"
 
 // Run satisfies the cli.Command Run function.
 func (s *ScalingPolicyInfoCommand) Run(args []string) int {
-	var json, verbose bool
-	var tmpl string
-
+	var verbose bool
+	var formatOpts FormatOpts
 	flags := s.Meta.FlagSet(s.Name(), FlagSetClient)
 	flags.Usage = func() { s.Ui.Output(s.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -109,13 +112,13 @@ func (s *ScalingPolicyInfoCommand) Run(args []string) int {
 	args = flags.Args()
 
 	// Formatted list mode if no policy ID
-	if len(args) == 0 && (json || len(tmpl) > 0) {
+	if len(args) == 0 && formatOpts.Enabled() {
 		policies, _, err := client.Scaling().ListPolicies(nil)
 		if err != nil {
 			s.Ui.Error(fmt.Sprintf("Error listing scaling policies: %v", err))
 			return 1
 		}
-		out, err := Format(json, tmpl, policies)
+		out, err := formatOpts.Output(policies)
 		if err != nil {
 			s.Ui.Error(err.Error())
 			return 1
@@ -161,8 +164,8 @@ func (s *ScalingPolicyInfoCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, policy)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(policy)
 		if err != nil {
 			s.Ui.Error(err.Error())
 			return 1
@@ -50,6 +50,8 @@ Policy Info Options:
 
   -t
     Format and display the scaling policy using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -67,6 +69,9 @@ func (s *ScalingPolicyListCommand) AutocompleteFlags() complete.Flags {
 			"-type":    complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -75,14 +80,13 @@ func (s *ScalingPolicyListCommand) Name() string { return "scaling policy list"
 
 // Run satisfies the cli.Command Run function.
 func (s *ScalingPolicyListCommand) Run(args []string) int {
-	var json, verbose bool
-	var tmpl, policyType, job string
-
+	var verbose bool
+	var policyType, job string
+	var formatOpts FormatOpts
 	flags := s.Meta.FlagSet(s.Name(), FlagSetClient)
 	flags.Usage = func() { s.Ui.Output(s.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 	flags.StringVar(&policyType, "type", "", "")
 	flags.StringVar(&job, "job", "", "")
 	if err := flags.Parse(args); err != nil {
@@ -123,8 +127,8 @@ func (s *ScalingPolicyListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, policies)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(policies)
 		if err != nil {
 			s.Ui.Error(err.Error())
 			return 1
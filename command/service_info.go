@@ -54,6 +54,8 @@ Service Info Options:
 
   -t
     Format and display the service using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -71,6 +73,9 @@ func (s *ServiceInfoCommand) AutocompleteFlags() complete.Flags {
 			"-per-page":   complete.PredictAnything,
 			"-page-token": complete.PredictAnything,
 			"-t":          complete.PredictAnything,
+			"-format":     complete.PredictSet("json", "yaml", "csv"),
+			"-columns":    complete.PredictAnything,
+			"-query":      complete.PredictAnything,
 			"-verbose":    complete.PredictNothing,
 		})
 }
@@ -81,16 +86,16 @@ func (s *ServiceInfoCommand) Name() string { return "service info" }
 // Run satisfies the cli.Command Run function.
 func (s *ServiceInfoCommand) Run(args []string) int {
 	var (
-		json, verbose           bool
-		perPage                 int
-		tmpl, filter, pageToken string
+		formatOpts        FormatOpts
+		verbose           bool
+		perPage           int
+		filter, pageToken string
 	)
 
 	flags := s.Meta.FlagSet(s.Name(), FlagSetClient)
 	flags.Usage = func() { s.Ui.Output(s.Help()) }
-	flags.BoolVar(&json, "json", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 	flags.StringVar(&filter, "filter", "", "")
 	flags.IntVar(&perPage, "per-page", 0, "")
 	flags.StringVar(&pageToken, "page-token", "", "")
@@ -129,8 +134,8 @@ func (s *ServiceInfoCommand) Run(args []string) int {
 		return 0
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, serviceInfo)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(serviceInfo)
 		if err != nil {
 			s.Ui.Error(err.Error())
 			return 1
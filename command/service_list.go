@@ -40,6 +40,8 @@ Service List Options:
 
   -t
     Format and display the services using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -52,8 +54,11 @@ func (s *ServiceListCommand) Synopsis() string {
 func (s *ServiceListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(s.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -64,15 +69,14 @@ func (s *ServiceListCommand) Name() string { return "service list" }
 func (s *ServiceListCommand) Run(args []string) int {
 
 	var (
-		json       bool
-		tmpl, name string
+		formatOpts FormatOpts
+		name       string
 	)
 
 	flags := s.Meta.FlagSet(s.Name(), FlagSetClient)
 	flags.Usage = func() { s.Ui.Output(s.Help()) }
-	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&name, "name", "", "")
-	flags.StringVar(&tmpl, "t", "", "")
+	formatOpts.SetFlags(flags)
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -100,8 +104,8 @@ func (s *ServiceListCommand) Run(args []string) int {
 		return 0
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, list)
+	if formatOpts.Enabled() {
+		out, err := formatOpts.Output(list)
 		if err != nil {
 			s.Ui.Error(err.Error())
 			return 1
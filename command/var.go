@@ -58,6 +58,14 @@ Usage: nomad var <subcommand> [options] [args]
 
       $ nomad var purge <path>
 
+  List the versions of a variable:
+
+      $ nomad var history <path>
+
+  Roll a variable back to a previous version:
+
+      $ nomad var rollback <path> <version>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -104,6 +112,9 @@ func renderSVAsUiTable(sv *api.Variable, c VarUI) {
 		fmt.Sprintf("Path|%s", sv.Path),
 		fmt.Sprintf("Create Time|%v", formatUnixNanoTime(sv.ModifyTime)),
 	}
+	if sv.Version != 0 {
+		meta = append(meta, fmt.Sprintf("Version|%v", sv.Version))
+	}
 	if sv.CreateTime != sv.ModifyTime {
 		meta = append(meta, fmt.Sprintf("Modify Time|%v", time.Unix(0, sv.ModifyTime)))
 	}
@@ -325,7 +336,9 @@ const (
 	errNoMatchingVariables         = `No matching variables found`
 	errInvalidInFormat             = `Invalid value for "-in"; valid values are [hcl, json]`
 	errInvalidOutFormat            = `Invalid value for "-out"; valid values are [go-template, hcl, json, none, table]`
-	errInvalidListOutFormat        = `Invalid value for "-out"; valid values are [go-template, json, table, terse]`
+	errInvalidListOutFormat        = `Invalid value for "-out"; valid values are [csv, go-template, json, table, terse, yaml]`
+	errUnexpectedColumns           = `The '-columns' flag is only valid when using 'csv' formatting`
+	errUnexpectedQuery             = `The '-query' flag is not valid when using 'table' or 'terse' formatting`
 	errWildcardNamespaceNotAllowed = `The wildcard namespace ("*") is not valid for this command.`
 
 	msgfmtCASMismatch = `
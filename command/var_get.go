@@ -46,6 +46,11 @@ Get Options:
   -template
      Template to render output with. Required when output is "go-template".
 
+  -version <version>
+     Get a previous version of the variable, as listed by 'nomad var history'.
+     The last version of a purged variable can be read until it is garbage
+     collected.
+
 `
 	return strings.TrimSpace(helpText)
 }
@@ -55,6 +60,7 @@ func (c *VarGetCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-out":      complete.PredictSet("go-template", "hcl", "json", "none", "table"),
 			"-template": complete.PredictAnything,
+			"-version":  complete.PredictAnything,
 		},
 	)
 }
@@ -71,12 +77,14 @@ func (c *VarGetCommand) Name() string { return "var get" }
 
 func (c *VarGetCommand) Run(args []string) int {
 	var out, item string
+	var version uint64
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 
 	flags.StringVar(&item, "item", "", "")
 	flags.StringVar(&c.tmpl, "template", "", "")
+	flags.Uint64Var(&version, "version", 0, "")
 
 	if fileInfo, _ := os.Stdout.Stat(); (fileInfo.Mode() & os.ModeCharDevice) != 0 {
 		flags.StringVar(&c.outFmt, "out", "table", "")
@@ -120,7 +128,12 @@ func (c *VarGetCommand) Run(args []string) int {
 		Namespace: c.Meta.namespace,
 	}
 
-	sv, _, err := client.Variables().Read(path, qo)
+	var sv *api.Variable
+	if version != 0 {
+		sv, _, err = client.Variables().ReadVersion(path, version, qo)
+	} else {
+		sv, _, err = client.Variables().Read(path, qo)
+	}
 	if err != nil {
 		if err.Error() == "variable not found" {
 			c.Ui.Warn(errVariableNotFound)
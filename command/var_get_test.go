@@ -153,7 +153,7 @@ func TestVarGetCommand(t *testing.T) {
 			case "table":
 				out := ui.OutputWriter.String()
 				outs := strings.Split(out, "\n")
-				require.Len(t, outs, 9)
+				require.Len(t, outs, 10)
 				require.Equal(t, "Namespace   = "+testNS, outs[0])
 				require.Equal(t, "Path        = test/var", outs[1])
 			case "go-template":
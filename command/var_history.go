@@ -0,0 +1,146 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type VarHistoryCommand struct {
+	Meta
+}
+
+func (c *VarHistoryCommand) Help() string {
+	helpText := `
+Usage: nomad var history [options] <path>
+
+  History is used to list the versions of a variable kept by the servers,
+  newest first. The versions of a purged variable are listed until they are
+  garbage collected.
+
+  If ACLs are enabled, this command requires a token with the 'variables:list'
+  capability for the target variable's namespace and path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+History Options:
+
+  -json
+    Output the versions in their JSON format.
+
+  -t
+    Format and display the versions using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarHistoryCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *VarHistoryCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarHistoryCommand) Synopsis() string {
+	return "List the versions of a variable"
+}
+
+func (c *VarHistoryCommand) Name() string { return "var history" }
+
+func (c *VarHistoryCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if c.Meta.namespace == "*" {
+		c.Ui.Error(errWildcardNamespaceNotAllowed)
+		return 1
+	}
+
+	path := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	qo := &api.QueryOptions{
+		Namespace: c.Meta.namespace,
+	}
+
+	versions, _, err := client.Variables().History(path, qo)
+	if err != nil {
+		if strings.Contains(err.Error(), api.ErrVariableNotFound) {
+			c.Ui.Warn(errVariableNotFound)
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error retrieving variable history: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, versions)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatVarVersions(versions))
+	return 0
+}
+
+func formatVarVersions(versions []*api.VariableVersionMetadata) string {
+	rows := make([]string, len(versions)+1)
+	rows[0] = "Version|Status|Modify Index|Modify Time"
+	for i, v := range versions {
+		status, modifyTime := "current", v.ModifyTime
+		switch {
+		case v.DeleteIndex != 0:
+			status, modifyTime = "purged", v.DeleteTime
+		case i > 0:
+			status = "previous"
+		}
+		rows[i+1] = fmt.Sprintf("%d|%s|%d|%s",
+			v.Version,
+			status,
+			v.ModifyIndex,
+			formatUnixNanoTime(modifyTime),
+		)
+	}
+	return formatList(rows)
+}
+
+func (c *VarHistoryCommand) GetConcurrentUI() cli.ConcurrentUi {
+	return cli.ConcurrentUi{Ui: c.Ui}
+}
@@ -0,0 +1,97 @@
+package command
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarHistoryCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarHistoryCommand{}
+}
+
+func TestVarHistoryCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	t.Run("bad_args", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"some", "bad", "args"})
+		out := ui.ErrorWriter.String()
+		require.Equal(t, 1, code, "expected exit code 1, got: %d")
+		require.Contains(t, out, commandErrorText(cmd), "expected help output, got: %s", out)
+	})
+	t.Run("bad_address", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=nope", "foo"})
+		out := ui.ErrorWriter.String()
+		require.Equal(t, 1, code, "expected exit code 1, got: %d")
+		require.Contains(t, out, "retrieving variable history", "connection error, got: %s", out)
+		require.Zero(t, ui.OutputWriter.String())
+	})
+}
+
+func TestVarHistoryCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	t.Cleanup(func() {
+		srv.Shutdown()
+	})
+	testutil.WaitForKeyring(t, srv.Agent.RPC, "global")
+
+	// Update the variable and purge it
+	sv := testVariable()
+	sv.Path = "test/history"
+	_, _, err := client.Variables().Create(sv, nil)
+	require.NoError(t, err)
+	sv.Items["keyA"] = "updated"
+	_, _, err = client.Variables().Update(sv, nil)
+	require.NoError(t, err)
+	_, err = client.Variables().Delete(sv.Path, nil)
+	require.NoError(t, err)
+
+	t.Run("table", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, sv.Path})
+		require.Equal(t, 0, code, "expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+
+		out := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+		require.Len(t, out, 3)
+		require.Regexp(t, `^Version\s+Status\s+Modify Index\s+Modify Time$`, out[0])
+		require.Regexp(t, `^2\s+purged\s+`, out[1])
+		require.Regexp(t, `^1\s+previous\s+`, out[2])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-json", sv.Path})
+		require.Equal(t, 0, code, "expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+
+		var versions []*api.VariableVersionMetadata
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &versions))
+		require.Len(t, versions, 2)
+		require.Equal(t, uint64(2), versions[0].Version)
+		require.NotZero(t, versions[0].DeleteIndex)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarHistoryCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "does/not/exist"})
+		require.Equal(t, 1, code)
+		require.Equal(t, errVariableNotFound, strings.TrimSpace(ui.ErrorWriter.String()))
+	})
+}
@@ -16,9 +16,11 @@ const (
 )
 
 type VarListCommand struct {
-	prefix string
-	outFmt string
-	tmpl   string
+	prefix  string
+	outFmt  string
+	tmpl    string
+	columns string
+	query   string
 	Meta
 }
 
@@ -52,7 +54,7 @@ List Options:
     option are less efficient than using the prefix parameter; therefore,
     the prefix parameter should be used whenever possible.
 
-  -out (go-template | json | table | terse )
+  -out (csv | go-template | json | table | terse | yaml)
     Format to render created or updated variable. Defaults to "none" when
     stdout is a terminal and "json" when the output is redirected. The "terse"
 	format outputs as little information as possible to uniquely identify a
@@ -62,6 +64,14 @@ List Options:
     Template to render output with. Required when format is "go-template",
     invalid for other formats.
 
+  -columns <path,...>
+    Comma-separated paths of the fields to output as columns. Only valid when
+    format is "csv". Defaults to all the top-level fields.
+
+  -query <path>
+    Output only the part of the data selected by a JSONPath-style expression,
+    such as "[*].Path". Invalid when format is "table" or "terse".
+
 `
 	return strings.TrimSpace(helpText)
 }
@@ -69,8 +79,10 @@ List Options:
 func (c *VarListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-out":      complete.PredictSet("go-template", "json", "terse", "table"),
+			"-out":      complete.PredictSet("csv", "go-template", "json", "terse", "table", "yaml"),
 			"-template": complete.PredictAnything,
+			"-columns":  complete.PredictAnything,
+			"-query":    complete.PredictAnything,
 		},
 	)
 }
@@ -91,6 +103,8 @@ func (c *VarListCommand) Run(args []string) int {
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.StringVar(&c.tmpl, "template", "", "")
+	flags.StringVar(&c.columns, "columns", "", "")
+	flags.StringVar(&c.query, "query", "", "")
 
 	flags.IntVar(&perPage, "per-page", 0, "")
 	flags.StringVar(&pageToken, "page-token", "", "")
@@ -149,7 +163,7 @@ func (c *VarListCommand) Run(args []string) int {
 	}
 
 	switch c.outFmt {
-	case "json":
+	case "json", "yaml":
 		// obj and items enable us to rework the output before sending it
 		// to the Format method for transformation into JSON.
 		var obj, items interface{}
@@ -170,9 +184,10 @@ func (c *VarListCommand) Run(args []string) int {
 			}
 		}
 
-		// By this point, the output is ready to be transformed to JSON via
-		// the Format func.
-		out, err := Format(true, "", obj)
+		// By this point, the output is ready to be transformed to JSON or
+		// YAML.
+		formatOpts := FormatOpts{Format: c.outFmt, Query: c.query}
+		out, err := formatOpts.Output(obj)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -189,8 +204,14 @@ func (c *VarListCommand) Run(args []string) int {
 			formatList(
 				dataToQuietStringSlice(vars, c.Meta.namespace)))
 
-	case "go-template":
-		out, err := Format(false, c.tmpl, vars)
+	case "go-template", "csv":
+		formatOpts := FormatOpts{Columns: c.columns, Query: c.query}
+		if c.outFmt == "csv" {
+			formatOpts.Format = c.outFmt
+		} else {
+			formatOpts.Template = c.tmpl
+		}
+		out, err := formatOpts.Output(vars)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -261,8 +282,16 @@ func (c *VarListCommand) validateOutputFlag() error {
 	if c.outFmt != "go-template" && c.tmpl != "" {
 		return errors.New(errUnexpectedTemplate)
 	}
+	if c.outFmt != "csv" && c.columns != "" {
+		return errors.New(errUnexpectedColumns)
+	}
 	switch c.outFmt {
-	case "json", "terse", "table":
+	case "terse", "table":
+		if c.query != "" {
+			return errors.New(errUnexpectedQuery)
+		}
+		return nil
+	case "json", "yaml", "csv":
 		return nil
 	case "go-template":
 		if c.tmpl == "" {
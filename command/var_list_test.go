@@ -62,6 +62,18 @@ func TestVarListCommand_Offline(t *testing.T) {
 			exitCode:           1,
 			expectStdErrPrefix: errUnexpectedTemplate,
 		},
+		{
+			name:               "unexpected_columns",
+			args:               []string{`-out=json`, `-columns=Path`, "foo"},
+			exitCode:           1,
+			expectStdErrPrefix: errUnexpectedColumns,
+		},
+		{
+			name:               "unexpected_query",
+			args:               []string{`-out=table`, `-query=[*].Path`, "foo"},
+			exitCode:           1,
+			expectStdErrPrefix: errUnexpectedQuery,
+		},
 		{
 			name:               "bad out",
 			args:               []string{`-out=bad`, "foo"},
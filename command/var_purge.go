@@ -18,7 +18,9 @@ func (c *VarPurgeCommand) Help() string {
 	helpText := `
 Usage: nomad var purge [options] <path>
 
-  Purge is used to permanently delete an existing variable.
+  Purge is used to delete an existing variable. The last version of the purged
+  variable is kept until it is garbage collected after the variables GC
+  threshold of the servers, and can be restored with 'nomad var rollback'.
 
   If ACLs are enabled, this command requires a token with the 'variables:destroy'
   capability for the target variable's namespace and path.
@@ -0,0 +1,134 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type VarRollbackCommand struct {
+	Meta
+}
+
+func (c *VarRollbackCommand) Help() string {
+	helpText := `
+Usage: nomad var rollback [options] <path> <version>
+
+  Rollback is used to write the items of a previous version of a variable, as
+  listed by 'nomad var history', as its new version. A purged variable can be
+  restored this way until it is garbage collected.
+
+  The rollback fails with a check-and-set conflict if the variable is modified
+  while it is rolled back.
+
+  If ACLs are enabled, this command requires a token with the 'variables:read'
+  and 'variables:write' capabilities for the target variable's namespace and
+  path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarRollbackCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *VarRollbackCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarRollbackCommand) Synopsis() string {
+	return "Roll a variable back to a previous version"
+}
+
+func (c *VarRollbackCommand) Name() string { return "var rollback" }
+
+func (c *VarRollbackCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got two arguments
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <path> <version>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	path := args[0]
+	version, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil || version == 0 {
+		c.Ui.Error(fmt.Sprintf("Invalid version %q: must be a positive integer", args[1]))
+		return 1
+	}
+
+	if c.Meta.namespace == "*" {
+		c.Ui.Error(errWildcardNamespaceNotAllowed)
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	qo := &api.QueryOptions{
+		Namespace: c.Meta.namespace,
+	}
+
+	prev, _, err := client.Variables().ReadVersion(path, version, qo)
+	if err != nil {
+		if err.Error() == api.ErrVariableNotFound {
+			c.Ui.Error(fmt.Sprintf("Version %d of variable %q not found", version, path))
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error retrieving variable version: %s", err))
+		return 1
+	}
+
+	// The current version is used as the check index, so the rollback doesn't
+	// overwrite a concurrent write. A purged variable has no current version.
+	current, _, err := client.Variables().Peek(path, qo)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving variable: %s", err))
+		return 1
+	}
+
+	sv := &api.Variable{
+		Namespace: c.Meta.namespace,
+		Path:      path,
+		Items:     prev.Items,
+	}
+	if current != nil {
+		sv.ModifyIndex = current.ModifyIndex
+	}
+
+	sv, _, err = client.Variables().CheckedUpdate(sv, &api.WriteOptions{Namespace: c.Meta.namespace})
+	if err != nil {
+		if handled := handleCASError(err, c); handled {
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error rolling back variable: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Rolled back variable %q to version %d as version %d", path, version, sv.Version))
+	return 0
+}
+
+func (c *VarRollbackCommand) GetConcurrentUI() cli.ConcurrentUi {
+	return cli.ConcurrentUi{Ui: c.Ui}
+}
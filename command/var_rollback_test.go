@@ -0,0 +1,93 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarRollbackCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarRollbackCommand{}
+}
+
+func TestVarRollbackCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	t.Run("bad_args", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"foo"})
+		out := ui.ErrorWriter.String()
+		require.Equal(t, 1, code, "expected exit code 1, got: %d")
+		require.Contains(t, out, commandErrorText(cmd), "expected help output, got: %s", out)
+	})
+	t.Run("bad_version", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"foo", "latest"})
+		out := strings.TrimSpace(ui.ErrorWriter.String())
+		require.Equal(t, 1, code, "expected exit code 1, got: %d")
+		require.Equal(t, `Invalid version "latest": must be a positive integer`, out)
+	})
+}
+
+func TestVarRollbackCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	t.Cleanup(func() {
+		srv.Shutdown()
+	})
+	testutil.WaitForKeyring(t, srv.Agent.RPC, "global")
+
+	sv := testVariable()
+	sv.Path = "test/rollback"
+	_, _, err := client.Variables().Create(sv, nil)
+	require.NoError(t, err)
+	update := sv.Copy()
+	update.Items["keyA"] = "updated"
+	_, _, err = client.Variables().Update(update, nil)
+	require.NoError(t, err)
+
+	t.Run("previous_version", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, sv.Path, "1"})
+		require.Equal(t, 0, code, "expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "to version 1 as version 3")
+
+		current, _, err := client.Variables().Read(sv.Path, nil)
+		require.NoError(t, err)
+		require.Equal(t, sv.Items, current.Items)
+	})
+
+	t.Run("purged_variable", func(t *testing.T) {
+		_, err := client.Variables().Delete(sv.Path, nil)
+		require.NoError(t, err)
+
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, sv.Path, "2"})
+		require.Equal(t, 0, code, "expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "to version 2 as version 4")
+
+		current, _, err := client.Variables().Read(sv.Path, nil)
+		require.NoError(t, err)
+		require.Equal(t, update.Items, current.Items)
+	})
+
+	t.Run("missing_version", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarRollbackCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, sv.Path, "99"})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), `Version 99 of variable "test/rollback" not found`)
+	})
+}
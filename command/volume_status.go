@@ -10,11 +10,10 @@ import (
 
 type VolumeStatusCommand struct {
 	Meta
-	length   int
-	short    bool
-	verbose  bool
-	json     bool
-	template string
+	length     int
+	short      bool
+	verbose    bool
+	formatOpts FormatOpts
 }
 
 func (c *VolumeStatusCommand) Help() string {
@@ -49,6 +48,8 @@ Status Options:
 
   -t
     Format and display allocation using a Go template.
+
+  ` + formatOptionsUsage + `
 `
 	return strings.TrimSpace(helpText)
 }
@@ -65,6 +66,9 @@ func (c *VolumeStatusCommand) AutocompleteFlags() complete.Flags {
 			"-verbose": complete.PredictNothing,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-format":  complete.PredictSet("json", "yaml", "csv"),
+			"-columns": complete.PredictAnything,
+			"-query":   complete.PredictAnything,
 		})
 }
 
@@ -93,8 +97,7 @@ func (c *VolumeStatusCommand) Run(args []string) int {
 	flags.StringVar(&typeArg, "type", "", "")
 	flags.BoolVar(&c.short, "short", false, "")
 	flags.BoolVar(&c.verbose, "verbose", false, "")
-	flags.BoolVar(&c.json, "json", false, "")
-	flags.StringVar(&c.template, "t", "", "")
+	c.formatOpts.SetFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing arguments %s", err))
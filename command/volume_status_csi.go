@@ -11,7 +11,7 @@ import (
 )
 
 func (c *VolumeStatusCommand) csiBanner() {
-	if !(c.json || len(c.template) > 0) {
+	if !c.formatOpts.Enabled() {
 		c.Ui.Output(c.Colorize().Color("[bold]Container Storage Interface[reset]"))
 	}
 }
@@ -170,8 +170,8 @@ func (c *VolumeStatusCommand) csiFormatVolumes(vols []*api.CSIVolumeListStub) (s
 	// Sort the output by volume id
 	sort.Slice(vols, func(i, j int) bool { return vols[i].ID < vols[j].ID })
 
-	if c.json || len(c.template) > 0 {
-		out, err := Format(c.json, c.template, vols)
+	if c.formatOpts.Enabled() {
+		out, err := c.formatOpts.Output(vols)
 		if err != nil {
 			return "", fmt.Errorf("format error: %v", err)
 		}
@@ -198,8 +198,8 @@ func csiFormatSortedVolumes(vols []*api.CSIVolumeListStub) (string, error) {
 }
 
 func (c *VolumeStatusCommand) formatBasic(vol *api.CSIVolume) (string, error) {
-	if c.json || len(c.template) > 0 {
-		out, err := Format(c.json, c.template, vol)
+	if c.formatOpts.Enabled() {
+		out, err := c.formatOpts.Output(vol)
 		if err != nil {
 			return "", fmt.Errorf("format error: %v", err)
 		}
@@ -59,6 +59,11 @@ var msgTypeNames = map[structs.MessageType]string{
 	structs.RootKeyMetaDeleteRequestType:                 "RootKeyMetaDeleteRequestType",
 	structs.ACLRolesUpsertRequestType:                    "ACLRolesUpsertRequestType",
 	structs.ACLRolesDeleteByIDRequestType:                "ACLRolesDeleteByIDRequestType",
+	structs.JobTemplateUpsertRequestType:                 "JobTemplateUpsertRequestType",
+	structs.JobTemplateDeleteRequestType:                 "JobTemplateDeleteRequestType",
+	structs.DispatchIdempotencyExpireRequestType:         "DispatchIdempotencyExpireRequestType",
+	structs.VarExpireDeletedRequestType:                  "VarExpireDeletedRequestType",
+	structs.ACLTokenUsageUpsertRequestType:               "ACLTokenUsageUpsertRequestType",
 	structs.NamespaceUpsertRequestType:                   "NamespaceUpsertRequestType",
 	structs.NamespaceDeleteRequestType:                   "NamespaceDeleteRequestType",
 }
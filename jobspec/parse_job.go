@@ -33,6 +33,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	delete(m, "migrate")
 	delete(m, "parameterized")
 	delete(m, "periodic")
+	delete(m, "preemption")
 	delete(m, "reschedule")
 	delete(m, "update")
 	delete(m, "vault")
@@ -71,6 +72,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"namespace",
 		"parameterized",
 		"periodic",
+		"preemption",
 		"priority",
 		"region",
 		"reschedule",
@@ -128,6 +130,13 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// If we have a preemption policy, then parse that
+	if o := listVal.Filter("preemption"); len(o.Items) > 0 {
+		if err := parsePreemption(&result.Preemption, o); err != nil {
+			return multierror.Prefix(err, "preemption ->")
+		}
+	}
+
 	// If we have a reschedule stanza, then parse that
 	if o := listVal.Filter("reschedule"); len(o.Items) > 0 {
 		if err := parseReschedulePolicy(&result.Reschedule, o); err != nil {
@@ -293,3 +302,37 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 	*result = &d
 	return nil
 }
+
+func parsePreemption(result **api.PreemptionPolicy, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'preemption' block allowed per job")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"never_preempt",
+		"max_per_hour",
+		"protect_deployments",
+	}
+	if err := checkHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	// Build the preemption policy
+	var p api.PreemptionPolicy
+	if err := mapstructure.WeakDecode(m, &p); err != nil {
+		return err
+	}
+
+	*result = &p
+	return nil
+}
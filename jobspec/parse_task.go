@@ -32,6 +32,9 @@ var (
 		"constraint",
 		"affinity",
 		"dispatch_payload",
+		"env_from_variable",
+		"file_from_variable",
+		"identity",
 		"lifecycle",
 		"leader",
 		"restart",
@@ -110,6 +113,9 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 	delete(m, "service")
 	delete(m, "template")
 	delete(m, "vault")
+	delete(m, "identity")
+	delete(m, "env_from_variable")
+	delete(m, "file_from_variable")
 	delete(m, "volume_mount")
 	delete(m, "csi_plugin")
 	delete(m, "scaling")
@@ -308,6 +314,27 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		t.Vault = v
 	}
 
+	// Parse workload identities
+	if o := listVal.Filter("identity"); len(o.Items) > 0 {
+		if err := parseIdentities(&t.Identities, o); err != nil {
+			return nil, multierror.Prefix(err, "identity ->")
+		}
+	}
+
+	// Parse variables exposed as environment variables
+	if o := listVal.Filter("env_from_variable"); len(o.Items) > 0 {
+		if err := parseEnvFromVariables(&t.EnvFromVariables, o); err != nil {
+			return nil, multierror.Prefix(err, "env_from_variable ->")
+		}
+	}
+
+	// Parse variables written to files
+	if o := listVal.Filter("file_from_variable"); len(o.Items) > 0 {
+		if err := parseFileFromVariables(&t.FileFromVariables, o); err != nil {
+			return nil, multierror.Prefix(err, "file_from_variable ->")
+		}
+	}
+
 	// If we have a dispatch_payload block parse that
 	if o := listVal.Filter("dispatch_payload"); len(o.Items) > 0 {
 		if len(o.Items) > 1 {
@@ -432,6 +459,138 @@ func parseArtifactOption(result map[string]string, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseIdentities(result *[]*api.WorkloadIdentity, list *ast.ObjectList) error {
+	list = list.Children()
+
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("identity '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// we'll need a list of all ast objects for later
+		var listVal *ast.ObjectList
+		if ot, ok := item.Val.(*ast.ObjectType); ok {
+			listVal = ot.List
+		} else {
+			return fmt.Errorf("identity '%s': should be an object", n)
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"aud",
+			"ttl",
+			"env",
+			"file",
+			"change_mode",
+			"change_signal",
+			"claims",
+		}
+		if err := checkHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s',", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+		delete(m, "claims") // claims is its own object
+
+		wi := &api.WorkloadIdentity{Name: n}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           wi,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return err
+		}
+
+		// If we have claims, then parse them
+		if o := listVal.Filter("claims"); len(o.Items) > 0 {
+			for _, o := range o.Elem().Items {
+				var m map[string]interface{}
+				if err := hcl.DecodeObject(&m, o.Val); err != nil {
+					return err
+				}
+				if err := mapstructure.WeakDecode(m, &wi.Claims); err != nil {
+					return err
+				}
+			}
+		}
+
+		*result = append(*result, wi)
+	}
+
+	return nil
+}
+
+func parseEnvFromVariables(result *[]*api.EnvFromVariable, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"path",
+			"prefix",
+			"change_mode",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		e := &api.EnvFromVariable{}
+		if err := mapstructure.WeakDecode(m, e); err != nil {
+			return err
+		}
+
+		*result = append(*result, e)
+	}
+
+	return nil
+}
+
+func parseFileFromVariables(result *[]*api.FileFromVariable, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"path",
+			"item",
+			"destination",
+			"perms",
+			"change_mode",
+			"change_signal",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		f := &api.FileFromVariable{}
+		if err := mapstructure.WeakDecode(m, f); err != nil {
+			return err
+		}
+
+		*result = append(*result, f)
+	}
+
+	return nil
+}
+
 func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// we'll need a list of all ast objects for later
@@ -607,6 +766,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		"network",
 		"device",
 		"cores",
+		"numa",
 	}
 	if err := checkHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "resources ->")
@@ -618,6 +778,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 	}
 	delete(m, "network")
 	delete(m, "device")
+	delete(m, "numa")
 
 	if err := mapstructure.WeakDecode(m, result); err != nil {
 		return err
@@ -696,6 +857,28 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse the NUMA affinity
+	if o := listVal.Filter("numa"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return fmt.Errorf("only one 'numa' block allowed per resources")
+		}
+
+		if err := checkHCLKeys(o.Items[0].Val, []string{"affinity"}); err != nil {
+			return multierror.Prefix(err, "resources, numa ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Items[0].Val); err != nil {
+			return err
+		}
+
+		var numa api.NUMAResource
+		if err := mapstructure.WeakDecode(m, &numa); err != nil {
+			return err
+		}
+		result.NUMA = &numa
+	}
+
 	return nil
 }
 
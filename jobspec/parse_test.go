@@ -854,6 +854,53 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"gang.hcl",
+			&api.Job{
+				ID:   stringToPtr("gang"),
+				Name: stringToPtr("gang"),
+				Type: stringToPtr("batch"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name:  stringToPtr("workers"),
+						Count: intToPtr(4),
+						Gang:  boolToPtr(true),
+						Tasks: []*api.Task{
+							{
+								Name:   "mpi",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"preemption.hcl",
+			&api.Job{
+				ID:   stringToPtr("preemption"),
+				Name: stringToPtr("preemption"),
+
+				Preemption: &api.PreemptionPolicy{
+					MaxPerHour:         2,
+					ProtectDeployments: true,
+				},
+
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("foo"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"job-with-kill-signal.hcl",
 			&api.Job{
@@ -1822,6 +1869,105 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"resources-numa.hcl",
+			&api.Job{
+				ID:   stringToPtr("numa-test"),
+				Name: stringToPtr("numa-test"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+								Resources: &api.Resources{
+									Cores:    intToPtr(4),
+									MemoryMB: intToPtr(128),
+									NUMA: &api.NUMAResource{
+										Affinity: "require",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"task-identity.hcl",
+			&api.Job{
+				ID:   stringToPtr("identity-test"),
+				Name: stringToPtr("identity-test"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+								Identities: []*api.WorkloadIdentity{
+									{
+										Name:     "vault",
+										Audience: []string{"vault.io"},
+									},
+									{
+										Name:         "aws",
+										Audience:     []string{"sts.amazonaws.com"},
+										TTL:          timeToPtr(time.Hour),
+										Env:          boolToPtr(true),
+										File:         boolToPtr(false),
+										ChangeMode:   stringToPtr("signal"),
+										ChangeSignal: stringToPtr("SIGHUP"),
+										Claims: map[string]string{
+											"datacenter": "${node.datacenter}",
+											"team":       "${meta.team}",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"task-variables.hcl",
+			&api.Job{
+				ID:   stringToPtr("variables-test"),
+				Name: stringToPtr("variables-test"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+								EnvFromVariables: []*api.EnvFromVariable{
+									{
+										Path:   stringToPtr("nomad/jobs/variables-test/db"),
+										Prefix: stringToPtr("DB_"),
+									},
+								},
+								FileFromVariables: []*api.FileFromVariable{
+									{
+										Path:         stringToPtr("nomad/jobs/variables-test/db"),
+										Item:         stringToPtr("cert"),
+										Destination:  stringToPtr("db/cert.pem"),
+										Perms:        stringToPtr("0600"),
+										ChangeMode:   stringToPtr("signal"),
+										ChangeSignal: stringToPtr("SIGHUP"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"service-provider.hcl",
 			&api.Job{
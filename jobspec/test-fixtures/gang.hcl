@@ -0,0 +1,12 @@
+job "gang" {
+  type = "batch"
+
+  group "workers" {
+    count = 4
+    gang  = true
+
+    task "mpi" {
+      driver = "docker"
+    }
+  }
+}
@@ -0,0 +1,12 @@
+job "preemption" {
+  preemption {
+    max_per_hour        = 2
+    protect_deployments = true
+  }
+
+  group "foo" {
+    task "bar" {
+      driver = "docker"
+    }
+  }
+}
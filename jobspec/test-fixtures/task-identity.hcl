@@ -0,0 +1,25 @@
+job "identity-test" {
+  group "group" {
+    task "task" {
+      driver = "docker"
+
+      identity "vault" {
+        aud = ["vault.io"]
+      }
+
+      identity "aws" {
+        aud           = ["sts.amazonaws.com"]
+        ttl           = "1h"
+        env           = true
+        file          = false
+        change_mode   = "signal"
+        change_signal = "SIGHUP"
+
+        claims {
+          datacenter = "${node.datacenter}"
+          team       = "${meta.team}"
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,21 @@
+job "variables-test" {
+  group "group" {
+    task "task" {
+      driver = "docker"
+
+      env_from_variable {
+        path   = "nomad/jobs/variables-test/db"
+        prefix = "DB_"
+      }
+
+      file_from_variable {
+        path          = "nomad/jobs/variables-test/db"
+        item          = "cert"
+        destination   = "db/cert.pem"
+        perms         = "0600"
+        change_mode   = "signal"
+        change_signal = "SIGHUP"
+      }
+    }
+  }
+}
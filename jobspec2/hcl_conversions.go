@@ -19,7 +19,6 @@ var hclDecoder *gohcl.Decoder
 
 func init() {
 	hclDecoder = newHCLDecoder()
-	hclDecoder.RegisterBlockDecoder(reflect.TypeOf(api.TaskGroup{}), decodeTaskGroup)
 	hclDecoder.RegisterBlockDecoder(reflect.TypeOf(api.Task{}), decodeTask)
 }
 
@@ -247,7 +246,10 @@ func decodeConstraint(body hcl.Body, ctx *hcl.EvalContext, val interface{}) hcl.
 	return diags
 }
 
-func decodeTaskGroup(body hcl.Body, ctx *hcl.EvalContext, val interface{}) hcl.Diagnostics {
+// decodeTaskGroup decodes a group block. It is a method of the jobConfig so
+// that the include blocks of the group are resolved relative to the file
+// being parsed.
+func (c *jobConfig) decodeTaskGroup(body hcl.Body, ctx *hcl.EvalContext, val interface{}) hcl.Diagnostics {
 	tg := val.(*api.TaskGroup)
 
 	var diags hcl.Diagnostics
@@ -260,24 +262,47 @@ func decodeTaskGroup(body hcl.Body, ctx *hcl.EvalContext, val interface{}) hcl.D
 	}{}
 
 	extra, _ := gohcl.ImpliedBodySchema(tgExtra)
+	extra.Blocks = append(extra.Blocks, hcl.BlockHeaderSchema{Type: includeLabel, LabelNames: []string{"name"}})
 	content, tgBody, moreDiags := body.PartialContent(extra)
 	diags = append(diags, moreDiags...)
 	if len(diags) != 0 {
 		return diags
 	}
 
+	var included []*api.Task
 	for _, b := range content.Blocks {
-		if b.Type == "vault" {
+		switch b.Type {
+		case "vault":
 			v := &api.Vault{}
 			diags = append(diags, hclDecoder.DecodeBody(b.Body, ctx, v)...)
 			tgExtra.Vault = v
+		case includeLabel:
+			inc, moreDiags := c.decodeInclude(b, ctx)
+			diags = append(diags, moreDiags...)
+			if inc == nil {
+				continue
+			}
+			for _, g := range inc.Groups {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unsupported block type",
+					Detail:   fmt.Sprintf("Group %q of %s cannot be included in a group, only tasks can.", *g.Name, inc.Path),
+					Subject:  b.DefRange.Ptr(),
+				})
+			}
+			included = append(included, inc.Tasks...)
 		}
 	}
+	if diags.HasErrors() {
+		return diags
+	}
 
 	d := newHCLDecoder()
 	d.RegisterBlockDecoder(reflect.TypeOf(api.Task{}), decodeTask)
 	diags = d.DecodeBody(tgBody, ctx, tg)
 
+	tg.Tasks = append(tg.Tasks, included...)
+
 	if metaAttr != nil {
 		tg.Meta = metaAttr
 	}
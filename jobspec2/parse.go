@@ -38,16 +38,66 @@ func Parse(path string, r io.Reader) (*api.Job, error) {
 }
 
 func ParseWithConfig(args *ParseConfig) (*api.Job, error) {
+	job, _, err := ParseWithVariables(args)
+	return job, err
+}
+
+// ParseWithVariables is like ParseWithConfig, but also returns the values of
+// the input variables set from the command line, variable files or the
+// environment, formatted as HCL expressions. The values of the variables
+// declared as sensitive are replaced with api.JobSubmissionSensitiveValue.
+func ParseWithVariables(args *ParseConfig) (*api.Job, map[string]string, error) {
+	job, sub, err := ParseWithSubmission(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, sub.Variables, nil
+}
+
+// ParseWithSubmission is like ParseWithConfig, but also returns the
+// submission of the job: its source, the values of its input variables as
+// returned by ParseWithVariables, and the sources of the files it includes.
+func ParseWithSubmission(args *ParseConfig) (*api.Job, *api.JobSubmission, error) {
 	args.normalize()
 
 	c := newJobConfig(args)
 	err := decode(c)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	normalizeJob(c)
-	return c.Job, nil
+	sub := &api.JobSubmission{
+		Source:    string(args.Body),
+		Format:    api.JobSubmissionFormatHCL2,
+		Variables: c.InputVariables.submittedValues(),
+	}
+	if len(c.includes) > 0 {
+		sub.Includes = c.includes
+	}
+	return c.Job, sub, nil
+}
+
+// ParseVariables parses the input variables declared by the jobspec, without
+// collecting their values or decoding the job.
+func ParseVariables(args *ParseConfig) (Variables, error) {
+	args.normalize()
+
+	file, diags := parseHCLOrJSON(args.Body, args.Path)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	content, _, diags := file.Body.PartialContent(jobConfigSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	c := newJobConfig(args)
+	if diags := c.decodeInputVariables(content); diags.HasErrors() {
+		return nil, diags
+	}
+	return c.InputVariables, nil
 }
 
 type ParseConfig struct {
@@ -187,6 +187,64 @@ job "example" {
 	})
 }
 
+func TestParseWithVariables(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+variable "dc_var" {
+  default = "default_dc"
+}
+
+variable "count_var" {
+  default = 1
+}
+
+variable "password" {
+  default   = "default_password"
+  sensitive = true
+}
+
+job "example" {
+  datacenters = [var.dc_var]
+  meta {
+    password = var.password
+  }
+  group "group" {
+    count = var.count_var
+  }
+}
+`
+
+	t.Run("defaults", func(t *testing.T) {
+		out, vars, err := ParseWithVariables(&ParseConfig{
+			Path:    "input.hcl",
+			Body:    []byte(hcl),
+			AllowFS: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"default_dc"}, out.Datacenters)
+		require.Nil(t, vars)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		out, vars, err := ParseWithVariables(&ParseConfig{
+			Path:    "input.hcl",
+			Body:    []byte(hcl),
+			ArgVars: []string{"dc_var=set_dc", "password=hunter2"},
+			Envs:    []string{"NOMAD_VAR_count_var=3"},
+			AllowFS: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"set_dc"}, out.Datacenters)
+		require.Equal(t, "hunter2", out.Meta["password"])
+		require.Equal(t, map[string]string{
+			"dc_var":    `"set_dc"`,
+			"count_var": "3",
+			"password":  api.JobSubmissionSensitiveValue,
+		}, vars)
+	})
+}
+
 // TestParse_UnknownVariables asserts that unknown variables are left intact for further processing
 func TestParse_UnknownVariables(t *testing.T) {
 	ci.Parallel(t)
@@ -1052,3 +1110,205 @@ func TestWaitConfig(t *testing.T) {
 	require.Equal(t, 5*time.Second, *tmpl.Wait.Min)
 	require.Equal(t, 60*time.Second, *tmpl.Wait.Max)
 }
+
+func TestParse_Include(t *testing.T) {
+	ci.Parallel(t)
+
+	path := "test-fixtures/include/job.nomad.hcl"
+	hclBytes, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	t.Run("groups and tasks", func(t *testing.T) {
+		job, err := ParseWithConfig(&ParseConfig{
+			Path:    path,
+			Body:    hclBytes,
+			ArgVars: []string{"env=staging"},
+			AllowFS: true,
+			Strict:  true,
+		})
+		require.NoError(t, err)
+		require.Len(t, job.TaskGroups, 2)
+
+		web := job.TaskGroups[0]
+		require.Equal(t, "web", *web.Name)
+		require.Len(t, web.Tasks, 2)
+		require.Equal(t, "web", web.Tasks[0].Name)
+
+		logging := web.Tasks[1]
+		require.Equal(t, "logging", logging.Name)
+		require.Equal(t, "fluent/fluent-bit:2.0", logging.Config["image"])
+		require.Equal(t, []interface{}{"--tag", "staging-logs"}, logging.Config["args"])
+		require.Equal(t, map[string]string{"ENVIRONMENT": "staging"}, logging.Env)
+		require.True(t, logging.Lifecycle.Sidecar)
+
+		cache := job.TaskGroups[1]
+		require.Equal(t, "cache", *cache.Name)
+		require.Len(t, cache.Tasks, 2)
+		require.Equal(t, "redis:7", cache.Tasks[0].Config["image"])
+		require.Equal(t, []interface{}{"--tag", "cache-logs"}, cache.Tasks[1].Config["args"])
+	})
+
+	t.Run("submission", func(t *testing.T) {
+		_, sub, err := ParseWithSubmission(&ParseConfig{
+			Path:    path,
+			Body:    hclBytes,
+			ArgVars: []string{"env=staging"},
+			AllowFS: true,
+			Strict:  true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, string(hclBytes), sub.Source)
+		require.Equal(t, api.JobSubmissionFormatHCL2, sub.Format)
+
+		logging, err := os.ReadFile("test-fixtures/include/fragments/logging.nomad.hcl")
+		require.NoError(t, err)
+		cache, err := os.ReadFile("test-fixtures/include/fragments/cache.nomad.hcl")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"fragments/logging.nomad.hcl": string(logging),
+			"fragments/cache.nomad.hcl":   string(cache),
+		}, sub.Includes)
+	})
+
+	t.Run("without fs access", func(t *testing.T) {
+		_, err := ParseWithConfig(&ParseConfig{
+			Path:    path,
+			Body:    hclBytes,
+			AllowFS: false,
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Include not allowed")
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		path := "test-fixtures/include/cycle.nomad.hcl"
+		hclBytes, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		_, err = ParseWithConfig(&ParseConfig{
+			Path:    path,
+			Body:    hclBytes,
+			AllowFS: true,
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Include cycle")
+	})
+}
+
+func TestParse_Include_Diagnostics(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	fragment := `
+variable "image" {
+  type = string
+}
+
+task "sidecar" {
+  driver = "docker"
+  config {
+    image = var.image
+  }
+  resources {
+    cpu = "lots"
+  }
+}
+`
+	require.NoError(t, os.WriteFile(dir+"/sidecar.hcl", []byte(fragment), 0644))
+
+	cases := []struct {
+		name    string
+		include string
+		expErr  string
+	}{
+		{
+			name:    "missing variable",
+			include: `source = "./sidecar.hcl"`,
+			expErr:  `job.hcl:4,5-22: Missing required argument; The input variable "image" declared at`,
+		},
+		{
+			name: "unknown variable",
+			include: `source = "./sidecar.hcl"
+    image  = "redis"
+    tag    = "latest"`,
+			expErr: `job.hcl:7,5-8: Unsupported argument; An input variable named "tag" is not declared`,
+		},
+		{
+			name: "error in included file",
+			include: `source = "./sidecar.hcl"
+    image  = "redis"`,
+			expErr: `sidecar.hcl:12,12-16: Unsuitable value type`,
+		},
+		{
+			name:    "missing file",
+			include: `source = "./nope.hcl"`,
+			expErr:  `job.hcl:5,14-26: Failed to read included file`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			hcl := `job "example" {
+  group "group" {
+    task "main" {}
+    include "sidecar" {
+    ` + tc.include + `
+    }
+  }
+}
+`
+			_, err := ParseWithConfig(&ParseConfig{
+				Path:    dir + "/job.hcl",
+				Body:    []byte(hcl),
+				AllowFS: true,
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expErr)
+		})
+	}
+}
+
+func TestParseVariables(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+variable "image" {
+  type        = string
+  description = "The image to run"
+}
+
+variable "count" {
+  default = 1
+}
+
+variables {
+  region = "global"
+}
+
+job "example" {
+  group "group" {
+    count = var.count
+    task "main" {
+      config {
+        image = var.image
+      }
+    }
+  }
+}
+`
+	vars, err := ParseVariables(&ParseConfig{
+		Path: "input.hcl",
+		Body: []byte(hcl),
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"count", "image", "region"}, vars.Keys())
+	require.Equal(t, "The image to run", vars["image"].Description)
+	require.Empty(t, vars["image"].Values)
+	require.Len(t, vars["count"].Values, 1)
+
+	_, err = ParseVariables(&ParseConfig{
+		Path: "input.hcl",
+		Body: []byte(`variable "image" {`),
+	})
+	require.ErrorContains(t, err, "input.hcl:1,19-19: Argument or block definition required")
+}
@@ -0,0 +1,5 @@
+job "cycle" {
+  include "self" {
+    source = "./cycle.nomad.hcl"
+  }
+}
@@ -0,0 +1,18 @@
+variable "image" {
+  type = string
+}
+
+group "cache" {
+  task "redis" {
+    driver = "docker"
+
+    config {
+      image = var.image
+    }
+  }
+
+  include "logging" {
+    source      = "./logging.nomad.hcl"
+    environment = "cache"
+  }
+}
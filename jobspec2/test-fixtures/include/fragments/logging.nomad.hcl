@@ -0,0 +1,31 @@
+# A sidecar task shipping the logs of the group.
+
+variable "environment" {
+  type = string
+}
+
+variable "image" {
+  default = "fluent/fluent-bit:2.0"
+}
+
+locals {
+  tag = "${var.environment}-logs"
+}
+
+task "logging" {
+  driver = "docker"
+
+  lifecycle {
+    hook    = "prestart"
+    sidecar = true
+  }
+
+  config {
+    image = var.image
+    args  = ["--tag", local.tag]
+  }
+
+  env {
+    ENVIRONMENT = var.environment
+  }
+}
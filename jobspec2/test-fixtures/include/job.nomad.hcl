@@ -0,0 +1,27 @@
+variable "env" {
+  default = "prod"
+}
+
+job "web" {
+  datacenters = ["dc1"]
+
+  group "web" {
+    task "web" {
+      driver = "docker"
+
+      config {
+        image = "nginx:1.23"
+      }
+    }
+
+    include "logging" {
+      source      = "./fragments/logging.nomad.hcl"
+      environment = var.env
+    }
+  }
+
+  include "cache" {
+    source = "./fragments/cache.nomad.hcl"
+    image  = "redis:7"
+  }
+}
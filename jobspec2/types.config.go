@@ -2,10 +2,13 @@ package jobspec2
 
 import (
 	"fmt"
+	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/dynblock"
+	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/jobspec2/hclutil"
 	"github.com/zclconf/go-cty/cty"
@@ -17,6 +20,8 @@ const (
 	localsLabel    = "locals"
 	vaultLabel     = "vault"
 	taskLabel      = "task"
+	groupLabel     = "group"
+	includeLabel   = "include"
 
 	inputVariablesAccessor = "var"
 	localsAccessor         = "local"
@@ -31,10 +36,23 @@ type jobConfig struct {
 	Vault *api.Vault  `hcl:"vault,block"`
 	Tasks []*api.Task `hcl:"task,block"`
 
+	// Groups are the groups pulled in by the include blocks of the job
+	Groups []*api.TaskGroup
+
 	InputVariables Variables
 	LocalVariables Variables
 
 	LocalBlocks []*LocalBlock
+
+	// includedFrom is the chain of files that included the file being
+	// parsed, used to detect include cycles
+	includedFrom []string
+
+	// includes are the sources of the files included by the jobspec, keyed
+	// by their path relative to the directory of the jobspec. They are
+	// shared by the configs of the included files.
+	includes    map[string]string
+	includesDir string
 }
 
 func newJobConfig(parseConfig *ParseConfig) *jobConfig {
@@ -43,6 +61,9 @@ func newJobConfig(parseConfig *ParseConfig) *jobConfig {
 
 		InputVariables: Variables{},
 		LocalVariables: Variables{},
+
+		includes:    map[string]string{},
+		includesDir: filepath.Dir(parseConfig.Path),
 	}
 }
 
@@ -171,6 +192,13 @@ func (c *jobConfig) decodeTopLevelExtras(content *hcl.BodyContent, ctx *hcl.Eval
 				t.Name = b.Labels[0]
 				c.Tasks = append(c.Tasks, t)
 			}
+		} else if b.Type == includeLabel {
+			inc, moreDiags := c.decodeInclude(b, ctx)
+			diags = append(diags, moreDiags...)
+			if inc != nil {
+				c.Groups = append(c.Groups, inc.Groups...)
+				c.Tasks = append(c.Tasks, inc.Tasks...)
+			}
 		}
 	}
 
@@ -275,12 +303,15 @@ func (c *jobConfig) decodeJob(content *hcl.BodyContent, ctx *hcl.EvalContext) hc
 			Blocks: []hcl.BlockHeaderSchema{
 				{Type: "vault"},
 				{Type: "task", LabelNames: []string{"name"}},
+				{Type: includeLabel, LabelNames: []string{"name"}},
 			},
 		})
 
 		diags = append(diags, mdiags...)
 		diags = append(diags, c.decodeTopLevelExtras(extra, ctx)...)
-		diags = append(diags, hclDecoder.DecodeBody(remain, ctx, c.Job)...)
+		diags = append(diags, c.decoder().DecodeBody(remain, ctx, c.Job)...)
+
+		c.Job.TaskGroups = append(c.Job.TaskGroups, c.Groups...)
 
 		if metaAttr != nil {
 			c.Job.Meta = metaAttr
@@ -299,6 +330,15 @@ func (c *jobConfig) decodeJob(content *hcl.BodyContent, ctx *hcl.EvalContext) hc
 
 }
 
+// decoder returns the decoder of the job and group blocks of the file being
+// parsed.
+func (c *jobConfig) decoder() *gohcl.Decoder {
+	decoder := newHCLDecoder()
+	decoder.RegisterBlockDecoder(reflect.TypeOf(api.TaskGroup{}), c.decodeTaskGroup)
+	decoder.RegisterBlockDecoder(reflect.TypeOf(api.Task{}), decodeTask)
+	return decoder
+}
+
 func (c *jobConfig) EvalContext() *hcl.EvalContext {
 	vars, _ := c.InputVariables.Values()
 	locals, _ := c.LocalVariables.Values()
@@ -0,0 +1,273 @@
+package jobspec2
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/dynblock"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/jobspec2/hclutil"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+const (
+	// includeSourceAttr is the attribute of the include block holding the
+	// path of the included file. All the other attributes of the block set
+	// the input variables of the included file.
+	includeSourceAttr = "source"
+)
+
+// includeFileSchema is the schema of an included file. Included files declare
+// their own input variables and locals, and define the groups or tasks they
+// contribute to the job.
+var includeFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: variablesLabel},
+		{Type: variableLabel, LabelNames: []string{"name"}},
+		{Type: localsLabel},
+		{Type: groupLabel, LabelNames: []string{"name"}},
+		{Type: taskLabel, LabelNames: []string{"name"}},
+		{Type: includeLabel, LabelNames: []string{"name"}},
+	},
+}
+
+// included is the result of decoding an include block.
+type included struct {
+	// Path is the path of the included file
+	Path string
+
+	Groups []*api.TaskGroup
+	Tasks  []*api.Task
+}
+
+// decodeInclude reads the file referenced by the include block and decodes
+// the groups and tasks it defines. The attributes of the block are evaluated
+// with the context of the including file, and the blocks of the included file
+// with a context of their own, where only the variables and locals declared
+// in the included file are visible.
+func (c *jobConfig) decodeInclude(block *hcl.Block, ctx *hcl.EvalContext) (*included, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	if !c.ParseConfig.AllowFS {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Include not allowed",
+			Detail:   "Including files requires file system access, which is not allowed in this context.",
+			Subject:  block.DefRange.Ptr(),
+		})
+	}
+
+	attrs, moreDiags := block.Body.JustAttributes()
+	diags = append(diags, moreDiags...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	sourceAttr, ok := attrs[includeSourceAttr]
+	if !ok {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing required argument",
+			Detail:   fmt.Sprintf("The argument %q is required, but no definition was found.", includeSourceAttr),
+			Subject:  block.DefRange.Ptr(),
+		})
+	}
+	delete(attrs, includeSourceAttr)
+
+	sourceVal, moreDiags := sourceAttr.Expr.Value(ctx)
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+	if sourceVal.Type() != cty.String || sourceVal.IsNull() || !sourceVal.IsKnown() {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid include source",
+			Detail:   "The source of an include block must be the path of a file.",
+			Subject:  sourceAttr.Expr.Range().Ptr(),
+		})
+	}
+
+	// Relative paths are resolved from the directory of the including file,
+	// which may differ from the base directory of the HCL functions
+	path := sourceVal.AsString()
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(c.ParseConfig.Path), path)
+	}
+
+	// Detect include cycles, which would otherwise never end
+	chain := append(append([]string{}, c.includedFrom...), absPath(c.ParseConfig.Path))
+	for _, p := range chain {
+		if p == absPath(path) {
+			return nil, append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Include cycle",
+				Detail:   fmt.Sprintf("%s includes itself: %s.", path, strings.Join(append(chain, absPath(path)), " -> ")),
+				Subject:  sourceAttr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to read included file",
+			Detail:   fmt.Sprintf("failed to read %q: %v", path, err),
+			Subject:  sourceAttr.Expr.Range().Ptr(),
+		})
+	}
+
+	file, moreDiags := parseHCLOrJSON(body, path)
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
+	content, moreDiags := file.Body.Content(includeFileSchema)
+	diags = append(diags, moreDiags...)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
+	ic := newJobConfig(&ParseConfig{
+		Path:    path,
+		BaseDir: filepath.Dir(path),
+		Body:    body,
+		AllowFS: c.ParseConfig.AllowFS,
+		Strict:  c.ParseConfig.Strict,
+	})
+	ic.includedFrom = chain
+	ic.includes = c.includes
+	ic.includesDir = c.includesDir
+
+	name, err := filepath.Rel(c.includesDir, path)
+	if err != nil {
+		name = path
+	}
+	c.includes[filepath.ToSlash(name)] = string(body)
+
+	diags = append(diags, ic.decodeInputVariables(content)...)
+	diags = append(diags, ic.parseLocalVariables(content)...)
+	diags = append(diags, ic.collectIncludeVariableValues(block, attrs, ctx)...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	_, moreDiags = ic.InputVariables.Values()
+	diags = append(diags, moreDiags...)
+	diags = append(diags, ic.evaluateLocalVariables(ic.LocalBlocks)...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	inc := &included{Path: path}
+	ictx := ic.EvalContext()
+	for _, b := range content.Blocks {
+		switch b.Type {
+		case groupLabel:
+			tg := &api.TaskGroup{}
+			body := dynblock.Expand(hclutil.BlocksAsAttrs(b.Body), ictx)
+			diags = append(diags, ic.decodeTaskGroup(body, ictx, tg)...)
+			tg.Name = &b.Labels[0]
+			inc.Groups = append(inc.Groups, tg)
+		case taskLabel:
+			t := &api.Task{}
+			body := dynblock.Expand(hclutil.BlocksAsAttrs(b.Body), ictx)
+			diags = append(diags, decodeTask(body, ictx, t)...)
+			t.Name = b.Labels[0]
+			inc.Tasks = append(inc.Tasks, t)
+		case includeLabel:
+			nested, moreDiags := ic.decodeInclude(b, ictx)
+			diags = append(diags, moreDiags...)
+			if nested != nil {
+				inc.Groups = append(inc.Groups, nested.Groups...)
+				inc.Tasks = append(inc.Tasks, nested.Tasks...)
+			}
+		}
+	}
+
+	// The task configs must be evaluated with the context of the included
+	// file, before they are merged into the job.
+	diags = append(diags, decodeMapInterfaceType(&inc.Groups, ictx)...)
+	diags = append(diags, decodeMapInterfaceType(&inc.Tasks, ictx)...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return inc, diags
+}
+
+// collectIncludeVariableValues sets the input variables of the included file
+// from the attributes of the include block.
+func (c *jobConfig) collectIncludeVariableValues(block *hcl.Block, attrs hcl.Attributes, ctx *hcl.EvalContext) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for name, attr := range attrs {
+		variable, found := c.InputVariables[name]
+		if !found {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported argument",
+				Detail:   fmt.Sprintf("An input variable named %q is not declared in %s.", name, c.ParseConfig.Path),
+				Subject:  attr.NameRange.Ptr(),
+			})
+			continue
+		}
+
+		val, moreDiags := attr.Expr.Value(ctx)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		if variable.Type != cty.NilType {
+			var err error
+			val, err = convert.Convert(val, variable.Type)
+			if err != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid value for variable",
+					Detail:   fmt.Sprintf("The value for %s is not compatible with the variable's type constraint: %s.", name, err),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+		}
+		variable.Values = append(variable.Values, VariableAssignment{
+			From:  "include",
+			Value: val,
+			Expr:  attr.Expr,
+		})
+	}
+
+	// Report the variables without a value against the include block, since
+	// that is where they are missing from.
+	names := c.InputVariables.Keys()
+	sort.Strings(names)
+	for _, name := range names {
+		if len(c.InputVariables[name].Values) != 0 {
+			continue
+		}
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Missing required argument",
+			Detail: fmt.Sprintf("The input variable %q declared at %s has no default value, so the include block must set it.",
+				name, c.InputVariables[name].Range.String()),
+			Subject: block.DefRange.Ptr(),
+		})
+	}
+
+	return diags
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
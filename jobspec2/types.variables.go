@@ -12,6 +12,8 @@ import (
 	"github.com/hashicorp/hcl/v2/ext/typeexpr"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/jobspec2/addrs"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
@@ -62,6 +64,8 @@ type Variable struct {
 	Name string
 	// Description of the variable
 	Description string
+	// Sensitive variables have their value redacted from job submissions
+	Sensitive bool
 
 	Range hcl.Range
 }
@@ -179,6 +183,34 @@ func (variables Variables) Values() (map[string]cty.Value, hcl.Diagnostics) {
 	return res, diags
 }
 
+// submittedValues returns the values of the variables that were not set by
+// their default, formatted as HCL expressions.
+func (variables Variables) submittedValues() map[string]string {
+	values := map[string]string{}
+	for name, v := range variables {
+		if len(v.Values) == 0 {
+			continue
+		}
+		val := v.Values[len(v.Values)-1]
+		if val.From == "default" {
+			continue
+		}
+
+		if v.Sensitive {
+			values[name] = api.JobSubmissionSensitiveValue
+			continue
+		}
+		if !val.Value.IsWhollyKnown() {
+			continue
+		}
+		values[name] = string(hclwrite.TokensForValue(val.Value).Bytes())
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
 // decodeVariable decodes a variable key and value into Variables
 func (variables *Variables) decodeVariable(key string, attr *hcl.Attribute, ectx *hcl.EvalContext) hcl.Diagnostics {
 	var diags hcl.Diagnostics
@@ -228,6 +260,9 @@ var variableBlockSchema = &hcl.BodySchema{
 		{
 			Name: "type",
 		},
+		{
+			Name: "sensitive",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -274,6 +309,11 @@ func (variables *Variables) decodeVariableBlock(block *hcl.Block, ectx *hcl.Eval
 		diags = append(diags, valDiags...)
 	}
 
+	if attr, exists := content.Attributes["sensitive"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Sensitive)
+		diags = append(diags, valDiags...)
+	}
+
 	if t, ok := content.Attributes["type"]; ok {
 		tp, moreDiags := typeexpr.Type(t.Expr)
 		diags = append(diags, moreDiags...)
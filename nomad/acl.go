@@ -7,6 +7,7 @@ import (
 	metrics "github.com/armon/go-metrics"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -26,6 +27,12 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 		return acl.ManagementACL, nil
 	}
 
+	// Workload identities are JWTs rather than secret IDs, and resolve to the
+	// policies attached to their job, group, or task.
+	if isWorkloadIdentity(secretID) {
+		return s.resolveWorkloadIdentity(secretID)
+	}
+
 	// Snapshot the state
 	snap, err := s.fsm.State().Snapshot()
 	if err != nil {
@@ -44,6 +51,13 @@ func (s *Server) VerifyClaim(token string) (*structs.IdentityClaims, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// named workload identities are meant for third parties and can't be
+	// used to authenticate to Nomad
+	if claims.IdentityName != "" {
+		return nil, fmt.Errorf("workload identity %q cannot be used to authenticate to Nomad", claims.IdentityName)
+	}
+
 	snap, err := s.fsm.State().Snapshot()
 	if err != nil {
 		return nil, err
@@ -82,6 +96,31 @@ func (s *Server) ResolveClaims(claims *structs.IdentityClaims) (*acl.ACL, error)
 	return aclObj, nil
 }
 
+// resolveWorkloadIdentity is used to translate a workload identity into an ACL
+// object built from the policies attached to its job, group, or task. An
+// identity without attached policies resolves to an ACL that denies
+// everything, as a nil ACL would mean that ACLs are disabled.
+func (s *Server) resolveWorkloadIdentity(token string) (*acl.ACL, error) {
+	claims, err := s.VerifyClaim(token)
+	if err != nil {
+		metrics.IncrCounter([]string{"nomad", "acl", "invalid_allocation_identity"}, 1)
+		s.logger.Trace("allocation identity was not valid", "error", err)
+		return nil, structs.ErrPermissionDenied
+	}
+
+	policies, err := s.resolvePoliciesForClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+	return structs.CompileACLObject(s.aclCache, policies)
+}
+
+// isWorkloadIdentity returns true if the auth token is a workload identity
+// JWT rather than an ACL token secret ID.
+func isWorkloadIdentity(authToken string) bool {
+	return authToken != "" && !helper.IsUUID(authToken)
+}
+
 // resolveTokenFromSnapshotCache is used to resolve an ACL object from a
 // snapshot of state, using a cache to avoid parsing and ACL construction when
 // possible. It is split from resolveToken to simplify testing.
@@ -104,6 +143,9 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		if token.IsExpired(time.Now().UTC()) {
 			return nil, structs.ErrTokenExpired
 		}
+		if token.Disabled {
+			return nil, structs.ErrTokenDisabled
+		}
 	}
 
 	// Check if this is a management token
@@ -185,6 +227,11 @@ func (s *Server) ResolveSecretToken(secretID string) (*structs.ACLToken, error)
 	}
 	defer metrics.MeasureSince([]string{"nomad", "acl", "resolveSecretToken"}, time.Now())
 
+	// The leader secret ID has no token
+	if leaderAcl := s.getLeaderAcl(); leaderAcl != "" && secretID == leaderAcl {
+		return nil, nil
+	}
+
 	snap, err := s.fsm.State().Snapshot()
 	if err != nil {
 		return nil, err
@@ -206,6 +253,9 @@ func (s *Server) ResolveSecretToken(secretID string) (*structs.ACLToken, error)
 		if token.IsExpired(time.Now().UTC()) {
 			return nil, structs.ErrTokenExpired
 		}
+		if token.Disabled {
+			return nil, structs.ErrTokenDisabled
+		}
 	}
 
 	return token, nil
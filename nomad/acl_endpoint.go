@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/go-set"
 	policy "github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
@@ -35,6 +36,7 @@ const (
 // ACL endpoint is used for manipulating ACL tokens and policies
 type ACL struct {
 	srv    *Server
+	ctx    *RPCContext
 	logger log.Logger
 }
 
@@ -610,6 +612,17 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 		// Write the normalized array of ACL role links back to the token.
 		token.Roles = normalizedRoleLinks
 
+		// The enable time is only set by servers, when a disabled token is
+		// enabled again so that its idle timeout restarts.
+		switch {
+		case existingToken == nil:
+			token.EnableTime = nil
+		case existingToken.Disabled && !token.Disabled:
+			token.EnableTime = pointer.Of(time.Now().UTC())
+		default:
+			token.EnableTime = existingToken.EnableTime
+		}
+
 		// Compute the token hash
 		token.SetHash()
 	}
@@ -774,7 +787,17 @@ func (a *ACL) ListTokens(args *structs.ACLTokenListRequest, reply *structs.ACLTo
 			paginator, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
 				func(raw interface{}) error {
 					token := raw.(*structs.ACLToken)
-					tokens = append(tokens, token.Stub())
+					stub := token.Stub()
+
+					// Token usage doesn't modify the token table, so it
+					// isn't watched by the blocking query.
+					usage, err := state.ACLTokenUsageByAccessorID(nil, token.AccessorID)
+					if err != nil {
+						return err
+					}
+					stub.SetUsage(usage)
+
+					tokens = append(tokens, stub)
 					return nil
 				})
 			if err != nil {
@@ -850,6 +873,14 @@ func (a *ACL) GetToken(args *structs.ACLTokenSpecificRequest, reply *structs.Sin
 			// Setup the output
 			reply.Token = out
 			if out != nil {
+				usage, err := state.ACLTokenUsageByAccessorID(nil, out.AccessorID)
+				if err != nil {
+					return err
+				}
+				if usage != nil {
+					reply.Token = out.Copy()
+					reply.Token.SetUsage(usage)
+				}
 				reply.Index = out.ModifyIndex
 			} else {
 				// Use the last index that affected the token table
@@ -936,6 +967,12 @@ func (a *ACL) ResolveToken(args *structs.ResolveACLTokenRequest, reply *structs.
 		return err
 	}
 
+	// Clients resolve the tokens of the requests they authorize themselves,
+	// so record their use from the client.
+	if out != nil {
+		a.srv.aclTokenUsage.record(args.SecretID, rpcSourceIP(a.ctx), time.Now().UTC())
+	}
+
 	// Setup the output
 	reply.Token = out
 	if out != nil {
@@ -951,6 +988,43 @@ func (a *ACL) ResolveToken(args *structs.ResolveACLTokenRequest, reply *structs.
 	return nil
 }
 
+// UpsertTokenUsage is used by servers to record when the ACL tokens used to
+// make the requests they handled were last used.
+func (a *ACL) UpsertTokenUsage(args *structs.ACLTokenUsageUpsertRequest, reply *structs.GenericResponse) error {
+
+	// Ensure the connection was initiated by another server if TLS is used.
+	err := validateTLSCertificateLevel(a.srv, a.ctx, tlsCertificateLevelServer)
+	if err != nil {
+		return err
+	}
+
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// Servers authenticate with claims signed by the keyring, as the TLS
+	// certificate is only checked when mTLS is used
+	if !a.srv.verifyACLTokenUsageClaims(args.AuthToken) {
+		return structs.ErrPermissionDenied
+	}
+	if done, err := a.srv.forward(structs.ACLUpsertTokenUsageRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_token_usage"}, time.Now())
+
+	if len(args.Usages) == 0 {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify at least one token usage")
+	}
+
+	_, index, err := a.srv.raftApply(structs.ACLTokenUsageUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
 func (a *ACL) UpsertOneTimeToken(args *structs.OneTimeTokenUpsertRequest, reply *structs.OneTimeTokenUpsertResponse) error {
 	if !a.srv.config.ACLEnabled {
 		return aclDisabled
@@ -491,3 +491,66 @@ func TestResolveClaims(t *testing.T) {
 	must.Len(t, 3, policies)
 	must.SliceContainsAll(t, policies, []*structs.ACLPolicy{policy1, policy2, policy3})
 }
+
+func TestResolveToken_WorkloadIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, cleanup := TestACLServer(t, nil)
+	defer cleanup()
+	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+
+	store := srv.fsm.State()
+	index := uint64(100)
+
+	alloc := mock.Alloc()
+	alloc2 := mock.Alloc()
+	index++
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, index,
+		[]*structs.Allocation{alloc, alloc2}))
+
+	claims := alloc.ToTaskIdentityClaims(alloc.Job, alloc.Job.TaskGroups[0].Tasks[0].Name)
+	token, _, err := srv.encrypter.SignClaims(claims)
+	must.NoError(t, err)
+
+	claims2 := alloc2.ToTaskIdentityClaims(alloc2.Job, alloc2.Job.TaskGroups[0].Tasks[0].Name)
+	token2, _, err := srv.encrypter.SignClaims(claims2)
+	must.NoError(t, err)
+
+	policy := mock.ACLPolicy()
+	policy.JobACL = &structs.JobACL{
+		Namespace: alloc.Namespace,
+		JobID:     alloc.Job.ID,
+	}
+	index++
+	must.NoError(t, store.UpsertACLPolicies(structs.MsgTypeTestSetup, index,
+		[]*structs.ACLPolicy{policy}))
+
+	// the identity resolves to the policies attached to its job
+	aclObj, err := srv.ResolveToken(token)
+	must.NoError(t, err)
+	must.NotNil(t, aclObj)
+	must.False(t, aclObj.IsManagement())
+	must.True(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+	must.False(t, aclObj.AllowNamespaceOperation("other", acl.NamespaceCapabilityListJobs))
+
+	// an identity without attached policies is denied everything
+	aclObj, err = srv.ResolveToken(token2)
+	must.NoError(t, err)
+	must.NotNil(t, aclObj)
+	must.False(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+
+	// an invalid identity is rejected
+	aclObj, err = srv.ResolveToken("not-a-jwt")
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+	must.Nil(t, aclObj)
+
+	// the identity of a terminal allocation is rejected
+	alloc = alloc.Copy()
+	alloc.DesiredStatus = structs.AllocDesiredStatusStop
+	index++
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, index,
+		[]*structs.Allocation{alloc}))
+	_, err = srv.ResolveToken(token)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+}
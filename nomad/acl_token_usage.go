@@ -0,0 +1,274 @@
+package nomad
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// aclTokenUsageSubject is the subject of the claims signed by servers to
+// authenticate the writes of the token usage they recorded. Only servers hold
+// the keyring, so nothing else can sign them.
+const aclTokenUsageSubject = "nomad-server:acl-token-usage"
+
+// aclTokenUsageTracker records when and from where ACL tokens are used to make
+// the requests handled by a server. The usage is batched in memory and
+// periodically flushed to Raft, so that each request doesn't result in a
+// write.
+type aclTokenUsageTracker struct {
+	lock sync.Mutex
+
+	// uses is keyed by the secret ID of the tokens, which are only resolved
+	// to their accessor ID when the usage is flushed.
+	uses map[string]*aclTokenUse
+}
+
+// aclTokenUse is the last use of an ACL token recorded by a server
+type aclTokenUse struct {
+	time time.Time
+	ip   string
+}
+
+func newACLTokenUsageTracker() *aclTokenUsageTracker {
+	return &aclTokenUsageTracker{
+		uses: make(map[string]*aclTokenUse),
+	}
+}
+
+// record the use of the token with the secret ID at the passed time. Workload
+// identities aren't ACL tokens and are ignored.
+func (t *aclTokenUsageTracker) record(secretID, ip string, now time.Time) {
+	if secretID == "" || !helper.IsUUID(secretID) {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if use, ok := t.uses[secretID]; ok && !now.After(use.time) {
+		return
+	}
+	t.uses[secretID] = &aclTokenUse{time: now, ip: ip}
+}
+
+// drain returns the uses recorded since the previous drain.
+func (t *aclTokenUsageTracker) drain() map[string]*aclTokenUse {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	uses := t.uses
+	t.uses = make(map[string]*aclTokenUse, len(uses))
+	return uses
+}
+
+// restore puts back uses that failed to be flushed, unless the token has been
+// used again since.
+func (t *aclTokenUsageTracker) restore(uses map[string]*aclTokenUse) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for secretID, use := range uses {
+		if _, ok := t.uses[secretID]; !ok {
+			t.uses[secretID] = use
+		}
+	}
+}
+
+// recordACLTokenUsage records the use of the ACL token of an RPC handled by
+// this server.
+func (s *Server) recordACLTokenUsage(info structs.RPCInfo) {
+	if !s.config.ACLEnabled {
+		return
+	}
+	s.aclTokenUsage.record(info.GetAuthToken(), info.RequestSourceIP(), time.Now().UTC())
+}
+
+// rpcSourceIP returns the IP address of the remote end of the RPC connection,
+// or an empty string for in-memory RPCs.
+func rpcSourceIP(ctx *RPCContext) string {
+	if ctx == nil || ctx.Conn == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(ctx.Conn.RemoteAddr().String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// flushACLTokenUsage is a long-lived routine which periodically writes the
+// usage of ACL tokens recorded by this server to Raft. The usage of all the
+// tokens used since the previous flush is written at once.
+func (s *Server) flushACLTokenUsage(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(s.config.ACLTokenUsageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			uses := s.aclTokenUsage.drain()
+			if err := s.writeACLTokenUsage(uses); err != nil {
+				s.logger.Warn("failed to write ACL token usage", "error", err)
+				s.aclTokenUsage.restore(uses)
+			}
+		}
+	}
+}
+
+// writeACLTokenUsage resolves the secret IDs of the uses to their tokens and
+// writes the usage of the tokens to Raft via the leader. Uses of secret IDs
+// that aren't tokens, such as the leader ACL or deleted tokens, are dropped.
+func (s *Server) writeACLTokenUsage(uses map[string]*aclTokenUse) error {
+	if len(uses) == 0 {
+		return nil
+	}
+
+	snap, err := s.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	usages := make([]*structs.ACLTokenUsage, 0, len(uses))
+	for secretID, use := range uses {
+		token, err := snap.ACLTokenBySecretID(nil, secretID)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			continue
+		}
+		usages = append(usages, &structs.ACLTokenUsage{
+			AccessorID:   token.AccessorID,
+			LastUsedTime: use.time,
+			LastUsedIP:   use.ip,
+		})
+	}
+	if len(usages) == 0 {
+		return nil
+	}
+
+	authToken, err := s.signACLTokenUsageClaims(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to sign token usage claims: %v", err)
+	}
+
+	req := &structs.ACLTokenUsageUpsertRequest{
+		Usages: usages,
+		WriteRequest: structs.WriteRequest{
+			Region:    s.Region(),
+			AuthToken: authToken,
+		},
+	}
+	var resp structs.GenericResponse
+	return s.RPC(structs.ACLUpsertTokenUsageRPCMethod, req, &resp)
+}
+
+// signACLTokenUsageClaims returns short-lived claims signed by the keyring,
+// which authenticate this server when writing the token usage.
+func (s *Server) signACLTokenUsageClaims(now time.Time) (string, error) {
+	claims := &structs.IdentityClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   aclTokenUsageSubject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+	token, _, err := s.encrypter.SignClaims(claims)
+	return token, err
+}
+
+// verifyACLTokenUsageClaims returns whether the auth token holds claims
+// signed by a server to write the token usage. Workload identities are signed by the
+// same keyring, but always have an allocation ID.
+func (s *Server) verifyACLTokenUsageClaims(authToken string) bool {
+	if !isWorkloadIdentity(authToken) {
+		return false
+	}
+	claims, err := s.encrypter.VerifyClaim(authToken)
+	if err != nil {
+		return false
+	}
+	return claims.Subject == aclTokenUsageSubject &&
+		claims.AllocationID == "" && claims.IdentityName == ""
+}
+
+// disableIdleACLTokens is a long-lived routine used by the leader to disable
+// the local client ACL tokens that were not used within the idle timeout.
+func (s *Server) disableIdleACLTokens(stopCh chan struct{}) {
+	ticker := time.NewTicker(s.config.ACLTokenIdleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := s.disableIdleACLTokensAt(time.Now().UTC()); err != nil {
+				s.logger.Error("failed to disable idle ACL tokens", "error", err)
+			}
+		}
+	}
+}
+
+// disableIdleACLTokensAt disables the tokens that were idle at the passed
+// time. Disabled tokens are rejected, but unlike expired tokens they are kept
+// and can be enabled again. Tokens that were never used are idle once they
+// are older than the idle timeout. Management tokens are never disabled, nor
+// are global tokens, as their usage is only known to the regions they are
+// used in.
+func (s *Server) disableIdleACLTokensAt(now time.Time) error {
+	snap, err := s.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	iter, err := snap.ACLTokensByGlobal(nil, false, state.SortDefault)
+	if err != nil {
+		return err
+	}
+
+	since := now.Add(-s.config.ACLTokenIdleTimeout)
+
+	var idle []*structs.ACLToken
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		token := raw.(*structs.ACLToken)
+		if token.Type == structs.ACLManagementToken || token.Disabled || token.IsExpired(now) {
+			continue
+		}
+
+		usage, err := snap.ACLTokenUsageByAccessorID(nil, token.AccessorID)
+		if err != nil {
+			return err
+		}
+		if !token.IsIdle(usage, since) {
+			continue
+		}
+
+		token = token.Copy()
+		token.Disabled = true
+		token.SetHash()
+		idle = append(idle, token)
+		if len(idle) == structs.ACLMaxIdleBatchSize {
+			break
+		}
+	}
+	if len(idle) == 0 {
+		return nil
+	}
+
+	req := &structs.ACLTokenUpsertRequest{Tokens: idle}
+	if _, _, err := s.raftApply(structs.ACLTokenUpsertRequestType, req); err != nil {
+		return fmt.Errorf("failed to disable idle tokens: %v", err)
+	}
+
+	s.logger.Info("disabled idle ACL tokens", "num_tokens", len(idle))
+	return nil
+}
@@ -0,0 +1,229 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestACLTokenUsageTracker(t *testing.T) {
+	ci.Parallel(t)
+
+	tracker := newACLTokenUsageTracker()
+	now := time.Now().UTC()
+	secretID := uuid.Generate()
+
+	// Workload identities and empty tokens aren't tracked
+	tracker.record("", "10.0.0.1", now)
+	tracker.record("eyJhbGciOiJFZERTQSJ9.e30.c2ln", "10.0.0.1", now)
+	must.MapEmpty(t, tracker.drain())
+
+	// Only the latest use of a token is kept
+	tracker.record(secretID, "10.0.0.1", now)
+	tracker.record(secretID, "10.0.0.2", now.Add(-time.Second))
+	uses := tracker.drain()
+	must.MapLen(t, 1, uses)
+	must.Eq(t, "10.0.0.1", uses[secretID].ip)
+	must.MapEmpty(t, tracker.drain())
+
+	// Restoring uses doesn't overwrite newer ones
+	tracker.record(secretID, "10.0.0.3", now.Add(time.Second))
+	tracker.restore(uses)
+	uses = tracker.drain()
+	must.Eq(t, "10.0.0.3", uses[secretID].ip)
+
+	otherID := uuid.Generate()
+	tracker.restore(map[string]*aclTokenUse{otherID: {time: now, ip: "10.0.0.4"}})
+	uses = tracker.drain()
+	must.MapLen(t, 1, uses)
+	must.Eq(t, "10.0.0.4", uses[otherID].ip)
+}
+
+func TestServer_WriteACLTokenUsage(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	token := mock.ACLToken()
+	must.NoError(t, s1.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{token}))
+
+	// Uses of unknown tokens are dropped when written
+	now := time.Now().UTC().Round(0)
+	must.NoError(t, s1.writeACLTokenUsage(map[string]*aclTokenUse{
+		token.SecretID:  {time: now, ip: "10.0.0.1"},
+		uuid.Generate(): {time: now, ip: "10.0.0.1"},
+	}))
+
+	iter, err := s1.fsm.State().ACLTokenUsages(nil)
+	must.NoError(t, err)
+	var num int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		num++
+	}
+	must.Eq(t, 1, num)
+
+	// The usage is returned when reading the token
+	get := &structs.ACLTokenSpecificRequest{
+		AccessorID: token.AccessorID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.SingleACLTokenResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetToken", get, &resp))
+	must.NotNil(t, resp.Token)
+	must.NotNil(t, resp.Token.LastUsedTime)
+	must.True(t, now.Equal(*resp.Token.LastUsedTime))
+	must.Eq(t, "10.0.0.1", resp.Token.LastUsedIP)
+
+	// And when listing tokens
+	list := &structs.ACLTokenListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var listResp structs.ACLTokenListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListTokens", list, &listResp))
+	for _, stub := range listResp.Tokens {
+		if stub.AccessorID == token.AccessorID {
+			must.NotNil(t, stub.LastUsedTime)
+			must.Eq(t, "10.0.0.1", stub.LastUsedIP)
+		} else {
+			must.Nil(t, stub.LastUsedTime)
+		}
+	}
+
+	// Only servers can write the token usage
+	alloc := mock.Alloc()
+	identity, _, err := s1.encrypter.SignClaims(
+		alloc.ToTaskIdentityClaims(alloc.Job, alloc.Job.TaskGroups[0].Tasks[0].Name))
+	must.NoError(t, err)
+
+	upsert := &structs.ACLTokenUsageUpsertRequest{
+		Usages: []*structs.ACLTokenUsage{{AccessorID: token.AccessorID, LastUsedTime: now}},
+		WriteRequest: structs.WriteRequest{
+			Region: "global",
+		},
+	}
+	for _, authToken := range []string{"", root.SecretID, identity} {
+		upsert.AuthToken = authToken
+		err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokenUsageRPCMethod, upsert, &structs.GenericResponse{})
+		must.EqError(t, err, structs.ErrPermissionDenied.Error())
+	}
+
+	upsert.AuthToken, err = s1.signACLTokenUsageClaims(time.Now())
+	must.NoError(t, err)
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokenUsageRPCMethod, upsert, &structs.GenericResponse{}))
+
+	// Requests handled by the server record the use of their token
+	s1.aclTokenUsage.drain()
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetToken", get, &resp))
+	must.MapContainsKeys(t, s1.aclTokenUsage.drain(), []string{root.SecretID})
+}
+
+func TestServer_DisableIdleACLTokens(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.ACLTokenIdleTimeout = time.Hour
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	codec := rpcClient(t, s1)
+
+	now := time.Now().UTC()
+	old := now.Add(-2 * time.Hour)
+
+	unused := mock.ACLToken()
+	unused.CreateTime = old
+
+	used := mock.ACLToken()
+	used.CreateTime = old
+
+	staleUse := mock.ACLToken()
+	staleUse.CreateTime = old
+
+	recent := mock.ACLToken()
+
+	management := mock.ACLManagementToken()
+	management.Global = false
+	management.CreateTime = old
+
+	global := mock.ACLToken()
+	global.Global = true
+	global.CreateTime = old
+
+	state := s1.fsm.State()
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1000,
+		[]*structs.ACLToken{unused, used, staleUse, recent, management, global}))
+	must.NoError(t, state.UpsertACLTokenUsage(structs.MsgTypeTestSetup, 1001,
+		[]*structs.ACLTokenUsage{
+			{AccessorID: used.AccessorID, LastUsedTime: now.Add(-time.Minute)},
+			{AccessorID: staleUse.AccessorID, LastUsedTime: old},
+		}))
+
+	must.NoError(t, s1.disableIdleACLTokensAt(now))
+
+	disabled := map[string]bool{
+		unused.AccessorID:     true,
+		used.AccessorID:       false,
+		staleUse.AccessorID:   true,
+		recent.AccessorID:     false,
+		management.AccessorID: false,
+		global.AccessorID:     false,
+	}
+	for accessorID, exp := range disabled {
+		token, err := state.ACLTokenByAccessorID(nil, accessorID)
+		must.NoError(t, err)
+		must.NotNil(t, token)
+		must.Eq(t, exp, token.Disabled,
+			must.Sprintf("unexpected disabled state for token %q", token.Name))
+		must.Nil(t, token.ExpirationTime)
+	}
+
+	// Disabled tokens are rejected, but not garbage collected as expired
+	_, err := s1.ResolveToken(unused.SecretID)
+	must.EqError(t, err, structs.ErrTokenDisabled.Error())
+
+	// Enabling a token again restarts its idle timeout
+	token, err := state.ACLTokenByAccessorID(nil, unused.AccessorID)
+	must.NoError(t, err)
+	token = token.Copy()
+	token.Disabled = false
+	upsert := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{token},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod,
+		upsert, &structs.ACLTokenUpsertResponse{}))
+
+	must.NoError(t, s1.disableIdleACLTokensAt(time.Now().UTC()))
+	token, err = state.ACLTokenByAccessorID(nil, unused.AccessorID)
+	must.NoError(t, err)
+	must.False(t, token.Disabled)
+	must.NotNil(t, token.EnableTime)
+
+	_, err = s1.ResolveToken(unused.SecretID)
+	must.NoError(t, err)
+
+	must.NoError(t, s1.disableIdleACLTokensAt(token.EnableTime.Add(2*time.Hour)))
+	token, err = state.ACLTokenByAccessorID(nil, unused.AccessorID)
+	must.NoError(t, err)
+	must.True(t, token.Disabled)
+}
@@ -362,6 +362,81 @@ func (a *Alloc) UpdateDesiredTransition(args *structs.AllocUpdateDesiredTransiti
 	return nil
 }
 
+// SignIdentities is used by clients to renew the named workload identities
+// of a task before they expire.
+func (a *Alloc) SignIdentities(args *structs.AllocIdentitiesRequest, reply *structs.AllocIdentitiesResponse) error {
+	if done, err := a.srv.forward("Alloc.SignIdentities", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "sign_identities"}, time.Now())
+
+	if args.AllocID == "" {
+		return fmt.Errorf("missing allocation ID")
+	}
+	if len(args.Identities) == 0 {
+		return fmt.Errorf("no identities specified")
+	}
+
+	// Only the node running the allocation may renew its identities, so the
+	// AuthToken must be the node's SecretID
+	snap, err := a.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	node, err := snap.NodeBySecretID(nil, args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil || alloc.NodeID != node.ID {
+		return structs.NewErrUnknownAllocation(args.AllocID)
+	}
+	if alloc.TerminalStatus() {
+		return fmt.Errorf("allocation %q is terminal", args.AllocID)
+	}
+
+	task := alloc.LookupTask(args.TaskName)
+	if task == nil {
+		return fmt.Errorf("task %q not found in allocation %q", args.TaskName, args.AllocID)
+	}
+
+	reply.SignedIdentities = make(map[string]string, len(args.Identities))
+	for _, name := range args.Identities {
+		var wi *structs.WorkloadIdentity
+		for _, id := range task.Identities {
+			if id.Name == name {
+				wi = id
+				break
+			}
+		}
+		if wi == nil {
+			return fmt.Errorf("identity %q not found for task %q", name, args.TaskName)
+		}
+
+		claims := alloc.ToWorkloadIdentityClaims(alloc.Job, node, task.Name, wi)
+		token, _, err := a.srv.encrypter.SignClaims(claims)
+		if err != nil {
+			return err
+		}
+		reply.SignedIdentities[name] = token
+	}
+
+	index, err := snap.Index("allocs")
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	a.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
 // GetServiceRegistrations returns a list of service registrations which belong
 // to the passed allocation ID.
 func (a *Alloc) GetServiceRegistrations(
@@ -1113,6 +1113,85 @@ func TestAllocEndpoint_UpdateDesiredTransition(t *testing.T) {
 	require.True(*out2.DesiredTransition.Migrate)
 }
 
+func TestAllocEndpoint_SignIdentities(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForKeyring(t, s1.RPC, "global")
+
+	node := mock.Node()
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Identities = []*structs.WorkloadIdentity{{
+		Name:       "aws",
+		Audience:   []string{"sts.amazonaws.com"},
+		TTL:        time.Hour,
+		ChangeMode: structs.WorkloadIdentityChangeModeNoop,
+		Claims:     map[string]string{"dc": "${node.datacenter}"},
+	}}
+
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 998, node))
+	require.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+	req := &structs.AllocIdentitiesRequest{
+		AllocID:    alloc.ID,
+		TaskName:   task.Name,
+		Identities: []string{"aws"},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: uuid.Generate(),
+		},
+	}
+
+	// Try with a token that isn't a node secret
+	var resp structs.AllocIdentitiesResponse
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.SignIdentities", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with the secret of another node
+	otherNode := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, otherNode))
+	req.AuthToken = otherNode.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.SignIdentities", req, &resp)
+	require.ErrorContains(t, err, structs.ErrUnknownAllocationPrefix)
+
+	// Try with an unknown identity
+	req.AuthToken = node.SecretID
+	req.Identities = []string{"vault"}
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.SignIdentities", req, &resp)
+	require.ErrorContains(t, err, `identity "vault" not found`)
+
+	// Renew the identity
+	req.Identities = []string{"aws"}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.SignIdentities", req, &resp))
+	require.Len(t, resp.SignedIdentities, 1)
+
+	claims, err := s1.encrypter.VerifyClaim(resp.SignedIdentities["aws"])
+	require.NoError(t, err)
+	require.Equal(t, "aws", claims.IdentityName)
+	require.Equal(t, alloc.ID, claims.AllocationID)
+	require.Equal(t, []string{"sts.amazonaws.com"}, []string(claims.Audience))
+	require.NotNil(t, claims.ExpiresAt)
+
+	// Named identities can't be used to authenticate to Nomad
+	_, err = s1.VerifyClaim(resp.SignedIdentities["aws"])
+	require.ErrorContains(t, err, "cannot be used to authenticate")
+
+	// Terminal allocations can't renew their identities
+	stopped := alloc.Copy()
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	stopped.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{stopped}))
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.SignIdentities", req, &resp)
+	require.ErrorContains(t, err, "is terminal")
+}
+
 func TestAllocEndpoint_Stop_ACL(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
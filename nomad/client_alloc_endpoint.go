@@ -40,10 +40,12 @@ func (a *ClientAllocations) GarbageCollectAll(args *structs.NodeSpecificRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "client_allocations", "garbage_collect_all"}, time.Now())
 
-	// Check node read permissions
-	if aclObj, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check node read permissions, the node rules are checked once the node
+	// is found
+	aclObj, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -58,10 +60,13 @@ func (a *ClientAllocations) GarbageCollectAll(args *structs.NodeSpecificRequest,
 		return err
 	}
 
-	_, err = getNodeForRpc(snap, args.NodeID)
+	node, err := getNodeForRpc(snap, args.NodeID)
 	if err != nil {
 		return err
 	}
+	if aclObj != nil && !aclObj.AllowNodeWriteFor(node.ACLAttributes()) {
+		return structs.ErrPermissionDenied
+	}
 
 	// Get the connection to the client
 	state, ok := a.srv.getNodeConn(args.NodeID)
@@ -30,10 +30,12 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	}
 	defer metrics.MeasureSince([]string{"nomad", "client_stats", "stats"}, time.Now())
 
-	// Check node read permissions
-	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check node read permissions, the node rules are checked once the node
+	// is found
+	aclObj, err := s.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeRead() {
 		return nstructs.ErrPermissionDenied
 	}
 
@@ -49,10 +51,13 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	}
 
 	// Make sure Node is new enough to support RPC
-	_, err = getNodeForRpc(snap, args.NodeID)
+	node, err := getNodeForRpc(snap, args.NodeID)
 	if err != nil {
 		return err
 	}
+	if aclObj != nil && !aclObj.AllowNodeReadFor(node.ACLAttributes()) {
+		return nstructs.ErrPermissionDenied
+	}
 
 	// Get the connection to the client
 	state, ok := s.srv.getNodeConn(args.NodeID)
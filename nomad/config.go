@@ -159,6 +159,11 @@ type Config struct {
 	// the user time to inspect the job.
 	JobGCThreshold time.Duration
 
+	// DispatchIdempotencyTTL is how long the idempotency token of a dispatched
+	// job is remembered, so retries of the dispatch return the job instead of
+	// dispatching a new one.
+	DispatchIdempotencyTTL time.Duration
+
 	// NodeGCInterval is how often we dispatch a job to GC failed nodes.
 	NodeGCInterval time.Duration
 
@@ -217,6 +222,18 @@ type Config struct {
 	// rekey any variables associated with a key in the Rekeying state
 	VariablesRekeyInterval time.Duration
 
+	// VariablesMaxVersions is the number of previous versions of each
+	// variable kept in the state store.
+	VariablesMaxVersions int
+
+	// VariablesGCInterval is how often we dispatch a job to GC deleted
+	// variables
+	VariablesGCInterval time.Duration
+
+	// VariablesGCThreshold is how long the versions of a deleted variable
+	// are kept before they're eligible for GC.
+	VariablesGCThreshold time.Duration
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -264,6 +281,14 @@ type Config struct {
 	// rejections for nodes.
 	NodePlanRejectionWindow time.Duration
 
+	// AdmissionWebhooks are the external webhooks called in order when jobs
+	// are registered or planned, which can mutate or reject the jobs.
+	AdmissionWebhooks []*config.AdmissionWebhookConfig
+
+	// KeyringConfig configures the provider of the key encryption keys that
+	// wrap the root keys of the keyring in the on-disk keystore.
+	KeyringConfig *config.KeyringConfig
+
 	// MinHeartbeatTTL is the minimum time between heartbeats.
 	// This is used as a floor to prevent excessive updates.
 	MinHeartbeatTTL time.Duration
@@ -318,6 +343,18 @@ type Config struct {
 	// for ACL token expiration.
 	ACLTokenMaxExpirationTTL time.Duration
 
+	// ACLTokenUsageFlushInterval is how often the server writes the usage of
+	// the ACL tokens used to make the requests it handled to Raft.
+	ACLTokenUsageFlushInterval time.Duration
+
+	// ACLTokenIdleTimeout is how long a local client ACL token can go unused
+	// before the leader disables it. Idle tokens are not disabled if zero.
+	ACLTokenIdleTimeout time.Duration
+
+	// ACLTokenIdleCheckInterval is how often the leader checks for idle ACL
+	// tokens to disable.
+	ACLTokenIdleCheckInterval time.Duration
+
 	// SentinelGCInterval is the interval that we GC unused policies.
 	SentinelGCInterval time.Duration
 
@@ -386,9 +423,18 @@ type Config struct {
 	// It is used primarily for licensing
 	AgentShutdown func() error
 
+	// JobTemplateRenderer is used to render job templates. It is set by the
+	// agent, and job templates can't be registered or run when nil.
+	JobTemplateRenderer JobTemplateRenderer
+
 	// DeploymentQueryRateLimit is in queries per second and is used by the
 	// DeploymentWatcher to throttle the amount of simultaneously deployments
 	DeploymentQueryRateLimit float64
+
+	// OIDCIssuer is the issuer of the workload identities signed by the
+	// server. When set, it's added to the identities as their "iss" claim
+	// and the OIDC discovery configuration is published.
+	OIDCIssuer string
 }
 
 func (c *Config) Copy() *Config {
@@ -446,6 +492,7 @@ func DefaultConfig() *Config {
 		EvalGCThreshold:                  1 * time.Hour,
 		JobGCInterval:                    5 * time.Minute,
 		JobGCThreshold:                   4 * time.Hour,
+		DispatchIdempotencyTTL:           24 * time.Hour,
 		NodeGCInterval:                   5 * time.Minute,
 		NodeGCThreshold:                  24 * time.Hour,
 		DeploymentGCInterval:             5 * time.Minute,
@@ -461,6 +508,9 @@ func DefaultConfig() *Config {
 		RootKeyGCThreshold:               1 * time.Hour,
 		RootKeyRotationThreshold:         720 * time.Hour, // 30 days
 		VariablesRekeyInterval:           10 * time.Minute,
+		VariablesMaxVersions:             10,
+		VariablesGCInterval:              5 * time.Minute,
+		VariablesGCThreshold:             72 * time.Hour,
 		EvalNackTimeout:                  60 * time.Second,
 		EvalDeliveryLimit:                3,
 		EvalNackInitialReenqueueDelay:    1 * time.Second,
@@ -486,6 +536,8 @@ func DefaultConfig() *Config {
 		EventBufferSize:                  100,
 		ACLTokenMinExpirationTTL:         1 * time.Minute,
 		ACLTokenMaxExpirationTTL:         24 * time.Hour,
+		ACLTokenUsageFlushInterval:       1 * time.Minute,
+		ACLTokenIdleCheckInterval:        5 * time.Minute,
 		AutopilotConfig: &structs.AutopilotConfig{
 			CleanupDeadServers:      true,
 			LastContactThreshold:    200 * time.Millisecond,
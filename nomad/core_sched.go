@@ -63,6 +63,8 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.rootKeyRotateOrGC(eval)
 	case structs.CoreJobVariablesRekey:
 		return c.variablesRekey(eval)
+	case structs.CoreJobVariablesGC:
+		return c.variablesGC(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -99,6 +101,9 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.rootKeyGC(eval); err != nil {
 		return err
 	}
+	if err := c.variablesGC(eval); err != nil {
+		return err
+	}
 	// Node GC must occur after the others to ensure the allocations are
 	// cleared.
 	return c.nodeGC(eval)
@@ -160,6 +165,12 @@ OUTER:
 
 	}
 
+	// The idempotency tokens of dispatched jobs outlive the jobs, so they are
+	// expired separately
+	if err := c.expiredDispatchIdempotencyGC(eval); err != nil {
+		return err
+	}
+
 	// Fast-path the nothing case
 	if len(gcEval) == 0 && len(gcAlloc) == 0 && len(gcJob) == 0 {
 		return nil
@@ -177,6 +188,37 @@ OUTER:
 	return c.jobReap(gcJob, eval.LeaderACL)
 }
 
+// expiredDispatchIdempotencyGC deletes the idempotency records of dispatched
+// jobs once they are older than the dispatch_idempotency_ttl.
+func (c *CoreScheduler) expiredDispatchIdempotencyGC(eval *structs.Evaluation) error {
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.DispatchIdempotencyRecords(ws)
+	if err != nil {
+		return err
+	}
+
+	// Avoid a Raft write unless there are expired records
+	cutoff := time.Now().Add(-c.srv.config.DispatchIdempotencyTTL).UnixNano()
+	expired := false
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.DispatchIdempotencyRecord).CreateTime < cutoff {
+			expired = true
+			break
+		}
+	}
+	if !expired {
+		return nil
+	}
+
+	req := &structs.DispatchIdempotencyExpireRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	return c.srv.RPC("Job.ExpireDispatchIdempotencyRecords", req, &structs.GenericResponse{})
+}
+
 // jobReap contacts the leader and issues a reap on the passed jobs
 func (c *CoreScheduler) jobReap(jobs []*structs.Job, leaderACL string) error {
 	// Call to the leader to issue the reap
@@ -995,10 +1037,10 @@ func (c *CoreScheduler) rootKeyRotate(eval *structs.Evaluation) (bool, error) {
 }
 
 // variablesReKey is optionally run after rotating the active
-// root key. It iterates over all the variables for the keys in the
-// re-keying state, decrypts them, and re-encrypts them in batches
-// with the currently active key. This job does not GC the keys, which
-// is handled in the normal periodic GC job.
+// root key. It iterates over all the variables and their previous
+// versions for the keys in the re-keying state, decrypts them, and
+// re-encrypts them in batches with the currently active key. This job
+// does not GC the keys, which is handled in the normal periodic GC job.
 func (c *CoreScheduler) variablesRekey(eval *structs.Evaluation) error {
 
 	ws := memdb.NewWatchSet()
@@ -1025,18 +1067,29 @@ func (c *CoreScheduler) variablesRekey(eval *structs.Evaluation) error {
 			return err
 		}
 
+		// The previous versions keep the key in use until they are
+		// re-encrypted too
+		versionIter, err := c.snap.GetVariableVersionsByKeyID(ws, keyMeta.KeyID)
+		if err != nil {
+			return err
+		}
+		err = c.rotateVariables(versionIter, eval)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// rotateVariables runs over an iterator of variables and decrypts them, and
-// then sends them back to be re-encrypted with the currently active key,
-// checking for conflicts
+// rotateVariables runs over an iterator of variables or variable versions and
+// decrypts them, and then sends them back to be re-encrypted with the
+// currently active key, checking for conflicts. The variables keep their
+// version, so re-encrypting them doesn't archive their previous ciphertext.
 func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs.Evaluation) error {
 
 	args := &structs.VariablesApplyRequest{
-		Op: structs.VarOpCAS,
+		Op: structs.VarOpRekey,
 		WriteRequest: structs.WriteRequest{
 			Region:    c.srv.config.Region,
 			AuthToken: eval.LeaderACL,
@@ -1087,7 +1140,13 @@ func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs
 		default:
 		}
 
-		ev := raw.(*structs.VariableEncrypted)
+		var ev *structs.VariableEncrypted
+		switch v := raw.(type) {
+		case *structs.VariableEncrypted:
+			ev = v
+		case *structs.VariableVersion:
+			ev = &v.VariableEncrypted
+		}
 		cleartext, err := c.srv.encrypter.Decrypt(ev.Data, ev.KeyID)
 		if err != nil {
 			return err
@@ -1114,7 +1173,9 @@ func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs
 		if reply.IsConflict() {
 			// we've already rotated the key by the time we took this
 			// evaluation's snapshot, so any conflict is going to be on a write
-			// made with the new key, so there's nothing for us to do here
+			// made with the new key. That write archived the previous
+			// ciphertext as a version, which keeps the old key in use until
+			// it's rekeyed again
 			continue
 		}
 	}
@@ -1122,6 +1183,43 @@ func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs
 	return nil
 }
 
+// variablesGC purges the versions of variables that were deleted before the
+// variables GC threshold.
+func (c *CoreScheduler) variablesGC(eval *structs.Evaluation) error {
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.VariablesVersions(ws)
+	if err != nil {
+		return err
+	}
+
+	before := time.Now().Add(-c.srv.config.VariablesGCThreshold).UnixNano()
+	if eval.JobID == structs.CoreJobForceGC {
+		before = math.MaxInt64
+	}
+
+	// Avoid a Raft write unless there are deleted variables to purge
+	expired := false
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		version := raw.(*structs.VariableVersion)
+		if version.Deleted() && version.DeleteTime < before {
+			expired = true
+			break
+		}
+	}
+	if !expired {
+		return nil
+	}
+
+	req := &structs.VariablesExpireDeletedRequest{
+		Before: before,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	return c.srv.RPC(structs.VariablesExpireDeletedRPCMethod, req, &structs.GenericResponse{})
+}
+
 // getThreshold returns the index threshold for determining whether an
 // object is old enough to GC
 func (c *CoreScheduler) getThreshold(eval *structs.Evaluation, objectName, configName string, configThreshold time.Duration) uint64 {
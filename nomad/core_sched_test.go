@@ -2567,6 +2567,7 @@ func TestCoreScheduler_VariablesRekey(t *testing.T) {
 	srv, cleanup := TestServer(t, nil)
 	defer cleanup()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, srv.config.Region)
 
 	store := srv.fsm.State()
 	key0, err := store.GetActiveRootKeyMeta(nil)
@@ -2583,6 +2584,19 @@ func TestCoreScheduler_VariablesRekey(t *testing.T) {
 		require.NoError(t, srv.RPC("Variables.Apply", req, resp))
 	}
 
+	// A variable with a previous version encrypted with the old key
+	versioned := mock.Variable()
+	for i := 0; i < 2; i++ {
+		versioned.Items["version"] = fmt.Sprint(i)
+		req := &structs.VariablesApplyRequest{
+			Op:           structs.VarOpSet,
+			Var:          versioned,
+			WriteRequest: structs.WriteRequest{Region: srv.config.Region},
+		}
+		resp := &structs.VariablesApplyResponse{}
+		require.NoError(t, srv.RPC("Variables.Apply", req, resp))
+	}
+
 	rotateReq := &structs.KeyringRotateRootKeyRequest{
 		WriteRequest: structs.WriteRequest{
 			Region: srv.config.Region,
@@ -2619,10 +2633,87 @@ func TestCoreScheduler_VariablesRekey(t *testing.T) {
 				return false
 			}
 		}
+
+		iter, err = store.VariablesVersions(ws)
+		require.NoError(t, err)
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			if raw.(*structs.VariableVersion).KeyID != newKeyID {
+				return false
+			}
+		}
 		return true
 	}, time.Second*5, 100*time.Millisecond,
 		"variable rekey should be complete")
 
+	// Rekeying doesn't create versions
+	_, live, err := store.VarGet(nil, versioned.Namespace, versioned.Path)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), live.Version)
+	versions, err := store.GetVariableVersions(nil, versioned.Namespace, versioned.Path)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.Equal(t, uint64(1), versions[0].Version)
+
+	inUse, err := store.IsRootKeyMetaInUse(key0.KeyID)
+	require.NoError(t, err)
+	require.False(t, inUse)
+}
+
+func TestCoreScheduler_VariablesGC(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanup := TestServer(t, func(c *Config) {
+		c.VariablesGCThreshold = time.Hour
+	})
+	defer cleanup()
+	testutil.WaitForLeader(t, srv.RPC)
+
+	store := srv.fsm.State()
+	deleteVar := func(idx uint64, path string, deleteTime time.Time) {
+		sv := mock.VariableEncrypted()
+		sv.Path = path
+		resp := store.VarSet(idx, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: sv,
+		})
+		require.NoError(t, resp.Error)
+
+		deleted := sv.Copy()
+		deleted.ModifyTime = deleteTime.UnixNano()
+		resp = store.VarDelete(idx+1, &structs.VarApplyStateRequest{
+			Op:          structs.VarOpDelete,
+			Var:         &deleted,
+			MaxVersions: 1,
+		})
+		require.NoError(t, resp.Error)
+	}
+	deleteVar(1000, "old", time.Now().Add(-2*time.Hour))
+	deleteVar(1002, "new", time.Now())
+
+	snap, err := store.Snapshot()
+	require.NoError(t, err)
+	core := NewCoreScheduler(srv, snap)
+	eval := srv.coreJobEval(structs.CoreJobVariablesGC, 2000)
+	require.NoError(t, core.Process(eval))
+
+	versions, err := store.GetVariableVersions(nil, "default", "old")
+	require.NoError(t, err)
+	require.Empty(t, versions, "variable deleted before the threshold should have been GCd")
+
+	versions, err = store.GetVariableVersions(nil, "default", "new")
+	require.NoError(t, err)
+	require.Len(t, versions, 1, "recently deleted variable should not have been GCd")
+
+	// a forced GC purges all deleted variables
+	snap, err = store.Snapshot()
+	require.NoError(t, err)
+	core = NewCoreScheduler(srv, snap)
+	eval = srv.coreJobEval(structs.CoreJobForceGC, 2001)
+	require.NoError(t, core.(*CoreScheduler).variablesGC(eval))
+
+	versions, err = store.GetVariableVersions(nil, "default", "new")
+	require.NoError(t, err)
+	require.Empty(t, versions)
 }
 
 func TestCoreScheduler_FailLoop(t *testing.T) {
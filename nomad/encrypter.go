@@ -5,6 +5,8 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io/fs"
@@ -23,6 +25,7 @@ import (
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/crypto"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 )
 
 const nomadKeystoreExtension = ".nks.json"
@@ -33,6 +36,10 @@ type Encrypter struct {
 	srv          *Server
 	keystorePath string
 
+	// kekProvider wraps the key encryption keys in the keystore. If nil,
+	// they're stored unwrapped next to the root keys.
+	kekProvider kekProvider
+
 	keyring map[string]*keyset
 	lock    sync.RWMutex
 }
@@ -41,19 +48,33 @@ type keyset struct {
 	rootKey    *structs.RootKey
 	cipher     cipher.AEAD
 	privateKey ed25519.PrivateKey
+
+	// rsaPrivateKey is used to sign workload identities instead of
+	// privateKey, unless the root key predates RSA keys
+	rsaPrivateKey *rsa.PrivateKey
 }
 
 // NewEncrypter loads or creates a new local keystore and returns an
 // encryption keyring with the keys it finds.
 func NewEncrypter(srv *Server, keystorePath string) (*Encrypter, error) {
 
+	var keyringConfig *config.KeyringConfig
+	if srv.config != nil {
+		keyringConfig = srv.config.KeyringConfig
+	}
+	provider, err := newKEKProvider(keyringConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure keyring provider: %v", err)
+	}
+
 	encrypter := &Encrypter{
 		srv:          srv,
 		keystorePath: keystorePath,
+		kekProvider:  provider,
 		keyring:      make(map[string]*keyset),
 	}
 
-	err := encrypter.loadKeystore()
+	err = encrypter.loadKeystore()
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +183,7 @@ func (e *Encrypter) SignClaims(claim *structs.IdentityClaims) (string, string, e
 	if err != nil {
 		ctx, cancel := context.WithTimeout(e.srv.shutdownCtx, 5*time.Second)
 		defer cancel()
+	RETRY:
 		for {
 			select {
 			case <-ctx.Done():
@@ -170,16 +192,28 @@ func (e *Encrypter) SignClaims(claim *structs.IdentityClaims) (string, string, e
 				time.Sleep(50 * time.Millisecond)
 				keyset, err = e.activeKeySet()
 				if keyset != nil {
-					break
+					break RETRY
 				}
 			}
 		}
 	}
 
-	token := jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, claim)
+	if issuer := e.srv.config.OIDCIssuer; issuer != "" {
+		claim.Issuer = issuer
+	}
+
+	var token *jwt.Token
+	var signingKey interface{}
+	if keyset.rsaPrivateKey != nil {
+		token = jwt.NewWithClaims(jwt.SigningMethodRS256, claim)
+		signingKey = keyset.rsaPrivateKey
+	} else {
+		token = jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, claim)
+		signingKey = keyset.privateKey
+	}
 	token.Header[keyIDHeader] = keyset.rootKey.Meta.KeyID
 
-	tokenString, err := token.SignedString(keyset.privateKey)
+	tokenString, err := token.SignedString(signingKey)
 	if err != nil {
 		return "", "", err
 	}
@@ -192,9 +226,6 @@ func (e *Encrypter) SignClaims(claim *structs.IdentityClaims) (string, string, e
 func (e *Encrypter) VerifyClaim(tokenString string) (*structs.IdentityClaims, error) {
 
 	token, err := jwt.ParseWithClaims(tokenString, &structs.IdentityClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Method.Alg())
-		}
 		raw := token.Header[keyIDHeader]
 		if raw == nil {
 			return nil, fmt.Errorf("missing key ID header")
@@ -207,7 +238,20 @@ func (e *Encrypter) VerifyClaim(tokenString string) (*structs.IdentityClaims, er
 		if err != nil {
 			return nil, err
 		}
-		return keyset.privateKey.Public(), nil
+
+		// only accept the signing method the key signs with, so the
+		// public key can't be used as another kind of key
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA:
+			if keyset.rsaPrivateKey != nil {
+				return keyset.rsaPrivateKey.Public(), nil
+			}
+		case *jwt.SigningMethodEd25519:
+			if keyset.rsaPrivateKey == nil {
+				return keyset.privateKey.Public(), nil
+			}
+		}
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Method.Alg())
 	})
 
 	if err != nil {
@@ -260,18 +304,28 @@ func (e *Encrypter) addCipher(rootKey *structs.RootKey) error {
 
 	privateKey := ed25519.NewKeyFromSeed(rootKey.Key)
 
+	var rsaPrivateKey *rsa.PrivateKey
+	if len(rootKey.RSAKey) > 0 {
+		var err error
+		rsaPrivateKey, err = x509.ParsePKCS1PrivateKey(rootKey.RSAKey)
+		if err != nil {
+			return fmt.Errorf("could not parse rsa key: %v", err)
+		}
+	}
+
 	e.lock.Lock()
 	defer e.lock.Unlock()
 	e.keyring[rootKey.Meta.KeyID] = &keyset{
-		rootKey:    rootKey,
-		cipher:     aead,
-		privateKey: privateKey,
+		rootKey:       rootKey,
+		cipher:        aead,
+		privateKey:    privateKey,
+		rsaPrivateKey: rsaPrivateKey,
 	}
 	return nil
 }
 
 // GetKey retrieves the key material by ID from the keyring
-func (e *Encrypter) GetKey(keyID string) ([]byte, error) {
+func (e *Encrypter) GetKey(keyID string) (*structs.RootKey, error) {
 	e.lock.RLock()
 	defer e.lock.RUnlock()
 
@@ -279,7 +333,36 @@ func (e *Encrypter) GetKey(keyID string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	return keyset.rootKey.Key, nil
+	return keyset.rootKey, nil
+}
+
+// GetPublicKey returns the public half of the key used to sign workload
+// identities with the root key.
+func (e *Encrypter) GetPublicKey(keyID string) (*structs.KeyringPublicKey, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	keyset, err := e.keysetByIDLocked(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey := &structs.KeyringPublicKey{
+		KeyID:      keyID,
+		Use:        structs.PubKeyUseSig,
+		CreateTime: keyset.rootKey.Meta.CreateTime,
+	}
+	if keyset.rsaPrivateKey != nil {
+		pubKey.Algorithm = structs.PubKeyAlgRS256
+		pubKey.PublicKey, err = x509.MarshalPKIXPublicKey(keyset.rsaPrivateKey.Public())
+	} else {
+		pubKey.Algorithm = structs.PubKeyAlgEdDSA
+		pubKey.PublicKey, err = x509.MarshalPKIXPublicKey(keyset.privateKey.Public())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %v", err)
+	}
+	return pubKey, nil
 }
 
 // activeKeySetLocked returns the keyset that belongs to the key marked as
@@ -336,7 +419,25 @@ func (e *Encrypter) saveKeyToStore(rootKey *structs.RootKey) error {
 	kekWrapper := &structs.KeyEncryptionKeyWrapper{
 		Meta:                       rootKey.Meta,
 		EncryptedDataEncryptionKey: blob.Ciphertext,
-		KeyEncryptionKey:           kek,
+	}
+
+	if e.kekProvider == nil {
+		kekWrapper.KeyEncryptionKey = kek
+	} else {
+		wrappedKEK, err := e.kekProvider.Wrap(e.srv.shutdownCtx, kek)
+		if err != nil {
+			return fmt.Errorf("failed to wrap key encryption key: %v", err)
+		}
+		kekWrapper.Provider = e.kekProvider.Name()
+		kekWrapper.WrappedKeyEncryptionKey = wrappedKEK
+	}
+
+	if len(rootKey.RSAKey) > 0 {
+		rsaBlob, err := wrapper.Encrypt(e.srv.shutdownCtx, rootKey.RSAKey)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt rsa key: %v", err)
+		}
+		kekWrapper.EncryptedRSAKey = rsaBlob.Ciphertext
 	}
 
 	buf, err := json.Marshal(kekWrapper)
@@ -370,9 +471,21 @@ func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 		return nil, err
 	}
 
+	kek := kekWrapper.KeyEncryptionKey
+	if kekWrapper.Provider != "" {
+		if e.kekProvider == nil || e.kekProvider.Name() != kekWrapper.Provider {
+			return nil, fmt.Errorf("key is wrapped by keyring provider %q, which is not configured",
+				kekWrapper.Provider)
+		}
+		kek, err = e.kekProvider.Unwrap(e.srv.shutdownCtx, kekWrapper.WrappedKeyEncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unwrap key encryption key: %v", err)
+		}
+	}
+
 	// the errors that bubble up from this library can be a bit opaque, so make
 	// sure we wrap them with as much context as possible
-	wrapper, err := e.newKMSWrapper(meta.KeyID, kekWrapper.KeyEncryptionKey)
+	wrapper, err := e.newKMSWrapper(meta.KeyID, kek)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create key wrapper cipher: %v", err)
 	}
@@ -383,16 +496,38 @@ func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 		return nil, fmt.Errorf("unable to decrypt wrapped root key: %v", err)
 	}
 
-	return &structs.RootKey{
-		Meta: meta,
-		Key:  key,
-	}, nil
+	// keys created by older versions of Nomad have no rsa key
+	var rsaKey []byte
+	if len(kekWrapper.EncryptedRSAKey) > 0 {
+		rsaKey, err = wrapper.Decrypt(e.srv.shutdownCtx, &kms.BlobInfo{
+			Ciphertext: kekWrapper.EncryptedRSAKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt wrapped rsa key: %v", err)
+		}
+	}
+
+	rootKey := &structs.RootKey{
+		Meta:   meta,
+		Key:    key,
+		RSAKey: rsaKey,
+	}
+
+	// keys saved before a KEK provider was configured are wrapped with it,
+	// so that they are no longer stored next to their KEK
+	if kekWrapper.Provider == "" && e.kekProvider != nil {
+		if err := e.saveKeyToStore(rootKey); err != nil {
+			return nil, fmt.Errorf("unable to wrap key with keyring provider: %v", err)
+		}
+	}
+
+	return rootKey, nil
 }
 
 // newKMSWrapper returns a go-kms-wrapping interface the caller can use to
-// encrypt the RootKey with a key encryption key (KEK). This is a bit of
-// security theatre for local on-disk key material, but gives us a shim for
-// external KMS providers in the future.
+// encrypt the RootKey with a key encryption key (KEK). The KEK is itself
+// wrapped by the configured kekProvider, if any, before being saved to the
+// keystore.
 func (e *Encrypter) newKMSWrapper(keyID string, kek []byte) (kms.Wrapper, error) {
 	wrapper := aead.NewWrapper()
 	wrapper.SetConfig(context.Background(),
@@ -471,7 +606,7 @@ START:
 				}
 
 				keyMeta := raw.(*structs.RootKeyMeta)
-				if key, err := krr.encrypter.GetKey(keyMeta.KeyID); err == nil && len(key) > 0 {
+				if key, err := krr.encrypter.GetKey(keyMeta.KeyID); err == nil && len(key.Key) > 0 {
 					// the key material is immutable so if we've already got it
 					// we can move on to the next key
 					continue
@@ -0,0 +1,210 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	vapi "github.com/hashicorp/vault/api"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// defaultExternalKEKTimeout is the time to wait for the command of the
+// external KEK provider when no timeout is configured.
+const defaultExternalKEKTimeout = 30 * time.Second
+
+// kekProvider wraps the key encryption keys (KEK) of the root keys in the
+// on-disk keystore, so that a copy of the keystore can't be decrypted without
+// access to the provider.
+type kekProvider interface {
+	// Name is the name of the provider recorded next to the wrapped KEK
+	Name() string
+
+	// Wrap encrypts the KEK
+	Wrap(ctx context.Context, kek []byte) ([]byte, error)
+
+	// Unwrap decrypts a KEK encrypted by Wrap
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// newKEKProvider returns the KEK provider for the keyring configuration, or
+// nil if the KEK is stored unwrapped next to the root key.
+func newKEKProvider(conf *config.KeyringConfig) (kekProvider, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch conf.Provider {
+	case config.KeyringProviderVaultTransit:
+		return newVaultTransitKEKProvider(conf.Config)
+	case config.KeyringProviderExternal:
+		return newExternalKEKProvider(conf.Config)
+	case config.KeyringProviderPKCS11:
+		return newPKCS11KEKProvider(conf.Config)
+	default:
+		return nil, nil
+	}
+}
+
+// vaultTransitKEKProvider wraps the KEK with a key of the transit secrets
+// engine of Vault.
+type vaultTransitKEKProvider struct {
+	client    *vapi.Client
+	mountPath string
+	keyName   string
+}
+
+func newVaultTransitKEKProvider(conf map[string]string) (*vaultTransitKEKProvider, error) {
+	// the default configuration includes the VAULT_ environment variables
+	vconf := vapi.DefaultConfig()
+	if vconf.Error != nil {
+		return nil, fmt.Errorf("failed to read Vault environment: %v", vconf.Error)
+	}
+	if addr := conf["address"]; addr != "" {
+		vconf.Address = addr
+	}
+
+	tlsConf := &vapi.TLSConfig{
+		CACert:        conf["ca_cert"],
+		CAPath:        conf["ca_path"],
+		ClientCert:    conf["client_cert"],
+		ClientKey:     conf["client_key"],
+		TLSServerName: conf["tls_server_name"],
+		Insecure:      conf["tls_skip_verify"] == "true",
+	}
+	if err := vconf.ConfigureTLS(tlsConf); err != nil {
+		return nil, fmt.Errorf("failed to configure Vault TLS: %v", err)
+	}
+
+	client, err := vapi.NewClient(vconf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vault client: %v", err)
+	}
+	if token := conf["token"]; token != "" {
+		client.SetToken(token)
+	}
+	if namespace := conf["namespace"]; namespace != "" {
+		client.SetNamespace(namespace)
+	}
+
+	mountPath := conf["mount_path"]
+	if mountPath == "" {
+		mountPath = "transit"
+	}
+
+	return &vaultTransitKEKProvider{
+		client:    client,
+		mountPath: mountPath,
+		keyName:   conf["key_name"],
+	}, nil
+}
+
+func (*vaultTransitKEKProvider) Name() string {
+	return config.KeyringProviderVaultTransit
+}
+
+func (p *vaultTransitKEKProvider) Wrap(ctx context.Context, kek []byte) ([]byte, error) {
+	secret, err := p.client.Logical().WriteWithContext(ctx,
+		path.Join(p.mountPath, "encrypt", p.keyName),
+		map[string]interface{}{"plaintext": base64.StdEncoding.EncodeToString(kek)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt with Vault transit key %q: %v", p.keyName, err)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("empty response from Vault transit encrypt")
+	}
+
+	ciphertext, ok := secret.Data["ciphertext"].(string)
+	if !ok || ciphertext == "" {
+		return nil, fmt.Errorf("no ciphertext in response from Vault transit encrypt")
+	}
+	return []byte(ciphertext), nil
+}
+
+func (p *vaultTransitKEKProvider) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	secret, err := p.client.Logical().WriteWithContext(ctx,
+		path.Join(p.mountPath, "decrypt", p.keyName),
+		map[string]interface{}{"ciphertext": string(wrapped)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt with Vault transit key %q: %v", p.keyName, err)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("empty response from Vault transit decrypt")
+	}
+
+	plaintext, ok := secret.Data["plaintext"].(string)
+	if !ok {
+		return nil, fmt.Errorf("no plaintext in response from Vault transit decrypt")
+	}
+	return base64.StdEncoding.DecodeString(plaintext)
+}
+
+// externalKEKProvider wraps the KEK by running an external command, with
+// "wrap" or "unwrap" appended to its arguments. The command reads the
+// base64-encoded input on stdin and writes the base64-encoded output on
+// stdout.
+type externalKEKProvider struct {
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+func newExternalKEKProvider(conf map[string]string) (*externalKEKProvider, error) {
+	timeout := defaultExternalKEKTimeout
+	if raw := conf["timeout"]; raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %q: %v", raw, err)
+		}
+		timeout = d
+	}
+
+	return &externalKEKProvider{
+		command: conf["command"],
+		args:    strings.Fields(conf["args"]),
+		timeout: timeout,
+	}, nil
+}
+
+func (*externalKEKProvider) Name() string {
+	return config.KeyringProviderExternal
+}
+
+func (p *externalKEKProvider) Wrap(ctx context.Context, kek []byte) ([]byte, error) {
+	return p.run(ctx, "wrap", kek)
+}
+
+func (p *externalKEKProvider) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	return p.run(ctx, "unwrap", wrapped)
+}
+
+func (p *externalKEKProvider) run(ctx context.Context, op string, input []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.command, append(p.args, op)...)
+	cmd.Stdin = strings.NewReader(base64.StdEncoding.EncodeToString(input))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("keyring command failed to %s key: %v: %s",
+			op, err, strings.TrimSpace(stderr.String()))
+	}
+
+	output, err := base64.StdEncoding.DecodeString(strings.TrimSpace(stdout.String()))
+	if err != nil {
+		return nil, fmt.Errorf("keyring command returned invalid output to %s key: %v", op, err)
+	}
+	return output, nil
+}
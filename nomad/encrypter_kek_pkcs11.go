@@ -0,0 +1,199 @@
+//go:build cgo
+// +build cgo
+
+package nomad
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// pkcs11GCMNonceSize is the size of the nonce prepended to the KEK wrapped
+// by the PKCS#11 provider.
+const pkcs11GCMNonceSize = 12
+
+// pkcs11KEKProvider wraps the KEK with AES-GCM, using an AES key held by an
+// HSM accessed through a PKCS#11 library. The wrapped KEK is the nonce
+// followed by the ciphertext.
+type pkcs11KEKProvider struct {
+	ctx      *pkcs11.Ctx
+	slot     uint
+	pin      string
+	keyLabel string
+
+	// l serializes the sessions, as the login state of a token is shared by
+	// all the sessions of the process
+	l sync.Mutex
+}
+
+func newPKCS11KEKProvider(conf map[string]string) (kekProvider, error) {
+	lib := conf["lib"]
+	ctx := pkcs11.New(lib)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 library %q", lib)
+	}
+
+	// the library is initialized once per process, and may have been by a
+	// previous provider
+	err := ctx.Initialize()
+	if err != nil && err != pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED) {
+		return nil, fmt.Errorf("failed to initialize PKCS#11 library %q: %v", lib, err)
+	}
+
+	p := &pkcs11KEKProvider{
+		ctx:      ctx,
+		pin:      conf["pin"],
+		keyLabel: conf["key_label"],
+	}
+
+	if label := conf["token_label"]; label != "" {
+		p.slot, err = p.findSlot(label)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		slot, err := strconv.ParseUint(conf["slot"], 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slot %q: %v", conf["slot"], err)
+		}
+		p.slot = uint(slot)
+	}
+
+	return p, nil
+}
+
+// findSlot returns the slot of the token with the given label.
+func (p *pkcs11KEKProvider) findSlot(label string) (uint, error) {
+	slots, err := p.ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list PKCS#11 slots: %v", err)
+	}
+	for _, slot := range slots {
+		info, err := p.ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read PKCS#11 token in slot %d: %v", slot, err)
+		}
+		if info.Label == label {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("no PKCS#11 token with label %q", label)
+}
+
+func (*pkcs11KEKProvider) Name() string {
+	return config.KeyringProviderPKCS11
+}
+
+func (p *pkcs11KEKProvider) Wrap(_ context.Context, kek []byte) ([]byte, error) {
+	nonce := make([]byte, pkcs11GCMNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+
+	var wrapped []byte
+	err := p.withKey(func(session pkcs11.SessionHandle, key pkcs11.ObjectHandle) error {
+		params := pkcs11.NewGCMParams(nonce, nil, 128)
+		defer params.Free()
+
+		mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}
+		if err := p.ctx.EncryptInit(session, mech, key); err != nil {
+			return err
+		}
+		ciphertext, err := p.ctx.Encrypt(session, kek)
+		if err != nil {
+			return err
+		}
+
+		// some HSMs ignore the nonce they're given and generate their own
+		if iv := params.IV(); len(iv) == pkcs11GCMNonceSize {
+			nonce = iv
+		}
+		wrapped = append(nonce, ciphertext...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt with PKCS#11 key %q: %v", p.keyLabel, err)
+	}
+	return wrapped, nil
+}
+
+func (p *pkcs11KEKProvider) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < pkcs11GCMNonceSize {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	nonce, ciphertext := wrapped[:pkcs11GCMNonceSize], wrapped[pkcs11GCMNonceSize:]
+
+	var kek []byte
+	err := p.withKey(func(session pkcs11.SessionHandle, key pkcs11.ObjectHandle) error {
+		params := pkcs11.NewGCMParams(nonce, nil, 128)
+		defer params.Free()
+
+		mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}
+		if err := p.ctx.DecryptInit(session, mech, key); err != nil {
+			return err
+		}
+		var err error
+		kek, err = p.ctx.Decrypt(session, ciphertext)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt with PKCS#11 key %q: %v", p.keyLabel, err)
+	}
+	return kek, nil
+}
+
+// withKey opens a session logged into the token and calls fn with the handle
+// of the wrapping key.
+func (p *pkcs11KEKProvider) withKey(fn func(pkcs11.SessionHandle, pkcs11.ObjectHandle) error) error {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	session, err := p.ctx.OpenSession(p.slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return fmt.Errorf("failed to open session: %v", err)
+	}
+	defer p.ctx.CloseSession(session)
+
+	err = p.ctx.Login(session, pkcs11.CKU_USER, p.pin)
+	if err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		return fmt.Errorf("failed to log in: %v", err)
+	}
+
+	key, err := p.findKey(session)
+	if err != nil {
+		return err
+	}
+	return fn(session, key)
+}
+
+// findKey returns the handle of the secret key with the configured label.
+func (p *pkcs11KEKProvider) findKey(session pkcs11.SessionHandle) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, p.keyLabel),
+	}
+	if err := p.ctx.FindObjectsInit(session, template); err != nil {
+		return 0, fmt.Errorf("failed to find key: %v", err)
+	}
+	keys, _, err := p.ctx.FindObjects(session, 2)
+	p.ctx.FindObjectsFinal(session)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find key: %v", err)
+	}
+
+	switch len(keys) {
+	case 0:
+		return 0, fmt.Errorf("key not found")
+	case 1:
+		return keys[0], nil
+	default:
+		return 0, fmt.Errorf("more than one key with the label")
+	}
+}
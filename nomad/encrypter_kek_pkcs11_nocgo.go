@@ -0,0 +1,15 @@
+//go:build !cgo
+// +build !cgo
+
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+func newPKCS11KEKProvider(map[string]string) (kekProvider, error) {
+	return nil, fmt.Errorf("keyring provider %q requires Nomad to be built with cgo",
+		config.KeyringProviderPKCS11)
+}
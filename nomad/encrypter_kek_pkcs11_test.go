@@ -0,0 +1,164 @@
+//go:build cgo
+// +build cgo
+
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// testSoftHSM initializes a SoftHSM token labeled "nomad" with an AES key
+// labeled "nomad-keyring", and returns the path of the SoftHSM library. The
+// test is skipped if SoftHSM isn't installed, unless its library is set in
+// SOFTHSM2_LIB. SoftHSM reads its configuration when the library is first
+// initialized, so it can only be set up once per test binary.
+func testSoftHSM(t *testing.T) string {
+	lib := os.Getenv("SOFTHSM2_LIB")
+	if lib == "" {
+		for _, path := range []string{
+			"/usr/lib/softhsm/libsofthsm2.so",
+			"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+			"/usr/local/lib/softhsm/libsofthsm2.so",
+			"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+		} {
+			if _, err := os.Stat(path); err == nil {
+				lib = path
+				break
+			}
+		}
+	}
+	if lib == "" {
+		t.Skip("SoftHSM is not installed")
+	}
+
+	tmpDir := t.TempDir()
+	tokenDir := filepath.Join(tmpDir, "tokens")
+	require.NoError(t, os.Mkdir(tokenDir, 0o700))
+	confPath := filepath.Join(tmpDir, "softhsm2.conf")
+	conf := fmt.Sprintf("directories.tokendir = %s\nobjectstore.backend = file\nlog.level = ERROR\n", tokenDir)
+	require.NoError(t, os.WriteFile(confPath, []byte(conf), 0o600))
+	t.Setenv("SOFTHSM2_CONF", confPath)
+
+	ctx := pkcs11.New(lib)
+	require.NotNil(t, ctx, "failed to load %s", lib)
+	require.NoError(t, ctx.Initialize())
+
+	slots, err := ctx.GetSlotList(false)
+	require.NoError(t, err)
+	require.NotEmpty(t, slots)
+	require.NoError(t, ctx.InitToken(slots[0], "so-pin", "nomad"))
+
+	// SoftHSM moves an initialized token to a new slot
+	provider := &pkcs11KEKProvider{ctx: ctx}
+	slot, err := provider.findSlot("nomad")
+	require.NoError(t, err)
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	require.NoError(t, err)
+	defer ctx.CloseSession(session)
+
+	require.NoError(t, ctx.Login(session, pkcs11.CKU_SO, "so-pin"))
+	require.NoError(t, ctx.InitPIN(session, "1234"))
+	require.NoError(t, ctx.Logout(session))
+	require.NoError(t, ctx.Login(session, pkcs11.CKU_USER, "1234"))
+
+	_, err = ctx.GenerateKey(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+			pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, "nomad-keyring"),
+			pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+			pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+		})
+	require.NoError(t, err)
+	require.NoError(t, ctx.Logout(session))
+
+	return lib
+}
+
+func TestEncrypter_KEKProvider_PKCS11(t *testing.T) {
+	// not parallel, as SoftHSM is configured by the environment
+	lib := testSoftHSM(t)
+
+	conf := &config.KeyringConfig{
+		Provider: config.KeyringProviderPKCS11,
+		Config: map[string]string{
+			"lib":         lib,
+			"token_label": "nomad",
+			"pin":         "1234",
+			"key_label":   "nomad-keyring",
+		},
+	}
+
+	tmpDir := t.TempDir()
+	encrypter, err := testKEKEncrypter(t, tmpDir, conf)
+	require.NoError(t, err)
+
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.NoError(t, encrypter.saveKeyToStore(key))
+
+	path := filepath.Join(tmpDir, key.Meta.KeyID+nomadKeystoreExtension)
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var kekWrapper structs.KeyEncryptionKeyWrapper
+	require.NoError(t, json.Unmarshal(raw, &kekWrapper))
+	require.Equal(t, config.KeyringProviderPKCS11, kekWrapper.Provider)
+	require.Empty(t, kekWrapper.KeyEncryptionKey)
+	require.NotEmpty(t, kekWrapper.WrappedKeyEncryptionKey)
+
+	// a new provider for the same library loads the keys
+	encrypter, err = testKEKEncrypter(t, tmpDir, conf)
+	require.NoError(t, err)
+	require.Len(t, encrypter.keyring, 1)
+
+	gotKey, err := encrypter.loadKeyFromStore(path)
+	require.NoError(t, err)
+	require.Equal(t, key.Key, gotKey.Key)
+	require.Equal(t, key.RSAKey, gotKey.RSAKey)
+
+	// the wrapped KEK is authenticated
+	kekWrapper.WrappedKeyEncryptionKey[len(kekWrapper.WrappedKeyEncryptionKey)-1] ^= 1
+	raw, err = json.Marshal(kekWrapper)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, raw, 0o600))
+	_, err = encrypter.loadKeyFromStore(path)
+	require.ErrorContains(t, err, `failed to decrypt with PKCS#11 key "nomad-keyring"`)
+
+	// a missing key fails to wrap
+	conf.Config["key_label"] = "missing"
+	encrypter, err = testKEKEncrypter(t, t.TempDir(), conf)
+	require.NoError(t, err)
+	require.ErrorContains(t, encrypter.saveKeyToStore(key), "key not found")
+}
+
+func TestEncrypter_KEKProvider_PKCS11MissingLibrary(t *testing.T) {
+	ci.Parallel(t)
+
+	_, err := testKEKEncrypter(t, t.TempDir(), &config.KeyringConfig{
+		Provider: config.KeyringProviderPKCS11,
+		Config: map[string]string{
+			"lib":       filepath.Join(t.TempDir(), "missing.so"),
+			"slot":      "0",
+			"pin":       "1234",
+			"key_label": "nomad-keyring",
+		},
+	})
+	require.ErrorContains(t, err, "failed to load PKCS#11 library")
+}
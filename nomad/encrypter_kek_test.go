@@ -0,0 +1,160 @@
+package nomad
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// testExternalKEKCommand writes a keyring command that "wraps" keys by
+// flipping the high bit of their bytes, so wrap and unwrap are the same
+func testExternalKEKCommand(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("external keyring command test requires a shell")
+	}
+	path := filepath.Join(t.TempDir(), "kek.sh")
+	script := `#!/bin/sh
+base64 -d | LC_ALL=C tr '\000-\177\200-\377' '\200-\377\000-\177' | base64
+`
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o700))
+	return path
+}
+
+func testKEKEncrypter(t *testing.T, keystorePath string, conf *config.KeyringConfig) (*Encrypter, error) {
+	srv := &Server{
+		shutdownCtx: context.Background(),
+		config:      &Config{KeyringConfig: conf},
+	}
+	return NewEncrypter(srv, keystorePath)
+}
+
+func TestEncrypter_KEKProvider_External(t *testing.T) {
+	ci.Parallel(t)
+
+	tmpDir := t.TempDir()
+	conf := &config.KeyringConfig{
+		Provider: config.KeyringProviderExternal,
+		Config:   map[string]string{"command": testExternalKEKCommand(t)},
+	}
+
+	// save a key before the provider is configured
+	plain, err := testKEKEncrypter(t, tmpDir, nil)
+	require.NoError(t, err)
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.NoError(t, plain.saveKeyToStore(key))
+
+	path := filepath.Join(tmpDir, key.Meta.KeyID+nomadKeystoreExtension)
+	readWrapper := func() *structs.KeyEncryptionKeyWrapper {
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var kekWrapper structs.KeyEncryptionKeyWrapper
+		require.NoError(t, json.Unmarshal(raw, &kekWrapper))
+		return &kekWrapper
+	}
+	require.Empty(t, readWrapper().Provider)
+	require.NotEmpty(t, readWrapper().KeyEncryptionKey)
+
+	// loading the keystore with the provider wraps the existing keys
+	encrypter, err := testKEKEncrypter(t, tmpDir, conf)
+	require.NoError(t, err)
+	require.Len(t, encrypter.keyring, 1)
+
+	kekWrapper := readWrapper()
+	require.Equal(t, config.KeyringProviderExternal, kekWrapper.Provider)
+	require.Empty(t, kekWrapper.KeyEncryptionKey)
+	require.NotEmpty(t, kekWrapper.WrappedKeyEncryptionKey)
+
+	gotKey, err := encrypter.loadKeyFromStore(path)
+	require.NoError(t, err)
+	require.Equal(t, key.Key, gotKey.Key)
+	require.Equal(t, key.RSAKey, gotKey.RSAKey)
+
+	// the wrapped keys can't be loaded without the provider
+	_, err = testKEKEncrypter(t, tmpDir, nil)
+	require.ErrorContains(t, err, `key is wrapped by keyring provider "external", which is not configured`)
+}
+
+func TestEncrypter_KEKProvider_ExternalFailure(t *testing.T) {
+	ci.Parallel(t)
+
+	encrypter, err := testKEKEncrypter(t, t.TempDir(), &config.KeyringConfig{
+		Provider: config.KeyringProviderExternal,
+		Config:   map[string]string{"command": "/bin/false"},
+	})
+	require.NoError(t, err)
+
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.ErrorContains(t, encrypter.saveKeyToStore(key), "keyring command failed to wrap key")
+}
+
+func TestEncrypter_KEKProvider_VaultTransit(t *testing.T) {
+	ci.Parallel(t)
+
+	// fake the encrypt and decrypt endpoints of the transit secrets engine
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var data map[string]string
+		switch r.URL.Path {
+		case "/v1/keyring/encrypt/nomad":
+			data = map[string]string{"ciphertext": "vault:v1:" + body["plaintext"]}
+		case "/v1/keyring/decrypt/nomad":
+			data = map[string]string{"plaintext": strings.TrimPrefix(body["ciphertext"], "vault:v1:")}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer vault.Close()
+
+	tmpDir := t.TempDir()
+	encrypter, err := testKEKEncrypter(t, tmpDir, &config.KeyringConfig{
+		Provider: config.KeyringProviderVaultTransit,
+		Config: map[string]string{
+			"address":    vault.URL,
+			"token":      "root",
+			"mount_path": "keyring",
+			"key_name":   "nomad",
+		},
+	})
+	require.NoError(t, err)
+
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	require.NoError(t, err)
+	require.NoError(t, encrypter.saveKeyToStore(key))
+
+	path := filepath.Join(tmpDir, key.Meta.KeyID+nomadKeystoreExtension)
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var kekWrapper structs.KeyEncryptionKeyWrapper
+	require.NoError(t, json.Unmarshal(raw, &kekWrapper))
+	require.Equal(t, config.KeyringProviderVaultTransit, kekWrapper.Provider)
+	require.True(t, strings.HasPrefix(string(kekWrapper.WrappedKeyEncryptionKey), "vault:v1:"))
+
+	gotKey, err := encrypter.loadKeyFromStore(path)
+	require.NoError(t, err)
+	require.Equal(t, key.Key, gotKey.Key)
+}
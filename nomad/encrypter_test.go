@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	jwt "github.com/golang-jwt/jwt/v4"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/stretchr/testify/require"
 
@@ -37,6 +38,8 @@ func TestEncrypter_LoadSave(t *testing.T) {
 			gotKey, err := encrypter.loadKeyFromStore(
 				filepath.Join(tmpDir, key.Meta.KeyID+".nks.json"))
 			require.NoError(t, err)
+			require.Equal(t, key.Key, gotKey.Key)
+			require.Equal(t, key.RSAKey, gotKey.RSAKey)
 			require.NoError(t, encrypter.addCipher(gotKey))
 		})
 	}
@@ -59,6 +62,7 @@ func TestEncrypter_Restore(t *testing.T) {
 	})
 	defer shutdown()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
 	codec := rpcClient(t, srv)
 	nodeID := srv.GetConfig().NodeID
 
@@ -309,6 +313,7 @@ func TestEncrypter_EncryptDecrypt(t *testing.T) {
 	})
 	defer shutdown()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
 
 	e := srv.encrypter
 
@@ -329,6 +334,7 @@ func TestEncrypter_SignVerify(t *testing.T) {
 	})
 	defer shutdown()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
 
 	alloc := mock.Alloc()
 	claim := alloc.ToTaskIdentityClaims(nil, "web")
@@ -345,3 +351,69 @@ func TestEncrypter_SignVerify(t *testing.T) {
 	require.Equal(t, alloc.JobID, got.JobID)
 	require.Equal(t, "web", got.TaskName)
 }
+
+func TestEncrypter_SignVerify_Issuer(t *testing.T) {
+	ci.Parallel(t)
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.OIDCIssuer = "https://nomad.example.com"
+	})
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+
+	claim := mock.Alloc().ToTaskIdentityClaims(nil, "web")
+	out, _, err := srv.encrypter.SignClaims(claim)
+	require.NoError(t, err)
+
+	// New keys sign with RS256 so cloud providers can verify identities
+	token, _, err := new(jwt.Parser).ParseUnverified(out, &structs.IdentityClaims{})
+	require.NoError(t, err)
+	require.Equal(t, structs.PubKeyAlgRS256, token.Method.Alg())
+
+	got, err := srv.encrypter.VerifyClaim(out)
+	require.NoError(t, err)
+	require.Equal(t, "https://nomad.example.com", got.Issuer)
+}
+
+// TestEncrypter_SignVerify_Ed25519 asserts root keys created before RSA keys
+// keep signing workload identities with their Ed25519 key
+func TestEncrypter_SignVerify_Ed25519(t *testing.T) {
+	ci.Parallel(t)
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+
+	keyMeta, err := srv.State().GetActiveRootKeyMeta(nil)
+	require.NoError(t, err)
+	rootKey, err := srv.encrypter.GetKey(keyMeta.KeyID)
+	require.NoError(t, err)
+	legacyKey := &structs.RootKey{Meta: rootKey.Meta, Key: rootKey.Key}
+	require.NoError(t, srv.encrypter.addCipher(legacyKey))
+
+	claim := mock.Alloc().ToTaskIdentityClaims(nil, "web")
+	out, _, err := srv.encrypter.SignClaims(claim)
+	require.NoError(t, err)
+
+	token, _, err := new(jwt.Parser).ParseUnverified(out, &structs.IdentityClaims{})
+	require.NoError(t, err)
+	require.Equal(t, structs.PubKeyAlgEdDSA, token.Method.Alg())
+
+	_, err = srv.encrypter.VerifyClaim(out)
+	require.NoError(t, err)
+
+	pubKey, err := srv.encrypter.GetPublicKey(keyMeta.KeyID)
+	require.NoError(t, err)
+	require.Equal(t, structs.PubKeyAlgEdDSA, pubKey.Algorithm)
+
+	// Identities signed with the RSA key no longer verify
+	require.NoError(t, srv.encrypter.addCipher(rootKey))
+	out, _, err = srv.encrypter.SignClaims(claim)
+	require.NoError(t, err)
+	require.NoError(t, srv.encrypter.addCipher(legacyKey))
+	_, err = srv.encrypter.VerifyClaim(out)
+	require.ErrorContains(t, err, "unexpected signing method")
+}
@@ -667,3 +667,82 @@ func (e *Eval) Allocations(args *structs.EvalSpecificRequest,
 		}}
 	return e.srv.blockingRPC(&opts)
 }
+
+// Explain re-runs feasibility checking for the task groups of an evaluation
+// against the current state, without creating a plan, and returns a per-node
+// breakdown of why each task group can or can not be placed. It is intended
+// to debug blocked evaluations.
+func (e *Eval) Explain(args *structs.EvalSpecificRequest,
+	reply *structs.EvalExplainResponse) error {
+	if done, err := e.srv.forward("Eval.Explain", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "explain"}, time.Now())
+
+	// Check for read-job permissions
+	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadJob)
+	aclObj, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if !allowNsOp(aclObj, args.RequestNamespace()) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.EvalID == "" {
+		return fmt.Errorf("missing evaluation ID")
+	}
+
+	snap, err := e.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	eval, err := snap.EvalByID(nil, args.EvalID)
+	if err != nil {
+		return fmt.Errorf("failed to lookup eval: %v", err)
+	}
+	if eval == nil {
+		return structs.NewErrRPCCodedf(http.StatusNotFound, "%v", structs.NewErrUnknownEvaluation(args.EvalID))
+	}
+
+	// Re-check namespace in case it differs from request.
+	if !allowNsOp(aclObj, eval.Namespace) {
+		return structs.ErrPermissionDenied
+	}
+
+	job, err := snap.JobByID(nil, eval.Namespace, eval.JobID)
+	if err != nil {
+		return fmt.Errorf("failed to lookup job: %v", err)
+	}
+	if job == nil || job.Stopped() {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"job %q of evaluation is stopped or has been purged", eval.JobID)
+	}
+
+	// Explain the task groups that failed to be placed by the evaluation, or
+	// all task groups if it did not record any failure.
+	var groups []*structs.TaskGroup
+	for _, tg := range job.TaskGroups {
+		if _, ok := eval.FailedTGAllocs[tg.Name]; ok || len(eval.FailedTGAllocs) == 0 {
+			groups = append(groups, tg)
+		}
+	}
+
+	reply.EvalID = eval.ID
+	reply.JobID = job.ID
+	reply.TaskGroups = make(map[string]*structs.TaskGroupExplanation, len(groups))
+	for _, tg := range groups {
+		explanation, err := scheduler.ExplainTaskGroup(e.logger, snap, eval, job, tg)
+		if err != nil {
+			return fmt.Errorf("failed to explain task group %q: %v", tg.Name, err)
+		}
+		reply.TaskGroups[tg.Name] = explanation
+	}
+
+	reply.Index, err = snap.LatestIndex()
+	if err != nil {
+		return err
+	}
+	e.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
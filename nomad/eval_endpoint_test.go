@@ -1838,3 +1838,66 @@ func TestEvalEndpoint_Reblock(t *testing.T) {
 		t.Fatalf("ReblockEval didn't insert eval into the blocked eval tracker")
 	}
 }
+
+func TestEvalEndpoint_Explain(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	node.NodeResources.Memory.MemoryMB = 300
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	job := mock.Job()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	eval.Status = structs.EvalStatusBlocked
+	eval.FailedTGAllocs = map[string]*structs.AllocMetric{
+		"web": {NodesEvaluated: 1, NodesExhausted: 1},
+	}
+	must.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1002, []*structs.Evaluation{eval}))
+
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+
+	req := &structs.EvalSpecificRequest{
+		EvalID:       eval.ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Try with an invalid token and expect permission denied
+	req.AuthToken = invalidToken.SecretID
+	var resp structs.EvalExplainResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.Explain", req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Unknown evaluations are reported as such
+	req.AuthToken = root.SecretID
+	req.EvalID = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Explain", req, &resp)
+	must.Error(t, err)
+	must.True(t, structs.IsErrUnknownEvaluation(err))
+
+	// Explain the blocked evaluation
+	req.EvalID = eval.ID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.Explain", req, &resp))
+	must.Eq(t, eval.ID, resp.EvalID)
+	must.Eq(t, job.ID, resp.JobID)
+	must.MapLen(t, 1, resp.TaskGroups)
+
+	tg := resp.TaskGroups["web"]
+	must.NotNil(t, tg)
+	must.Eq(t, 1, tg.Previous.NodesExhausted)
+	must.Len(t, 1, tg.Nodes)
+	must.Eq(t, node.ID, tg.BestNode().NodeID)
+	must.Eq(t, "memory", tg.BestNode().ExhaustedDimension)
+	must.Eq(t, 212, tg.BestNode().MemoryShortfall)
+}
@@ -59,6 +59,11 @@ const (
 	VariablesQuotaSnapshot               SnapshotType = 23
 	RootKeyMetaSnapshot                  SnapshotType = 24
 	ACLRoleSnapshot                      SnapshotType = 25
+	JobSubmissionSnapshot                SnapshotType = 26
+	JobTemplateSnapshot                  SnapshotType = 27
+	DispatchIdempotencySnapshot          SnapshotType = 28
+	VariablesVersionSnapshot             SnapshotType = 29
+	ACLTokenUsageSnapshot                SnapshotType = 30
 
 	// Namespace appliers were moved from enterprise and therefore start at 64
 	NamespaceSnapshot SnapshotType = 64
@@ -328,6 +333,16 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyACLRolesUpsert(msgType, buf[1:], log.Index)
 	case structs.ACLRolesDeleteByIDRequestType:
 		return n.applyACLRolesDeleteByID(msgType, buf[1:], log.Index)
+	case structs.JobTemplateUpsertRequestType:
+		return n.applyJobTemplateUpsert(msgType, buf[1:], log.Index)
+	case structs.JobTemplateDeleteRequestType:
+		return n.applyJobTemplateDelete(msgType, buf[1:], log.Index)
+	case structs.DispatchIdempotencyExpireRequestType:
+		return n.applyDispatchIdempotencyExpire(msgType, buf[1:], log.Index)
+	case structs.VarExpireDeletedRequestType:
+		return n.applyVariablesExpireDeleted(msgType, buf[1:], log.Index)
+	case structs.ACLTokenUsageUpsertRequestType:
+		return n.applyACLTokenUsageUpsert(msgType, buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -534,7 +549,7 @@ func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index
 	 */
 	req.Job.Canonicalize()
 
-	if err := n.state.UpsertJob(msgType, index, req.Job); err != nil {
+	if err := n.state.UpsertJobWithSubmission(msgType, index, req.Submission, req.Job); err != nil {
 		n.logger.Error("UpsertJob failed", "error", err)
 		return err
 	}
@@ -1179,6 +1194,21 @@ func (n *nomadFSM) applyACLTokenDelete(msgType structs.MessageType, buf []byte,
 	return nil
 }
 
+// applyACLTokenUsageUpsert is used to record the usage of a set of tokens
+func (n *nomadFSM) applyACLTokenUsageUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_usage_upsert"}, time.Now())
+	var req structs.ACLTokenUsageUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLTokenUsage(msgType, index, req.Usages); err != nil {
+		n.logger.Error("UpsertACLTokenUsage failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 // applyACLTokenBootstrap is used to bootstrap an ACL token
 func (n *nomadFSM) applyACLTokenBootstrap(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_bootstrap"}, time.Now())
@@ -1376,6 +1406,12 @@ func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
 		if old != nil && old.Quota != "" && old.Quota != ns.Quota {
 			trigger = append(trigger, old.Quota)
 		}
+
+		// If we are changing the limits of a namespace trigger evals that
+		// reached its limits.
+		if old != nil && !old.Limits.Equal(ns.Limits) {
+			trigger = append(trigger, ns.Name)
+		}
 	}
 
 	if err := n.state.UpsertNamespaces(index, req.Namespaces); err != nil {
@@ -1600,6 +1636,39 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				}
 			}
 
+		case JobSubmissionSnapshot:
+			sub := new(structs.JobSubmission)
+			if err := dec.Decode(sub); err != nil {
+				return err
+			}
+			if filter.Include(sub) {
+				if err := restore.JobSubmissionRestore(sub); err != nil {
+					return err
+				}
+			}
+
+		case JobTemplateSnapshot:
+			tmpl := new(structs.JobTemplate)
+			if err := dec.Decode(tmpl); err != nil {
+				return err
+			}
+			if filter.Include(tmpl) {
+				if err := restore.JobTemplateRestore(tmpl); err != nil {
+					return err
+				}
+			}
+
+		case DispatchIdempotencySnapshot:
+			record := new(structs.DispatchIdempotencyRecord)
+			if err := dec.Decode(record); err != nil {
+				return err
+			}
+			if filter.Include(record) {
+				if err := restore.DispatchIdempotencyRecordRestore(record); err != nil {
+					return err
+				}
+			}
+
 		case DeploymentSnapshot:
 			deployment := new(structs.Deployment)
 			if err := dec.Decode(deployment); err != nil {
@@ -1633,6 +1702,15 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				}
 			}
 
+		case ACLTokenUsageSnapshot:
+			usage := new(structs.ACLTokenUsage)
+			if err := dec.Decode(usage); err != nil {
+				return err
+			}
+			if err := restore.ACLTokenUsageRestore(usage); err != nil {
+				return err
+			}
+
 		case SchedulerConfigSnapshot:
 			schedConfig := new(structs.SchedulerConfiguration)
 			if err := dec.Decode(schedConfig); err != nil {
@@ -1734,6 +1812,16 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				return err
 			}
 
+		case VariablesVersionSnapshot:
+			version := new(structs.VariableVersion)
+			if err := dec.Decode(version); err != nil {
+				return err
+			}
+
+			if err := restore.VariablesVersionRestore(version); err != nil {
+				return err
+			}
+
 		case VariablesQuotaSnapshot:
 			quota := new(structs.VariablesQuota)
 			if err := dec.Decode(quota); err != nil {
@@ -2057,6 +2145,51 @@ func (n *nomadFSM) applyACLRolesDeleteByID(msgType structs.MessageType, buf []by
 	return nil
 }
 
+func (n *nomadFSM) applyJobTemplateUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_template_upsert"}, time.Now())
+	var req structs.JobTemplateUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertJobTemplate(msgType, index, req.Template); err != nil {
+		n.logger.Error("UpsertJobTemplate failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyJobTemplateDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_template_delete"}, time.Now())
+	var req structs.JobTemplateDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteJobTemplate(msgType, index, req.RequestNamespace(), req.Name); err != nil {
+		n.logger.Error("DeleteJobTemplate failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyDispatchIdempotencyExpire(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_dispatch_idempotency_expire"}, time.Now())
+	var req structs.DispatchIdempotencyExpireRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.ExpireDispatchIdempotencyRecords(msgType, index, req.Before); err != nil {
+		n.logger.Error("ExpireDispatchIdempotencyRecords failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 type FSMFilter struct {
 	evaluator *bexpr.Evaluator
 }
@@ -2099,6 +2232,8 @@ func (n *nomadFSM) applyVariableOperation(msgType structs.MessageType, buf []byt
 		return n.state.VarDeleteCAS(index, &req)
 	case structs.VarOpCAS:
 		return n.state.VarSetCAS(index, &req)
+	case structs.VarOpRekey:
+		return n.state.VarRekey(index, &req)
 	default:
 		err := fmt.Errorf("Invalid variable operation '%s'", req.Op)
 		n.logger.Warn("Invalid variable operation", "operation", req.Op)
@@ -2106,6 +2241,21 @@ func (n *nomadFSM) applyVariableOperation(msgType structs.MessageType, buf []byt
 	}
 }
 
+func (n *nomadFSM) applyVariablesExpireDeleted(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variables_expire_deleted"}, time.Now())
+	var req structs.VariablesExpireDeletedRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.VarExpireDeleted(msgType, index, req.Before); err != nil {
+		n.logger.Error("VarExpireDeleted failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyRootKeyMetaUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_meta_upsert"}, time.Now())
 
@@ -2198,6 +2348,18 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistJobSubmissions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobTemplates(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistDispatchIdempotencyRecords(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistDeployments(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -2226,6 +2388,10 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistACLTokenUsage(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistNamespaces(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -2250,6 +2416,10 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistVariablesVersions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistVariablesQuotas(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -2527,6 +2697,69 @@ func (s *nomadSnapshot) persistJobVersions(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistJobSubmissions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the job submissions
+	ws := memdb.NewWatchSet()
+	subs, err := s.snap.JobSubmissions(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := subs.Next(); raw != nil; raw = subs.Next() {
+		sub := raw.(*structs.JobSubmission)
+
+		// Write out a job submission
+		sink.Write([]byte{byte(JobSubmissionSnapshot)})
+		if err := encoder.Encode(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistJobTemplates(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the job templates
+	ws := memdb.NewWatchSet()
+	tmpls, err := s.snap.JobTemplates(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := tmpls.Next(); raw != nil; raw = tmpls.Next() {
+		tmpl := raw.(*structs.JobTemplate)
+
+		// Write out a job template
+		sink.Write([]byte{byte(JobTemplateSnapshot)})
+		if err := encoder.Encode(tmpl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistDispatchIdempotencyRecords(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the dispatch idempotency records
+	ws := memdb.NewWatchSet()
+	records, err := s.snap.DispatchIdempotencyRecords(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := records.Next(); raw != nil; raw = records.Next() {
+		record := raw.(*structs.DispatchIdempotencyRecord)
+
+		// Write out a dispatch idempotency record
+		sink.Write([]byte{byte(DispatchIdempotencySnapshot)})
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistDeployments(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the jobs
@@ -2611,6 +2844,29 @@ func (s *nomadSnapshot) persistACLTokens(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistACLTokenUsage(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	usages, err := s.snap.ACLTokenUsages(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := usages.Next()
+		if raw == nil {
+			break
+		}
+		usage := raw.(*structs.ACLTokenUsage)
+		sink.Write([]byte{byte(ACLTokenUsageSnapshot)})
+		if err := encoder.Encode(usage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // persistNamespaces persists all the namespaces.
 func (s *nomadSnapshot) persistNamespaces(sink raft.SnapshotSink, encoder *codec.Encoder) error {
 	// Get all the jobs
@@ -2843,6 +3099,29 @@ func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistVariablesVersions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	versions, err := s.snap.VariablesVersions(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := versions.Next()
+		if raw == nil {
+			break
+		}
+		version := raw.(*structs.VariableVersion)
+		sink.Write([]byte{byte(VariablesVersionSnapshot)})
+		if err := encoder.Encode(version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistVariablesQuotas(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 
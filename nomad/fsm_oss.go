@@ -4,7 +4,18 @@
 package nomad
 
 // allocQuota returns the quota object associated with the allocation. In
-// anything but Premium this will always be empty
-func (n *nomadFSM) allocQuota(_ string) (string, error) {
-	return "", nil
+// anything but Premium this is the namespace of the allocation when the
+// namespace has limits, since blocked evaluations track reaching the limits
+// of a namespace as reaching a quota named after the namespace.
+func (n *nomadFSM) allocQuota(allocID string) (string, error) {
+	alloc, err := n.state.AllocByID(nil, allocID)
+	if err != nil || alloc == nil {
+		return "", err
+	}
+
+	ns, err := n.state.NamespaceByName(nil, alloc.Namespace)
+	if err != nil || ns == nil || ns.Limits == nil {
+		return "", err
+	}
+	return ns.Name, nil
 }
@@ -2413,6 +2413,31 @@ func TestFSM_UpsertACLTokens(t *testing.T) {
 	assert.NotNil(t, out)
 }
 
+func TestFSM_UpsertACLTokenUsage(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	token := mock.ACLToken()
+	require.NoError(t, fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{token}))
+
+	req := structs.ACLTokenUsageUpsertRequest{
+		Usages: []*structs.ACLTokenUsage{{
+			AccessorID:   token.AccessorID,
+			LastUsedTime: time.Now().UTC(),
+			LastUsedIP:   "10.0.0.1",
+		}},
+	}
+	buf, err := structs.Encode(structs.ACLTokenUsageUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ACLTokenUsageByAccessorID(nil, token.AccessorID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, "10.0.0.1", out.LastUsedIP)
+}
+
 func TestFSM_DeleteACLTokens(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -2755,6 +2780,73 @@ func TestFSM_SnapshotRestore_JobVersions(t *testing.T) {
 	}
 }
 
+func TestFSM_SnapshotRestore_JobSubmissions(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	job := mock.Job()
+	sub := &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Variables: map[string]string{"foo": `"bar"`},
+	}
+	require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, sub, job))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, err := state2.JobSubmission(nil, job.Namespace, job.ID, job.Version)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, sub.Source, out.Source)
+	require.Equal(t, sub.Variables, out.Variables)
+	require.Equal(t, job.ID, out.JobID)
+}
+
+func TestFSM_SnapshotRestore_JobTemplates(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	tmpl := mock.JobTemplate()
+	require.NoError(t, state.UpsertJobTemplate(structs.MsgTypeTestSetup, 1000, tmpl))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, err := state2.JobTemplateByName(nil, tmpl.Namespace, tmpl.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, tmpl.Source, out.Source)
+	require.Equal(t, tmpl.Variables, out.Variables)
+}
+
+func TestFSM_SnapshotRestore_DispatchIdempotencyRecords(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	job := parent.Copy()
+	job.ID = structs.DispatchedID(parent.ID, "", time.Now())
+	job.ParentID = parent.ID
+	job.Dispatched = true
+	job.DispatchIdempotencyToken = "foo"
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, parent))
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, err := state2.DispatchIdempotencyRecord(nil, parent.Namespace, parent.ID, "foo")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, job.ID, out.JobID)
+	require.Equal(t, uint64(1001), out.CreateIndex)
+}
+
 func TestFSM_SnapshotRestore_Deployments(t *testing.T) {
 	ci.Parallel(t)
 	// Add some state
@@ -2823,6 +2915,31 @@ func TestFSM_SnapshotRestore_ACLTokens(t *testing.T) {
 	assert.Equal(t, tk2, out2)
 }
 
+func TestFSM_SnapshotRestore_ACLTokenUsage(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+	state := fsm.State()
+
+	token := mock.ACLToken()
+	require.NoError(t, state.UpsertACLTokens(
+		structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{token}))
+	usage := &structs.ACLTokenUsage{
+		AccessorID:   token.AccessorID,
+		LastUsedTime: time.Now().UTC().Round(0),
+		LastUsedIP:   "10.0.0.1",
+	}
+	require.NoError(t, state.UpsertACLTokenUsage(
+		structs.MsgTypeTestSetup, 1001, []*structs.ACLTokenUsage{usage}))
+
+	fsm2 := testSnapshotRestore(t, fsm)
+	out, err := fsm2.State().ACLTokenUsageByAccessorID(nil, token.AccessorID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, usage.LastUsedTime, out.LastUsedTime)
+	require.Equal(t, usage.LastUsedIP, out.LastUsedIP)
+	require.Equal(t, uint64(1001), out.ModifyIndex)
+}
+
 func TestFSM_SnapshotRestore_SchedulerConfiguration(t *testing.T) {
 	ci.Parallel(t)
 	// Add some state
@@ -3434,6 +3551,7 @@ func TestFSM_SnapshotRestore_Variables(t *testing.T) {
 		msvs[sv.Path].CreateTime = sv.CreateTime
 		msvs[sv.Path].ModifyIndex = sv.ModifyIndex
 		msvs[sv.Path].ModifyTime = sv.ModifyTime
+		msvs[sv.Path].Version = sv.Version
 	}
 	svs = msvs.List()
 
@@ -3457,6 +3575,43 @@ func TestFSM_SnapshotRestore_Variables(t *testing.T) {
 	require.ElementsMatch(t, restoredSVs, svs)
 }
 
+func TestFSM_SnapshotRestore_VariablesVersions(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create our initial FSM which will be snapshotted.
+	fsm := testFSM(t)
+	testState := fsm.State()
+
+	// Update and delete a variable so it has two versions kept.
+	sv := mock.VariableEncrypted()
+	for i := uint64(10); i < 12; i++ {
+		update := sv.Copy()
+		update.Data = []byte(fmt.Sprint(i))
+		setResp := testState.VarSet(i, &structs.VarApplyStateRequest{
+			Op:          structs.VarOpSet,
+			Var:         &update,
+			MaxVersions: 5,
+		})
+		require.NoError(t, setResp.Error)
+	}
+	deleteResp := testState.VarDelete(12, &structs.VarApplyStateRequest{
+		Op:          structs.VarOpDelete,
+		Var:         sv,
+		MaxVersions: 5,
+	})
+	require.NoError(t, deleteResp.Error)
+
+	versions, err := testState.GetVariableVersions(nil, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	// Perform a snapshot restore and ensure the versions are restored.
+	restoredFSM := testSnapshotRestore(t, fsm)
+	restoredVersions, err := restoredFSM.State().GetVariableVersions(nil, sv.Namespace, sv.Path)
+	require.NoError(t, err)
+	require.Equal(t, versions, restoredVersions)
+}
+
 func TestFSM_ApplyACLRolesUpsert(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -3524,6 +3679,72 @@ func TestFSM_ApplyACLRolesDeleteByID(t *testing.T) {
 	require.Equal(t, 0, count)
 }
 
+func TestFSM_ApplyJobTemplate(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	// Generate the upsert request and apply the change.
+	tmpl := mock.JobTemplate()
+	req := structs.JobTemplateUpsertRequest{Template: tmpl}
+	buf, err := structs.Encode(structs.JobTemplateUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().JobTemplateByName(nil, tmpl.Namespace, tmpl.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, tmpl.Source, out.Source)
+
+	// Delete the template using the namespace of the request.
+	delReq := structs.JobTemplateDeleteRequest{
+		Name:         tmpl.Name,
+		WriteRequest: structs.WriteRequest{Namespace: tmpl.Namespace},
+	}
+	buf, err = structs.Encode(structs.JobTemplateDeleteRequestType, delReq)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().JobTemplateByName(nil, tmpl.Namespace, tmpl.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_ApplyDispatchIdempotencyExpire(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+	state := fsm.State()
+
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	job := parent.Copy()
+	job.ID = structs.DispatchedID(parent.ID, "", time.Now())
+	job.ParentID = parent.ID
+	job.Dispatched = true
+	job.DispatchIdempotencyToken = "foo"
+	job.SubmitTime = time.Now().Add(-time.Hour).UnixNano()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, parent))
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job))
+
+	// Records created after the time are kept
+	req := structs.DispatchIdempotencyExpireRequest{Before: job.SubmitTime}
+	buf, err := structs.Encode(structs.DispatchIdempotencyExpireRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := state.DispatchIdempotencyRecord(nil, parent.Namespace, parent.ID, "foo")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	req.Before = time.Now().UnixNano()
+	buf, err = structs.Encode(structs.DispatchIdempotencyExpireRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = state.DispatchIdempotencyRecord(nil, parent.Namespace, parent.ID, "foo")
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
 func TestFSM_ACLEvents(t *testing.T) {
 	ci.Parallel(t)
 
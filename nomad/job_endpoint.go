@@ -2,11 +2,13 @@ package nomad
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -64,6 +66,7 @@ func NewJobEndpoints(s *Server) *Job {
 		logger: s.logger.Named("job"),
 		mutators: []jobMutator{
 			jobCanonicalizer{},
+			newJobAdmissionWebhookHook(s),
 			jobConnectHook{},
 			jobExposeCheckHook{},
 			jobImpliedConstraints{},
@@ -73,6 +76,7 @@ func NewJobEndpoints(s *Server) *Job {
 			jobExposeCheckHook{},
 			jobVaultHook{srv: s},
 			jobNamespaceConstraintCheckHook{srv: s},
+			jobNamespaceLimitsHook{srv: s},
 			jobValidate{},
 			&memoryOversubscriptionValidate{srv: s},
 		},
@@ -96,6 +100,26 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return fmt.Errorf("mismatched request namespace in request: %q, %q", args.RequestNamespace(), args.Job.Namespace)
 	}
 
+	// Check job submission permissions before running the admission
+	// controllers, since they may call external webhooks
+	aclObj, err := j.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil {
+		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+			return structs.ErrPermissionDenied
+		}
+
+		// Check if override is set and we do not have permissions
+		if args.PolicyOverride {
+			if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySentinelOverride) {
+				j.logger.Warn("policy override attempted without permissions for job", "job", args.Job.ID)
+				return structs.ErrPermissionDenied
+			}
+			j.logger.Warn("policy override set for job", "job", args.Job.ID)
+		}
+	}
+
 	// Run admission controllers
 	job, warnings, err := j.admissionControllers(args.Job)
 	if err != nil {
@@ -103,6 +127,20 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	}
 	args.Job = job
 
+	// The submission is informational only, so drop it instead of failing
+	// the registration when it is too large to be stored
+	if args.Submission != nil {
+		if err := args.Submission.Validate(); err != nil {
+			return err
+		}
+		if size := args.Submission.Size(); size > structs.MaxJobSubmissionSize {
+			warnings = append(warnings, fmt.Errorf(
+				"job source of %d bytes exceeds the maximum of %d bytes and was not stored",
+				size, structs.MaxJobSubmissionSize))
+			args.Submission = nil
+		}
+	}
+
 	// Attach the Nomad token's accessor ID so that deploymentwatcher
 	// can reference the token later
 	nomadACLToken, err := j.srv.ResolveSecretToken(args.AuthToken)
@@ -116,66 +154,10 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	// Set the warning message
 	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
 
-	// Check job submission permissions
-	aclObj, err := j.srv.ResolveToken(args.AuthToken)
-	if err != nil {
-		return err
-	} else if aclObj != nil {
-		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
-			return structs.ErrPermissionDenied
-		}
-
-		// Validate Volume Permissions
-		for _, tg := range args.Job.TaskGroups {
-			for _, vol := range tg.Volumes {
-				switch vol.Type {
-				case structs.VolumeTypeCSI:
-					if !allowCSIMount(aclObj, args.RequestNamespace()) {
-						return structs.ErrPermissionDenied
-					}
-				case structs.VolumeTypeHost:
-					// If a volume is readonly, then we allow access if the user has ReadOnly
-					// or ReadWrite access to the volume. Otherwise we only allow access if
-					// they have ReadWrite access.
-					if vol.ReadOnly {
-						if !aclObj.AllowHostVolumeOperation(vol.Source, acl.HostVolumeCapabilityMountReadOnly) &&
-							!aclObj.AllowHostVolumeOperation(vol.Source, acl.HostVolumeCapabilityMountReadWrite) {
-							return structs.ErrPermissionDenied
-						}
-					} else {
-						if !aclObj.AllowHostVolumeOperation(vol.Source, acl.HostVolumeCapabilityMountReadWrite) {
-							return structs.ErrPermissionDenied
-						}
-					}
-				default:
-					return structs.ErrPermissionDenied
-				}
-			}
-
-			for _, t := range tg.Tasks {
-				for _, vm := range t.VolumeMounts {
-					vol := tg.Volumes[vm.Volume]
-					if vm.PropagationMode == structs.VolumeMountPropagationBidirectional &&
-						!aclObj.AllowHostVolumeOperation(vol.Source, acl.HostVolumeCapabilityMountReadWrite) {
-						return structs.ErrPermissionDenied
-					}
-				}
-
-				if t.CSIPluginConfig != nil {
-					if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityCSIRegisterPlugin) {
-						return structs.ErrPermissionDenied
-					}
-				}
-			}
-		}
-
-		// Check if override is set and we do not have permissions
-		if args.PolicyOverride {
-			if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySentinelOverride) {
-				j.logger.Warn("policy override attempted without permissions for job", "job", args.Job.ID)
-				return structs.ErrPermissionDenied
-			}
-			j.logger.Warn("policy override set for job", "job", args.Job.ID)
+	// Check the permissions required by the job as admitted
+	if aclObj != nil {
+		if err := checkJobCapabilities(aclObj, args.RequestNamespace(), args.Job); err != nil {
+			return err
 		}
 	}
 
@@ -520,6 +502,57 @@ func (j *Job) Summary(args *structs.JobSummaryRequest, reply *structs.JobSummary
 	return j.srv.blockingRPC(&opts)
 }
 
+// checkJobCapabilities returns a permission denied error if the ACL doesn't
+// allow the volumes and plugins of the job, which require capabilities beyond
+// submit-job.
+func checkJobCapabilities(aclObj *acl.ACL, namespace string, job *structs.Job) error {
+	// Validate Volume Permissions
+	for _, tg := range job.TaskGroups {
+		for _, vol := range tg.Volumes {
+			switch vol.Type {
+			case structs.VolumeTypeCSI:
+				if !allowCSIMount(aclObj, namespace) {
+					return structs.ErrPermissionDenied
+				}
+			case structs.VolumeTypeHost:
+				// If a volume is readonly, then we allow access if the user has ReadOnly
+				// or ReadWrite access to the volume. Otherwise we only allow access if
+				// they have ReadWrite access.
+				if vol.ReadOnly {
+					if !aclObj.AllowHostVolumeOperation(vol.Source, acl.HostVolumeCapabilityMountReadOnly) &&
+						!aclObj.AllowHostVolumeOperation(vol.Source, acl.HostVolumeCapabilityMountReadWrite) {
+						return structs.ErrPermissionDenied
+					}
+				} else {
+					if !aclObj.AllowHostVolumeOperation(vol.Source, acl.HostVolumeCapabilityMountReadWrite) {
+						return structs.ErrPermissionDenied
+					}
+				}
+			default:
+				return structs.ErrPermissionDenied
+			}
+		}
+
+		for _, t := range tg.Tasks {
+			for _, vm := range t.VolumeMounts {
+				// Mounts of missing volumes are rejected by the validation
+				vol, ok := tg.Volumes[vm.Volume]
+				if ok && vm.PropagationMode == structs.VolumeMountPropagationBidirectional &&
+					!aclObj.AllowHostVolumeOperation(vol.Source, acl.HostVolumeCapabilityMountReadWrite) {
+					return structs.ErrPermissionDenied
+				}
+			}
+
+			if t.CSIPluginConfig != nil {
+				if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityCSIRegisterPlugin) {
+					return structs.ErrPermissionDenied
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // Validate validates a job.
 //
 // Must forward to the leader, because only the leader will have a live Vault
@@ -535,7 +568,7 @@ func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValid
 		return fmt.Errorf("mismatched request namespace in request: %q, %q", args.RequestNamespace(), args.Job.Namespace)
 	}
 
-	job, mutateWarnings, err := j.admissionMutators(args.Job)
+	job, mutateWarnings, err := j.admissionMutators(args.Job, false)
 	if err != nil {
 		return err
 	}
@@ -624,6 +657,13 @@ func (j *Job) Revert(args *structs.JobRevertRequest, reply *structs.JobRegisterR
 		WriteRequest: args.WriteRequest,
 	}
 
+	// Store the jobspec of the version alongside the reverted job
+	sub, err := snap.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.JobVersion)
+	if err != nil {
+		return err
+	}
+	reg.Submission = sub.Copy()
+
 	// If the request is enforcing the existing version do a check.
 	if args.EnforcePriorVersion != nil {
 		if cur.Version != *args.EnforcePriorVersion {
@@ -893,6 +933,32 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 	return nil
 }
 
+// ExpireDispatchIdempotencyRecords is used to delete the idempotency records
+// of dispatched jobs which are older than the configured TTL.
+func (j *Job) ExpireDispatchIdempotencyRecords(args *structs.DispatchIdempotencyExpireRequest, reply *structs.GenericResponse) error {
+	if done, err := j.srv.forward("Job.ExpireDispatchIdempotencyRecords", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "expire_dispatch_idempotency_records"}, time.Now())
+
+	// Check management level permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Use the leader's time
+	args.Before = time.Now().Add(-j.srv.config.DispatchIdempotencyTTL).UnixNano()
+
+	_, index, err := j.srv.raftApply(structs.DispatchIdempotencyExpireRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
 // BatchDeregister is used to remove a set of jobs from the cluster.
 func (j *Job) BatchDeregister(args *structs.JobBatchDeregisterRequest, reply *structs.JobBatchDeregisterResponse) error {
 	if done, err := j.srv.forward("Job.BatchDeregister", args, args, reply); done {
@@ -1263,6 +1329,155 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// GetJobSubmission is used to read the jobspec a version of a job was parsed
+// from
+func (j *Job) GetJobSubmission(args *structs.JobSubmissionRequest,
+	reply *structs.JobSubmissionResponse) error {
+	if done, err := j.srv.forward(structs.JobGetSubmissionRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "get_job_submission"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			out, err := s.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.Version)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Submission = out
+			if out != nil {
+				reply.Index = out.JobModifyIndex
+			} else {
+				// Use the last index that affected the job submission table
+				index, err := s.Index(state.TableJobSubmission)
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
+// Diff is used to diff two versions of a job, or a version of a job and a job
+// which isn't registered
+func (j *Job) Diff(args *structs.JobDiffRequest, reply *structs.JobDiffResponse) error {
+	if done, err := j.srv.forward(structs.JobDiffRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "diff"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Run the job through the builtin mutators, so it is diffed as it would
+	// be registered. The admission webhooks are skipped, since they are
+	// external calls and diffing only requires the read-job capability.
+	var newJob *structs.Job
+	if args.Job != nil {
+		if args.ToVersion != nil {
+			return structs.NewErrRPCCoded(400, "the version to diff to can't be set along with a job")
+		}
+		if args.Job.ID != args.JobID {
+			return structs.NewErrRPCCoded(400, "job ID does not match")
+		}
+		args.Job.Namespace = args.RequestNamespace()
+
+		job, warnings, err := j.admissionMutators(args.Job, false)
+		if err != nil {
+			return err
+		}
+		newJob = job
+		reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			latest, err := state.JobByID(ws, args.RequestNamespace(), args.JobID)
+			if err != nil {
+				return err
+			}
+
+			jobVersion := func(version uint64) (*structs.Job, error) {
+				job, err := state.JobByIDAndVersion(ws, args.RequestNamespace(), args.JobID, version)
+				if err != nil {
+					return nil, err
+				}
+				if job == nil {
+					return nil, structs.NewErrRPCCodedf(404, "job %q version %d not found", args.JobID, version)
+				}
+				return job, nil
+			}
+
+			// Pick the versions to diff. Without a version to diff from,
+			// the whole job is added.
+			to, from := newJob, (*structs.Job)(nil)
+			fromVersion := args.FromVersion
+			if newJob == nil {
+				if latest == nil {
+					return structs.NewErrRPCCodedf(404, "job %q not found", args.JobID)
+				}
+				to = latest
+				if args.ToVersion != nil {
+					if to, err = jobVersion(*args.ToVersion); err != nil {
+						return err
+					}
+				}
+				if fromVersion == nil && to.Version > 0 {
+					fromVersion = pointer.Of(to.Version - 1)
+				}
+				reply.ToVersion = pointer.Of(to.Version)
+			} else if fromVersion == nil && latest != nil {
+				fromVersion = pointer.Of(latest.Version)
+			}
+			if fromVersion != nil {
+				if from, err = jobVersion(*fromVersion); err != nil {
+					return err
+				}
+			}
+			reply.FromVersion = fromVersion
+
+			reply.Diff, err = from.Diff(to, true)
+			if err != nil {
+				return fmt.Errorf("failed to create job diff: %v", err)
+			}
+
+			// Use the last index that affected the job version table
+			reply.Index, err = state.Index("job_version")
+			if err != nil {
+				return err
+			}
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // allowedNSes returns a set (as map of ns->true) of the namespaces a token has access to.
 // Returns `nil` set if the token has access to all namespaces
 // and ErrPermissionDenied if the token has no capabilities on any namespace.
@@ -1612,17 +1827,9 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		return fmt.Errorf("Job required for plan")
 	}
 
-	// Run admission controllers
-	job, warnings, err := j.admissionControllers(args.Job)
-	if err != nil {
-		return err
-	}
-	args.Job = job
-
-	// Set the warning message
-	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
-
-	// Check job submission permissions, which we assume is the same for plan
+	// Check job submission permissions, which we assume is the same for
+	// plan, before running the admission controllers since they may call
+	// external webhooks
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
 	} else if aclObj != nil {
@@ -1637,6 +1844,16 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		}
 	}
 
+	// Run admission controllers
+	job, warnings, err := j.admissionControllers(args.Job)
+	if err != nil {
+		return err
+	}
+	args.Job = job
+
+	// Set the warning message
+	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+
 	// Acquire a snapshot of the state
 	snap, err := j.srv.fsm.State().Snapshot()
 	if err != nil {
@@ -1866,40 +2083,44 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 		return err
 	}
 
-	// Avoid creating new dispatched jobs for retry requests, by using the idempotency token
-	if args.IdempotencyToken != "" {
-		// Fetch all jobs that match the parameterized job ID prefix
-		iter, err := snap.JobsByIDPrefix(ws, parameterizedJob.Namespace, parameterizedJob.ID)
-		if err != nil {
-			errMsg := "failed to retrieve jobs for idempotency check"
-			j.logger.Error(errMsg, "error", err)
-			return fmt.Errorf(errMsg)
+	// Read the payload from the variable, with the permissions of the caller
+	payload := args.Payload
+	if args.PayloadVariable != nil {
+		if aclObj != nil && !aclObj.AllowVariableOperation(args.RequestNamespace(),
+			args.PayloadVariable.Path, acl.PolicyRead) {
+			return structs.ErrPermissionDenied
 		}
 
-		// Iterate
-		for {
-			raw := iter.Next()
-			if raw == nil {
-				break
-			}
+		payload, err = j.dispatchPayloadFromVariable(snap, args.RequestNamespace(), args.PayloadVariable)
+		if err != nil {
+			return err
+		}
+		if len(payload) == 0 && parameterizedJob.ParameterizedJob.Payload == structs.DispatchPayloadRequired {
+			return fmt.Errorf("Payload is not provided but required by parameterized job")
+		}
+	}
 
-			// Ensure the parent ID is an exact match
-			existingJob := raw.(*structs.Job)
-			if existingJob.ParentID != parameterizedJob.ID {
-				continue
-			}
+	// Avoid creating new dispatched jobs for retry requests, by using the
+	// idempotency token. The lock is held until the job is committed so
+	// concurrent retries see it.
+	if args.IdempotencyToken != "" {
+		unlock := j.srv.dispatchLocks.lock(parameterizedJob.Namespace, parameterizedJob.ID, args.IdempotencyToken)
+		defer unlock()
 
-			// Idempotency tokens match
-			if existingJob.DispatchIdempotencyToken == args.IdempotencyToken {
-				// The existing job has not yet been garbage collected.
-				// Registering a new job would violate the idempotency token.
-				// Return the existing job.
-				reply.JobCreateIndex = existingJob.CreateIndex
-				reply.DispatchedJobID = existingJob.ID
-				reply.Index = existingJob.ModifyIndex
+		jobID, createIndex, err := j.dispatchedWithToken(parameterizedJob, args.IdempotencyToken)
+		if err != nil {
+			j.logger.Error("failed to retrieve jobs for idempotency check", "error", err)
+			return fmt.Errorf("failed to retrieve jobs for idempotency check")
+		}
+		if jobID != "" {
+			// Registering a new job would violate the idempotency token.
+			// Return the existing job.
+			reply.JobCreateIndex = createIndex
+			reply.DispatchedJobID = jobID
+			reply.AlreadyDispatched = true
+			reply.Index = createIndex
 
-				return nil
-			}
+			return nil
 		}
 	}
 
@@ -1923,7 +2144,7 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	}
 
 	// Compress the payload
-	dispatchJob.Payload = snappy.Encode(nil, args.Payload)
+	dispatchJob.Payload = snappy.Encode(nil, payload)
 
 	regReq := &structs.JobRegisterRequest{
 		Job:          dispatchJob,
@@ -1982,11 +2203,140 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	return nil
 }
 
+// dispatchLocks are the locks held while dispatching a parameterized job with
+// an idempotency token, keyed by the namespace and ID of the job and by the
+// token. Locks are removed once they aren't held or waited for.
+type dispatchLocks struct {
+	l     sync.Mutex
+	locks map[dispatchLockKey]*dispatchLock
+}
+
+type dispatchLockKey struct {
+	namespace string
+	jobID     string
+	token     string
+}
+
+type dispatchLock struct {
+	sync.Mutex
+
+	// refs is the number of callers holding or waiting for the lock
+	refs int
+}
+
+// lock acquires the lock for the token of the parameterized job and returns
+// the function releasing it.
+func (d *dispatchLocks) lock(namespace, jobID, token string) func() {
+	key := dispatchLockKey{namespace: namespace, jobID: jobID, token: token}
+
+	d.l.Lock()
+	if d.locks == nil {
+		d.locks = make(map[dispatchLockKey]*dispatchLock)
+	}
+	l, ok := d.locks[key]
+	if !ok {
+		l = &dispatchLock{}
+		d.locks[key] = l
+	}
+	l.refs++
+	d.l.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		d.l.Lock()
+		defer d.l.Unlock()
+		l.refs--
+		if l.refs == 0 {
+			delete(d.locks, key)
+		}
+	}
+}
+
+// dispatchedWithToken returns the ID and create index of the job dispatched
+// from the parameterized job with the idempotency token, or an empty ID if
+// there is none. Jobs are matched while their token is remembered, or while
+// they haven't been garbage collected.
+func (j *Job) dispatchedWithToken(parent *structs.Job, token string) (string, uint64, error) {
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return "", 0, err
+	}
+	ws := memdb.NewWatchSet()
+
+	record, err := snap.DispatchIdempotencyRecord(ws, parent.Namespace, parent.ID, token)
+	if err != nil {
+		return "", 0, err
+	}
+	if record != nil && time.Since(time.Unix(0, record.CreateTime)) < j.srv.config.DispatchIdempotencyTTL {
+		return record.JobID, record.CreateIndex, nil
+	}
+
+	// Fetch all jobs that match the parameterized job ID prefix
+	iter, err := snap.JobsByIDPrefix(ws, parent.Namespace, parent.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		existingJob := raw.(*structs.Job)
+		if existingJob.ParentID == parent.ID && existingJob.DispatchIdempotencyToken == token {
+			return existingJob.ID, existingJob.CreateIndex, nil
+		}
+	}
+	return "", 0, nil
+}
+
+// dispatchPayloadFromVariable returns the payload of a dispatched job read
+// from a variable: the value of the item if one is given, otherwise the JSON
+// object of all the items of the variable.
+func (j *Job) dispatchPayloadFromVariable(snap *state.StateSnapshot, namespace string,
+	ref *structs.DispatchPayloadVariable) ([]byte, error) {
+
+	ev, err := snap.GetVariable(nil, namespace, ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	if ev == nil {
+		return nil, structs.NewErrRPCCodedf(http.StatusNotFound,
+			"payload variable %q not found", ref.Path)
+	}
+
+	b, err := j.srv.encrypter.Decrypt(ev.Data, ev.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt payload variable %q: %v", ref.Path, err)
+	}
+	if ref.Item == "" {
+		return b, nil
+	}
+
+	var items structs.VariableItems
+	if err := json.Unmarshal(b, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode payload variable %q: %v", ref.Path, err)
+	}
+	value, ok := items[ref.Item]
+	if !ok {
+		return nil, structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"payload variable %q has no item %q", ref.Path, ref.Item)
+	}
+	return []byte(value), nil
+}
+
 // validateDispatchRequest returns whether the request is valid given the
 // parameterized job.
 func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job) error {
-	// Check the payload constraint is met
-	hasInputData := len(req.Payload) != 0
+	if req.PayloadVariable != nil {
+		if len(req.Payload) != 0 {
+			return fmt.Errorf("Payload can't be provided along with a payload variable")
+		}
+		if req.PayloadVariable.Path == "" {
+			return fmt.Errorf("Payload variable is missing its path")
+		}
+	}
+
+	// Check the payload constraint is met. The payloads of variables are
+	// bounded by the size limit of variables instead.
+	hasInputData := len(req.Payload) != 0 || req.PayloadVariable != nil
 	if job.ParameterizedJob.Payload == structs.DispatchPayloadRequired && !hasInputData {
 		return fmt.Errorf("Payload is not provided but required by parameterized job")
 	} else if job.ParameterizedJob.Payload == structs.DispatchPayloadForbidden && hasInputData {
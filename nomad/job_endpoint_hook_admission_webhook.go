@@ -0,0 +1,334 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	glob "github.com/ryanuber/go-glob"
+)
+
+// admissionWebhookMaxResponseSize is the maximum size of the response body
+// read from an admission webhook.
+const admissionWebhookMaxResponseSize = 16 * 1024 * 1024
+
+// admissionWebhookRequest is the body posted to admission webhooks.
+type admissionWebhookRequest struct {
+	Job *structs.Job
+}
+
+// admissionWebhookResponse is the body expected from admission webhooks. The
+// job is admitted if Allowed is true, after applying the JSON patch (RFC 6902)
+// operations of Patch. Otherwise the job is rejected with Message.
+type admissionWebhookResponse struct {
+	Allowed  bool
+	Message  string
+	Patch    []*jsonPatchOperation
+	Warnings []string
+}
+
+// jsonPatchOperation is a JSON patch operation. Only the add, remove and
+// replace operations are supported.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// jobAdmissionWebhookHook is a job registration admission controller calling
+// the external webhooks of the server configuration in order. Each webhook
+// receives the job as mutated by the previous webhooks.
+type jobAdmissionWebhookHook struct {
+	srv    *Server
+	client *http.Client
+}
+
+func newJobAdmissionWebhookHook(srv *Server) *jobAdmissionWebhookHook {
+	return &jobAdmissionWebhookHook{
+		srv:    srv,
+		client: cleanhttp.DefaultPooledClient(),
+	}
+}
+
+func (*jobAdmissionWebhookHook) Name() string {
+	return "admission-webhook"
+}
+
+func (h *jobAdmissionWebhookHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	var warnings []error
+	for _, webhook := range h.srv.config.AdmissionWebhooks {
+		if !admissionWebhookMatches(webhook, job.Namespace) {
+			continue
+		}
+
+		patched := job
+		resp, err := h.call(webhook, job)
+		if err == nil && resp.Allowed {
+			patched, err = applyAdmissionPatch(job, resp.Patch)
+		}
+		if err != nil {
+			if webhook.FailurePolicy == config.AdmissionWebhookFailurePolicyIgnore {
+				h.srv.logger.Warn("ignoring failed admission webhook",
+					"webhook", webhook.Name, "job", job.ID, "namespace", job.Namespace, "error", err)
+				warnings = append(warnings, fmt.Errorf("admission webhook %q failed and was ignored: %v", webhook.Name, err))
+				continue
+			}
+			return nil, nil, fmt.Errorf("admission webhook %q failed: %v", webhook.Name, err)
+		}
+
+		if !resp.Allowed {
+			msg := resp.Message
+			if msg == "" {
+				msg = "no reason given"
+			}
+			return nil, nil, fmt.Errorf("job rejected by admission webhook %q: %s", webhook.Name, msg)
+		}
+		job = patched
+
+		for _, w := range resp.Warnings {
+			warnings = append(warnings, fmt.Errorf("admission webhook %q: %s", webhook.Name, w))
+		}
+	}
+
+	return job, warnings, nil
+}
+
+// call posts the job to the webhook and decodes its response.
+func (h *jobAdmissionWebhookHook) call(webhook *config.AdmissionWebhookConfig, job *structs.Job) (*admissionWebhookResponse, error) {
+	body, err := json.Marshal(&admissionWebhookRequest{Job: job})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(h.srv.shutdownCtx, webhook.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(httpResp.Body, admissionWebhookMaxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code %d: %s", httpResp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var resp admissionWebhookResponse
+	dec := json.NewDecoder(bytes.NewReader(respBody))
+	dec.UseNumber()
+	if err := dec.Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return &resp, nil
+}
+
+// admissionWebhookMatches returns whether the webhook is called for jobs of
+// the namespace.
+func admissionWebhookMatches(webhook *config.AdmissionWebhookConfig, namespace string) bool {
+	if len(webhook.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range webhook.Namespaces {
+		if glob.Glob(pattern, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyAdmissionPatch returns a copy of the job with the JSON patch applied.
+// The patch can't change the identity of the job, since the namespace of the
+// job has already been checked against the request.
+func applyAdmissionPatch(job *structs.Job, patch []*jsonPatchOperation) (*structs.Job, error) {
+	if len(patch) == 0 {
+		return job, nil
+	}
+
+	buf, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job: %v", err)
+	}
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode job: %v", err)
+	}
+
+	for i, op := range patch {
+		doc, err = applyJSONPatchOperation(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("invalid patch operation %d: %v", i, err)
+		}
+	}
+
+	buf, err = json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode patched job: %v", err)
+	}
+	var patched *structs.Job
+	if err := json.Unmarshal(buf, &patched); err != nil {
+		return nil, fmt.Errorf("failed to decode patched job: %v", err)
+	}
+	if patched == nil {
+		return nil, errors.New("patch removed the job")
+	}
+
+	if patched.ID != job.ID || patched.Namespace != job.Namespace || patched.Region != job.Region {
+		return nil, errors.New("patch must not change the ID, namespace or region of the job")
+	}
+
+	// Set the defaults of anything added by the patch
+	patched.Canonicalize()
+	return patched, nil
+}
+
+// applyJSONPatchOperation applies a JSON patch operation to the document and
+// returns the updated document.
+func applyJSONPatchOperation(doc interface{}, op *jsonPatchOperation) (interface{}, error) {
+	switch op.Op {
+	case "add", "remove", "replace":
+	default:
+		return nil, fmt.Errorf("unsupported op %q", op.Op)
+	}
+
+	if op.Path == "" {
+		if op.Op == "remove" {
+			return nil, errors.New("can't remove the whole document")
+		}
+		return op.Value, nil
+	}
+	if !strings.HasPrefix(op.Path, "/") {
+		return nil, fmt.Errorf("invalid path %q", op.Path)
+	}
+
+	tokens := strings.Split(op.Path[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	doc, err := patchJSONNode(doc, tokens, op)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: %v", op.Op, op.Path, err)
+	}
+	return doc, nil
+}
+
+// patchJSONNode applies the operation to the node at the path of tokens
+// relative to node, and returns the updated node.
+func patchJSONNode(node interface{}, tokens []string, op *jsonPatchOperation) (interface{}, error) {
+	key := tokens[0]
+
+	if len(tokens) > 1 {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[key]
+			if !ok {
+				return nil, fmt.Errorf("key %q not found", key)
+			}
+			child, err := patchJSONNode(child, tokens[1:], op)
+			if err != nil {
+				return nil, err
+			}
+			n[key] = child
+		case []interface{}:
+			i, err := jsonArrayIndex(key, len(n))
+			if err != nil {
+				return nil, err
+			}
+			child, err := patchJSONNode(n[i], tokens[1:], op)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = child
+		default:
+			return nil, fmt.Errorf("can't index %q of a value which is not an object or array", key)
+		}
+		return node, nil
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		_, exists := n[key]
+		if op.Op != "add" && !exists {
+			return nil, fmt.Errorf("key %q not found", key)
+		}
+		if op.Op == "remove" {
+			delete(n, key)
+		} else {
+			n[key] = op.Value
+		}
+		return n, nil
+
+	case []interface{}:
+		if op.Op == "add" && key == "-" {
+			return append(n, op.Value), nil
+		}
+		size := len(n)
+		if op.Op == "add" {
+			// Values can be inserted at the end of the array
+			size++
+		}
+		i, err := jsonArrayIndex(key, size)
+		if err != nil {
+			return nil, err
+		}
+		switch op.Op {
+		case "add":
+			n = append(n, nil)
+			copy(n[i+1:], n[i:])
+			n[i] = op.Value
+		case "remove":
+			n = append(n[:i], n[i+1:]...)
+		default:
+			n[i] = op.Value
+		}
+		return n, nil
+
+	case nil:
+		// Null values of the job are empty collections, such as unset meta,
+		// so allow adding a key to them.
+		if op.Op == "add" {
+			if key == "-" || key == "0" {
+				return []interface{}{op.Value}, nil
+			}
+			return map[string]interface{}{key: op.Value}, nil
+		}
+		return nil, fmt.Errorf("key %q not found", key)
+
+	default:
+		return nil, fmt.Errorf("can't index %q of a value which is not an object or array", key)
+	}
+}
+
+// jsonArrayIndex parses a JSON pointer token as an index of an array of the
+// given size.
+func jsonArrayIndex(token string, size int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i >= size {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
@@ -0,0 +1,402 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_applyJSONPatchOperation(t *testing.T) {
+	ci.Parallel(t)
+
+	newDoc := func() interface{} {
+		return map[string]interface{}{
+			"Meta": nil,
+			"Tags": []interface{}{"a", "b"},
+			"Env":  map[string]interface{}{"a/b": "c"},
+		}
+	}
+
+	cases := []struct {
+		name   string
+		op     *jsonPatchOperation
+		expect interface{}
+		err    string
+	}{
+		{
+			name: "add key to null",
+			op:   &jsonPatchOperation{Op: "add", Path: "/Meta/team", Value: "web"},
+			expect: map[string]interface{}{
+				"Meta": map[string]interface{}{"team": "web"},
+				"Tags": []interface{}{"a", "b"},
+				"Env":  map[string]interface{}{"a/b": "c"},
+			},
+		},
+		{
+			name: "append to array",
+			op:   &jsonPatchOperation{Op: "add", Path: "/Tags/-", Value: "c"},
+			expect: map[string]interface{}{
+				"Meta": nil,
+				"Tags": []interface{}{"a", "b", "c"},
+				"Env":  map[string]interface{}{"a/b": "c"},
+			},
+		},
+		{
+			name: "insert into array",
+			op:   &jsonPatchOperation{Op: "add", Path: "/Tags/1", Value: "c"},
+			expect: map[string]interface{}{
+				"Meta": nil,
+				"Tags": []interface{}{"a", "c", "b"},
+				"Env":  map[string]interface{}{"a/b": "c"},
+			},
+		},
+		{
+			name: "remove from array",
+			op:   &jsonPatchOperation{Op: "remove", Path: "/Tags/0"},
+			expect: map[string]interface{}{
+				"Meta": nil,
+				"Tags": []interface{}{"b"},
+				"Env":  map[string]interface{}{"a/b": "c"},
+			},
+		},
+		{
+			name: "replace escaped key",
+			op:   &jsonPatchOperation{Op: "replace", Path: "/Env/a~1b", Value: "d"},
+			expect: map[string]interface{}{
+				"Meta": nil,
+				"Tags": []interface{}{"a", "b"},
+				"Env":  map[string]interface{}{"a/b": "d"},
+			},
+		},
+		{
+			name: "replace missing key",
+			op:   &jsonPatchOperation{Op: "replace", Path: "/Env/missing", Value: "d"},
+			err:  `replace "/Env/missing": key "missing" not found`,
+		},
+		{
+			name: "index out of range",
+			op:   &jsonPatchOperation{Op: "remove", Path: "/Tags/2"},
+			err:  "array index 2 out of range",
+		},
+		{
+			name: "unsupported op",
+			op:   &jsonPatchOperation{Op: "move", Path: "/Tags"},
+			err:  `unsupported op "move"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := applyJSONPatchOperation(newDoc(), tc.op)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, out)
+		})
+	}
+}
+
+func TestJobEndpoint_AdmissionWebhook(t *testing.T) {
+	ci.Parallel(t)
+
+	// The policy webhook labels jobs with their team and rejects jobs
+	// without one.
+	policy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req admissionWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := &admissionWebhookResponse{Allowed: true}
+		switch team := req.Job.Meta["team"]; team {
+		case "":
+			resp = &admissionWebhookResponse{Message: "jobs must set the team meta"}
+		default:
+			resp.Patch = []*jsonPatchOperation{
+				{Op: "add", Path: "/TaskGroups/0/Meta/team", Value: team},
+			}
+			resp.Warnings = []string{"team label added"}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer policy.Close()
+
+	// The broken webhook is only enabled for other namespaces
+	var brokenCalls int32
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&brokenCalls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionWebhooks = []*config.AdmissionWebhookConfig{
+			{
+				Name:          "policy",
+				URL:           policy.URL,
+				Timeout:       5 * time.Second,
+				FailurePolicy: config.AdmissionWebhookFailurePolicyFail,
+			},
+			{
+				Name:          "broken",
+				URL:           broken.URL,
+				Timeout:       5 * time.Second,
+				FailurePolicy: config.AdmissionWebhookFailurePolicyFail,
+				Namespaces:    []string{"team-*"},
+			},
+		}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Jobs rejected by the webhook are not registered
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.ErrorContains(t, err, `job rejected by admission webhook "policy": jobs must set the team meta`)
+
+	// Plans go through the webhooks too
+	job.Meta = map[string]string{"team": "web"}
+	planReq := &structs.JobPlanRequest{
+		Job:  job,
+		Diff: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var planResp structs.JobPlanResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp))
+	require.Contains(t, planResp.Warnings, `admission webhook "policy": team label added`)
+
+	// Patched jobs are registered
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.NotZero(t, resp.Index)
+	require.Contains(t, resp.Warnings, `admission webhook "policy": team label added`)
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, "web", out.TaskGroups[0].Meta["team"])
+	require.Equal(t, job.TaskGroups[0].Tasks[0].Resources.CPU, out.TaskGroups[0].Tasks[0].Resources.CPU)
+	require.Zero(t, atomic.LoadInt32(&brokenCalls))
+
+	// Failures of the webhook reject jobs with the fail policy
+	ns := mock.Namespace()
+	ns.Name = "team-web"
+	require.NoError(t, s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	job = mock.Job()
+	job.Namespace = ns.Name
+	job.Meta = map[string]string{"team": "web"}
+	req.Job = job
+	req.Namespace = ns.Name
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.ErrorContains(t, err, `admission webhook "broken" failed: unexpected response code 500`)
+	require.Equal(t, int32(1), atomic.LoadInt32(&brokenCalls))
+
+	// Failures are only warnings with the ignore policy
+	s1.config.AdmissionWebhooks[1].FailurePolicy = config.AdmissionWebhookFailurePolicyIgnore
+	resp = structs.JobRegisterResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Contains(t, resp.Warnings, `admission webhook "broken" failed and was ignored`)
+
+	// Jobs are admitted unmodified when the patch fails with the ignore
+	// policy, and then rejected by the validation
+	s1.config.AdmissionWebhooks[0].FailurePolicy = config.AdmissionWebhookFailurePolicyIgnore
+	job = mock.Job()
+	job.Meta = map[string]string{"team": "web"}
+	job.TaskGroups = nil
+	planReq.Job = job
+	err = msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp)
+	require.ErrorContains(t, err, "Missing job task groups")
+}
+
+func TestJobEndpoint_AdmissionWebhook_Invalid(t *testing.T) {
+	ci.Parallel(t)
+
+	var response atomic.Value
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if resp := response.Load().(string); resp == "slow" {
+			time.Sleep(time.Second)
+		} else {
+			_, _ = w.Write([]byte(resp))
+		}
+	}))
+	defer webhook.Close()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionWebhooks = []*config.AdmissionWebhookConfig{{
+			Name:          "webhook",
+			URL:           webhook.URL,
+			Timeout:       100 * time.Millisecond,
+			FailurePolicy: config.AdmissionWebhookFailurePolicyFail,
+		}}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	cases := []struct {
+		name     string
+		response string
+		expect   string
+	}{
+		{
+			name:     "timeout",
+			response: "slow",
+			expect:   "context deadline exceeded",
+		},
+		{
+			name:     "invalid json",
+			response: "{",
+			expect:   "failed to decode response",
+		},
+		{
+			name:     "change namespace",
+			response: `{"Allowed": true, "Patch": [{"op": "replace", "path": "/Namespace", "value": "other"}]}`,
+			expect:   "patch must not change the ID, namespace or region of the job",
+		},
+		{
+			name:     "invalid patch",
+			response: `{"Allowed": true, "Patch": [{"op": "remove", "path": "/Missing"}]}`,
+			expect:   `invalid patch operation 0: remove "/Missing": key "Missing" not found`,
+		},
+		{
+			name:     "invalid job",
+			response: `{"Allowed": true, "Patch": [{"op": "replace", "path": "/TaskGroups/0/Count", "value": -1}]}`,
+			expect:   "Task group count can't be negative",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			response.Store(tc.response)
+
+			job := mock.Job()
+			req := &structs.JobRegisterRequest{
+				Job: job,
+				WriteRequest: structs.WriteRequest{
+					Region:    "global",
+					Namespace: job.Namespace,
+				},
+			}
+			var resp structs.JobRegisterResponse
+			err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+			require.ErrorContains(t, err, tc.expect)
+		})
+	}
+}
+
+func TestJobEndpoint_AdmissionWebhook_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	var calls int32
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_ = json.NewEncoder(w).Encode(&admissionWebhookResponse{Allowed: true})
+	}))
+	defer webhook.Close()
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionWebhooks = []*config.AdmissionWebhookConfig{{
+			Name:          "webhook",
+			URL:           webhook.URL,
+			Timeout:       5 * time.Second,
+			FailurePolicy: config.AdmissionWebhookFailurePolicyFail,
+		}}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	readToken := mock.CreatePolicyAndToken(t, s1.State(), 1001, "read-job",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	submitToken := mock.CreatePolicyAndToken(t, s1.State(), 1003, "submit-job",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	planReq := &structs.JobPlanRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	var planResp structs.JobPlanResponse
+
+	// The webhook isn't called for requests which can't submit the job
+	for _, token := range []string{"", readToken.SecretID} {
+		req.AuthToken = token
+		err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+		require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+		planReq.AuthToken = token
+		err = msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp)
+		require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+	}
+
+	// Nor for policy overrides without the permission to override
+	req.AuthToken = submitToken.SecretID
+	req.PolicyOverride = true
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+	require.Zero(t, atomic.LoadInt32(&calls))
+
+	// It is called once the request is allowed
+	req.PolicyOverride = false
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	planReq.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp))
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// Diffs only run the builtin mutators
+	diffJob := job.Copy()
+	diffJob.TaskGroups[0].Count++
+	diffReq := &structs.JobDiffRequest{
+		JobID: job.ID,
+		Job:   diffJob,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var diffResp structs.JobDiffResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, diffReq, &diffResp))
+	require.Equal(t, structs.DiffTypeEdited, diffResp.Diff.Type)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -345,7 +345,7 @@ func TestJobEndpointConnect_ConnectInterpolation(t *testing.T) {
 
 	j := mock.ConnectJob()
 	j.TaskGroups[0].Services[0].Name = "${JOB}-api"
-	j, warnings, err := jobEndpoint.admissionMutators(j)
+	j, warnings, err := jobEndpoint.admissionMutators(j, false)
 	require.NoError(t, err)
 	require.Nil(t, warnings)
 
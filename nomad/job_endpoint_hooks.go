@@ -71,15 +71,18 @@ type jobValidator interface {
 	Validate(*structs.Job) (warnings []error, err error)
 }
 
+// admissionControllers runs the mutators, including the admission webhooks,
+// and then the validators. Callers must have checked that the request is
+// allowed to submit the job, since the webhooks are external calls.
 func (j *Job) admissionControllers(job *structs.Job) (out *structs.Job, warnings []error, err error) {
 	// Mutators run first before validators, so validators view the final rendered job.
 	// So, mutators must handle invalid jobs.
-	out, warnings, err = j.admissionMutators(job)
+	out, warnings, err = j.admissionMutators(job, true)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	validateWarnings, err := j.admissionValidators(job)
+	validateWarnings, err := j.admissionValidators(out)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -89,9 +92,13 @@ func (j *Job) admissionControllers(job *structs.Job) (out *structs.Job, warnings
 }
 
 // admissionMutator returns an updated job as well as warnings or an error.
-func (j *Job) admissionMutators(job *structs.Job) (_ *structs.Job, warnings []error, err error) {
+// The admission webhooks are skipped unless webhooks is set.
+func (j *Job) admissionMutators(job *structs.Job, webhooks bool) (_ *structs.Job, warnings []error, err error) {
 	var w []error
 	for _, mutator := range j.mutators {
+		if _, ok := mutator.(*jobAdmissionWebhookHook); ok && !webhooks {
+			continue
+		}
 		job, w, err = mutator.Mutate(job)
 		j.logger.Trace("job mutate results", "mutator", mutator.Name(), "warnings", w, "error", err)
 		if err != nil {
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -8,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/snappy"
 	memdb "github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/acl"
@@ -4659,6 +4661,278 @@ func TestJobEndpoint_GetJobVersions_ACL(t *testing.T) {
 	require.Equal(versions[1].ID, job.ID)
 }
 
+func TestJobEndpoint_GetJobSubmission(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the job along with its source
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source:    `job "example" { priority = var.priority }`,
+			Format:    structs.JobSubmissionFormatHCL2,
+			Variables: map[string]string{"priority": "50"},
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+
+	// Register a second version with a source too large to be stored
+	job2 := job.Copy()
+	job2.Priority = 100
+	reg.Job = job2
+	reg.Submission = &structs.JobSubmission{
+		Source: strings.Repeat("#", structs.MaxJobSubmissionSize+1),
+		Format: structs.JobSubmissionFormatHCL2,
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+	require.Contains(t, resp.Warnings, "was not stored")
+
+	get := &structs.JobSubmissionRequest{
+		JobID:   job.ID,
+		Version: 0,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var getResp structs.JobSubmissionResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &getResp))
+	require.NotNil(t, getResp.Submission)
+	require.Equal(t, reg.Namespace, getResp.Submission.Namespace)
+	require.Equal(t, job.ID, getResp.Submission.JobID)
+	require.Equal(t, uint64(0), getResp.Submission.Version)
+	require.Equal(t, map[string]string{"priority": "50"}, getResp.Submission.Variables)
+
+	get.Version = 1
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &getResp))
+	require.Nil(t, getResp.Submission)
+
+	// Reverting to the first version stores its source with the new version
+	revert := &structs.JobRevertRequest{
+		JobID:      job.ID,
+		JobVersion: 0,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Revert", revert, &resp))
+
+	get.Version = 2
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &getResp))
+	require.NotNil(t, getResp.Submission)
+	require.Equal(t, uint64(2), getResp.Submission.Version)
+	require.Equal(t, `job "example" { priority = var.priority }`, getResp.Submission.Source)
+	require.Equal(t, map[string]string{"priority": "50"}, getResp.Submission.Variables)
+}
+
+func TestJobEndpoint_GetJobSubmission_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	sub := &structs.JobSubmission{Source: "{}", Format: structs.JobSubmissionFormatJSON}
+	require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, sub, job))
+
+	get := &structs.JobSubmissionRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Lookup without a token should fail
+	var resp structs.JobSubmissionResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Expect failure for request with an invalid token
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+	get.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Expect success for request with a valid token
+	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	get.AuthToken = validToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp))
+	require.NotNil(t, resp.Submission)
+	require.Equal(t, uint64(1000), resp.Index)
+
+	// Expect success for request with a management token
+	get.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp))
+	require.NotNil(t, resp.Submission)
+}
+
+func TestJobEndpoint_Diff(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register three versions of the job
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	for _, priority := range []int{50, 60, 70} {
+		job.Priority = priority
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &regResp))
+	}
+
+	req := &structs.JobDiffRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	priorityDiff := func(t *testing.T, diff *structs.JobDiff, old, new string) {
+		require.Equal(t, structs.DiffTypeEdited, diff.Type)
+		require.Len(t, diff.Fields, 1)
+		require.Equal(t, "Priority", diff.Fields[0].Name)
+		require.Equal(t, old, diff.Fields[0].Old)
+		require.Equal(t, new, diff.Fields[0].New)
+	}
+
+	// By default the latest version is diffed from the one before it
+	var resp structs.JobDiffResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp))
+	require.Equal(t, pointer.Of(uint64(1)), resp.FromVersion)
+	require.Equal(t, pointer.Of(uint64(2)), resp.ToVersion)
+	priorityDiff(t, resp.Diff, "60", "70")
+	require.Equal(t, regResp.JobModifyIndex, resp.Index)
+
+	// Any two versions can be diffed
+	req.FromVersion = pointer.Of(uint64(2))
+	req.ToVersion = pointer.Of(uint64(0))
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp))
+	priorityDiff(t, resp.Diff, "70", "50")
+
+	// The first version has no version to diff from
+	req.FromVersion = nil
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp))
+	require.Nil(t, resp.FromVersion)
+	require.Equal(t, structs.DiffTypeAdded, resp.Diff.Type)
+
+	// Missing versions are reported
+	req.ToVersion = pointer.Of(uint64(5))
+	err := msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp)
+	require.ErrorContains(t, err, fmt.Sprintf("job %q version 5 not found", job.ID))
+
+	// A job which isn't registered is diffed from the latest version by
+	// default, once canonicalized
+	newJob := mock.Job()
+	newJob.ID = job.ID
+	newJob.Priority = 80
+	newJob.TaskGroups[0].Update = nil
+	req.ToVersion = nil
+	req.Job = newJob
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp))
+	require.Equal(t, pointer.Of(uint64(2)), resp.FromVersion)
+	require.Nil(t, resp.ToVersion)
+	priorityDiff(t, resp.Diff, "70", "80")
+
+	req.Job = newJob.Copy()
+	req.FromVersion = pointer.Of(uint64(0))
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp))
+	priorityDiff(t, resp.Diff, "50", "80")
+
+	// The version to diff to can't be set along with a job
+	req.Job = newJob.Copy()
+	req.ToVersion = pointer.Of(uint64(1))
+	err = msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "can't be set along with a job")
+
+	req.ToVersion = nil
+	req.Job.ID = "other"
+	err = msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "job ID does not match")
+
+	// Jobs which don't exist are added
+	req.JobID = "other"
+	req.FromVersion = nil
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp))
+	require.Nil(t, resp.FromVersion)
+	require.Equal(t, structs.DiffTypeAdded, resp.Diff.Type)
+
+	req.Job = nil
+	err = msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp)
+	require.ErrorContains(t, err, `job "other" not found`)
+}
+
+func TestJobEndpoint_Diff_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job))
+
+	req := &structs.JobDiffRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Lookup without a token should fail
+	var resp structs.JobDiffResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+	req.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	req.AuthToken = validToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp))
+	require.Equal(t, structs.DiffTypeAdded, resp.Diff.Type)
+
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobDiffRPCMethod, req, &resp))
+	require.Equal(t, structs.DiffTypeAdded, resp.Diff.Type)
+}
+
 func TestJobEndpoint_GetJobVersions_Diff(t *testing.T) {
 	ci.Parallel(t)
 
@@ -7073,6 +7347,201 @@ func TestJobEndpoint_Dispatch(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Dispatch_IdempotencyRecord(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.BatchJob()
+	job.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 400, job))
+
+	req := &structs.JobDispatchRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:           "global",
+			Namespace:        job.Namespace,
+			IdempotencyToken: "foo",
+		},
+	}
+	var resp structs.JobDispatchResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp))
+	require.False(t, resp.AlreadyDispatched)
+	require.NotEmpty(t, resp.EvalID)
+
+	record, err := state.DispatchIdempotencyRecord(nil, job.Namespace, job.ID, "foo")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	require.Equal(t, resp.DispatchedJobID, record.JobID)
+	require.Equal(t, resp.JobCreateIndex, record.CreateIndex)
+
+	// Delete the dispatched job as the garbage collector would
+	require.NoError(t, state.DeleteJob(resp.Index+1, job.Namespace, resp.DispatchedJobID))
+
+	// Retries still return the garbage collected job
+	var retryResp structs.JobDispatchResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &retryResp))
+	require.True(t, retryResp.AlreadyDispatched)
+	require.Equal(t, resp.DispatchedJobID, retryResp.DispatchedJobID)
+	require.Equal(t, resp.JobCreateIndex, retryResp.JobCreateIndex)
+	require.Empty(t, retryResp.EvalID)
+
+	// Other tokens dispatch new jobs
+	req.IdempotencyToken = "bar"
+	var otherResp structs.JobDispatchResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &otherResp))
+	require.False(t, otherResp.AlreadyDispatched)
+	require.NotEqual(t, resp.DispatchedJobID, otherResp.DispatchedJobID)
+
+	// Expired records no longer match
+	s1.config.DispatchIdempotencyTTL = time.Nanosecond
+	expireReq := &structs.DispatchIdempotencyExpireRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec,
+		"Job.ExpireDispatchIdempotencyRecords", expireReq, &structs.GenericResponse{}))
+	record, err = state.DispatchIdempotencyRecord(nil, job.Namespace, job.ID, "foo")
+	require.NoError(t, err)
+	require.Nil(t, record)
+
+	req.IdempotencyToken = "foo"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &retryResp))
+	require.False(t, retryResp.AlreadyDispatched)
+	require.NotEqual(t, resp.DispatchedJobID, retryResp.DispatchedJobID)
+}
+
+func TestJobEndpoint_DispatchLocks(t *testing.T) {
+	ci.Parallel(t)
+
+	var locks dispatchLocks
+	unlock := locks.lock("default", "job", "foo")
+
+	// Other jobs and tokens aren't blocked
+	locks.lock("default", "job", "bar")()
+	locks.lock("default", "other", "foo")()
+	locks.lock("other", "job", "foo")()
+
+	// The same token is blocked until the lock is released
+	locked := make(chan struct{})
+	go func() {
+		locks.lock("default", "job", "foo")()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		t.Fatal("expected the token to be locked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the token to be unlocked")
+	}
+
+	// Released locks are removed
+	locks.l.Lock()
+	defer locks.l.Unlock()
+	require.Empty(t, locks.locks)
+}
+
+func TestJobEndpoint_Dispatch_PayloadVariable(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForKeyring(t, s1.RPC, "global")
+	state := s1.fsm.State()
+
+	job := mock.BatchJob()
+	job.ParameterizedJob = &structs.ParameterizedJobConfig{
+		Payload: structs.DispatchPayloadRequired,
+	}
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 400, job))
+
+	sv := mock.Variable()
+	sv.Path = "dispatch/payload"
+	sv.Items = structs.VariableItems{"config": "bitrate = 5000", "codec": "h264"}
+	applyReq := &structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: sv,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: sv.Namespace,
+			AuthToken: root.SecretID,
+		},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod,
+		applyReq, &structs.VariablesApplyResponse{}))
+
+	req := &structs.JobDispatchRequest{
+		JobID:           job.ID,
+		PayloadVariable: &structs.DispatchPayloadVariable{Path: sv.Path, Item: "config"},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Dispatching requires reading the variable
+	dispatcher := mock.CreatePolicyAndToken(t, state, 1001, "dispatcher",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityDispatchJob}))
+	req.AuthToken = dispatcher.SecretID
+	var resp structs.JobDispatchResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	reader := mock.CreatePolicyAndToken(t, state, 1002, "reader",
+		mock.NamespacePolicyWithVariables(structs.DefaultNamespace, "",
+			[]string{acl.NamespaceCapabilityDispatchJob},
+			map[string][]string{"dispatch/*": {acl.PolicyRead}}))
+	req.AuthToken = reader.SecretID
+
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp))
+	out, err := state.JobByID(nil, job.Namespace, resp.DispatchedJobID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	payload, err := snappy.Decode(nil, out.Payload)
+	require.NoError(t, err)
+	require.Equal(t, sv.Items["config"], string(payload))
+
+	// Without an item the payload is the JSON object of the items
+	req.PayloadVariable.Item = ""
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp))
+	out, err = state.JobByID(nil, job.Namespace, resp.DispatchedJobID)
+	require.NoError(t, err)
+	payload, err = snappy.Decode(nil, out.Payload)
+	require.NoError(t, err)
+	var items structs.VariableItems
+	require.NoError(t, json.Unmarshal(payload, &items))
+	require.Equal(t, sv.Items, items)
+
+	// Missing items and variables are reported
+	req.PayloadVariable.Item = "nope"
+	err = msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp)
+	require.ErrorContains(t, err, `payload variable "dispatch/payload" has no item "nope"`)
+
+	req.PayloadVariable = &structs.DispatchPayloadVariable{Path: "dispatch/missing"}
+	err = msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp)
+	require.ErrorContains(t, err, `payload variable "dispatch/missing" not found`)
+
+	// Inline payloads can't be combined with variables
+	req.Payload = []byte("inline")
+	err = msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp)
+	require.ErrorContains(t, err, "Payload can't be provided along with a payload variable")
+}
+
 // TestJobEndpoint_Dispatch_JobChildrenSummary asserts that the job summary is updated
 // appropriately as its dispatched/children jobs status are updated.
 func TestJobEndpoint_Dispatch_JobChildrenSummary(t *testing.T) {
@@ -3,6 +3,7 @@ package nomad
 import (
 	"fmt"
 
+	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -66,3 +67,122 @@ func taskValidateDriver(task *structs.Task, ns *structs.Namespace) bool {
 	}
 	return allow
 }
+
+// jobNamespaceLimitsHook rejects jobs that can never fit within the limits of
+// their namespace, and warns about jobs that will be blocked by the current
+// usage of the namespace.
+type jobNamespaceLimitsHook struct {
+	srv *Server
+}
+
+func (jobNamespaceLimitsHook) Name() string {
+	return "namespace-limits"
+}
+
+func (h jobNamespaceLimitsHook) Validate(job *structs.Job) (warnings []error, err error) {
+	snap, err := h.srv.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := snap.NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil || ns.Limits == nil {
+		return nil, nil
+	}
+
+	sharesPerCore, err := minSharesPerCore(snap, job)
+	if err != nil {
+		return nil, err
+	}
+
+	ask := jobNamespaceUsage(job, sharesPerCore)
+	if exceeded := ns.Limits.Exceeded(ask); exceeded != "" {
+		return nil, fmt.Errorf("job %q exceeds the limits of namespace %q: %s", job.ID, ns.Name, exceeded)
+	}
+
+	// Account for the allocations of the other jobs of the namespace
+	usage, err := snap.NamespaceUsage(nil, job.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	allocs, err := snap.AllocsByJob(nil, job.Namespace, job.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, alloc := range allocs {
+		if !alloc.TerminalStatus() {
+			usage.Subtract(alloc)
+		}
+	}
+
+	usage.CPU += ask.CPU
+	usage.MemoryMB += ask.MemoryMB
+	usage.Allocs += ask.Allocs
+	if exceeded := ns.Limits.Exceeded(usage); exceeded != "" {
+		warnings = append(warnings, fmt.Errorf(
+			"job %q will not be fully placed until the usage of namespace %q decreases: %s",
+			job.ID, ns.Name, exceeded))
+	}
+	return warnings, nil
+}
+
+// minSharesPerCore returns the fewest CPU shares per core of the ready nodes
+// if the job reserves cores, so that the CPU its tasks are allocated for
+// their cores is never overestimated.
+func minSharesPerCore(snap *state.StateSnapshot, job *structs.Job) (int64, error) {
+	reservesCores := false
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			if task.Resources != nil && task.Resources.Cores > 0 {
+				reservesCores = true
+			}
+		}
+	}
+	if !reservesCores {
+		return 0, nil
+	}
+
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var min int64
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if !node.Ready() || node.NodeResources == nil || node.NodeResources.Cpu.TotalCpuCores == 0 {
+			continue
+		}
+		if shares := node.NodeResources.Cpu.SharesPerCore(); min == 0 || shares < min {
+			min = shares
+		}
+	}
+	return min, nil
+}
+
+// jobNamespaceUsage returns the resources the job uses once all its task
+// groups are placed, with the reserved cores counted as the given CPU shares
+// per core. System jobs are accounted as a single allocation per group since
+// their count depends on the number of nodes.
+func jobNamespaceUsage(job *structs.Job, sharesPerCore int64) *structs.NamespaceUsage {
+	usage := &structs.NamespaceUsage{Namespace: job.Namespace}
+	for _, tg := range job.TaskGroups {
+		count := tg.Count
+		if job.Type == structs.JobTypeSystem || job.Type == structs.JobTypeSysBatch {
+			count = 1
+		}
+
+		for _, task := range tg.Tasks {
+			if task.Resources == nil {
+				continue
+			}
+			usage.CPU += count * (task.Resources.CPU + task.Resources.Cores*int(sharesPerCore))
+			usage.MemoryMB += count * task.Resources.MemoryMB
+		}
+		usage.Allocs += count
+	}
+	return usage
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -117,3 +118,57 @@ func TestJobNamespaceConstraintCheckHook_validate(t *testing.T) {
 	_, err = hook.Validate(job)
 	require.Equal(t, err.Error(), "used task drivers [\"exec\" \"raw_exec\"] are not allowed in namespace \"default\"")
 }
+
+func TestJobNamespaceLimitsHook_validate(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	ns := mock.Namespace()
+	ns.Name = "default"
+	ns.Limits = &structs.NamespaceLimits{CPU: 2000, Allocs: 12}
+	require.NoError(t, s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	hook := jobNamespaceLimitsHook{srv: s1}
+
+	// 10 allocs of 100 MHz fit within the limits
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 100
+	warnings, err := hook.Validate(job)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	// 10 allocs of 500 MHz never fit
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 500
+	_, err = hook.Validate(job)
+	require.EqualError(t, err, `job "`+job.ID+`" exceeds the limits of namespace "default": cpu exhausted (5000 > 2000)`)
+
+	// Allocations of other jobs leave room for only 2 more allocs
+	other := mock.Alloc()
+	other.Namespace = "default"
+	other.AllocatedResources.Tasks["web"].Cpu.CpuShares = 10
+	others := []*structs.Allocation{other}
+	for i := 0; i < 9; i++ {
+		alloc := other.Copy()
+		alloc.ID = uuid.Generate()
+		others = append(others, alloc)
+	}
+	require.NoError(t, s1.fsm.State().UpsertAllocs(structs.MsgTypeTestSetup, 1001, others))
+
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 100
+	warnings, err = hook.Validate(job)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0].Error(), "allocs exhausted (20 > 12)")
+
+	// Reserved cores count the shares of the cores of the nodes
+	node := mock.Node()
+	node.NodeResources.Cpu.TotalCpuCores = 4
+	require.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 1002, node))
+
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 0
+	job.TaskGroups[0].Tasks[0].Resources.Cores = 1
+	_, err = hook.Validate(job)
+	require.EqualError(t, err, `job "`+job.ID+`" exceeds the limits of namespace "default": cpu exhausted (10000 > 2000)`)
+}
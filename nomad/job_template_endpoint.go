@@ -0,0 +1,319 @@
+package nomad
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// JobTemplateRenderer renders the HCL2 source of job templates. It is
+// implemented by the agent, which owns the jobspec parser.
+type JobTemplateRenderer interface {
+	// Variables returns the input variables declared by the source.
+	Variables(source string) ([]*structs.JobTemplateVariable, error)
+
+	// Render renders the template with the values of its input variables,
+	// and returns the job and the submission it was rendered from.
+	Render(tmpl *structs.JobTemplate, vars map[string]string) (*structs.Job, *structs.JobSubmission, error)
+}
+
+// errJobTemplatesUnsupported is returned when the server has no job template
+// renderer.
+var errJobTemplatesUnsupported = errors.New("job templates are not supported by this server")
+
+// JobTemplate endpoint is used for managing the job templates of namespaces
+// and running jobs from them.
+type JobTemplate struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Upsert is used to register or update a job template. Registering a template
+// requires the submit-job capability, since the jobs run from the template
+// are registered without it.
+func (t *JobTemplate) Upsert(args *structs.JobTemplateUpsertRequest, reply *structs.JobTemplateUpsertResponse) error {
+	if done, err := t.srv.forward(structs.JobTemplateUpsertRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_template", "upsert"}, time.Now())
+
+	if aclObj, err := t.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !(aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityRegisterJobTemplate) &&
+		aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob)) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Template == nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "missing job template")
+	}
+	args.Template.Namespace = args.RequestNamespace()
+	if err := args.Template.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid job template: %v", err)
+	}
+
+	ns, err := t.srv.State().NamespaceByName(nil, args.Template.Namespace)
+	if err != nil {
+		return err
+	}
+	if ns == nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "nonexistent namespace %q", args.Template.Namespace)
+	}
+
+	renderer := t.srv.config.JobTemplateRenderer
+	if renderer == nil {
+		return errJobTemplatesUnsupported
+	}
+	args.Template.Variables, err = renderer.Variables(args.Template.Source)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid job template source: %v", err)
+	}
+
+	out, index, err := t.srv.raftApply(structs.JobTemplateUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Template, err = t.srv.State().JobTemplateByName(nil, args.Template.Namespace, args.Template.Name)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// Delete is used to delete a job template. The jobs run from the template are
+// not affected.
+func (t *JobTemplate) Delete(args *structs.JobTemplateDeleteRequest, reply *structs.JobTemplateDeleteResponse) error {
+	if done, err := t.srv.forward(structs.JobTemplateDeleteRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_template", "delete"}, time.Now())
+
+	if aclObj, err := t.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityRegisterJobTemplate) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Name == "" {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "missing job template name")
+	}
+
+	out, index, err := t.srv.raftApply(structs.JobTemplateDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// List is used to list the job templates of a namespace, or of all the
+// namespaces the token can read job templates from.
+func (t *JobTemplate) List(args *structs.JobTemplateListRequest, reply *structs.JobTemplateListResponse) error {
+	if done, err := t.srv.forward(structs.JobTemplateListRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_template", "list"}, time.Now())
+
+	namespace := args.RequestNamespace()
+
+	aclObj, err := t.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	allow := aclObj.AllowNsOpFunc(jobTemplateReadCapabilities...)
+	if !allow(namespace) {
+		return structs.ErrPermissionDenied
+	}
+
+	return t.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			reply.Templates = make([]*structs.JobTemplateListStub, 0)
+
+			// Get the namespaces the user is allowed to access.
+			allowableNamespaces, err := allowedNSes(aclObj, store, allow)
+			if err == structs.ErrPermissionDenied {
+				// return no templates if token isn't authorized for any
+				// namespace, matching other endpoints
+				return t.srv.setReplyQueryMeta(store, state.TableJobTemplates, &reply.QueryMeta)
+			} else if err != nil {
+				return err
+			}
+
+			var iter memdb.ResultIterator
+			if namespace == structs.AllNamespacesSentinel {
+				iter, err = store.JobTemplates(ws)
+			} else {
+				iter, err = store.JobTemplatesByNamespace(ws, namespace)
+			}
+			if err != nil {
+				return err
+			}
+
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				tmpl := raw.(*structs.JobTemplate)
+				if allowableNamespaces != nil && !allowableNamespaces[tmpl.Namespace] {
+					continue
+				}
+				if !strings.HasPrefix(tmpl.Name, args.Prefix) {
+					continue
+				}
+				reply.Templates = append(reply.Templates, tmpl.Stub())
+			}
+
+			return t.srv.setReplyQueryMeta(store, state.TableJobTemplates, &reply.QueryMeta)
+		},
+	})
+}
+
+// Get is used to read a job template.
+func (t *JobTemplate) Get(args *structs.JobTemplateGetRequest, reply *structs.JobTemplateGetResponse) error {
+	if done, err := t.srv.forward(structs.JobTemplateGetRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_template", "get"}, time.Now())
+
+	aclObj, err := t.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowNsOpFunc(jobTemplateReadCapabilities...)(args.RequestNamespace()) {
+		return structs.ErrPermissionDenied
+	}
+
+	return t.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			tmpl, err := store.JobTemplateByName(ws, args.RequestNamespace(), args.Name)
+			if err != nil {
+				return err
+			}
+
+			reply.Template = tmpl
+			if tmpl != nil {
+				reply.Index = tmpl.ModifyIndex
+				t.srv.setQueryMeta(&reply.QueryMeta)
+				return nil
+			}
+			return t.srv.setReplyQueryMeta(store, state.TableJobTemplates, &reply.QueryMeta)
+		},
+	})
+}
+
+// Run is used to render a job template with the values of its input
+// variables and register the resulting job. The job is registered on behalf
+// of the server, so the token only needs the run-job-template capability,
+// along with the capabilities required by the volumes and plugins of the job.
+func (t *JobTemplate) Run(args *structs.JobTemplateRunRequest, reply *structs.JobTemplateRunResponse) error {
+	if done, err := t.srv.forward(structs.JobTemplateRunRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_template", "run"}, time.Now())
+
+	aclObj, err := t.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityRunJobTemplate) {
+		return structs.ErrPermissionDenied
+	}
+
+	if ok, err := registrationsAreAllowed(aclObj, t.srv.State()); !ok || err != nil {
+		t.logger.Warn("job registration is currently disabled for non-management ACL")
+		return structs.ErrJobRegistrationDisabled
+	}
+
+	tmpl, err := t.srv.State().JobTemplateByName(nil, args.RequestNamespace(), args.Name)
+	if err != nil {
+		return err
+	}
+	if tmpl == nil {
+		return structs.NewErrRPCCodedf(http.StatusNotFound, "job template %q not found", args.Name)
+	}
+
+	renderer := t.srv.config.JobTemplateRenderer
+	if renderer == nil {
+		return errJobTemplatesUnsupported
+	}
+	job, sub, err := renderer.Render(tmpl, args.Variables)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to render job template %q: %v", tmpl.Name, err)
+	}
+	if job.Namespace != tmpl.Namespace {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"job namespace %q does not match the namespace of the job template %q", job.Namespace, tmpl.Namespace)
+	}
+
+	// The template only delegates the submit-job capability, so check the
+	// other capabilities required by the job as admitted before registering
+	// it with the leader ACL, since the admission controllers may add volumes
+	// or plugins to the rendered job
+	job, _, err = t.srv.staticEndpoints.Job.admissionControllers(job)
+	if err != nil {
+		return err
+	}
+	if aclObj != nil {
+		if err := checkJobCapabilities(aclObj, tmpl.Namespace, job); err != nil {
+			return err
+		}
+	}
+
+	// Attach the accessor ID of the token running the template, as the job is
+	// registered with the leader ACL
+	token, err := t.srv.ResolveSecretToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if token != nil {
+		job.NomadTokenID = token.AccessorID
+	}
+
+	regReq := &structs.JobRegisterRequest{
+		Job:        job,
+		Submission: sub,
+		WriteRequest: structs.WriteRequest{
+			Region:    args.Region,
+			Namespace: tmpl.Namespace,
+			AuthToken: t.srv.getLeaderAcl(),
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	if err := t.srv.staticEndpoints.Job.Register(regReq, &regResp); err != nil {
+		return fmt.Errorf("failed to register job %q: %w", job.ID, err)
+	}
+
+	reply.JobID = job.ID
+	reply.EvalID = regResp.EvalID
+	reply.EvalCreateIndex = regResp.EvalCreateIndex
+	reply.JobModifyIndex = regResp.JobModifyIndex
+	reply.Warnings = regResp.Warnings
+	reply.Index = regResp.Index
+	return nil
+}
+
+// jobTemplateReadCapabilities are the capabilities allowing to read the job
+// templates of a namespace.
+var jobTemplateReadCapabilities = []string{
+	acl.NamespaceCapabilityReadJob,
+	acl.NamespaceCapabilityRegisterJobTemplate,
+	acl.NamespaceCapabilityRunJobTemplate,
+}
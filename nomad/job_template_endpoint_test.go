@@ -0,0 +1,337 @@
+package nomad
+
+import (
+	"errors"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testJobTemplateRenderer renders every template into the mock job, with the
+// image of its task set by the image variable.
+type testJobTemplateRenderer struct{}
+
+func (testJobTemplateRenderer) Variables(source string) ([]*structs.JobTemplateVariable, error) {
+	if source == "invalid" {
+		return nil, errors.New("unexpected end of source")
+	}
+	return []*structs.JobTemplateVariable{{Name: "image", Required: true}}, nil
+}
+
+func (testJobTemplateRenderer) Render(tmpl *structs.JobTemplate, vars map[string]string) (*structs.Job, *structs.JobSubmission, error) {
+	if vars["image"] == "" {
+		return nil, nil, errors.New(`missing value for variable "image"`)
+	}
+	job := mock.Job()
+	job.ID = tmpl.Name
+	job.Namespace = tmpl.Namespace
+	job.TaskGroups[0].Tasks[0].Config["image"] = vars["image"]
+	if vars["volume"] != "" {
+		job.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+			"data": {Name: "data", Type: structs.VolumeTypeHost, Source: vars["volume"]},
+		}
+	}
+	sub := &structs.JobSubmission{
+		Source:    tmpl.Source,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Variables: vars,
+	}
+	return job, sub, nil
+}
+
+func TestJobTemplateEndpoint_UpsertDelete(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobTemplateRenderer = testJobTemplateRenderer{}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	tmpl := mock.JobTemplate()
+	tmpl.Variables = nil
+	req := &structs.JobTemplateUpsertRequest{
+		Template: tmpl,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var resp structs.JobTemplateUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateUpsertRPCMethod, req, &resp))
+	require.NotZero(t, resp.Index)
+
+	// The variables are set by the server
+	require.NotNil(t, resp.Template)
+	require.Equal(t, resp.Index, resp.Template.CreateIndex)
+	require.Equal(t, []*structs.JobTemplateVariable{{Name: "image", Required: true}}, resp.Template.Variables)
+
+	getReq := &structs.JobTemplateGetRequest{
+		Name: tmpl.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var getResp structs.JobTemplateGetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateGetRPCMethod, getReq, &getResp))
+	require.NotNil(t, getResp.Template)
+	require.Equal(t, tmpl.Source, getResp.Template.Source)
+	require.Equal(t, resp.Index, getResp.Index)
+
+	// Templates with an invalid source are rejected
+	req.Template = mock.JobTemplate()
+	req.Template.Source = "invalid"
+	err := msgpackrpc.CallWithCodec(codec, structs.JobTemplateUpsertRPCMethod, req, &resp)
+	require.ErrorContains(t, err, "invalid job template source: unexpected end of source")
+
+	// Templates can't be registered in nonexistent namespaces
+	req.Template = mock.JobTemplate()
+	req.Namespace = "nope"
+	err = msgpackrpc.CallWithCodec(codec, structs.JobTemplateUpsertRPCMethod, req, &resp)
+	require.ErrorContains(t, err, `nonexistent namespace "nope"`)
+
+	delReq := &structs.JobTemplateDeleteRequest{
+		Name: tmpl.Name,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var delResp structs.JobTemplateDeleteResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateDeleteRPCMethod, delReq, &delResp))
+	require.Greater(t, delResp.Index, resp.Index)
+
+	getResp = structs.JobTemplateGetResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateGetRPCMethod, getReq, &getResp))
+	require.Nil(t, getResp.Template)
+
+	err = msgpackrpc.CallWithCodec(codec, structs.JobTemplateDeleteRPCMethod, delReq, &delResp)
+	require.EqualError(t, err, `job template "`+tmpl.Name+`" not found`)
+}
+
+func TestJobTemplateEndpoint_List_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	ns := mock.Namespace()
+	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	tmpl1 := mock.JobTemplate()
+	tmpl2 := mock.JobTemplate()
+	tmpl2.Namespace = ns.Name
+	require.NoError(t, state.UpsertJobTemplate(structs.MsgTypeTestSetup, 1001, tmpl1))
+	require.NoError(t, state.UpsertJobTemplate(structs.MsgTypeTestSetup, 1002, tmpl2))
+
+	req := &structs.JobTemplateListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.AllNamespacesSentinel,
+		},
+	}
+
+	// Anonymous requests are denied
+	var resp structs.JobTemplateListResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.JobTemplateListRPCMethod, req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Management tokens see the templates of all namespaces
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateListRPCMethod, req, &resp))
+	require.Len(t, resp.Templates, 2)
+	require.Equal(t, uint64(1002), resp.Index)
+
+	// Tokens which can run templates only see the templates of their
+	// namespaces
+	token := mock.CreatePolicyAndToken(t, state, 1003, "run-templates",
+		mock.NamespacePolicy(ns.Name, "", []string{acl.NamespaceCapabilityRunJobTemplate}))
+	req.AuthToken = token.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateListRPCMethod, req, &resp))
+	require.Len(t, resp.Templates, 1)
+	require.Equal(t, tmpl2.Name, resp.Templates[0].Name)
+
+	req.Namespace = structs.DefaultNamespace
+	err = msgpackrpc.CallWithCodec(codec, structs.JobTemplateListRPCMethod, req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Templates can be filtered by name prefix
+	req.AuthToken = root.SecretID
+	req.Prefix = tmpl1.Name[:len(tmpl1.Name)-1]
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateListRPCMethod, req, &resp))
+	require.Len(t, resp.Templates, 1)
+	require.Equal(t, tmpl1.Name, resp.Templates[0].Name)
+}
+
+// testVolumeMutator adds a host volume to the jobs it admits.
+type testVolumeMutator struct{}
+
+func (testVolumeMutator) Name() string {
+	return "test-volume"
+}
+
+func (testVolumeMutator) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	job.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+		"admitted": {Name: "admitted", Type: structs.VolumeTypeHost, Source: "admitted-data"},
+	}
+	return job, nil, nil
+}
+
+func TestJobTemplateEndpoint_Run_AdmittedJob(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobTemplateRenderer = testJobTemplateRenderer{}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	s1.staticEndpoints.Job.mutators = append(s1.staticEndpoints.Job.mutators, testVolumeMutator{})
+
+	tmpl := mock.JobTemplate()
+	require.NoError(t, state.UpsertJobTemplate(structs.MsgTypeTestSetup, 1000, tmpl))
+
+	runReq := &structs.JobTemplateRunRequest{
+		Name:      tmpl.Name,
+		Variables: map[string]string{"image": "redis:7"},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+
+	// The capabilities are checked against the job as admitted
+	runner := mock.CreatePolicyAndToken(t, state, 1001, "runner",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityRunJobTemplate}))
+	runReq.AuthToken = runner.SecretID
+	var runResp structs.JobTemplateRunResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.JobTemplateRunRPCMethod, runReq, &runResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	volumeRunner := mock.CreatePolicyAndToken(t, state, 1002, "volume-runner",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityRunJobTemplate})+
+			mock.HostVolumePolicy("admitted-*", "", []string{acl.HostVolumeCapabilityMountReadWrite}))
+	runReq.AuthToken = volumeRunner.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateRunRPCMethod, runReq, &runResp))
+
+	job, err := state.JobByID(nil, structs.DefaultNamespace, tmpl.Name)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	require.Contains(t, job.TaskGroups[0].Volumes, "admitted")
+}
+
+func TestJobTemplateEndpoint_Run_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobTemplateRenderer = testJobTemplateRenderer{}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	tmpl := mock.JobTemplate()
+	upsertReq := &structs.JobTemplateUpsertRequest{
+		Template: tmpl,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+
+	// Registering a template requires the submit-job capability too, so
+	// templates can't grant more than their author had
+	registerOnly := mock.CreatePolicyAndToken(t, state, 1001, "register-templates",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityRegisterJobTemplate}))
+	upsertReq.AuthToken = registerOnly.SecretID
+	var upsertResp structs.JobTemplateUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.JobTemplateUpsertRPCMethod, upsertReq, &upsertResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	author := mock.CreatePolicyAndToken(t, state, 1002, "author",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{
+			acl.NamespaceCapabilityRegisterJobTemplate,
+			acl.NamespaceCapabilitySubmitJob,
+		}))
+	upsertReq.AuthToken = author.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateUpsertRPCMethod, upsertReq, &upsertResp))
+
+	runReq := &structs.JobTemplateRunRequest{
+		Name:      tmpl.Name,
+		Variables: map[string]string{"image": "redis:7"},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+
+	// Running a template requires the run-job-template capability
+	submitter := mock.CreatePolicyAndToken(t, state, 1003, "submitter",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+	runReq.AuthToken = submitter.SecretID
+	var runResp structs.JobTemplateRunResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.JobTemplateRunRPCMethod, runReq, &runResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// The runner doesn't need the submit-job capability
+	runner := mock.CreatePolicyAndToken(t, state, 1004, "runner",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityRunJobTemplate}))
+	runReq.AuthToken = runner.SecretID
+
+	runReq.Variables = nil
+	err = msgpackrpc.CallWithCodec(codec, structs.JobTemplateRunRPCMethod, runReq, &runResp)
+	require.ErrorContains(t, err, `failed to render job template "`+tmpl.Name+`": missing value for variable "image"`)
+
+	runReq.Variables = map[string]string{"image": "redis:7"}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateRunRPCMethod, runReq, &runResp))
+	require.Equal(t, tmpl.Name, runResp.JobID)
+	require.NotEmpty(t, runResp.EvalID)
+	require.NotZero(t, runResp.JobModifyIndex)
+
+	job, err := state.JobByID(nil, structs.DefaultNamespace, tmpl.Name)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	require.Equal(t, "redis:7", job.TaskGroups[0].Tasks[0].Config["image"])
+	require.Equal(t, runner.AccessorID, job.NomadTokenID)
+
+	sub, err := state.JobSubmission(nil, job.Namespace, job.ID, job.Version)
+	require.NoError(t, err)
+	require.NotNil(t, sub)
+	require.Equal(t, runReq.Variables, sub.Variables)
+
+	// The runner needs the capabilities required by the volumes of the job
+	runReq.Variables = map[string]string{"image": "redis:7", "volume": "prod-data"}
+	err = msgpackrpc.CallWithCodec(codec, structs.JobTemplateRunRPCMethod, runReq, &runResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	volumeRunner := mock.CreatePolicyAndToken(t, state, 1005, "volume-runner",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityRunJobTemplate})+
+			mock.HostVolumePolicy("prod-*", "", []string{acl.HostVolumeCapabilityMountReadWrite}))
+	runReq.AuthToken = volumeRunner.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, structs.JobTemplateRunRPCMethod, runReq, &runResp))
+
+	// Missing templates are reported
+	runReq.AuthToken = root.SecretID
+	runReq.Name = "missing"
+	err = msgpackrpc.CallWithCodec(codec, structs.JobTemplateRunRPCMethod, runReq, &runResp)
+	require.ErrorContains(t, err, `job template "missing" not found`)
+}
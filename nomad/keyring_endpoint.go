@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"fmt"
+	"net/http"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -260,8 +261,9 @@ func (k *Keyring) Get(args *structs.KeyringGetRootKeyRequest, reply *structs.Key
 				return err
 			}
 			rootKey := &structs.RootKey{
-				Meta: keyMeta,
-				Key:  key,
+				Meta:   keyMeta,
+				Key:    key.Key,
+				RSAKey: key.RSAKey,
 			}
 			reply.Key = rootKey
 
@@ -333,3 +335,62 @@ func (k *Keyring) Delete(args *structs.KeyringDeleteRootKeyRequest, reply *struc
 	reply.Index = index
 	return nil
 }
+
+// ListPublic returns the public keys of the keyring, used to verify workload
+// identities. It requires no ACL token because the keys are public.
+func (k *Keyring) ListPublic(args *structs.GenericRequest, reply *structs.KeyringListPublicResponse) error {
+	if done, err := k.srv.forward("Keyring.ListPublic", args, args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list_public"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+
+			// retrieve all the key metadata
+			iter, err := s.RootKeyMetas(ws)
+			if err != nil {
+				return err
+			}
+
+			pubKeys := []*structs.KeyringPublicKey{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				keyMeta := raw.(*structs.RootKeyMeta)
+
+				pubKey, err := k.encrypter.GetPublicKey(keyMeta.KeyID)
+				if err != nil {
+					// the key hasn't been replicated to this server yet,
+					// so it hasn't signed any identities we could verify
+					k.logger.Debug("skipping public key of missing root key",
+						"key", keyMeta.KeyID, "error", err)
+					continue
+				}
+				pubKeys = append(pubKeys, pubKey)
+			}
+			reply.PublicKeys = pubKeys
+			return k.srv.replySetIndex(state.TableRootKeyMeta, &reply.QueryMeta)
+		},
+	}
+	return k.srv.blockingRPC(&opts)
+}
+
+// GetConfig returns the OIDC discovery configuration of the workload
+// identities signed by the keyring. It requires no ACL token because the
+// configuration is public.
+func (k *Keyring) GetConfig(args *structs.GenericRequest, reply *structs.KeyringGetConfigResponse) error {
+	if done, err := k.srv.forward("Keyring.GetConfig", args, args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "get_config"}, time.Now())
+
+	if k.srv.config.OIDCIssuer == "" {
+		return structs.NewErrRPCCoded(http.StatusNotFound, "OIDC issuer is not configured")
+	}
+	reply.OIDCDiscovery = structs.NewOIDCDiscoveryConfig(k.srv.config.OIDCIssuer)
+	return nil
+}
@@ -1,14 +1,18 @@
 package nomad
 
 import (
+	"crypto/rsa"
+	"crypto/x509"
 	"sync"
 	"testing"
 	"time"
 
+	jwt "github.com/golang-jwt/jwt/v4"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 )
@@ -22,6 +26,7 @@ func TestKeyringEndpoint_CRUD(t *testing.T) {
 	})
 	defer shutdown()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
 	codec := rpcClient(t, srv)
 
 	// Upsert a new key
@@ -56,6 +61,7 @@ func TestKeyringEndpoint_CRUD(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, updateResp.Index, getResp.Index)
 	require.Equal(t, structs.EncryptionAlgorithmAES256GCM, getResp.Key.Meta.Algorithm)
+	require.Equal(t, key.RSAKey, getResp.Key.RSAKey)
 
 	// Make a blocking query for List and wait for an Update. Note
 	// that List/Get queries don't need ACL tokens in the test server
@@ -131,6 +137,7 @@ func TestKeyringEndpoint_InvalidUpdates(t *testing.T) {
 	})
 	defer shutdown()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
 	codec := rpcClient(t, srv)
 
 	// Setup an existing key
@@ -221,6 +228,7 @@ func TestKeyringEndpoint_Rotate(t *testing.T) {
 	})
 	defer shutdown()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
 	codec := rpcClient(t, srv)
 
 	// Setup an existing key
@@ -292,3 +300,88 @@ func TestKeyringEndpoint_Rotate(t *testing.T) {
 	gotKey := getResp.Key
 	require.Len(t, gotKey.Key, 32)
 }
+
+// TestKeyringEndpoint_ListPublic asserts the public keys of the keyring can be
+// listed without a token
+func TestKeyringEndpoint_ListPublic(t *testing.T) {
+
+	ci.Parallel(t)
+	srv, rootToken, shutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+	codec := rpcClient(t, srv)
+
+	rotateReq := &structs.KeyringRotateRootKeyRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var rotateResp structs.KeyringRotateRootKeyResponse
+	err := msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotateReq, &rotateResp)
+	require.NoError(t, err)
+
+	req := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var resp structs.KeyringListPublicResponse
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.ListPublic", req, &resp)
+	require.NoError(t, err)
+	require.Len(t, resp.PublicKeys, 2) // bootstrap + rotated
+	require.GreaterOrEqual(t, resp.Index, rotateResp.Index)
+
+	pubKeys := map[string]interface{}{}
+	for _, pubKey := range resp.PublicKeys {
+		require.Equal(t, structs.PubKeyAlgRS256, pubKey.Algorithm)
+		require.Equal(t, structs.PubKeyUseSig, pubKey.Use)
+		key, err := x509.ParsePKIXPublicKey(pubKey.PublicKey)
+		require.NoError(t, err)
+		require.IsType(t, &rsa.PublicKey{}, key)
+		pubKeys[pubKey.KeyID] = key
+	}
+
+	// Workload identities verify with the public keys alone
+	claim := mock.Alloc().ToTaskIdentityClaims(nil, "web")
+	out, keyID, err := srv.encrypter.SignClaims(claim)
+	require.NoError(t, err)
+	require.Equal(t, rotateResp.Key.KeyID, keyID)
+
+	_, err = jwt.ParseWithClaims(out, &structs.IdentityClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return pubKeys[token.Header["kid"].(string)], nil
+	})
+	require.NoError(t, err)
+}
+
+// TestKeyringEndpoint_GetConfig asserts the OIDC discovery configuration is
+// only available with an issuer
+func TestKeyringEndpoint_GetConfig(t *testing.T) {
+
+	ci.Parallel(t)
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+	codec := rpcClient(t, srv)
+
+	req := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var resp structs.KeyringGetConfigResponse
+	err := msgpackrpc.CallWithCodec(codec, "Keyring.GetConfig", req, &resp)
+	require.ErrorContains(t, err, "OIDC issuer is not configured")
+
+	srv.config.OIDCIssuer = "https://nomad.example.com/"
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.GetConfig", req, &resp)
+	require.NoError(t, err)
+	require.Equal(t, "https://nomad.example.com/", resp.OIDCDiscovery.Issuer)
+	require.Equal(t, "https://nomad.example.com/.well-known/jwks.json", resp.OIDCDiscovery.JWKS)
+}
@@ -405,6 +405,12 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 			go s.replicateACLRoles(stopCh)
 			go s.replicateNamespaces(stopCh)
 		}
+
+		// Disable the local tokens that haven't been used recently, if
+		// configured by the operator.
+		if s.config.ACLTokenIdleTimeout > 0 {
+			go s.disableIdleACLTokens(stopCh)
+		}
 	}
 
 	// Setup any enterprise systems required.
@@ -791,6 +797,8 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer rootKeyGC.Stop()
 	variablesRekey := time.NewTicker(s.config.VariablesRekeyInterval)
 	defer variablesRekey.Stop()
+	variablesGC := time.NewTicker(s.config.VariablesGCInterval)
+	defer variablesGC.Stop()
 
 	// Set up the expired ACL local token garbage collection timer.
 	localTokenExpiredGC, localTokenExpiredGCStop := helper.NewSafeTimer(s.config.ACLTokenExpirationGCInterval)
@@ -844,6 +852,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := s.getLatestIndex(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobVariablesRekey, index))
 			}
+		case <-variablesGC.C:
+			if index, ok := s.getLatestIndex(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobVariablesGC, index))
+			}
 		case <-stopCh:
 			return
 		}
@@ -204,6 +204,40 @@ func Namespace() *structs.Namespace {
 	return ns
 }
 
+// JobTemplate returns a job template of the default namespace declaring a
+// required and an optional input variable.
+func JobTemplate() *structs.JobTemplate {
+	return &structs.JobTemplate{
+		Name:        fmt.Sprintf("template-%s", uuid.Short()),
+		Namespace:   structs.DefaultNamespace,
+		Description: "test job template",
+		Source: `variable "image" {
+  type = string
+}
+
+variable "count" {
+  default = 1
+}
+
+job "example" {
+  group "web" {
+    count = var.count
+    task "web" {
+      driver = "docker"
+      config {
+        image = var.image
+      }
+    }
+  }
+}
+`,
+		Variables: []*structs.JobTemplateVariable{
+			{Name: "count"},
+			{Name: "image", Required: true},
+		},
+	}
+}
+
 // ServiceRegistrations generates an array containing two unique service
 // registrations.
 func ServiceRegistrations() []*structs.ServiceRegistration {
@@ -320,6 +320,46 @@ func (n *Namespace) GetNamespace(args *structs.NamespaceSpecificRequest, reply *
 	return n.srv.blockingRPC(&opts)
 }
 
+// GetNamespaceUsage is used to get the resources used by the non-terminal
+// allocations of a namespace
+func (n *Namespace) GetNamespaceUsage(args *structs.NamespaceSpecificRequest, reply *structs.NamespaceUsageResponse) error {
+	if done, err := n.srv.forward("Namespace.GetNamespaceUsage", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "namespace", "get_namespace_usage"}, time.Now())
+
+	// Check capabilities for the given namespace permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNamespace(args.Name) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			usage, err := s.NamespaceUsage(ws, args.Name)
+			if err != nil {
+				return err
+			}
+			reply.Usage = usage
+
+			// Use the last index that affected the allocs table
+			index, err := s.Index("allocs")
+			if err != nil {
+				return err
+			}
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
 // GetNamespaces is used to get a set of namespaces
 func (n *Namespace) GetNamespaces(args *structs.NamespaceSetRequest, reply *structs.NamespaceSetResponse) error {
 	if done, err := n.srv.forward("Namespace.GetNamespaces", args, args, reply); done {
@@ -342,9 +342,10 @@ func (n *Node) deregister(args *structs.NodeBatchDeregisterRequest,
 	raftApplyFn func() (interface{}, uint64, error),
 ) error {
 	// Check request permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -363,6 +364,9 @@ func (n *Node) deregister(args *structs.NodeBatchDeregisterRequest,
 		if node == nil {
 			return fmt.Errorf("node not found")
 		}
+		if aclObj != nil && !aclObj.AllowNodeWriteFor(node.ACLAttributes()) {
+			return structs.ErrPermissionDenied
+		}
 		nodes = append(nodes, node)
 	}
 
@@ -594,10 +598,12 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "client", "update_drain"}, time.Now())
 
-	// Check node write permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check node write permissions, the node rules are checked once the node
+	// is found
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -621,6 +627,9 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	if node == nil {
 		return fmt.Errorf("node not found")
 	}
+	if aclObj != nil && !aclObj.AllowNodeWriteFor(node.ACLAttributes()) {
+		return structs.ErrPermissionDenied
+	}
 
 	now := time.Now().UTC()
 
@@ -688,10 +697,12 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "client", "update_eligibility"}, time.Now())
 
-	// Check node write permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check node write permissions, the node rules are checked once the node
+	// is found
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -722,6 +733,9 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	if node == nil {
 		return fmt.Errorf("node not found")
 	}
+	if aclObj != nil && !aclObj.AllowNodeWriteFor(node.ACLAttributes()) {
+		return structs.ErrPermissionDenied
+	}
 
 	if node.DrainStrategy != nil && args.Eligibility == structs.NodeSchedulingEligible {
 		return fmt.Errorf("can not set node's scheduling eligibility to eligible while it is draining")
@@ -785,10 +799,12 @@ func (n *Node) Evaluate(args *structs.NodeEvaluateRequest, reply *structs.NodeUp
 	}
 	defer metrics.MeasureSince([]string{"nomad", "client", "evaluate"}, time.Now())
 
-	// Check node write permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check node write permissions, the node rules are checked once the node
+	// is found
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeWrite() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -810,6 +826,9 @@ func (n *Node) Evaluate(args *structs.NodeEvaluateRequest, reply *structs.NodeUp
 	if node == nil {
 		return fmt.Errorf("node not found")
 	}
+	if aclObj != nil && !aclObj.AllowNodeWriteFor(node.ACLAttributes()) {
+		return structs.ErrPermissionDenied
+	}
 
 	// Create the evaluation
 	evalIDs, evalIndex, err := n.createNodeEvals(node, node.ModifyIndex)
@@ -840,8 +859,10 @@ func (n *Node) GetNode(args *structs.NodeSpecificRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "client", "get_node"}, time.Now())
 
-	// Check node read permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check node read permissions, the node rules are checked once the node
+	// is found
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		// If ResolveToken had an unexpected error return that
 		if err != structs.ErrTokenNotFound {
 			return err
@@ -861,7 +882,7 @@ func (n *Node) GetNode(args *structs.NodeSpecificRequest,
 		if node == nil {
 			return structs.ErrTokenNotFound
 		}
-	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeRead() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -883,6 +904,9 @@ func (n *Node) GetNode(args *structs.NodeSpecificRequest,
 
 			// Setup the output
 			if out != nil {
+				if aclObj != nil && !aclObj.AllowNodeReadFor(out.ACLAttributes()) {
+					return structs.ErrPermissionDenied
+				}
 				out = out.Sanitize()
 				reply.Node = out
 				reply.Index = out.ModifyIndex
@@ -916,7 +940,7 @@ func (n *Node) GetAllocs(args *structs.NodeSpecificRequest,
 	if err != nil {
 		return err
 	}
-	if aclObj != nil && !aclObj.AllowNodeRead() {
+	if aclObj != nil && !aclObj.AllowAnyNodeRead() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -946,6 +970,19 @@ func (n *Node) GetAllocs(args *structs.NodeSpecificRequest,
 		return fmt.Errorf("missing node ID")
 	}
 
+	// Check the node rules against the node, tokens limited to node rules
+	// can't read the allocations of unknown nodes
+	if aclObj != nil {
+		node, err := n.srv.fsm.State().NodeByID(nil, args.NodeID)
+		if err != nil {
+			return err
+		}
+		if node == nil && !aclObj.AllowNodeRead() ||
+			node != nil && !aclObj.AllowNodeReadFor(node.ACLAttributes()) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
 	// Setup the blocking query
 	opts := blockingOptions{
 		queryOpts: &args.QueryOptions,
@@ -1383,10 +1420,11 @@ func (n *Node) List(args *structs.NodeListRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "client", "list"}, time.Now())
 
-	// Check node read permissions
-	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check node read permissions, the nodes are filtered by the node rules
+	aclObj, err := n.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+	} else if aclObj != nil && !aclObj.AllowAnyNodeRead() {
 		return structs.ErrPermissionDenied
 	}
 
@@ -1414,9 +1452,19 @@ func (n *Node) List(args *structs.NodeListRequest,
 
 			var nodes []*structs.NodeListStub
 
+			// Only list the nodes allowed by the node rules
+			filters := []paginator.Filter{
+				paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						node := raw.(*structs.Node)
+						return aclObj == nil || aclObj.AllowNodeReadFor(node.ACLAttributes()), nil
+					},
+				},
+			}
+
 			// Build the paginator. This includes the function that is
 			// responsible for appending a node to the nodes array.
-			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
+			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
 				func(raw interface{}) error {
 					nodes = append(nodes, raw.(*structs.Node).Stub(args.Fields))
 					return nil
@@ -1537,6 +1537,65 @@ func TestClientEndpoint_UpdateEligibility_ACL(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_UpdateEligibility_ACL_NodeRules(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a GPU node and a web node
+	gpuNode := mock.Node()
+	gpuNode.NodeClass = "gpu-a100"
+	webNode := mock.Node()
+	webNode.NodeClass = "web"
+	webNode.Meta["team"] = "web"
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1, gpuNode))
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 2, webNode))
+
+	// Create tokens allowed to write the GPU node only, either by class or
+	// by excluding the nodes of the web team
+	classToken := mock.CreatePolicyAndToken(t, state, 1001, "test-class",
+		`node "class:gpu-*" { policy = "write" }`)
+	metaToken := mock.CreatePolicyAndToken(t, state, 1003, "test-meta",
+		mock.NodePolicy(acl.PolicyWrite)+`node "meta.team:web" { policy = "deny" }`)
+
+	for _, token := range []*structs.ACLToken{classToken, metaToken} {
+		req := &structs.NodeUpdateEligibilityRequest{
+			NodeID:      gpuNode.ID,
+			Eligibility: structs.NodeSchedulingIneligible,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: token.SecretID,
+			},
+		}
+		var resp structs.NodeEligibilityUpdateResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateEligibility", req, &resp))
+
+		req.NodeID = webNode.ID
+		err := msgpackrpc.CallWithCodec(codec, "Node.UpdateEligibility", req, &resp)
+		require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+	}
+
+	// Tokens without node rules granting write are rejected before the node
+	// is looked up
+	readToken := mock.CreatePolicyAndToken(t, state, 1005, "test-read",
+		`node "class:gpu-*" { policy = "read" }`)
+	req := &structs.NodeUpdateEligibilityRequest{
+		NodeID:      uuid.Generate(),
+		Eligibility: structs.NodeSchedulingIneligible,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var resp structs.NodeEligibilityUpdateResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.UpdateEligibility", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+}
+
 func TestClientEndpoint_GetNode(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3181,6 +3240,54 @@ func TestClientEndpoint_ListNodes_ACL(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_ListNodes_ACL_NodeRules(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a node in each datacenter
+	node1 := mock.Node()
+	node2 := mock.Node()
+	node2.Datacenter = "dc2"
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1, node1))
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 2, node2))
+
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-dc",
+		`node "datacenter:dc2" { policy = "read" }`)
+
+	// Only the node matching the node rule is listed
+	req := &structs.NodeListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.NodeListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.List", req, &resp))
+	require.Len(t, resp.Nodes, 1)
+	require.Equal(t, node2.ID, resp.Nodes[0].ID)
+
+	// Reading the other node is denied
+	get := &structs.NodeSpecificRequest{
+		NodeID: node1.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var getResp structs.SingleNodeResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.GetNode", get, &getResp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	get.NodeID = node2.ID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.GetNode", get, &getResp))
+	require.Equal(t, node2.ID, getResp.Node.ID)
+}
+
 func TestClientEndpoint_ListNodes_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
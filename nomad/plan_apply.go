@@ -276,7 +276,7 @@ func (p *planner) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap
 		// to approximate the scheduling time.
 		updateAllocTimestamps(req.AllocsUpdated, now)
 
-		err := p.signAllocIdentities(plan.Job, req.AllocsUpdated)
+		err := p.signAllocIdentities(plan.Job, req.AllocsUpdated, snap)
 		if err != nil {
 			return nil, err
 		}
@@ -369,6 +369,7 @@ func normalizePreemptedAlloc(preemptedAlloc *structs.Allocation, now int64) *str
 	return &structs.AllocationDiff{
 		ID:                    preemptedAlloc.ID,
 		PreemptedByAllocation: preemptedAlloc.PreemptedByAllocation,
+		PreemptedTime:         now,
 		ModifyTime:            now,
 	}
 }
@@ -406,10 +407,25 @@ func updateAllocTimestamps(allocations []*structs.Allocation, timestamp int64) {
 	}
 }
 
-func (p *planner) signAllocIdentities(job *structs.Job, allocations []*structs.Allocation) error {
+func (p *planner) signAllocIdentities(job *structs.Job, allocations []*structs.Allocation, snap *state.StateSnapshot) error {
 
 	encrypter := p.Server.encrypter
 
+	// nodes are only needed to interpolate the extra claims of named
+	// identities, so look each of them up at most once per plan
+	nodes := map[string]*structs.Node{}
+	nodeByID := func(nodeID string) (*structs.Node, error) {
+		if node, ok := nodes[nodeID]; ok {
+			return node, nil
+		}
+		node, err := snap.NodeByID(nil, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		nodes[nodeID] = node
+		return node, nil
+	}
+
 	for _, alloc := range allocations {
 		alloc.SignedIdentities = map[string]string{}
 		tg := job.LookupTaskGroup(alloc.TaskGroup)
@@ -421,6 +437,19 @@ func (p *planner) signAllocIdentities(job *structs.Job, allocations []*structs.A
 			}
 			alloc.SignedIdentities[task.Name] = token
 			alloc.SigningKeyID = keyID
+
+			for _, wi := range task.Identities {
+				node, err := nodeByID(alloc.NodeID)
+				if err != nil {
+					return err
+				}
+				claims := alloc.ToWorkloadIdentityClaims(job, node, task.Name, wi)
+				token, _, err := encrypter.SignClaims(claims)
+				if err != nil {
+					return err
+				}
+				alloc.SignedIdentities[structs.WorkloadIdentityKey(task.Name, wi.Name)] = token
+			}
 		}
 	}
 	return nil
@@ -656,6 +685,10 @@ OUTER:
 			mErr.Errors = append(mErr.Errors, err)
 		}
 
+		// Gang task groups must be placed as a whole, so reject their
+		// placements on the nodes that did fit as well
+		rejectPartialGangs(plan, result, rejectedNodes)
+
 		// If there was a partial commit and we are operating within a
 		// deployment correct for any canary that may have been desired to be
 		// placed but wasn't actually placed
@@ -668,6 +701,65 @@ OUTER:
 	return result, mErr.ErrorOrNil()
 }
 
+// rejectPartialGangs removes from the result the allocations of gang task
+// groups that had an allocation on a rejected node, along with the
+// preemptions they require, so that gang task groups are never partially
+// placed. The stops of the allocations they replace are kept, as for any
+// partial commit, since the plan doesn't tell the stops made room for the
+// placements apart from those the scheduler must commit regardless, such as
+// the stops of lost allocations. The scheduler retries the placements against
+// the refreshed state.
+func rejectPartialGangs(plan *structs.Plan, result *structs.PlanResult, rejectedNodes map[string]struct{}) {
+	// Hot path
+	if plan.Job == nil {
+		return
+	}
+
+	// Find the gang task groups with allocations on rejected nodes
+	gangs := make(map[string]struct{})
+	for nodeID := range rejectedNodes {
+		for _, alloc := range plan.NodeAllocation[nodeID] {
+			if tg := plan.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil && tg.Gang {
+				gangs[alloc.TaskGroup] = struct{}{}
+			}
+		}
+	}
+	if len(gangs) == 0 {
+		return
+	}
+
+	rejected := make(map[string]struct{})
+	for nodeID, allocs := range result.NodeAllocation {
+		kept := make([]*structs.Allocation, 0, len(allocs))
+		for _, alloc := range allocs {
+			if _, ok := gangs[alloc.TaskGroup]; !ok {
+				kept = append(kept, alloc)
+				continue
+			}
+			rejected[alloc.ID] = struct{}{}
+		}
+		if len(kept) > 0 {
+			result.NodeAllocation[nodeID] = kept
+		} else {
+			delete(result.NodeAllocation, nodeID)
+		}
+	}
+
+	for nodeID, allocs := range result.NodePreemptions {
+		kept := make([]*structs.Allocation, 0, len(allocs))
+		for _, alloc := range allocs {
+			if _, ok := rejected[alloc.PreemptedByAllocation]; !ok {
+				kept = append(kept, alloc)
+			}
+		}
+		if len(kept) > 0 {
+			result.NodePreemptions[nodeID] = kept
+		} else {
+			delete(result.NodePreemptions, nodeID)
+		}
+	}
+}
+
 // correctDeploymentCanaries ensures that the deployment object doesn't list any
 // canaries as placed if they didn't actually get placed. This could happen if
 // the plan had a partial commit.
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	jwt "github.com/golang-jwt/jwt/v4"
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -237,6 +238,50 @@ func TestPlanApply_applyPlan(t *testing.T) {
 	assert.Equal(index, evalOut.ModifyIndex)
 }
 
+func TestPlanApply_signAllocIdentities(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForKeyring(t, s1.RPC, "global")
+
+	node := mock.Node()
+	node.Datacenter = "dc2"
+	require.NoError(t, s1.State().UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	job := alloc.Job
+	job.TaskGroups[0].Tasks[0].Identities = []*structs.WorkloadIdentity{{
+		Name:     "consul",
+		Audience: []string{"consul.io"},
+		Claims:   map[string]string{"dc": "${node.datacenter}"},
+	}}
+
+	snap, err := s1.State().Snapshot()
+	require.NoError(t, err)
+	allocs := []*structs.Allocation{alloc}
+	require.NoError(t, s1.signAllocIdentities(job, allocs, snap))
+
+	// the default identity and the named identity are both signed
+	require.Len(t, alloc.SignedIdentities, 2)
+	_, err = s1.encrypter.VerifyClaim(alloc.SignedIdentities["web"])
+	require.NoError(t, err)
+
+	token := alloc.SignedIdentities[structs.WorkloadIdentityKey("web", "consul")]
+	claims, err := s1.encrypter.VerifyClaim(token)
+	require.NoError(t, err)
+	require.Equal(t, "consul", claims.IdentityName)
+	require.Equal(t, []string{"consul.io"}, []string(claims.Audience))
+	require.Nil(t, claims.ExpiresAt)
+
+	// extra claims are interpolated with the node
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	require.Equal(t, "dc2", parsed.Claims.(jwt.MapClaims)["dc"])
+}
+
 // Verifies that applyPlan properly updates the constituent objects in MemDB,
 // when the plan contains normalized allocs.
 func TestPlanApply_applyPlanWithNormalizedAllocs(t *testing.T) {
@@ -367,6 +412,7 @@ func TestPlanApply_applyPlanWithNormalizedAllocs(t *testing.T) {
 	require.NoError(err)
 	require.NotNil(updatedPreemptedAlloc)
 	assert.True(updatedPreemptedAlloc.ModifyTime > timestampBeforeCommit)
+	assert.Equal(updatedPreemptedAlloc.ModifyTime, updatedPreemptedAlloc.PreemptedTime)
 	assert.Equal(updatedPreemptedAlloc.DesiredDescription,
 		"Preempted by alloc ID "+preemptedAllocDiff.PreemptedByAllocation)
 	assert.Equal(updatedPreemptedAlloc.DesiredStatus, structs.AllocDesiredStatusEvict)
@@ -657,6 +703,93 @@ func TestPlanApply_EvalPlan_Partial_AllAtOnce(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalPlan_Partial_Gang(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1000, node)
+	node2 := mock.Node()
+	state.UpsertNode(structs.MsgTypeTestSetup, 1001, node2)
+
+	// Create a job with a gang task group and a regular one
+	job := mock.Job()
+	job.TaskGroups[0].Gang = true
+	cache := job.TaskGroups[0].Copy()
+	cache.Name = "cache"
+	cache.Gang = false
+	job.TaskGroups = append(job.TaskGroups, cache)
+
+	// Create a down node with a lost alloc replaced by the gang
+	downNode := mock.Node()
+	downNode.Status = structs.NodeStatusDown
+	state.UpsertNode(structs.MsgTypeTestSetup, 1002, downNode)
+	lost := mock.Alloc()
+	lost.NodeID = downNode.ID
+
+	// Create an existing alloc to be preempted by the gang
+	existing := mock.Alloc()
+	existing.NodeID = node.ID
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{existing, lost}))
+	snap, _ := state.Snapshot()
+
+	lostStop := lost.Copy()
+	lostStop.DesiredStatus = structs.AllocDesiredStatusStop
+	lostStop.ClientStatus = structs.AllocClientStatusLost
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.PreviousAllocation = lost.ID
+	alloc2 := mock.Alloc() // Ensure alloc2 does not fit
+	alloc2.Job = job
+	alloc2.JobID = job.ID
+	alloc2.AllocatedResources = structs.NodeResourcesToAllocatedResources(node2.NodeResources)
+	alloc3 := mock.Alloc()
+	alloc3.Job = job
+	alloc3.JobID = job.ID
+	alloc3.TaskGroup = "cache"
+	alloc3.AllocatedResources.Tasks["web"].Networks = nil
+
+	plan := &structs.Plan{
+		Job: job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID:  {alloc, alloc3},
+			node2.ID: {alloc2},
+		},
+		NodeUpdate: map[string][]*structs.Allocation{
+			downNode.ID: {lostStop},
+		},
+		NodePreemptions: map[string][]*structs.Allocation{
+			node.ID: {
+				{
+					ID:                    existing.ID,
+					Namespace:             existing.Namespace,
+					JobID:                 existing.JobID,
+					DesiredStatus:         structs.AllocDesiredStatusEvict,
+					PreemptedByAllocation: alloc.ID,
+					AllocatedResources:    existing.AllocatedResources,
+				},
+			},
+		},
+	}
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// Only the allocation of the regular task group is placed, and the
+	// preemption for the gang is dropped, but the lost alloc it replaces is
+	// still stopped
+	require.Equal(t, []*structs.Allocation{alloc3}, result.NodeAllocation[node.ID])
+	require.NotContains(t, result.NodeAllocation, node2.ID)
+	require.Empty(t, result.NodePreemptions)
+	require.Equal(t, []*structs.Allocation{lostStop}, result.NodeUpdate[downNode.ID])
+	require.Equal(t, uint64(1003), result.RefreshIndex)
+}
+
 func TestPlanApply_EvalNodePlan_Simple(t *testing.T) {
 	ci.Parallel(t)
 	state := testStateStore(t)
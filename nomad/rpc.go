@@ -550,6 +550,7 @@ func (r *rpcHandler) forward(method string, info structs.RPCInfo, args interface
 
 	// Check if we can allow a stale read
 	if info.IsRead() && info.AllowStaleRead() {
+		r.recordACLTokenUsage(info)
 		return false, nil
 	}
 
@@ -560,6 +561,7 @@ func (r *rpcHandler) forward(method string, info structs.RPCInfo, args interface
 
 	// we are the leader
 	if remoteServer == nil {
+		r.recordACLTokenUsage(info)
 		return false, nil
 	}
 
@@ -256,6 +256,10 @@ type Server struct {
 	// aclCache is used to maintain the parsed ACL objects
 	aclCache *lru.TwoQueueCache
 
+	// aclTokenUsage batches the usage of the ACL tokens used to make the
+	// requests handled by this server until it is flushed to Raft
+	aclTokenUsage *aclTokenUsageTracker
+
 	// leaderAcl is the management ACL token that is valid when resolved by the
 	// current leader.
 	leaderAcl     string
@@ -265,6 +269,10 @@ type Server struct {
 	// a cluster ID, racing against itself in calls of ClusterID
 	clusterIDLock sync.Mutex
 
+	// dispatchLocks serializes the dispatches of a job with the same
+	// idempotency token, so concurrent retries can't each dispatch a job
+	dispatchLocks dispatchLocks
+
 	// statsFetcher is used by autopilot to check the status of the other
 	// Nomad router.
 	statsFetcher *StatsFetcher
@@ -302,6 +310,7 @@ type endpoints struct {
 	Variables           *Variables
 	Keyring             *Keyring
 	ServiceRegistration *ServiceRegistration
+	JobTemplate         *JobTemplate
 
 	// Client endpoints
 	ClientStats       *ClientStats
@@ -365,6 +374,7 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 		blockedEvals:            NewBlockedEvals(evalBroker, logger),
 		rpcTLS:                  incomingTLS,
 		aclCache:                aclCache,
+		aclTokenUsage:           newACLTokenUsageTracker(),
 		workersEventCh:          make(chan interface{}, 1),
 	}
 
@@ -503,6 +513,11 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	// Emit raft and state store metrics
 	go s.EmitRaftStats(10*time.Second, s.shutdownCh)
 
+	// Periodically write the usage of ACL tokens to Raft
+	if s.config.ACLEnabled {
+		go s.flushACLTokenUsage(s.shutdownCh)
+	}
+
 	// Start enterprise background workers
 	s.startEnterpriseBackground()
 
@@ -1215,6 +1230,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) error {
 		s.staticEndpoints.Namespace = &Namespace{srv: s}
 		s.staticEndpoints.Variables = &Variables{srv: s, logger: s.logger.Named("variables"), encrypter: s.encrypter}
 		s.staticEndpoints.Keyring = &Keyring{srv: s, logger: s.logger.Named("keyring"), encrypter: s.encrypter}
+		s.staticEndpoints.JobTemplate = &JobTemplate{srv: s, logger: s.logger.Named("job_template")}
 
 		s.staticEndpoints.Enterprise = NewEnterpriseEndpoints(s)
 
@@ -1245,7 +1261,6 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) error {
 	}
 
 	// Register the static handlers
-	server.Register(s.staticEndpoints.ACL)
 	server.Register(s.staticEndpoints.Job)
 	server.Register(s.staticEndpoints.CSIVolume)
 	server.Register(s.staticEndpoints.CSIPlugin)
@@ -1264,8 +1279,10 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) error {
 	server.Register(s.staticEndpoints.Agent)
 	server.Register(s.staticEndpoints.Namespace)
 	server.Register(s.staticEndpoints.Variables)
+	server.Register(s.staticEndpoints.JobTemplate)
 
 	// Create new dynamic endpoints and add them to the RPC server.
+	aclReg := &ACL{srv: s, ctx: ctx, logger: s.logger.Named("acl")}
 	alloc := &Alloc{srv: s, ctx: ctx, logger: s.logger.Named("alloc")}
 	deployment := &Deployment{srv: s, ctx: ctx, logger: s.logger.Named("deployment")}
 	eval := &Eval{srv: s, ctx: ctx, logger: s.logger.Named("eval")}
@@ -1275,6 +1292,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) error {
 	keyringReg := &Keyring{srv: s, ctx: ctx, logger: s.logger.Named("keyring"), encrypter: s.encrypter}
 
 	// Register the dynamic endpoints
+	server.Register(aclReg)
 	server.Register(alloc)
 	server.Register(deployment)
 	server.Register(eval)
@@ -508,7 +508,9 @@ func (s *ServiceRegistration) handleMixedAuthEndpoint(args structs.QueryOptions,
 	case nil:
 		// Perform our ACL validation. If the object is nil, this means ACLs
 		// are not enabled, otherwise trigger the allowed namespace function.
-		if aclObj != nil {
+		// Workload identities can always read services, whether or not
+		// the policies attached to their job allow it.
+		if aclObj != nil && !isWorkloadIdentity(args.AuthToken) {
 			if !aclObj.AllowNsOp(args.RequestNamespace(), cap) {
 				return structs.ErrPermissionDenied
 			}
@@ -883,7 +883,9 @@ func TestServiceRegistration_List(t *testing.T) {
 				allocs := []*structs.Allocation{mock.Alloc()}
 				job := allocs[0].Job
 				require.NoError(t, s.State().UpsertJob(structs.MsgTypeTestSetup, 10, job))
-				s.signAllocIdentities(job, allocs)
+				snap, err := s.State().Snapshot()
+				require.NoError(t, err)
+				s.signAllocIdentities(job, allocs, snap)
 				require.NoError(t, s.State().UpsertAllocs(structs.MsgTypeTestSetup, 15, allocs))
 
 				signedToken := allocs[0].SignedIdentities["web"]
@@ -902,7 +904,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					},
 				}
 				var serviceRegResp structs.ServiceRegistrationListResponse
-				err := msgpackrpc.CallWithCodec(
+				err = msgpackrpc.CallWithCodec(
 					codec, structs.ServiceRegistrationListRPCMethod,
 					serviceRegReq, &serviceRegResp)
 				require.NoError(t, err)
@@ -1156,7 +1158,9 @@ func TestServiceRegistration_GetService(t *testing.T) {
 				allocs := []*structs.Allocation{mock.Alloc()}
 				job := allocs[0].Job
 				require.NoError(t, s.State().UpsertJob(structs.MsgTypeTestSetup, 10, job))
-				s.signAllocIdentities(job, allocs)
+				snap, err := s.State().Snapshot()
+				require.NoError(t, err)
+				s.signAllocIdentities(job, allocs, snap)
 				require.NoError(t, s.State().UpsertAllocs(structs.MsgTypeTestSetup, 15, allocs))
 
 				signedToken := allocs[0].SignedIdentities["web"]
@@ -1171,7 +1175,7 @@ func TestServiceRegistration_GetService(t *testing.T) {
 					},
 				}
 				var serviceRegResp structs.ServiceRegistrationByNameResponse
-				err := msgpackrpc.CallWithCodec(codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &serviceRegResp)
+				err = msgpackrpc.CallWithCodec(codec, structs.ServiceRegistrationGetServiceRPCMethod, serviceRegReq, &serviceRegResp)
 				require.NoError(t, err)
 				require.Equal(t, uint64(10), serviceRegResp.Services[0].CreateIndex)
 				require.Equal(t, uint64(20), serviceRegResp.Index)
@@ -13,12 +13,18 @@ const (
 	tableIndex = "index"
 
 	TableNamespaces           = "namespaces"
+	TableNamespaceUsage       = "namespace_usage"
 	TableServiceRegistrations = "service_registrations"
 	TableVariables            = "variables"
 	TableVariablesQuotas      = "variables_quota"
+	TableVariablesVersions    = "variables_versions"
 	TableRootKeyMeta          = "root_key_meta"
 	TableACLRoles             = "acl_roles"
+	TableACLTokenUsage        = "acl_token_usage"
 	TableAllocs               = "allocs"
+	TableJobSubmission        = "job_submission"
+	TableJobTemplates         = "job_templates"
+	TableDispatchIdempotency  = "dispatch_idempotency"
 )
 
 const (
@@ -63,6 +69,9 @@ func init() {
 		jobTableSchema,
 		jobSummarySchema,
 		jobVersionSchema,
+		jobSubmissionSchema,
+		jobTemplateSchema,
+		dispatchIdempotencySchema,
 		deploymentSchema,
 		periodicLaunchTableSchema,
 		evalTableSchema,
@@ -71,6 +80,7 @@ func init() {
 		siTokenAccessorTableSchema,
 		aclPolicyTableSchema,
 		aclTokenTableSchema,
+		aclTokenUsageTableSchema,
 		oneTimeTokenTableSchema,
 		autopilotConfigTableSchema,
 		schedulerConfigTableSchema,
@@ -80,9 +90,11 @@ func init() {
 		scalingPolicyTableSchema,
 		scalingEventTableSchema,
 		namespaceTableSchema,
+		namespaceUsageTableSchema,
 		serviceRegistrationsTableSchema,
 		variablesTableSchema,
 		variablesQuotasTableSchema,
+		variablesVersionsTableSchema,
 		variablesRootKeyMetaSchema,
 		aclRolesTableSchema,
 	}...)
@@ -272,6 +284,104 @@ func jobVersionSchema() *memdb.TableSchema {
 	}
 }
 
+// jobSubmissionSchema returns the MemDB schema for the job submission table.
+// This table is used to store the jobspec each version of a job was parsed
+// from.
+func jobSubmissionSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobSubmission,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, JobID,
+				// Version) is uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field:     "JobID",
+							Lowercase: true,
+						},
+
+						&memdb.UintFieldIndex{
+							Field: "Version",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// jobTemplateSchema returns the MemDB schema for the job templates table.
+// This table is used to store the jobspecs which can be run with the values
+// of their input variables.
+func jobTemplateSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobTemplates,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, Name) is
+				// uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "Name",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dispatchIdempotencySchema returns the MemDB schema for the dispatch
+// idempotency records table.
+func dispatchIdempotencySchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableDispatchIdempotency,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, ParentID,
+				// Token) is uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "ParentID",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "Token",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 // jobIsGCable satisfies the ConditionalIndexFunc interface and creates an index
 // on whether a job is eligible for garbage collection.
 func jobIsGCable(obj interface{}) (bool, error) {
@@ -984,6 +1094,24 @@ func indexExpiresFromACLToken(raw interface{}, global bool) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// aclTokenUsageTableSchema returns the MemDB schema for the token usage
+// table. This table is used to store when ACL tokens were last used.
+func aclTokenUsageTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableACLTokenUsage,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "AccessorID",
+				},
+			},
+		},
+	}
+}
+
 // oneTimeTokenTableSchema returns the MemDB schema for the tokens table.
 // This table is used to store one-time tokens for ACL tokens
 func oneTimeTokenTableSchema() *memdb.TableSchema {
@@ -1290,6 +1418,26 @@ func namespaceTableSchema() *memdb.TableSchema {
 	}
 }
 
+// namespaceUsageTableSchema returns the MemDB schema for the usage of the
+// namespaces. The usage is updated along with the allocations of the
+// namespace, so the table isn't persisted in snapshots but rebuilt when the
+// allocations are restored.
+func namespaceUsageTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableNamespaceUsage,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Namespace",
+				},
+			},
+		},
+	}
+}
+
 // serviceRegistrationsTableSchema returns the MemDB schema for Nomad native
 // service registrations.
 func serviceRegistrationsTableSchema() *memdb.TableSchema {
@@ -1441,12 +1589,16 @@ func (s *variableKeyIDFieldIndexer) PrefixFromArgs(args ...interface{}) ([]byte,
 // an index value from an object or to indicate that the index value
 // is missing.
 func (s *variableKeyIDFieldIndexer) FromObject(obj interface{}) (bool, []byte, error) {
-	variable, ok := obj.(*structs.VariableEncrypted)
-	if !ok {
+	var keyID string
+	switch variable := obj.(type) {
+	case *structs.VariableEncrypted:
+		keyID = variable.KeyID
+	case *structs.VariableVersion:
+		keyID = variable.KeyID
+	default:
 		return false, nil, fmt.Errorf("object %#v is not a Variable", obj)
 	}
 
-	keyID := variable.KeyID
 	if keyID == "" {
 		return false, nil, nil
 	}
@@ -1475,6 +1627,39 @@ func variablesQuotasTableSchema() *memdb.TableSchema {
 	}
 }
 
+// variablesVersionsTableSchema returns the MemDB schema for the previous
+// versions of Nomad variables.
+func variablesVersionsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableVariablesVersions,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+						&memdb.UintFieldIndex{
+							Field: "Version",
+						},
+					},
+				},
+			},
+			indexKeyID: {
+				Name:         indexKeyID,
+				AllowMissing: false,
+				Indexer:      &variableKeyIDFieldIndexer{},
+			},
+		},
+	}
+}
+
 // variablesRootKeyMetaSchema returns the MemDB schema for Nomad root keys
 func variablesRootKeyMetaSchema() *memdb.TableSchema {
 	return &memdb.TableSchema{
@@ -1678,6 +1678,12 @@ func (s *StateStore) upsertJobImpl(index uint64, job *structs.Job, keepVersion b
 		if updated != nil {
 			job = updated.(*structs.Job)
 		}
+
+		if job.Dispatched && job.DispatchIdempotencyToken != "" {
+			if err := s.upsertDispatchIdempotencyTxn(index, job, txn); err != nil {
+				return err
+			}
+		}
 	}
 
 	if err := s.updateSummaryWithJob(index, job, txn); err != nil {
@@ -1898,7 +1904,7 @@ func (s *StateStore) deleteJobVersions(index uint64, job *structs.Job, txn *txn)
 		return fmt.Errorf("index update failed: %v", err)
 	}
 
-	return nil
+	return s.deleteJobSubmissions(index, job.Namespace, job.ID, txn)
 }
 
 // upsertJobVersion inserts a job into its historic version table and limits the
@@ -1947,7 +1953,7 @@ func (s *StateStore) upsertJobVersion(index uint64, job *structs.Job, txn *txn)
 		return fmt.Errorf("failed to delete job %v (%d) from job_version", d.ID, d.Version)
 	}
 
-	return nil
+	return s.deleteJobSubmission(index, d.Namespace, d.ID, d.Version, txn)
 }
 
 // JobByID is used to lookup a job by its ID. JobByID returns the current/latest job
@@ -3284,6 +3290,9 @@ func (s *StateStore) DeleteEval(index uint64, evals, allocs []string, userInitia
 		if err := txn.Delete("allocs", raw); err != nil {
 			return fmt.Errorf("alloc delete failed: %v", err)
 		}
+		if err := updateNamespaceUsageWithAlloc(txn, nil, raw.(*structs.Allocation)); err != nil {
+			return err
+		}
 
 		// Mark that we have made a successful modification to the allocs
 		// table.
@@ -3598,6 +3607,10 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *txn, index uint64, alloc *
 		return err
 	}
 
+	if err := updateNamespaceUsageWithAlloc(txn, copyAlloc, exist); err != nil {
+		return err
+	}
+
 	if err := s.updatePluginForTerminalAlloc(index, copyAlloc, txn); err != nil {
 		return err
 	}
@@ -3708,6 +3721,10 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			return err
 		}
 
+		if err := updateNamespaceUsageWithAlloc(txn, alloc, exist); err != nil {
+			return err
+		}
+
 		if err := s.updatePluginForTerminalAlloc(index, alloc, txn); err != nil {
 			return err
 		}
@@ -5491,6 +5508,54 @@ func (s *StateStore) updateSummaryWithAlloc(index uint64, alloc *structs.Allocat
 	return nil
 }
 
+// updateNamespaceUsageWithAlloc updates the usage of the namespace of an
+// allocation when the allocation is inserted, updated or, if alloc is nil,
+// deleted. Only non-terminal allocations count towards the usage.
+func updateNamespaceUsageWithAlloc(txn *txn, alloc, existing *structs.Allocation) error {
+	delta := &structs.NamespaceUsage{}
+	if existing != nil && !existing.TerminalStatus() {
+		delta.Namespace = existing.Namespace
+		delta.Subtract(existing)
+	}
+	if alloc != nil && !alloc.TerminalStatus() {
+		delta.Namespace = alloc.Namespace
+		delta.Add(alloc)
+	}
+	if delta.CPU == 0 && delta.MemoryMB == 0 && delta.Allocs == 0 {
+		return nil
+	}
+
+	raw, err := txn.First(TableNamespaceUsage, indexID, delta.Namespace)
+	if err != nil {
+		return fmt.Errorf("namespace usage lookup failed: %v", err)
+	}
+
+	usage := &structs.NamespaceUsage{Namespace: delta.Namespace}
+	if raw != nil {
+		usage = raw.(*structs.NamespaceUsage).Copy()
+	}
+	usage.CPU += delta.CPU
+	usage.MemoryMB += delta.MemoryMB
+	usage.Allocs += delta.Allocs
+
+	// Don't keep the usage of namespaces without allocations around, so that
+	// it doesn't outlive the namespace
+	if usage.Allocs <= 0 {
+		if raw == nil {
+			return nil
+		}
+		if err := txn.Delete(TableNamespaceUsage, raw); err != nil {
+			return fmt.Errorf("namespace usage delete failed: %v", err)
+		}
+		return nil
+	}
+
+	if err := txn.Insert(TableNamespaceUsage, usage); err != nil {
+		return fmt.Errorf("namespace usage insert failed: %v", err)
+	}
+	return nil
+}
+
 // updatePluginForTerminalAlloc updates the CSI plugins for an alloc when the
 // allocation is updated or inserted with a terminal server status.
 func (s *StateStore) updatePluginForTerminalAlloc(index uint64, alloc *structs.Allocation,
@@ -5729,15 +5794,26 @@ func (s *StateStore) DeleteACLTokens(msgType structs.MessageType, index uint64,
 	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
-	// Delete the tokens
+	// Delete the tokens and their usage
+	var usageDeleted bool
 	for _, id := range ids {
 		if _, err := txn.DeleteAll("acl_token", "id", id); err != nil {
 			return fmt.Errorf("deleting acl token failed: %v", err)
 		}
+		num, err := txn.DeleteAll(TableACLTokenUsage, indexID, id)
+		if err != nil {
+			return fmt.Errorf("deleting acl token usage failed: %v", err)
+		}
+		usageDeleted = usageDeleted || num > 0
 	}
 	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
+	if usageDeleted {
+		if err := txn.Insert(tableIndex, &IndexEntry{TableACLTokenUsage, index}); err != nil {
+			return fmt.Errorf("index update failed: %v", err)
+		}
+	}
 	return txn.Commit()
 }
 
@@ -6291,6 +6367,23 @@ func (s *StateStore) namespaceByNameImpl(ws memdb.WatchSet, txn *txn, name strin
 	return nil, nil
 }
 
+// NamespaceUsage returns the resources used by the non-terminal allocations
+// of the namespace.
+func (s *StateStore) NamespaceUsage(ws memdb.WatchSet, namespace string) (*structs.NamespaceUsage, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableNamespaceUsage, indexID, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("namespace usage lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return &structs.NamespaceUsage{Namespace: namespace}, nil
+	}
+	return existing.(*structs.NamespaceUsage).Copy(), nil
+}
+
 // namespaceExists returns whether a namespace exists
 func (s *StateStore) namespaceExists(txn *txn, namespace string) (bool, error) {
 	if namespace == structs.DefaultNamespace {
@@ -6771,6 +6864,12 @@ func (s *StateSnapshot) DenormalizeAllocationDiffSlice(allocDiffs []*structs.All
 
 		if allocDiff.PreemptedByAllocation != "" {
 			allocCopy.PreemptedByAllocation = allocDiff.PreemptedByAllocation
+			allocCopy.PreemptedTime = allocDiff.PreemptedTime
+			if allocCopy.PreemptedTime == 0 {
+				// COMPAT: plans from older servers don't set the preemption
+				// time, which is when the plan is applied
+				allocCopy.PreemptedTime = allocDiff.ModifyTime
+			}
 			allocCopy.DesiredDescription = getPreemptedAllocDesiredDescription(allocDiff.PreemptedByAllocation)
 			allocCopy.DesiredStatus = structs.AllocDesiredStatusEvict
 		} else {
@@ -6961,7 +7060,7 @@ func (s *StateStore) GetActiveRootKeyMeta(ws memdb.WatchSet) (*structs.RootKeyMe
 }
 
 // IsRootKeyMetaInUse determines whether a key has been used to sign a workload
-// identity for a live allocation or encrypt any variables
+// identity for a live allocation or encrypt any variables or their versions
 func (s *StateStore) IsRootKeyMetaInUse(keyID string) (bool, error) {
 	txn := s.db.ReadTxn()
 
@@ -6983,5 +7082,14 @@ func (s *StateStore) IsRootKeyMetaInUse(keyID string) (bool, error) {
 		return true, nil
 	}
 
+	iter, err = txn.Get(TableVariablesVersions, indexKeyID, keyID)
+	if err != nil {
+		return false, err
+	}
+	version := iter.Next()
+	if version != nil {
+		return true, nil
+	}
+
 	return false, nil
 }
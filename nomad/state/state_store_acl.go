@@ -35,6 +35,89 @@ func expiresIndexName(global bool) string {
 	return indexExpiresLocal
 }
 
+// UpsertACLTokenUsage is used to record when a number of ACL tokens were last
+// used. The usage of tokens that no longer exist is discarded, as is usage
+// older than the one already recorded, since servers flush their usage
+// independently.
+func (s *StateStore) UpsertACLTokenUsage(
+	msgType structs.MessageType, index uint64, usages []*structs.ACLTokenUsage) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	var updated bool
+
+	for _, usage := range usages {
+		token, err := txn.First("acl_token", indexID, usage.AccessorID)
+		if err != nil {
+			return fmt.Errorf("acl token lookup failed: %v", err)
+		}
+		if token == nil {
+			continue
+		}
+
+		existingRaw, err := txn.First(TableACLTokenUsage, indexID, usage.AccessorID)
+		if err != nil {
+			return fmt.Errorf("acl token usage lookup failed: %v", err)
+		}
+
+		newUsage := *usage
+		if existingRaw != nil {
+			existing := existingRaw.(*structs.ACLTokenUsage)
+			if !usage.LastUsedTime.After(existing.LastUsedTime) {
+				continue
+			}
+			newUsage.CreateIndex = existing.CreateIndex
+		} else {
+			newUsage.CreateIndex = index
+		}
+		newUsage.ModifyIndex = index
+
+		if err := txn.Insert(TableACLTokenUsage, &newUsage); err != nil {
+			return fmt.Errorf("acl token usage insert failed: %v", err)
+		}
+		updated = true
+	}
+
+	if !updated {
+		return nil
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableACLTokenUsage, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// ACLTokenUsageByAccessorID returns when the ACL token with the accessor ID
+// was last used, or nil if its usage was never recorded.
+func (s *StateStore) ACLTokenUsageByAccessorID(ws memdb.WatchSet, accessorID string) (*structs.ACLTokenUsage, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableACLTokenUsage, indexID, accessorID)
+	if err != nil {
+		return nil, fmt.Errorf("acl token usage lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+	return existing.(*structs.ACLTokenUsage), nil
+}
+
+// ACLTokenUsages returns an iterator over the usage of all ACL tokens.
+func (s *StateStore) ACLTokenUsages(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableACLTokenUsage, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("acl token usage lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
 // UpsertACLRoles is used to insert a number of ACL roles into the state store.
 // It uses a single write transaction for efficiency, however, any error means
 // no entries will be committed.
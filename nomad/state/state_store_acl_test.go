@@ -126,6 +126,81 @@ func TestStateStore_ACLTokensByExpired(t *testing.T) {
 	testFn(expiredGlobalToken, true)
 }
 
+func TestStateStore_UpsertACLTokenUsage(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	token1 := mock.ACLToken()
+	token2 := mock.ACLToken()
+	must.NoError(t, testState.UpsertACLTokens(
+		structs.MsgTypeTestSetup, 10, []*structs.ACLToken{token1, token2}))
+
+	now := time.Now().UTC().Round(0)
+
+	// Usage of tokens that don't exist is discarded.
+	usages := []*structs.ACLTokenUsage{
+		{AccessorID: token1.AccessorID, LastUsedTime: now, LastUsedIP: "10.0.0.1"},
+		{AccessorID: uuid.Generate(), LastUsedTime: now, LastUsedIP: "10.0.0.1"},
+	}
+	must.NoError(t, testState.UpsertACLTokenUsage(structs.MsgTypeTestSetup, 20, usages))
+
+	usage, err := testState.ACLTokenUsageByAccessorID(nil, token1.AccessorID)
+	must.NoError(t, err)
+	must.NotNil(t, usage)
+	must.Eq(t, now, usage.LastUsedTime)
+	must.Eq(t, "10.0.0.1", usage.LastUsedIP)
+	must.Eq(t, 20, usage.CreateIndex)
+	must.Eq(t, 20, usage.ModifyIndex)
+
+	iter, err := testState.ACLTokenUsages(nil)
+	must.NoError(t, err)
+	var num int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		num++
+	}
+	must.Eq(t, 1, num)
+
+	// Usage older than the one recorded is discarded.
+	usages = []*structs.ACLTokenUsage{
+		{AccessorID: token1.AccessorID, LastUsedTime: now.Add(-time.Minute), LastUsedIP: "10.0.0.2"},
+		{AccessorID: token2.AccessorID, LastUsedTime: now, LastUsedIP: "10.0.0.2"},
+	}
+	must.NoError(t, testState.UpsertACLTokenUsage(structs.MsgTypeTestSetup, 30, usages))
+
+	usage, err = testState.ACLTokenUsageByAccessorID(nil, token1.AccessorID)
+	must.NoError(t, err)
+	must.Eq(t, "10.0.0.1", usage.LastUsedIP)
+	must.Eq(t, 20, usage.ModifyIndex)
+
+	// Newer usage replaces the one recorded.
+	usages = []*structs.ACLTokenUsage{
+		{AccessorID: token1.AccessorID, LastUsedTime: now.Add(time.Minute), LastUsedIP: "10.0.0.3"},
+	}
+	must.NoError(t, testState.UpsertACLTokenUsage(structs.MsgTypeTestSetup, 40, usages))
+
+	usage, err = testState.ACLTokenUsageByAccessorID(nil, token1.AccessorID)
+	must.NoError(t, err)
+	must.Eq(t, "10.0.0.3", usage.LastUsedIP)
+	must.Eq(t, 20, usage.CreateIndex)
+	must.Eq(t, 40, usage.ModifyIndex)
+
+	index, err := testState.Index(TableACLTokenUsage)
+	must.NoError(t, err)
+	must.Eq(t, 40, index)
+
+	// Deleting a token deletes its usage.
+	must.NoError(t, testState.DeleteACLTokens(
+		structs.MsgTypeTestSetup, 50, []string{token1.AccessorID}))
+
+	usage, err = testState.ACLTokenUsageByAccessorID(nil, token1.AccessorID)
+	must.NoError(t, err)
+	must.Nil(t, usage)
+
+	usage, err = testState.ACLTokenUsageByAccessorID(nil, token2.AccessorID)
+	must.NoError(t, err)
+	must.NotNil(t, usage)
+}
+
 func Test_expiresIndexName(t *testing.T) {
 	testCases := []struct {
 		globalInput    bool
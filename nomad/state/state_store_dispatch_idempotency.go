@@ -0,0 +1,93 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// upsertDispatchIdempotencyTxn records the job dispatched with an idempotency
+// token. A record left by an expired dispatch with the same token is
+// replaced.
+func (s *StateStore) upsertDispatchIdempotencyTxn(index uint64, job *structs.Job, txn *txn) error {
+	record := &structs.DispatchIdempotencyRecord{
+		Namespace:   job.Namespace,
+		ParentID:    job.ParentID,
+		Token:       job.DispatchIdempotencyToken,
+		JobID:       job.ID,
+		CreateTime:  job.SubmitTime,
+		CreateIndex: index,
+	}
+
+	if err := txn.Insert(TableDispatchIdempotency, record); err != nil {
+		return fmt.Errorf("dispatch idempotency record insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableDispatchIdempotency, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// DispatchIdempotencyRecord returns the record of the job dispatched from the
+// parameterized job with the idempotency token, or nil if there is none.
+func (s *StateStore) DispatchIdempotencyRecord(ws memdb.WatchSet, namespace, parentID, token string) (*structs.DispatchIdempotencyRecord, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableDispatchIdempotency, indexID, namespace, parentID, token)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch idempotency record lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.DispatchIdempotencyRecord), nil
+	}
+	return nil, nil
+}
+
+// DispatchIdempotencyRecords returns an iterator over all the dispatch
+// idempotency records.
+func (s *StateStore) DispatchIdempotencyRecords(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableDispatchIdempotency, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch idempotency records lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ExpireDispatchIdempotencyRecords deletes the dispatch idempotency records
+// created before the Unix nanoseconds time.
+func (s *StateStore) ExpireDispatchIdempotencyRecords(msgType structs.MessageType, index uint64, before int64) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	iter, err := txn.Get(TableDispatchIdempotency, indexID)
+	if err != nil {
+		return fmt.Errorf("dispatch idempotency records lookup failed: %v", err)
+	}
+
+	var expired []*structs.DispatchIdempotencyRecord
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		record := raw.(*structs.DispatchIdempotencyRecord)
+		if record.CreateTime < before {
+			expired = append(expired, record)
+		}
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	for _, record := range expired {
+		if err := txn.Delete(TableDispatchIdempotency, record); err != nil {
+			return fmt.Errorf("dispatch idempotency record delete failed: %v", err)
+		}
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableDispatchIdempotency, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
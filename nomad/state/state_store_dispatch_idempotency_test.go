@@ -0,0 +1,83 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestStateStore_DispatchIdempotencyRecords(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, parent))
+
+	dispatched := func(token string, submitTime time.Time) *structs.Job {
+		job := parent.Copy()
+		job.ID = structs.DispatchedID(parent.ID, "", submitTime)
+		job.ParentID = parent.ID
+		job.Dispatched = true
+		job.DispatchIdempotencyToken = token
+		job.SubmitTime = submitTime.UnixNano()
+		return job
+	}
+
+	ws := memdb.NewWatchSet()
+	out, err := state.DispatchIdempotencyRecord(ws, parent.Namespace, parent.ID, "old")
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// Dispatched jobs with a token are recorded
+	old := dispatched("old", time.Now().Add(-48*time.Hour))
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, old))
+	require.True(t, watchFired(ws))
+
+	out, err = state.DispatchIdempotencyRecord(nil, parent.Namespace, parent.ID, "old")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, old.ID, out.JobID)
+	require.Equal(t, old.SubmitTime, out.CreateTime)
+	require.Equal(t, uint64(1001), out.CreateIndex)
+
+	// Dispatched jobs without a token are not
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, dispatched("", time.Now())))
+	newJob := dispatched("new", time.Now())
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1003, newJob))
+
+	iter, err := state.DispatchIdempotencyRecords(nil)
+	require.NoError(t, err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 2, count)
+
+	// The records outlive the jobs until they expire
+	require.NoError(t, state.DeleteJob(1004, old.Namespace, old.ID))
+	out, err = state.DispatchIdempotencyRecord(nil, parent.Namespace, parent.ID, "old")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	before := time.Now().Add(-24 * time.Hour).UnixNano()
+	require.NoError(t, state.ExpireDispatchIdempotencyRecords(structs.MsgTypeTestSetup, 1005, before))
+
+	out, err = state.DispatchIdempotencyRecord(nil, parent.Namespace, parent.ID, "old")
+	require.NoError(t, err)
+	require.Nil(t, out)
+	out, err = state.DispatchIdempotencyRecord(nil, parent.Namespace, parent.ID, "new")
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, newJob.ID, out.JobID)
+
+	index, err := state.Index(TableDispatchIdempotency)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1005), index)
+}
@@ -0,0 +1,128 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertJobWithSubmission is used to register a job or update a job
+// definition, like UpsertJob, while storing the jobspec the new version of
+// the job was parsed from.
+func (s *StateStore) UpsertJobWithSubmission(msgType structs.MessageType, index uint64,
+	sub *structs.JobSubmission, job *structs.Job) error {
+
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+	if err := s.upsertJobImpl(index, job, false, txn); err != nil {
+		return err
+	}
+	if err := s.upsertJobSubmissionTxn(index, sub, job, txn); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
+// upsertJobSubmissionTxn stores the submission of the current version of the
+// job. A nil submission is a no-op.
+func (s *StateStore) upsertJobSubmissionTxn(index uint64, sub *structs.JobSubmission, job *structs.Job, txn *txn) error {
+	if sub == nil {
+		return nil
+	}
+
+	sub = sub.Copy()
+	sub.Namespace = job.Namespace
+	sub.JobID = job.ID
+	sub.Version = job.Version
+	sub.JobModifyIndex = job.JobModifyIndex
+
+	if err := txn.Insert(TableJobSubmission, sub); err != nil {
+		return fmt.Errorf("job submission insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobSubmission, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// JobSubmission returns the submission of the given version of the job, or nil
+// if the version was not registered with its jobspec.
+func (s *StateStore) JobSubmission(ws memdb.WatchSet, namespace, jobID string, version uint64) (*structs.JobSubmission, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableJobSubmission, indexID, namespace, jobID, version)
+	if err != nil {
+		return nil, fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.JobSubmission), nil
+	}
+	return nil, nil
+}
+
+// JobSubmissions returns an iterator over all the job submissions.
+func (s *StateStore) JobSubmissions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobSubmission, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("job submissions lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// deleteJobSubmission deletes the submission of the given version of the job,
+// if any.
+func (s *StateStore) deleteJobSubmission(index uint64, namespace, jobID string, version uint64, txn *txn) error {
+	existing, err := txn.First(TableJobSubmission, indexID, namespace, jobID, version)
+	if err != nil {
+		return fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	if existing == nil {
+		return nil
+	}
+
+	if err := txn.Delete(TableJobSubmission, existing); err != nil {
+		return fmt.Errorf("deleting job submission failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobSubmission, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// deleteJobSubmissions deletes the submissions of all the versions of the job.
+func (s *StateStore) deleteJobSubmissions(index uint64, namespace, jobID string, txn *txn) error {
+	iter, err := txn.Get(TableJobSubmission, indexID+"_prefix", namespace, jobID)
+	if err != nil {
+		return fmt.Errorf("job submissions lookup failed: %v", err)
+	}
+
+	// Put them into a slice so there are no safety concerns while actually
+	// performing the deletes
+	var subs []*structs.JobSubmission
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		// Ensure the ID is an exact match
+		sub := raw.(*structs.JobSubmission)
+		if sub.JobID == jobID {
+			subs = append(subs, sub)
+		}
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	for _, sub := range subs {
+		if err := txn.Delete(TableJobSubmission, sub); err != nil {
+			return fmt.Errorf("deleting job submission failed: %v", err)
+		}
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobSubmission, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
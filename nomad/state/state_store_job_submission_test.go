@@ -0,0 +1,97 @@
+package state
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestStateStore_UpsertJobWithSubmission(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+
+	job := mock.Job()
+	sub := &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Variables: map[string]string{"foo": `"bar"`},
+	}
+
+	ws := memdb.NewWatchSet()
+	_, err := state.JobSubmission(ws, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1000, sub, job))
+	require.True(t, watchFired(ws))
+
+	out, err := state.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, sub.Source, out.Source)
+	require.Equal(t, sub.Variables, out.Variables)
+	require.Equal(t, job.ID, out.JobID)
+	require.Equal(t, job.Namespace, out.Namespace)
+	require.Equal(t, uint64(1000), out.JobModifyIndex)
+
+	// The submission passed in is not modified
+	require.Empty(t, sub.JobID)
+
+	// Registering a new version without a submission leaves the previous one
+	// in place
+	job2 := job.Copy()
+	job2.Meta["foo"] = "bar"
+	require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, 1001, nil, job2))
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, 1)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	index, err := state.Index(TableJobSubmission)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), index)
+}
+
+func TestStateStore_JobSubmission_Pruned(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+
+	job := mock.Job()
+	index := uint64(1000)
+	for i := 0; i < structs.JobTrackedVersions+2; i++ {
+		job = job.Copy()
+		job.Meta["version"] = string(rune('a' + i))
+		sub := &structs.JobSubmission{
+			Source: job.Meta["version"],
+			Format: structs.JobSubmissionFormatHCL1,
+		}
+		require.NoError(t, state.UpsertJobWithSubmission(structs.MsgTypeTestSetup, index, sub, job))
+		index++
+	}
+
+	// The submissions of the pruned versions are deleted along with them
+	for v := uint64(0); v < structs.JobTrackedVersions+2; v++ {
+		out, err := state.JobSubmission(nil, job.Namespace, job.ID, v)
+		require.NoError(t, err)
+		if v < 2 {
+			require.Nil(t, out, "version %d", v)
+		} else {
+			require.NotNil(t, out, "version %d", v)
+		}
+	}
+
+	// Deleting the job deletes all of its submissions
+	require.NoError(t, state.DeleteJob(index, job.Namespace, job.ID))
+
+	iter, err := state.JobSubmissions(nil)
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+}
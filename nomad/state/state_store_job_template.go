@@ -0,0 +1,99 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertJobTemplate is used to register or update a job template.
+func (s *StateStore) UpsertJobTemplate(msgType structs.MessageType, index uint64, tmpl *structs.JobTemplate) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First(TableJobTemplates, indexID, tmpl.Namespace, tmpl.Name)
+	if err != nil {
+		return fmt.Errorf("job template lookup failed: %v", err)
+	}
+
+	tmpl = tmpl.Copy()
+	if existing != nil {
+		tmpl.CreateIndex = existing.(*structs.JobTemplate).CreateIndex
+	} else {
+		tmpl.CreateIndex = index
+	}
+	tmpl.ModifyIndex = index
+
+	if err := txn.Insert(TableJobTemplates, tmpl); err != nil {
+		return fmt.Errorf("job template insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobTemplates, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// DeleteJobTemplate is used to delete a job template.
+func (s *StateStore) DeleteJobTemplate(msgType structs.MessageType, index uint64, namespace, name string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First(TableJobTemplates, indexID, namespace, name)
+	if err != nil {
+		return fmt.Errorf("job template lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("job template %q not found", name)
+	}
+
+	if err := txn.Delete(TableJobTemplates, existing); err != nil {
+		return fmt.Errorf("job template delete failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableJobTemplates, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
+// JobTemplateByName returns the job template of the namespace with the given
+// name, or nil if it doesn't exist.
+func (s *StateStore) JobTemplateByName(ws memdb.WatchSet, namespace, name string) (*structs.JobTemplate, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableJobTemplates, indexID, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("job template lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.JobTemplate), nil
+	}
+	return nil, nil
+}
+
+// JobTemplates returns an iterator over all the job templates.
+func (s *StateStore) JobTemplates(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobTemplates, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("job templates lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// JobTemplatesByNamespace returns an iterator over the job templates of the
+// namespace.
+func (s *StateStore) JobTemplatesByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableJobTemplates, indexID+"_prefix", namespace, "")
+	if err != nil {
+		return nil, fmt.Errorf("job templates lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
@@ -0,0 +1,115 @@
+package state
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestStateStore_UpsertJobTemplate(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+
+	tmpl := mock.JobTemplate()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.JobTemplateByName(ws, tmpl.Namespace, tmpl.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	require.NoError(t, state.UpsertJobTemplate(structs.MsgTypeTestSetup, 1000, tmpl))
+	require.True(t, watchFired(ws))
+
+	out, err = state.JobTemplateByName(nil, tmpl.Namespace, tmpl.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, tmpl.Source, out.Source)
+	require.Equal(t, uint64(1000), out.CreateIndex)
+	require.Equal(t, uint64(1000), out.ModifyIndex)
+
+	// The template passed in is not modified
+	require.Zero(t, tmpl.CreateIndex)
+
+	// Updates keep the create index
+	tmpl.Description = "updated"
+	require.NoError(t, state.UpsertJobTemplate(structs.MsgTypeTestSetup, 1001, tmpl))
+
+	out, err = state.JobTemplateByName(nil, tmpl.Namespace, tmpl.Name)
+	require.NoError(t, err)
+	require.Equal(t, "updated", out.Description)
+	require.Equal(t, uint64(1000), out.CreateIndex)
+	require.Equal(t, uint64(1001), out.ModifyIndex)
+
+	index, err := state.Index(TableJobTemplates)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1001), index)
+}
+
+func TestStateStore_JobTemplatesByNamespace(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+
+	// The namespaces share a prefix to ensure the lookups don't match the
+	// templates of other namespaces
+	tmpl1 := mock.JobTemplate()
+	tmpl1.Namespace = "team"
+	tmpl2 := mock.JobTemplate()
+	tmpl2.Namespace = "team-web"
+	tmpl3 := mock.JobTemplate()
+	tmpl3.Namespace = "team-web"
+
+	for i, tmpl := range []*structs.JobTemplate{tmpl1, tmpl2, tmpl3} {
+		require.NoError(t, state.UpsertJobTemplate(structs.MsgTypeTestSetup, uint64(1000+i), tmpl))
+	}
+
+	countTemplates := func(iter memdb.ResultIterator) int {
+		n := 0
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			n++
+		}
+		return n
+	}
+
+	iter, err := state.JobTemplatesByNamespace(nil, "team")
+	require.NoError(t, err)
+	require.Equal(t, 1, countTemplates(iter))
+
+	iter, err = state.JobTemplatesByNamespace(nil, "team-web")
+	require.NoError(t, err)
+	require.Equal(t, 2, countTemplates(iter))
+
+	iter, err = state.JobTemplates(nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, countTemplates(iter))
+}
+
+func TestStateStore_DeleteJobTemplate(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+
+	tmpl := mock.JobTemplate()
+	err := state.DeleteJobTemplate(structs.MsgTypeTestSetup, 1000, tmpl.Namespace, tmpl.Name)
+	require.EqualError(t, err, `job template "`+tmpl.Name+`" not found`)
+
+	require.NoError(t, state.UpsertJobTemplate(structs.MsgTypeTestSetup, 1000, tmpl))
+
+	ws := memdb.NewWatchSet()
+	_, err = state.JobTemplateByName(ws, tmpl.Namespace, tmpl.Name)
+	require.NoError(t, err)
+
+	require.NoError(t, state.DeleteJobTemplate(structs.MsgTypeTestSetup, 1001, tmpl.Namespace, tmpl.Name))
+	require.True(t, watchFired(ws))
+
+	out, err := state.JobTemplateByName(nil, tmpl.Namespace, tmpl.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	index, err := state.Index(TableJobTemplates)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1001), index)
+}
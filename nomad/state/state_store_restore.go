@@ -52,7 +52,7 @@ func (r *StateRestore) AllocRestore(alloc *structs.Allocation) error {
 	if err := r.txn.Insert("allocs", alloc); err != nil {
 		return fmt.Errorf("alloc insert failed: %v", err)
 	}
-	return nil
+	return updateNamespaceUsageWithAlloc(r.txn, alloc, nil)
 }
 
 // IndexRestore is used to restore an index
@@ -87,6 +87,31 @@ func (r *StateRestore) JobVersionRestore(version *structs.Job) error {
 	return nil
 }
 
+// JobSubmissionRestore is used to restore a job submission
+func (r *StateRestore) JobSubmissionRestore(sub *structs.JobSubmission) error {
+	if err := r.txn.Insert(TableJobSubmission, sub); err != nil {
+		return fmt.Errorf("job submission insert failed: %v", err)
+	}
+	return nil
+}
+
+// JobTemplateRestore is used to restore a job template
+func (r *StateRestore) JobTemplateRestore(tmpl *structs.JobTemplate) error {
+	if err := r.txn.Insert(TableJobTemplates, tmpl); err != nil {
+		return fmt.Errorf("job template insert failed: %v", err)
+	}
+	return nil
+}
+
+// DispatchIdempotencyRecordRestore is used to restore a dispatch idempotency
+// record
+func (r *StateRestore) DispatchIdempotencyRecordRestore(record *structs.DispatchIdempotencyRecord) error {
+	if err := r.txn.Insert(TableDispatchIdempotency, record); err != nil {
+		return fmt.Errorf("dispatch idempotency record insert failed: %v", err)
+	}
+	return nil
+}
+
 // DeploymentRestore is used to restore a deployment
 func (r *StateRestore) DeploymentRestore(deployment *structs.Deployment) error {
 	if err := r.txn.Insert("deployment", deployment); err != nil {
@@ -127,6 +152,14 @@ func (r *StateRestore) ACLTokenRestore(token *structs.ACLToken) error {
 	return nil
 }
 
+// ACLTokenUsageRestore is used to restore the usage of an ACL token
+func (r *StateRestore) ACLTokenUsageRestore(usage *structs.ACLTokenUsage) error {
+	if err := r.txn.Insert(TableACLTokenUsage, usage); err != nil {
+		return fmt.Errorf("inserting acl token usage failed: %v", err)
+	}
+	return nil
+}
+
 // OneTimeTokenRestore is used to restore a one-time token
 func (r *StateRestore) OneTimeTokenRestore(token *structs.OneTimeToken) error {
 	if err := r.txn.Insert("one_time_token", token); err != nil {
@@ -207,6 +240,15 @@ func (r *StateRestore) VariablesRestore(variable *structs.VariableEncrypted) err
 	return nil
 }
 
+// VariablesVersionRestore is used to restore a single variable version into
+// the variables_versions table.
+func (r *StateRestore) VariablesVersionRestore(version *structs.VariableVersion) error {
+	if err := r.txn.Insert(TableVariablesVersions, version); err != nil {
+		return fmt.Errorf("variable version insert failed: %v", err)
+	}
+	return nil
+}
+
 // VariablesQuotaRestore is used to restore a single variable quota into the
 // variables_quota table.
 func (r *StateRestore) VariablesQuotaRestore(quota *structs.VariablesQuota) error {
@@ -1144,6 +1144,78 @@ func TestStateStore_NamespaceNames(t *testing.T) {
 	require.Equal(t, expectedNames, found)
 }
 
+func TestStateStore_NamespaceUsage(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	// expected returns the usage of the given allocations
+	expected := func(allocs ...*structs.Allocation) *structs.NamespaceUsage {
+		usage := &structs.NamespaceUsage{Namespace: structs.DefaultNamespace}
+		for _, alloc := range allocs {
+			usage.Add(alloc)
+		}
+		return usage
+	}
+
+	alloc1 := mock.Alloc()
+	alloc2 := mock.Alloc()
+	alloc2.AllocatedResources.Tasks["web"].Cpu.CpuShares = 1000
+	other := mock.Alloc()
+	other.Namespace = "other"
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Allocation{alloc1, alloc2, other}))
+
+	ws := memdb.NewWatchSet()
+	usage, err := state.NamespaceUsage(ws, structs.DefaultNamespace)
+	require.NoError(t, err)
+	require.Equal(t, expected(alloc1, alloc2), usage)
+
+	// Terminal allocations don't count towards the usage
+	update := alloc1.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, state.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 1001,
+		[]*structs.Allocation{update}))
+	require.True(t, watchFired(ws))
+
+	usage, err = state.NamespaceUsage(nil, structs.DefaultNamespace)
+	require.NoError(t, err)
+	require.Equal(t, expected(alloc2), usage)
+
+	// Updating the resources of an allocation updates the usage
+	alloc2 = alloc2.Copy()
+	alloc2.AllocatedResources.Tasks["web"].Cpu.CpuShares = 2000
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1002,
+		[]*structs.Allocation{alloc2}))
+
+	usage, err = state.NamespaceUsage(nil, structs.DefaultNamespace)
+	require.NoError(t, err)
+	require.Equal(t, expected(alloc2), usage)
+
+	// Deleting the last allocation of the namespace clears its usage
+	require.NoError(t, state.DeleteEval(1003, nil, []string{alloc2.ID}, false))
+
+	usage, err = state.NamespaceUsage(nil, structs.DefaultNamespace)
+	require.NoError(t, err)
+	require.Equal(t, expected(), usage)
+
+	usage, err = state.NamespaceUsage(nil, other.Namespace)
+	require.NoError(t, err)
+	require.Equal(t, 1, usage.Allocs)
+
+	// The usage is rebuilt when restoring allocations
+	restored := testStateStore(t)
+	restore, err := restored.Restore()
+	require.NoError(t, err)
+	require.NoError(t, restore.AllocRestore(alloc1))
+	require.NoError(t, restore.AllocRestore(alloc2))
+	require.NoError(t, restore.Commit())
+
+	usage, err = restored.NamespaceUsage(nil, structs.DefaultNamespace)
+	require.NoError(t, err)
+	require.Equal(t, expected(alloc1, alloc2), usage)
+}
+
 func TestStateStore_NamespaceByNamePrefix(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3,6 +3,7 @@ package state
 import (
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/hashicorp/go-memdb"
 
@@ -129,6 +130,61 @@ func (s *StateStore) VarSet(idx uint64, sv *structs.VarApplyStateRequest) *struc
 	return resp
 }
 
+// VarRekey replaces the ciphertext of the variable, or of its previous
+// version, with the version of the request. Unlike a set, neither the version
+// nor the metadata of the variable change. It's a check-and-set on the
+// ModifyIndex, so a variable written since it was read is left untouched.
+func (s *StateStore) VarRekey(idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	sv := req.Var
+	raw, err := tx.First(TableVariables, indexID, sv.Namespace, sv.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable lookup: %s", err))
+	}
+
+	if live, ok := raw.(*structs.VariableEncrypted); ok && live.Version == sv.Version {
+		if live.ModifyIndex != sv.ModifyIndex {
+			return req.ConflictResponse(idx, live)
+		}
+		updated := live.Copy()
+		updated.Data = sv.Data
+		updated.KeyID = sv.KeyID
+		if err := tx.Insert(TableVariables, &updated); err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("failed inserting variable: %s", err))
+		}
+		if err := tx.Insert(tableIndex, &IndexEntry{TableVariables, idx}); err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("failed updating variable index: %s", err))
+		}
+	} else {
+		raw, err := tx.First(TableVariablesVersions, indexID, sv.Namespace, sv.Path, sv.Version)
+		if err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("variable version lookup failed: %v", err))
+		}
+		version, ok := raw.(*structs.VariableVersion)
+		if !ok || version.ModifyIndex != sv.ModifyIndex {
+			// The version was pruned or purged since it was read
+			return req.SuccessResponse(idx, nil)
+		}
+		updated := *version
+		updated.VariableEncrypted = version.VariableEncrypted.Copy()
+		updated.Data = sv.Data
+		updated.KeyID = sv.KeyID
+		if err := tx.Insert(TableVariablesVersions, &updated); err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("failed inserting variable version: %v", err))
+		}
+		if err := tx.Insert(tableIndex, &IndexEntry{TableVariablesVersions, idx}); err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("failed updating variable versions index: %v", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+	return req.SuccessResponse(idx, nil)
+}
+
 // VarSetCAS is used to do a check-and-set operation on a
 // variable. The ModifyIndex in the provided entry is used to determine if
 // we should write the entry to the state store or not.
@@ -205,6 +261,7 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 	if existing != nil {
 		sv.CreateIndex = existing.CreateIndex
 		sv.CreateTime = existing.CreateTime
+		sv.Version = existing.Version
 
 		if existing.Equal(*sv) {
 			// Skip further writing in the state store if the entry is not actually
@@ -216,10 +273,27 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 		}
 		sv.ModifyIndex = idx
 		quotaChange = int64(len(sv.Data) - len(existing.Data))
+
+		prev, err := s.varArchiveTxn(tx, idx, existing, 0, req.MaxVersions)
+		if err != nil {
+			return req.ErrorResponse(idx, err)
+		}
+		sv.Version = prev.Version + 1
 	} else {
 		sv.CreateIndex = idx
 		sv.ModifyIndex = idx
 		quotaChange = int64(len(sv.Data))
+
+		// A deleted variable that is written again continues from its last
+		// version
+		versions, err := varVersionsTxn(tx, nil, sv.Namespace, sv.Path)
+		if err != nil {
+			return req.ErrorResponse(idx, err)
+		}
+		sv.Version = 1
+		if len(versions) > 0 {
+			sv.Version = versions[0].Version + 1
+		}
 	}
 
 	if err := tx.Insert(TableVariables, sv); err != nil {
@@ -423,6 +497,12 @@ func (s *StateStore) svDeleteTxn(tx WriteTxn, idx uint64, req *structs.VarApplyS
 		}
 	}
 
+	// Keep the deleted variable as its last version until it's purged by the
+	// variables GC, so it can be restored.
+	if _, err := s.varArchiveTxn(tx, idx, sv, req.Var.ModifyTime, req.MaxVersions); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+
 	// Delete the variable and update the index table.
 	if err := tx.Delete(TableVariables, sv); err != nil {
 		return req.ErrorResponse(idx, fmt.Errorf("failed deleting variable entry: %s", err))
@@ -435,6 +515,183 @@ func (s *StateStore) svDeleteTxn(tx WriteTxn, idx uint64, req *structs.VarApplyS
 	return req.SuccessResponse(idx, nil)
 }
 
+// varArchiveTxn keeps a copy of the variable being replaced or deleted in the
+// versions table and prunes the versions beyond maxVersions. The last version
+// of a deleted variable is always kept. It returns the archived version.
+func (s *StateStore) varArchiveTxn(tx WriteTxn, idx uint64, sv *structs.VariableEncrypted,
+	deleteTime int64, maxVersions int) (*structs.VariableVersion, error) {
+
+	version := &structs.VariableVersion{
+		VariableEncrypted: sv.Copy(),
+	}
+
+	// Variables written before versioning was introduced have no version
+	if version.Version == 0 {
+		version.Version = 1
+	}
+
+	if deleteTime != 0 {
+		version.DeleteIndex = idx
+		version.DeleteTime = deleteTime
+		maxVersions = helper.Max(maxVersions, 1)
+	}
+
+	if maxVersions > 0 {
+		if err := tx.Insert(TableVariablesVersions, version); err != nil {
+			return nil, fmt.Errorf("failed inserting variable version: %v", err)
+		}
+	}
+
+	versions, err := varVersionsTxn(tx, nil, sv.Namespace, sv.Path)
+	if err != nil {
+		return nil, err
+	}
+	for i := helper.Min(maxVersions, len(versions)); i < len(versions); i++ {
+		if err := tx.Delete(TableVariablesVersions, versions[i]); err != nil {
+			return nil, fmt.Errorf("failed deleting variable version: %v", err)
+		}
+	}
+
+	if err := tx.Insert(tableIndex,
+		&IndexEntry{TableVariablesVersions, idx}); err != nil {
+		return nil, fmt.Errorf("failed updating variable versions index: %v", err)
+	}
+	return version, nil
+}
+
+// GetVariableVersionsByKeyID returns an iterator of the previous versions of
+// variables encrypted with the given root key.
+func (s *StateStore) GetVariableVersionsByKeyID(
+	ws memdb.WatchSet, keyID string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariablesVersions, indexKeyID, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("variable versions lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// GetVariableVersion returns a previous version of the variable at the given
+// namespace and path, or nil if the version isn't kept.
+func (s *StateStore) GetVariableVersion(
+	ws memdb.WatchSet, namespace, path string, version uint64) (*structs.VariableVersion, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, raw, err := txn.FirstWatch(TableVariablesVersions, indexID, namespace, path, version)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+	if raw == nil {
+		return nil, nil
+	}
+	return raw.(*structs.VariableVersion), nil
+}
+
+// GetVariableVersions returns the previous versions of the variable at the
+// given namespace and path, newest first.
+func (s *StateStore) GetVariableVersions(
+	ws memdb.WatchSet, namespace, path string) ([]*structs.VariableVersion, error) {
+	txn := s.db.ReadTxn()
+	return varVersionsTxn(txn, ws, namespace, path)
+}
+
+func varVersionsTxn(tx ReadTxn, ws memdb.WatchSet, namespace, path string) ([]*structs.VariableVersion, error) {
+	iter, err := tx.Get(TableVariablesVersions, indexID+"_prefix", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable versions lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var versions []*structs.VariableVersion
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		// Ensure the path is an exact match
+		version := raw.(*structs.VariableVersion)
+		if version.Path != path {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+	return versions, nil
+}
+
+// VariablesVersions queries all the variable versions and is used only for
+// snapshot/restore and the variables GC.
+func (s *StateStore) VariablesVersions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariablesVersions, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// VarExpireDeleted purges all the versions of the variables that were deleted
+// before the given time, in UnixNano, and have not been written since.
+func (s *StateStore) VarExpireDeleted(msgType structs.MessageType, idx uint64, before int64) error {
+	txn := s.db.WriteTxnMsgT(msgType, idx)
+	defer txn.Abort()
+
+	iter, err := txn.Get(TableVariablesVersions, indexID)
+	if err != nil {
+		return fmt.Errorf("variable versions lookup failed: %v", err)
+	}
+
+	// Find the last version of each deleted variable
+	latest := map[structs.NamespacedID]*structs.VariableVersion{}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		version := raw.(*structs.VariableVersion)
+		id := structs.NamespacedID{Namespace: version.Namespace, ID: version.Path}
+		if last, ok := latest[id]; !ok || version.Version > last.Version {
+			latest[id] = version
+		}
+	}
+
+	expired := 0
+	for id, last := range latest {
+		if !last.Deleted() || last.DeleteTime >= before {
+			continue
+		}
+		live, err := txn.First(TableVariables, indexID, id.Namespace, id.ID)
+		if err != nil {
+			return fmt.Errorf("variable lookup failed: %v", err)
+		}
+		if live != nil {
+			continue
+		}
+
+		versions, err := varVersionsTxn(txn, nil, id.Namespace, id.ID)
+		if err != nil {
+			return err
+		}
+		for _, version := range versions {
+			if err := txn.Delete(TableVariablesVersions, version); err != nil {
+				return fmt.Errorf("failed deleting variable version: %v", err)
+			}
+		}
+		expired++
+	}
+
+	if expired == 0 {
+		return nil
+	}
+	if err := txn.Insert(tableIndex,
+		&IndexEntry{TableVariablesVersions, idx}); err != nil {
+		return fmt.Errorf("failed updating variable versions index: %v", err)
+	}
+	return txn.Commit()
+}
+
 // This extra indirection is to facilitate the tombstone case if it matters.
 func svMaxIndex(tx ReadTxn) uint64 {
 	return maxIndexTxn(tx, TableVariables)
@@ -3,6 +3,7 @@ package state
 import (
 	"encoding/json"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -730,3 +731,177 @@ func TestStateStore_Variables_DeleteCAS(t *testing.T) {
 		require.True(t, resp.IsOk())
 	})
 }
+
+func TestStateStore_VariableVersions(t *testing.T) {
+	ci.Parallel(t)
+	ts := testStateStore(t)
+
+	setVar := func(idx uint64, data string) *structs.VariableEncrypted {
+		sv := mock.VariableEncrypted()
+		sv.Namespace = "default"
+		sv.Path = "versioned"
+		sv.KeyID = data
+		sv.Data = []byte(data)
+		resp := ts.VarSet(idx, &structs.VarApplyStateRequest{
+			Op:          structs.VarOpSet,
+			Var:         sv,
+			MaxVersions: 2,
+		})
+		require.True(t, resp.IsOk(), "resp: %+v", resp)
+		return sv
+	}
+	deleteVar := func(idx uint64, deleteTime int64) {
+		resp := ts.VarDelete(idx, &structs.VarApplyStateRequest{
+			Op: structs.VarOpDelete,
+			Var: &structs.VariableEncrypted{
+				VariableMetadata: structs.VariableMetadata{
+					Namespace:  "default",
+					Path:       "versioned",
+					ModifyTime: deleteTime,
+				},
+			},
+			MaxVersions: 2,
+		})
+		require.True(t, resp.IsOk(), "resp: %+v", resp)
+	}
+	versions := func() []uint64 {
+		out, err := ts.GetVariableVersions(nil, "default", "versioned")
+		require.NoError(t, err)
+		var vs []uint64
+		for _, v := range out {
+			vs = append(vs, v.Version)
+		}
+		return vs
+	}
+
+	// Only the last 2 previous versions are kept
+	for i := 1; i <= 4; i++ {
+		sv := setVar(uint64(10+i), "v"+strconv.Itoa(i))
+		require.Equal(t, uint64(i), sv.Version)
+	}
+	require.Equal(t, []uint64{3, 2}, versions())
+
+	version, err := ts.GetVariableVersion(nil, "default", "versioned", 3)
+	require.NoError(t, err)
+	require.Equal(t, "v3", string(version.Data))
+	require.False(t, version.Deleted())
+
+	inUse, err := ts.IsRootKeyMetaInUse("v3")
+	require.NoError(t, err)
+	require.True(t, inUse)
+
+	// The deleted variable is kept as its last version
+	deleteVar(20, 1000)
+	sv, err := ts.GetVariable(nil, "default", "versioned")
+	require.NoError(t, err)
+	require.Nil(t, sv)
+	require.Equal(t, []uint64{4, 3}, versions())
+
+	version, err = ts.GetVariableVersion(nil, "default", "versioned", 4)
+	require.NoError(t, err)
+	require.True(t, version.Deleted())
+	require.Equal(t, uint64(20), version.DeleteIndex)
+	require.Equal(t, int64(1000), version.DeleteTime)
+
+	// Writing the variable again continues from its last version, and a
+	// variable that exists again is never expired
+	sv = setVar(21, "v5")
+	require.Equal(t, uint64(5), sv.Version)
+	require.NoError(t, ts.VarExpireDeleted(structs.VarExpireDeletedRequestType, 22, 2000))
+	require.Equal(t, []uint64{4, 3}, versions())
+
+	deleteVar(23, 3000)
+	require.Equal(t, []uint64{5, 4}, versions())
+
+	require.NoError(t, ts.VarExpireDeleted(structs.VarExpireDeletedRequestType, 24, 3000))
+	require.Equal(t, []uint64{5, 4}, versions())
+
+	require.NoError(t, ts.VarExpireDeleted(structs.VarExpireDeletedRequestType, 25, 3001))
+	require.Empty(t, versions())
+
+	index, err := ts.Index(TableVariablesVersions)
+	require.NoError(t, err)
+	require.Equal(t, uint64(25), index)
+}
+
+func TestStateStore_VarRekey(t *testing.T) {
+	ci.Parallel(t)
+	ts := testStateStore(t)
+
+	for i := 1; i <= 2; i++ {
+		sv := mock.VariableEncrypted()
+		sv.Namespace = "default"
+		sv.Path = "rekeyed"
+		sv.KeyID = "old"
+		sv.Data = []byte("v" + strconv.Itoa(i))
+		resp := ts.VarSet(uint64(10+i), &structs.VarApplyStateRequest{
+			Op:          structs.VarOpSet,
+			Var:         sv,
+			MaxVersions: 2,
+		})
+		require.True(t, resp.IsOk(), "resp: %+v", resp)
+	}
+
+	rekey := func(idx uint64, sv *structs.VariableEncrypted) *structs.VarApplyStateResponse {
+		rekeyed := sv.Copy()
+		rekeyed.KeyID = "new"
+		rekeyed.Data = append([]byte("new-"), sv.Data...)
+		return ts.VarRekey(idx, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpRekey,
+			Var: &rekeyed,
+		})
+	}
+
+	// Rekeying the live variable keeps its version and metadata
+	live, err := ts.GetVariable(nil, "default", "rekeyed")
+	require.NoError(t, err)
+	stale := live.Copy()
+	require.True(t, rekey(20, live).IsOk())
+
+	got, err := ts.GetVariable(nil, "default", "rekeyed")
+	require.NoError(t, err)
+	require.Equal(t, "new", got.KeyID)
+	require.Equal(t, "new-v2", string(got.Data))
+	require.Equal(t, live.Version, got.Version)
+	require.Equal(t, live.ModifyIndex, got.ModifyIndex)
+
+	// Rekeying a previous version replaces its ciphertext in place
+	version, err := ts.GetVariableVersion(nil, "default", "rekeyed", 1)
+	require.NoError(t, err)
+	require.True(t, rekey(21, &version.VariableEncrypted).IsOk())
+
+	version, err = ts.GetVariableVersion(nil, "default", "rekeyed", 1)
+	require.NoError(t, err)
+	require.Equal(t, "new", version.KeyID)
+	require.Equal(t, "new-v1", string(version.Data))
+
+	inUse, err := ts.IsRootKeyMetaInUse("old")
+	require.NoError(t, err)
+	require.False(t, inUse)
+
+	// A variable written since it was read is rekeyed as its previous
+	// version, leaving the new write untouched
+	sv := live.Copy()
+	sv.KeyID = "old"
+	sv.Data = []byte("v3")
+	resp := ts.VarSet(22, &structs.VarApplyStateRequest{
+		Op:          structs.VarOpSet,
+		Var:         &sv,
+		MaxVersions: 2,
+	})
+	require.True(t, resp.IsOk(), "resp: %+v", resp)
+	require.True(t, rekey(23, &stale).IsOk())
+
+	got, err = ts.GetVariable(nil, "default", "rekeyed")
+	require.NoError(t, err)
+	require.Equal(t, "old", got.KeyID)
+	require.Equal(t, "v3", string(got.Data))
+
+	version, err = ts.GetVariableVersion(nil, "default", "rekeyed", 2)
+	require.NoError(t, err)
+	require.Equal(t, "new", version.KeyID)
+
+	// A version that's no longer kept is skipped
+	stale.Version = 100
+	require.True(t, rekey(24, &stale).IsOk())
+}
@@ -291,6 +291,9 @@ func aclObjFromSnapshotForTokenSecretID(
 	if aclToken.IsExpired(time.Now().UTC()) {
 		return nil, nil, structs.ErrTokenExpired
 	}
+	if aclToken.Disabled {
+		return nil, nil, structs.ErrTokenDisabled
+	}
 
 	// Check if this is a management token
 	if aclToken.Type == structs.ACLManagementToken {
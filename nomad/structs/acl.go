@@ -78,9 +78,21 @@ const (
 	// Args: ACLRoleByNameRequest
 	// Reply: ACLRoleByNameResponse
 	ACLGetRoleByNameRPCMethod = "ACL.GetRoleByName"
+
+	// ACLUpsertTokenUsageRPCMethod is the RPC method for recording when ACL
+	// tokens were last used. This is an internal only RPC endpoint used by
+	// servers to write the usage they batched in memory.
+	//
+	// Args: ACLTokenUsageUpsertRequest
+	// Reply: GenericResponse
+	ACLUpsertTokenUsageRPCMethod = "ACL.UpsertTokenUsage"
 )
 
 const (
+	// ACLMaxIdleBatchSize is the maximum number of idle ACL tokens that will
+	// be disabled in a single Raft transaction.
+	ACLMaxIdleBatchSize = 4096
+
 	// ACLMaxExpiredBatchSize is the maximum number of expired ACL tokens that
 	// will be garbage collected in a single trigger. This number helps limit
 	// the replication pressure due to expired token deletion. If there are a
@@ -133,6 +145,11 @@ func (a *ACLToken) Canonicalize() {
 			a.ExpirationTime = pointer.Of(a.CreateTime.Add(a.ExpirationTTL))
 		}
 	}
+
+	// The usage of the token is tracked separately and must never be stored
+	// with the token.
+	a.LastUsedTime = nil
+	a.LastUsedIP = ""
 }
 
 // Validate is used to check a token for reasonableness
@@ -517,3 +534,52 @@ type ACLRoleByNameResponse struct {
 	ACLRole *ACLRole
 	QueryMeta
 }
+
+// ACLTokenUsage records when an ACL token was last used to make a request and
+// where the request came from. Usage is tracked separately from the token, so
+// that recording it doesn't modify the token or trigger its replication.
+type ACLTokenUsage struct {
+	AccessorID string
+
+	// LastUsedTime is when the token was last used to make a request handled
+	// by a server of this region.
+	LastUsedTime time.Time
+
+	// LastUsedIP is the IP address of the HTTP client that last used the
+	// token, or of the Nomad client when the token was resolved by a client
+	// agent. It is empty if the source of the request is unknown.
+	LastUsedIP string
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SetUsage populates the last used fields of the token from its usage. The
+// token must be a copy, as it is otherwise shared with the state store.
+func (a *ACLToken) SetUsage(usage *ACLTokenUsage) {
+	if usage == nil {
+		return
+	}
+	a.LastUsedTime = pointer.Of(usage.LastUsedTime)
+	a.LastUsedIP = usage.LastUsedIP
+}
+
+// IsIdle returns whether the token hasn't been used since the passed time.
+// Tokens that were never used are idle if they were created before it, and
+// tokens enabled again since aren't idle.
+func (a *ACLToken) IsIdle(usage *ACLTokenUsage, since time.Time) bool {
+	if a.EnableTime != nil && !a.EnableTime.Before(since) {
+		return false
+	}
+	if usage != nil {
+		return usage.LastUsedTime.Before(since)
+	}
+	return a.CreateTime.Before(since)
+}
+
+// ACLTokenUsageUpsertRequest is the request object used to record the usage
+// of a batch of ACL tokens.
+type ACLTokenUsageUpsertRequest struct {
+	Usages []*ACLTokenUsage
+	WriteRequest
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// AdmissionWebhookFailurePolicyFail rejects the job when the webhook
+	// can't be reached or returns an invalid response.
+	AdmissionWebhookFailurePolicyFail = "fail"
+
+	// AdmissionWebhookFailurePolicyIgnore admits the job unmodified when the
+	// webhook can't be reached or returns an invalid response.
+	AdmissionWebhookFailurePolicyIgnore = "ignore"
+
+	// DefaultAdmissionWebhookTimeout is the time to wait for the response of
+	// a webhook when no timeout is configured.
+	DefaultAdmissionWebhookTimeout = 10 * time.Second
+)
+
+// AdmissionWebhookConfig is the configuration of an external admission
+// webhook called by the servers when jobs are registered or planned.
+type AdmissionWebhookConfig struct {
+	// Name is a unique name given to the webhook
+	Name string `hcl:",key"`
+
+	// URL is the HTTP or HTTPS endpoint the job is posted to.
+	URL string `hcl:"url"`
+
+	// Timeout is the time to wait for the response of the webhook.
+	Timeout    time.Duration `hcl:"-"`
+	TimeoutHCL string        `hcl:"timeout" json:"-"`
+
+	// FailurePolicy is what to do with the job when the webhook can't be
+	// reached or returns an invalid response. (fail, ignore)
+	FailurePolicy string `hcl:"failure_policy"`
+
+	// Namespaces is the list of namespaces, which may contain glob patterns,
+	// the webhook is called for. The webhook is called for all namespaces if
+	// empty.
+	Namespaces []string `hcl:"namespaces"`
+}
+
+// Copy returns a new copy of an AdmissionWebhookConfig
+func (a *AdmissionWebhookConfig) Copy() *AdmissionWebhookConfig {
+	if a == nil {
+		return nil
+	}
+
+	nc := new(AdmissionWebhookConfig)
+	*nc = *a
+	nc.Namespaces = slices.Clone(a.Namespaces)
+	return nc
+}
+
+// Canonicalize sets the defaults of the unset fields.
+func (a *AdmissionWebhookConfig) Canonicalize() {
+	if a.Timeout == 0 {
+		a.Timeout = DefaultAdmissionWebhookTimeout
+	}
+	if a.FailurePolicy == "" {
+		a.FailurePolicy = AdmissionWebhookFailurePolicyFail
+	}
+}
+
+// Validate returns an error if the webhook configuration is invalid.
+func (a *AdmissionWebhookConfig) Validate() error {
+	var mErr *multierror.Error
+	if a.Name == "" {
+		mErr = multierror.Append(mErr, errors.New("missing name"))
+	}
+
+	if a.URL == "" {
+		mErr = multierror.Append(mErr, errors.New("missing url"))
+	} else if u, err := url.Parse(a.URL); err != nil {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid url: %v", err))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid url scheme %q: must be http or https", u.Scheme))
+	}
+
+	if a.Timeout < 0 {
+		mErr = multierror.Append(mErr, errors.New("timeout must not be negative"))
+	}
+
+	switch a.FailurePolicy {
+	case "", AdmissionWebhookFailurePolicyFail, AdmissionWebhookFailurePolicyIgnore:
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid failure_policy %q: must be %q or %q",
+			a.FailurePolicy, AdmissionWebhookFailurePolicyFail, AdmissionWebhookFailurePolicyIgnore))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// CopySliceAdmissionWebhookConfig returns a deep copy of the webhooks.
+func CopySliceAdmissionWebhookConfig(a []*AdmissionWebhookConfig) []*AdmissionWebhookConfig {
+	l := len(a)
+	if l == 0 {
+		return nil
+	}
+
+	ns := make([]*AdmissionWebhookConfig, l)
+	for idx, cfg := range a {
+		ns[idx] = cfg.Copy()
+	}
+
+	return ns
+}
+
+// AdmissionWebhookSliceMerge merges the webhooks of b into a, replacing the
+// webhooks of a with the same name.
+func AdmissionWebhookSliceMerge(a, b []*AdmissionWebhookConfig) []*AdmissionWebhookConfig {
+	n := make([]*AdmissionWebhookConfig, len(a))
+	seenKeys := make(map[string]int, len(a))
+
+	for i, config := range a {
+		n[i] = config.Copy()
+		seenKeys[config.Name] = i
+	}
+
+	for _, config := range b {
+		if fIndex, ok := seenKeys[config.Name]; ok {
+			n[fIndex] = config.Copy()
+			continue
+		}
+
+		n = append(n, config.Copy())
+	}
+
+	return n
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmissionWebhookConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		config *AdmissionWebhookConfig
+		expect string
+	}{
+		{
+			name:   "valid",
+			config: &AdmissionWebhookConfig{Name: "policy", URL: "https://policy.example.com/admit"},
+		},
+		{
+			name:   "missing url",
+			config: &AdmissionWebhookConfig{Name: "policy"},
+			expect: "missing url",
+		},
+		{
+			name:   "bad scheme",
+			config: &AdmissionWebhookConfig{Name: "policy", URL: "ftp://policy.example.com"},
+			expect: `invalid url scheme "ftp"`,
+		},
+		{
+			name: "negative timeout",
+			config: &AdmissionWebhookConfig{
+				Name:    "policy",
+				URL:     "http://localhost:8080",
+				Timeout: -time.Second,
+			},
+			expect: "timeout must not be negative",
+		},
+		{
+			name: "bad failure policy",
+			config: &AdmissionWebhookConfig{
+				Name:          "policy",
+				URL:           "http://localhost:8080",
+				FailurePolicy: "retry",
+			},
+			expect: `invalid failure_policy "retry"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.expect == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expect)
+			}
+		})
+	}
+}
+
+func TestAdmissionWebhookSliceMerge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := []*AdmissionWebhookConfig{
+		{Name: "policy", URL: "http://policy:8080", Namespaces: []string{"prod"}},
+		{Name: "labels", URL: "http://labels:8080"},
+	}
+	b := []*AdmissionWebhookConfig{
+		{Name: "policy", URL: "http://policy:9090", FailurePolicy: "ignore"},
+		{Name: "images", URL: "http://images:8080"},
+	}
+
+	require.Equal(t, []*AdmissionWebhookConfig{
+		{Name: "policy", URL: "http://policy:9090", FailurePolicy: "ignore"},
+		{Name: "labels", URL: "http://labels:8080"},
+		{Name: "images", URL: "http://images:8080"},
+	}, AdmissionWebhookSliceMerge(a, b))
+
+	// The inputs are not modified
+	require.Equal(t, "http://policy:8080", a[0].URL)
+}
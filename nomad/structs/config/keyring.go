@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// KeyringProviderAEAD stores the key encryption key of each root key next
+	// to it in the keystore. This is the default provider.
+	KeyringProviderAEAD = "aead"
+
+	// KeyringProviderVaultTransit wraps the key encryption keys with a key of
+	// the transit secrets engine of Vault.
+	KeyringProviderVaultTransit = "vault_transit"
+
+	// KeyringProviderExternal wraps the key encryption keys by running an
+	// external command, such as a script calling an HSM.
+	KeyringProviderExternal = "external"
+
+	// KeyringProviderPKCS11 wraps the key encryption keys with an AES key of
+	// an HSM accessed through a PKCS#11 library.
+	KeyringProviderPKCS11 = "pkcs11"
+)
+
+// KeyringConfig configures the provider of the key encryption keys (KEK)
+// that wrap the root keys of the keyring in the on-disk keystore of a server.
+type KeyringConfig struct {
+	// Provider is the name of the KEK provider. (aead, vault_transit, external, pkcs11)
+	Provider string `hcl:"provider"`
+
+	// Config is the configuration of the provider.
+	Config map[string]string `hcl:"config"`
+}
+
+// Copy returns a new copy of a KeyringConfig
+func (k *KeyringConfig) Copy() *KeyringConfig {
+	if k == nil {
+		return nil
+	}
+
+	nk := new(KeyringConfig)
+	*nk = *k
+	nk.Config = maps.Clone(k.Config)
+	return nk
+}
+
+// Merge returns the result of merging b into k. As the configuration is
+// specific to the provider, a block with a provider replaces the whole
+// configuration.
+func (k *KeyringConfig) Merge(b *KeyringConfig) *KeyringConfig {
+	if k == nil {
+		return b.Copy()
+	}
+	if b == nil {
+		return k.Copy()
+	}
+	if b.Provider != "" && b.Provider != k.Provider {
+		return b.Copy()
+	}
+
+	result := k.Copy()
+	if result.Config == nil && len(b.Config) > 0 {
+		result.Config = make(map[string]string, len(b.Config))
+	}
+	for key, value := range b.Config {
+		result.Config[key] = value
+	}
+	return result
+}
+
+// Validate returns an error if the keyring configuration is invalid.
+func (k *KeyringConfig) Validate() error {
+	if k == nil {
+		return nil
+	}
+
+	var required []string
+	switch k.Provider {
+	case "", KeyringProviderAEAD:
+	case KeyringProviderVaultTransit:
+		required = []string{"key_name"}
+	case KeyringProviderExternal:
+		required = []string{"command"}
+	case KeyringProviderPKCS11:
+		required = []string{"lib", "pin", "key_label"}
+		if k.Config["slot"] == "" && k.Config["token_label"] == "" {
+			return fmt.Errorf("keyring provider %q requires %q or %q in its config",
+				k.Provider, "slot", "token_label")
+		}
+	default:
+		return fmt.Errorf("unknown keyring provider %q", k.Provider)
+	}
+
+	for _, key := range required {
+		if k.Config[key] == "" {
+			return fmt.Errorf("keyring provider %q requires %q in its config", k.Provider, key)
+		}
+	}
+	return nil
+}
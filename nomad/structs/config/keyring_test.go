@@ -0,0 +1,121 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		config *KeyringConfig
+		expect string
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:   "aead",
+			config: &KeyringConfig{Provider: KeyringProviderAEAD},
+		},
+		{
+			name: "vault transit",
+			config: &KeyringConfig{
+				Provider: KeyringProviderVaultTransit,
+				Config:   map[string]string{"key_name": "nomad"},
+			},
+		},
+		{
+			name:   "vault transit without key",
+			config: &KeyringConfig{Provider: KeyringProviderVaultTransit},
+			expect: `keyring provider "vault_transit" requires "key_name" in its config`,
+		},
+		{
+			name:   "external without command",
+			config: &KeyringConfig{Provider: KeyringProviderExternal},
+			expect: `keyring provider "external" requires "command" in its config`,
+		},
+		{
+			name: "pkcs11",
+			config: &KeyringConfig{
+				Provider: KeyringProviderPKCS11,
+				Config: map[string]string{
+					"lib":         "/usr/lib/softhsm/libsofthsm2.so",
+					"token_label": "nomad",
+					"pin":         "1234",
+					"key_label":   "nomad-keyring",
+				},
+			},
+		},
+		{
+			name: "pkcs11 without slot",
+			config: &KeyringConfig{
+				Provider: KeyringProviderPKCS11,
+				Config: map[string]string{
+					"lib":       "/usr/lib/softhsm/libsofthsm2.so",
+					"pin":       "1234",
+					"key_label": "nomad-keyring",
+				},
+			},
+			expect: `keyring provider "pkcs11" requires "slot" or "token_label" in its config`,
+		},
+		{
+			name: "pkcs11 without key",
+			config: &KeyringConfig{
+				Provider: KeyringProviderPKCS11,
+				Config: map[string]string{
+					"lib":  "/usr/lib/softhsm/libsofthsm2.so",
+					"slot": "0",
+					"pin":  "1234",
+				},
+			},
+			expect: `keyring provider "pkcs11" requires "key_label" in its config`,
+		},
+		{
+			name:   "unknown provider",
+			config: &KeyringConfig{Provider: "awskms"},
+			expect: `unknown keyring provider "awskms"`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.expect == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expect)
+			}
+		})
+	}
+}
+
+func TestKeyringConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &KeyringConfig{
+		Provider: KeyringProviderVaultTransit,
+		Config:   map[string]string{"key_name": "nomad", "mount_path": "transit"},
+	}
+
+	// the config of the same provider is merged
+	result := a.Merge(&KeyringConfig{Config: map[string]string{"key_name": "other"}})
+	require.Equal(t, &KeyringConfig{
+		Provider: KeyringProviderVaultTransit,
+		Config:   map[string]string{"key_name": "other", "mount_path": "transit"},
+	}, result)
+	require.Equal(t, "nomad", a.Config["key_name"])
+
+	// another provider replaces the config
+	b := &KeyringConfig{
+		Provider: KeyringProviderExternal,
+		Config:   map[string]string{"command": "/usr/local/bin/kek"},
+	}
+	require.Equal(t, b, a.Merge(b))
+
+	require.Equal(t, a, (*KeyringConfig)(nil).Merge(a))
+	require.Equal(t, a, a.Merge(nil))
+}
@@ -140,6 +140,11 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, cDiff)
 	}
 
+	// Preemption diff
+	if pDiff := primitiveObjectDiff(j.Preemption, other.Preemption, nil, "Preemption", contextual); pDiff != nil {
+		diff.Objects = append(diff.Objects, pDiff)
+	}
+
 	// Multiregion diff
 	if mrDiff := multiregionDiff(j.Multiregion, other.Multiregion, contextual); mrDiff != nil {
 		diff.Objects = append(diff.Objects, mrDiff)
@@ -544,6 +549,31 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, tmplDiffs...)
 	}
 
+	// Identities diff
+	if idDiffs := workloadIdentityDiffs(t.Identities, other.Identities, contextual); idDiffs != nil {
+		diff.Objects = append(diff.Objects, idDiffs...)
+	}
+
+	// Env from variables diff
+	if eDiffs := primitiveObjectSetDiff(
+		interfaceSlice(t.EnvFromVariables),
+		interfaceSlice(other.EnvFromVariables),
+		nil,
+		"EnvFromVariable",
+		contextual); eDiffs != nil {
+		diff.Objects = append(diff.Objects, eDiffs...)
+	}
+
+	// File from variables diff
+	if fDiffs := primitiveObjectSetDiff(
+		interfaceSlice(t.FileFromVariables),
+		interfaceSlice(other.FileFromVariables),
+		nil,
+		"FileFromVariable",
+		contextual); fDiffs != nil {
+		diff.Objects = append(diff.Objects, fDiffs...)
+	}
+
 	return diff, nil
 }
 
@@ -1623,6 +1653,74 @@ func vaultDiff(old, new *Vault, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// workloadIdentityDiffs diffs a set of workload identities, matched by name.
+// If contextual diff is enabled, unchanged fields within the identities will
+// be returned.
+func workloadIdentityDiffs(old, new []*WorkloadIdentity, contextual bool) []*ObjectDiff {
+	oldMap := make(map[string]*WorkloadIdentity, len(old))
+	newMap := make(map[string]*WorkloadIdentity, len(new))
+	for _, o := range old {
+		oldMap[o.Name] = o
+	}
+	for _, n := range new {
+		newMap[n.Name] = n
+	}
+
+	var diffs []*ObjectDiff
+	for name, oldIdentity := range oldMap {
+		// Diff the same, deleted and edited
+		if diff := workloadIdentityDiff(oldIdentity, newMap[name], contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	for name, newIdentity := range newMap {
+		// Diff the added
+		if _, ok := oldMap[name]; !ok {
+			if diff := workloadIdentityDiff(nil, newIdentity, contextual); diff != nil {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
+// workloadIdentityDiff returns the diff of two workload identities. If
+// contextual diff is enabled, all fields will be returned, even if no diff
+// occurred.
+func workloadIdentityDiff(old, new *WorkloadIdentity, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Identity"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &WorkloadIdentity{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &WorkloadIdentity{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// Audience diffs
+	if setDiff := stringSetDiff(old.Audience, new.Audience, "Audience", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+
+	return diff
+}
+
 // waitConfigDiff returns the diff of two WaitConfig objects. If contextual diff is
 // enabled, all fields will be returned, even if no diff occurred.
 func waitConfigDiff(old, new *WaitConfig, contextual bool) *ObjectDiff {
@@ -2109,6 +2207,11 @@ func (r *Resources) Diff(other *Resources, contextual bool) *ObjectDiff {
 		diff.Objects = append(diff.Objects, nDiffs...)
 	}
 
+	// NUMA diff
+	if numaDiff := primitiveObjectDiff(r.NUMA, other.NUMA, nil, "NUMA", contextual); numaDiff != nil {
+		diff.Objects = append(diff.Objects, numaDiff)
+	}
+
 	return diff
 }
 
@@ -902,6 +902,12 @@ func TestJobDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Gang",
+								Old:  "",
+								New:  "false",
+							},
 						},
 					},
 					{
@@ -930,6 +936,49 @@ func TestJobDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Gang",
+								Old:  "false",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			// Preemption policy edited
+			Old: &Job{
+				Preemption: &PreemptionPolicy{
+					MaxPerHour: 2,
+				},
+			},
+			New: &Job{
+				Preemption: &PreemptionPolicy{
+					MaxPerHour:         4,
+					ProtectDeployments: true,
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Preemption",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "MaxPerHour",
+								Old:  "2",
+								New:  "4",
+							},
+							{
+								Type: DiffTypeEdited,
+								Name: "ProtectDeployments",
+								Old:  "false",
+								New:  "true",
+							},
 						},
 					},
 				},
@@ -6765,6 +6814,81 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "Identity added",
+			Old:  &Task{},
+			New: &Task{
+				Identities: []*WorkloadIdentity{{
+					Name:       "vault",
+					Audience:   []string{"vault.io"},
+					TTL:        time.Hour,
+					File:       true,
+					ChangeMode: "noop",
+					Claims:     map[string]string{"dc": "${node.datacenter}"},
+				}},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "Identity",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "ChangeMode",
+								Old:  "",
+								New:  "noop",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Claims[dc]",
+								Old:  "",
+								New:  "${node.datacenter}",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Env",
+								Old:  "",
+								New:  "false",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "File",
+								Old:  "",
+								New:  "true",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Name",
+								Old:  "",
+								New:  "vault",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "TTL",
+								Old:  "",
+								New:  "3600000000000",
+							},
+						},
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Audience",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Audience",
+										Old:  "",
+										New:  "vault.io",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "Vault added",
 			Old:  &Task{},
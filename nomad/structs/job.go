@@ -1,7 +1,10 @@
 package structs
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/go-set"
+	"golang.org/x/exp/maps"
 )
 
 const (
@@ -11,8 +14,202 @@ const (
 	// Args: JobServiceRegistrationsRequest
 	// Reply: JobServiceRegistrationsResponse
 	JobServiceRegistrationsRPCMethod = "Job.GetServiceRegistrations"
+
+	// JobGetSubmissionRPCMethod is the RPC method for reading the jobspec
+	// submitted to create a specific version of a job.
+	//
+	// Args: JobSubmissionRequest
+	// Reply: JobSubmissionResponse
+	JobGetSubmissionRPCMethod = "Job.GetJobSubmission"
+
+	// JobDiffRPCMethod is the RPC method for diffing two versions of a job,
+	// or a version of a job and a job which isn't registered.
+	//
+	// Args: JobDiffRequest
+	// Reply: JobDiffResponse
+	JobDiffRPCMethod = "Job.Diff"
+)
+
+const (
+	// JobSubmissionFormatHCL1 is the format of a jobspec parsed with the
+	// HCL1 parser
+	JobSubmissionFormatHCL1 = "hcl1"
+
+	// JobSubmissionFormatHCL2 is the format of a jobspec parsed with the
+	// HCL2 parser
+	JobSubmissionFormatHCL2 = "hcl2"
+
+	// JobSubmissionFormatJSON is the format of a jobspec submitted as JSON
+	JobSubmissionFormatJSON = "json"
+
+	// MaxJobSubmissionSize is the maximum size of the source and variables
+	// of a job submission. Larger submissions are dropped to avoid bloating
+	// the state store, while the job itself is still registered.
+	MaxJobSubmissionSize = 1024 * 1024
+
+	// JobSubmissionSensitiveValue replaces the value of the variables
+	// declared as sensitive, which are never stored.
+	JobSubmissionSensitiveValue = "<sensitive>"
 )
 
+// JobSubmission is the jobspec a version of a job was parsed from, as
+// submitted by the user.
+type JobSubmission struct {
+	// Source is the content of the jobspec
+	Source string
+
+	// Format is the format of the source: hcl1, hcl2 or json
+	Format string
+
+	// Variables are the values of the HCL2 input variables set from the
+	// command line, variable files or the environment, formatted as HCL
+	// expressions. The values of sensitive variables are redacted.
+	Variables map[string]string
+
+	// Includes are the sources of the files included by an HCL2 jobspec,
+	// keyed by their path relative to the directory of the jobspec
+	Includes map[string]string
+
+	// Namespace, JobID and Version identify the job version the
+	// submission belongs to and are set by the server
+	Namespace string
+	JobID     string
+	Version   uint64
+
+	// JobModifyIndex is the index of the job version
+	JobModifyIndex uint64
+}
+
+// Copy returns a deep copy of the submission.
+func (s *JobSubmission) Copy() *JobSubmission {
+	if s == nil {
+		return nil
+	}
+	ns := *s
+	ns.Variables = maps.Clone(s.Variables)
+	ns.Includes = maps.Clone(s.Includes)
+	return &ns
+}
+
+// Size returns the number of bytes of the source, variables and included
+// files.
+func (s *JobSubmission) Size() int {
+	size := len(s.Source)
+	for k, v := range s.Variables {
+		size += len(k) + len(v)
+	}
+	for k, v := range s.Includes {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+// Validate returns an error if the submission is invalid.
+func (s *JobSubmission) Validate() error {
+	switch s.Format {
+	case JobSubmissionFormatHCL1, JobSubmissionFormatHCL2, JobSubmissionFormatJSON:
+	default:
+		return fmt.Errorf("invalid job submission format %q", s.Format)
+	}
+	if s.Format != JobSubmissionFormatHCL2 && len(s.Variables) > 0 {
+		return fmt.Errorf("job submission variables are only supported with the %q format", JobSubmissionFormatHCL2)
+	}
+	if s.Format != JobSubmissionFormatHCL2 && len(s.Includes) > 0 {
+		return fmt.Errorf("job submission includes are only supported with the %q format", JobSubmissionFormatHCL2)
+	}
+	return nil
+}
+
+// JobSubmissionRequest is used to read the submission of a job version.
+type JobSubmissionRequest struct {
+	JobID   string
+	Version uint64
+	QueryOptions
+}
+
+// JobSubmissionResponse is the response to a JobSubmissionRequest.
+type JobSubmissionResponse struct {
+	Submission *JobSubmission
+	QueryMeta
+}
+
+// JobDiffRequest is used to diff two versions of a job, or a version of a
+// job and a job which isn't registered.
+type JobDiffRequest struct {
+	JobID string
+
+	// FromVersion is the version to diff from. Defaults to the version
+	// before ToVersion, or to the latest version when diffing Job.
+	FromVersion *uint64
+
+	// ToVersion is the version to diff to. Defaults to the latest version
+	// and can't be set along with Job.
+	ToVersion *uint64
+
+	// Job is the job to diff to instead of a version. It goes through the
+	// same admission controllers as the jobs being registered.
+	Job *Job
+
+	QueryOptions
+}
+
+// JobDiffResponse is the response to a JobDiffRequest.
+type JobDiffResponse struct {
+	Diff *JobDiff
+
+	// FromVersion is the version the diff is from. It is nil if the job has
+	// no version to diff from, in which case the whole job is added.
+	FromVersion *uint64
+
+	// ToVersion is the version the diff is to. It is nil when diffing to the
+	// job of the request.
+	ToVersion *uint64
+
+	// Warnings are the warnings of the admission controllers for the job of
+	// the request.
+	Warnings string
+
+	QueryMeta
+}
+
+// DispatchPayloadVariable is the variable a dispatched job reads its payload
+// from, in the namespace of the job.
+type DispatchPayloadVariable struct {
+	// Path is the path of the variable
+	Path string
+
+	// Item is the item of the variable to use as the payload. If empty, the
+	// payload is the JSON object of all the items of the variable.
+	Item string
+}
+
+// DispatchIdempotencyRecord records the job dispatched from a parameterized
+// job with an idempotency token, so retries of the dispatch return that job
+// even once it has been garbage collected.
+type DispatchIdempotencyRecord struct {
+	Namespace string
+	ParentID  string
+	Token     string
+
+	// JobID is the ID of the dispatched job
+	JobID string
+
+	// CreateTime is the submit time of the dispatched job, from which the
+	// record expires
+	CreateTime int64
+
+	CreateIndex uint64
+}
+
+// DispatchIdempotencyExpireRequest is used to delete the dispatch
+// idempotency records created before a time.
+type DispatchIdempotencyExpireRequest struct {
+	// Before is the Unix nanoseconds time before which the records are
+	// deleted
+	Before int64
+	WriteRequest
+}
+
 // JobServiceRegistrationsRequest is the request object used to list all
 // service registrations belonging to the specified Job.ID.
 type JobServiceRegistrationsRequest struct {
@@ -0,0 +1,227 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// JobTemplateUpsertRPCMethod is the RPC method for registering or
+	// updating a job template.
+	//
+	// Args: JobTemplateUpsertRequest
+	// Reply: JobTemplateUpsertResponse
+	JobTemplateUpsertRPCMethod = "JobTemplate.Upsert"
+
+	// JobTemplateDeleteRPCMethod is the RPC method for deleting a job
+	// template.
+	//
+	// Args: JobTemplateDeleteRequest
+	// Reply: JobTemplateDeleteResponse
+	JobTemplateDeleteRPCMethod = "JobTemplate.Delete"
+
+	// JobTemplateListRPCMethod is the RPC method for listing job templates.
+	//
+	// Args: JobTemplateListRequest
+	// Reply: JobTemplateListResponse
+	JobTemplateListRPCMethod = "JobTemplate.List"
+
+	// JobTemplateGetRPCMethod is the RPC method for reading a job template.
+	//
+	// Args: JobTemplateGetRequest
+	// Reply: JobTemplateGetResponse
+	JobTemplateGetRPCMethod = "JobTemplate.Get"
+
+	// JobTemplateRunRPCMethod is the RPC method for rendering a job template
+	// and registering the resulting job.
+	//
+	// Args: JobTemplateRunRequest
+	// Reply: JobTemplateRunResponse
+	JobTemplateRunRPCMethod = "JobTemplate.Run"
+)
+
+const (
+	// MaxJobTemplateSourceSize is the maximum size of the source of a job
+	// template.
+	MaxJobTemplateSourceSize = 1024 * 1024
+
+	// maxJobTemplateDescriptionLength limits the description length of a job
+	// template.
+	maxJobTemplateDescriptionLength = 256
+)
+
+var (
+	// validJobTemplateName is used to validate a job template name.
+	validJobTemplateName = regexp.MustCompile("^[a-zA-Z0-9-_.]{1,128}$")
+)
+
+// JobTemplate is an HCL2 jobspec stored on the servers, which can be run
+// with the values of its input variables to register a job.
+type JobTemplate struct {
+	// Name is the unique name of the template within its namespace.
+	Name string
+
+	// Namespace is the namespace of the template and of the jobs run from
+	// it.
+	Namespace string
+
+	// Description is a human readable description of the template.
+	Description string
+
+	// Source is the HCL2 jobspec of the template.
+	Source string
+
+	// Variables are the input variables declared by the source. They are
+	// set by the server when the template is registered.
+	Variables []*JobTemplateVariable
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobTemplateVariable is an input variable declared by a job template.
+type JobTemplateVariable struct {
+	Name        string
+	Description string
+
+	// Required is true if the variable has no default value and must be set
+	// when the template is run.
+	Required bool
+
+	// Sensitive is true if the value of the variable is redacted from the
+	// submission of the jobs run from the template.
+	Sensitive bool
+}
+
+// Copy returns a deep copy of the template.
+func (t *JobTemplate) Copy() *JobTemplate {
+	if t == nil {
+		return nil
+	}
+	nt := *t
+	if t.Variables != nil {
+		nt.Variables = make([]*JobTemplateVariable, len(t.Variables))
+		for i, v := range t.Variables {
+			nv := *v
+			nt.Variables[i] = &nv
+		}
+	}
+	return &nt
+}
+
+// Validate returns an error if the template is invalid.
+func (t *JobTemplate) Validate() error {
+	var mErr multierror.Error
+
+	if !validJobTemplateName.MatchString(t.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name %q", t.Name))
+	}
+	if t.Namespace == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing namespace"))
+	}
+	if len(t.Description) > maxJobTemplateDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxJobTemplateDescriptionLength))
+	}
+	if t.Source == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing source"))
+	} else if len(t.Source) > MaxJobTemplateSourceSize {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("source of %d bytes exceeds the maximum of %d bytes",
+			len(t.Source), MaxJobTemplateSourceSize))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Stub returns a summary of the template, without its source.
+func (t *JobTemplate) Stub() *JobTemplateListStub {
+	return &JobTemplateListStub{
+		Name:        t.Name,
+		Namespace:   t.Namespace,
+		Description: t.Description,
+		CreateIndex: t.CreateIndex,
+		ModifyIndex: t.ModifyIndex,
+	}
+}
+
+// JobTemplateListStub is the summary of a job template returned when listing
+// templates.
+type JobTemplateListStub struct {
+	Name        string
+	Namespace   string
+	Description string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobTemplateUpsertRequest is used to register or update a job template. The
+// namespace of the template is the namespace of the request.
+type JobTemplateUpsertRequest struct {
+	Template *JobTemplate
+	WriteRequest
+}
+
+// JobTemplateUpsertResponse is the response to a JobTemplateUpsertRequest.
+type JobTemplateUpsertResponse struct {
+	Template *JobTemplate
+	WriteMeta
+}
+
+// JobTemplateDeleteRequest is used to delete a job template.
+type JobTemplateDeleteRequest struct {
+	Name string
+	WriteRequest
+}
+
+// JobTemplateDeleteResponse is the response to a JobTemplateDeleteRequest.
+type JobTemplateDeleteResponse struct {
+	WriteMeta
+}
+
+// JobTemplateListRequest is used to list the job templates of a namespace,
+// or of all namespaces.
+type JobTemplateListRequest struct {
+	QueryOptions
+}
+
+// JobTemplateListResponse is the response to a JobTemplateListRequest.
+type JobTemplateListResponse struct {
+	Templates []*JobTemplateListStub
+	QueryMeta
+}
+
+// JobTemplateGetRequest is used to read a job template.
+type JobTemplateGetRequest struct {
+	Name string
+	QueryOptions
+}
+
+// JobTemplateGetResponse is the response to a JobTemplateGetRequest.
+type JobTemplateGetResponse struct {
+	Template *JobTemplate
+	QueryMeta
+}
+
+// JobTemplateRunRequest is used to render a job template with the values of
+// its input variables and register the resulting job.
+type JobTemplateRunRequest struct {
+	Name string
+
+	// Variables are the values of the input variables of the template, as
+	// they would be set with the -var flag of "job run".
+	Variables map[string]string
+
+	WriteRequest
+}
+
+// JobTemplateRunResponse is the response to a JobTemplateRunRequest.
+type JobTemplateRunResponse struct {
+	JobID           string
+	EvalID          string
+	EvalCreateIndex uint64
+	JobModifyIndex  uint64
+	Warnings        string
+	WriteMeta
+}
@@ -335,3 +335,28 @@ func TestJob_RequiredConsulServiceDiscovery(t *testing.T) {
 		})
 	}
 }
+
+func TestJobSubmission_Validate(t *testing.T) {
+	sub := &JobSubmission{Source: "{}", Format: JobSubmissionFormatJSON}
+	must.NoError(t, sub.Validate())
+
+	sub.Variables = map[string]string{"foo": `"bar"`}
+	must.StrContains(t, sub.Validate().Error(), "only supported with the \"hcl2\" format")
+
+	sub.Format = JobSubmissionFormatHCL2
+	must.NoError(t, sub.Validate())
+	must.Eq(t, 2+3+5, sub.Size())
+
+	sub.Includes = map[string]string{"a.hcl": "{}"}
+	must.NoError(t, sub.Validate())
+	must.Eq(t, 2+3+5+5+2, sub.Size())
+
+	sub.Format = JobSubmissionFormatJSON
+	sub.Variables = nil
+	must.StrContains(t, sub.Validate().Error(), "includes are only supported")
+
+	sub.Format = JobSubmissionFormatHCL2
+
+	sub.Format = "yaml"
+	must.StrContains(t, sub.Validate().Error(), "invalid job submission format")
+}
@@ -1,7 +1,11 @@
 package structs
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/nomad/helper"
@@ -13,6 +17,13 @@ import (
 type RootKey struct {
 	Meta *RootKeyMeta
 	Key  []byte // serialized to keystore as base64 blob
+
+	// RSAKey is the PKCS #1 DER encoded private key used to sign workload
+	// identities with RS256, which is more widely supported by third parties
+	// verifying them than EdDSA. It's empty for keys created by older
+	// versions of Nomad, which sign workload identities with an Ed25519 key
+	// derived from Key instead.
+	RSAKey []byte
 }
 
 // NewRootKey returns a new root key and its metadata.
@@ -33,6 +44,12 @@ func NewRootKey(algorithm EncryptionAlgorithm) (*RootKey, error) {
 		rootKey.Key = key
 	}
 
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate rsa key: %v", err)
+	}
+	rootKey.RSAKey = x509.MarshalPKCS1PrivateKey(rsaKey)
+
 	return rootKey, nil
 }
 
@@ -158,7 +175,14 @@ func (rkm *RootKeyMeta) Validate() error {
 type KeyEncryptionKeyWrapper struct {
 	Meta                       *RootKeyMeta
 	EncryptedDataEncryptionKey []byte `json:"DEK"`
-	KeyEncryptionKey           []byte `json:"KEK"`
+	EncryptedRSAKey            []byte `json:"RSAKey,omitempty"`
+	KeyEncryptionKey           []byte `json:"KEK,omitempty"`
+
+	// Provider is the name of the keyring provider that wrapped the key
+	// encryption key into WrappedKeyEncryptionKey. The key encryption key is
+	// stored unwrapped in KeyEncryptionKey if empty.
+	Provider                string `json:"Provider,omitempty"`
+	WrappedKeyEncryptionKey []byte `json:"WrappedKEK,omitempty"`
 }
 
 // EncryptionAlgorithm chooses which algorithm is used for
@@ -240,3 +264,73 @@ type KeyringDeleteRootKeyRequest struct {
 type KeyringDeleteRootKeyResponse struct {
 	WriteMeta
 }
+
+const (
+	// PubKeyAlgRS256 is the JWT signing algorithm of workload identities
+	// signed with the RSA key of a root key.
+	PubKeyAlgRS256 = "RS256"
+
+	// PubKeyAlgEdDSA is the JWT signing algorithm of workload identities
+	// signed with the Ed25519 key of a root key without an RSA key.
+	PubKeyAlgEdDSA = "EdDSA"
+
+	// PubKeyUseSig is the use of the public keys of the keyring, which is to
+	// verify signatures.
+	PubKeyUseSig = "sig"
+)
+
+// KeyringPublicKey is the public half of the key a root key signs workload
+// identities with. It's published so third parties can verify them.
+type KeyringPublicKey struct {
+	KeyID string
+
+	// PublicKey is the PKIX DER encoded public key
+	PublicKey []byte
+
+	// Algorithm is the JWT signing algorithm of the key
+	Algorithm string
+
+	// Use is the use of the key, which is always "sig"
+	Use string
+
+	// CreateTime is the time the root key was created
+	CreateTime int64
+}
+
+// KeyringListPublicResponse is the response value of the
+// Keyring.ListPublic RPC
+type KeyringListPublicResponse struct {
+	PublicKeys []*KeyringPublicKey
+	QueryMeta
+}
+
+// KeyringGetConfigResponse is the response value of the Keyring.GetConfig
+// RPC
+type KeyringGetConfigResponse struct {
+	OIDCDiscovery *OIDCDiscoveryConfig
+	QueryMeta
+}
+
+// OIDCDiscoveryConfig is the OpenID Connect discovery document of the
+// workload identities signed by the keyring, as described by
+// https://openid.net/specs/openid-connect-discovery-1_0.html
+type OIDCDiscoveryConfig struct {
+	Issuer        string   `json:"issuer"`
+	JWKS          string   `json:"jwks_uri"`
+	IDTokenAlgs   []string `json:"id_token_signing_alg_values_supported"`
+	ResponseTypes []string `json:"response_types_supported"`
+	Subjects      []string `json:"subject_types_supported"`
+}
+
+// NewOIDCDiscoveryConfig returns the OpenID Connect discovery document of
+// workload identities issued by the issuer, whose keys are served at the
+// /.well-known/jwks.json path of the issuer URL.
+func NewOIDCDiscoveryConfig(issuer string) *OIDCDiscoveryConfig {
+	return &OIDCDiscoveryConfig{
+		Issuer:        issuer,
+		JWKS:          strings.TrimSuffix(issuer, "/") + "/.well-known/jwks.json",
+		IDTokenAlgs:   []string{PubKeyAlgRS256, PubKeyAlgEdDSA},
+		ResponseTypes: []string{"code"},
+		Subjects:      []string{"public"},
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/base32"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash"
@@ -117,6 +118,11 @@ const (
 	RootKeyMetaDeleteRequestType                 MessageType = 52
 	ACLRolesUpsertRequestType                    MessageType = 53
 	ACLRolesDeleteByIDRequestType                MessageType = 54
+	JobTemplateUpsertRequestType                 MessageType = 55
+	JobTemplateDeleteRequestType                 MessageType = 56
+	DispatchIdempotencyExpireRequestType         MessageType = 57
+	VarExpireDeletedRequestType                  MessageType = 58
+	ACLTokenUsageUpsertRequestType               MessageType = 59
 
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
@@ -223,6 +229,8 @@ type RPCInfo interface {
 	// so Callers should readback TimeToBlock. E.g. you cannot set time to block at all on WriteRequests
 	// and it cannot exceed MaxBlockingRPCQueryTime
 	SetTimeToBlock(t time.Duration)
+	GetAuthToken() string
+	RequestSourceIP() string
 }
 
 // InternalRpcInfo allows adding internal RPC metadata to an RPC. This struct
@@ -230,6 +238,10 @@ type RPCInfo interface {
 type InternalRpcInfo struct {
 	// Forwarded marks whether the RPC has been forwarded.
 	Forwarded bool
+
+	// SourceIP is the IP address of the HTTP client the RPC is made on
+	// behalf of. It is used to track the usage of ACL tokens.
+	SourceIP string
 }
 
 // IsForwarded returns whether the RPC is forwarded from another server.
@@ -242,6 +254,12 @@ func (i *InternalRpcInfo) SetForwarded() {
 	i.Forwarded = true
 }
 
+// RequestSourceIP returns the IP address of the HTTP client the RPC is made
+// on behalf of, if any.
+func (i *InternalRpcInfo) RequestSourceIP() string {
+	return i.SourceIP
+}
+
 // QueryOptions is used to specify various flags for read queries
 type QueryOptions struct {
 	// The target region for this query
@@ -316,6 +334,12 @@ func (q QueryOptions) RequestRegion() string {
 	return q.Region
 }
 
+// GetAuthToken returns the secret ID or workload identity the query is made
+// with.
+func (q QueryOptions) GetAuthToken() string {
+	return q.AuthToken
+}
+
 // RequestNamespace returns the request's namespace or the default namespace if
 // no explicit namespace was sent.
 //
@@ -412,6 +436,12 @@ func (w WriteRequest) RequestRegion() string {
 	return w.Region
 }
 
+// GetAuthToken returns the secret ID or workload identity the write is made
+// with.
+func (w WriteRequest) GetAuthToken() string {
+	return w.AuthToken
+}
+
 // RequestNamespace returns the request's namespace or the default namespace if
 // no explicit namespace was sent.
 //
@@ -612,6 +642,9 @@ type JobRegisterRequest struct {
 	// Eval is the evaluation that is associated with the job registration
 	Eval *Evaluation
 
+	// Submission is the jobspec the job was parsed from, if known
+	Submission *JobSubmission
+
 	WriteRequest
 }
 
@@ -776,6 +809,10 @@ type JobDispatchRequest struct {
 	Meta    map[string]string
 	WriteRequest
 	IdPrefixTemplate string
+
+	// PayloadVariable is the variable to read the payload from, instead of
+	// inlining it in Payload
+	PayloadVariable *DispatchPayloadVariable
 }
 
 // JobValidateRequest is used to validate a job
@@ -1035,6 +1072,16 @@ type AllocsGetRequest struct {
 	QueryOptions
 }
 
+// AllocIdentitiesRequest is used by clients to renew the named workload
+// identities of a task. The AuthToken must be the SecretID of the node the
+// allocation is running on.
+type AllocIdentitiesRequest struct {
+	AllocID    string
+	TaskName   string
+	Identities []string
+	QueryOptions
+}
+
 // AllocRestartRequest is used to restart a specific allocations tasks.
 type AllocRestartRequest struct {
 	AllocID  string
@@ -1463,6 +1510,12 @@ type JobDispatchResponse struct {
 	EvalID          string
 	EvalCreateIndex uint64
 	JobCreateIndex  uint64
+
+	// AlreadyDispatched is true if a job was already dispatched with the
+	// idempotency token of the request, in which case DispatchedJobID is the
+	// ID of that job and no evaluation is created
+	AlreadyDispatched bool
+
 	WriteMeta
 }
 
@@ -1524,6 +1577,13 @@ type SingleAllocResponse struct {
 	QueryMeta
 }
 
+// AllocIdentitiesResponse returns the renewed workload identities of a task,
+// keyed by identity name.
+type AllocIdentitiesResponse struct {
+	SignedIdentities map[string]string
+	QueryMeta
+}
+
 // AllocsGetResponse is used to return a set of allocations
 type AllocsGetResponse struct {
 	Allocs []*Allocation
@@ -1605,6 +1665,18 @@ type EvalAllocationsResponse struct {
 	QueryMeta
 }
 
+// EvalExplainResponse is used to return the placement explanation for the
+// task groups of an evaluation
+type EvalExplainResponse struct {
+	EvalID string
+	JobID  string
+
+	// TaskGroups is the explanation for each task group the evaluation failed
+	// to place, keyed by task group name
+	TaskGroups map[string]*TaskGroupExplanation
+	QueryMeta
+}
+
 // PeriodicForceResponse is used to respond to a periodic job force launch
 type PeriodicForceResponse struct {
 	EvalID          string
@@ -2028,6 +2100,20 @@ func (n *Node) Sanitize() *Node {
 	return clean
 }
 
+// ACLAttributes returns the attributes of the node matched by the node rules
+// of ACL policies.
+func (n *Node) ACLAttributes() *acl.NodeAttributes {
+	if n == nil {
+		return nil
+	}
+	return &acl.NodeAttributes{
+		Name:       n.Name,
+		Class:      n.NodeClass,
+		Datacenter: n.Datacenter,
+		Meta:       n.Meta,
+	}
+}
+
 // Ready returns true if the node is ready for running allocations
 func (n *Node) Ready() bool {
 	return n.Status == NodeStatusReady && n.DrainStrategy == nil && n.SchedulingEligibility == NodeSchedulingEligible
@@ -2245,6 +2331,64 @@ type Resources struct {
 	IOPS        int // COMPAT(0.10): Only being used to issue warnings
 	Networks    Networks
 	Devices     ResourceDevices
+	NUMA        *NUMAResource
+}
+
+const (
+	// NUMAAffinityNone places reserved cores regardless of NUMA topology.
+	NUMAAffinityNone = "none"
+
+	// NUMAAffinityPrefer places reserved cores on a single NUMA node when
+	// possible, falling back to cores across NUMA nodes.
+	NUMAAffinityPrefer = "prefer"
+
+	// NUMAAffinityRequire places reserved cores on a single NUMA node or
+	// not at all.
+	NUMAAffinityRequire = "require"
+)
+
+// NUMAResource is used to request that the reserved cores of a task, and the
+// devices local to them, are placed on a single NUMA node.
+type NUMAResource struct {
+	// Affinity is one of "none", "prefer" or "require".
+	Affinity string
+}
+
+func (n *NUMAResource) Copy() *NUMAResource {
+	if n == nil {
+		return nil
+	}
+	nn := new(NUMAResource)
+	*nn = *n
+	return nn
+}
+
+func (n *NUMAResource) Equal(o *NUMAResource) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Affinity == o.Affinity
+}
+
+// Requested returns whether the NUMA topology must be considered when
+// reserving cores.
+func (n *NUMAResource) Requested() bool {
+	return n != nil && n.Affinity != "" && n.Affinity != NUMAAffinityNone
+}
+
+func (n *NUMAResource) Validate(cores int) error {
+	switch n.Affinity {
+	case "", NUMAAffinityNone:
+		return nil
+	case NUMAAffinityPrefer, NUMAAffinityRequire:
+		if cores == 0 {
+			return fmt.Errorf("NUMA affinity %q requires the task to reserve cores", n.Affinity)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid NUMA affinity %q, must be one of %q, %q or %q",
+			n.Affinity, NUMAAffinityNone, NUMAAffinityPrefer, NUMAAffinityRequire)
+	}
 }
 
 const (
@@ -2307,6 +2451,12 @@ func (r *Resources) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("MemoryMaxMB value (%d) should be larger than MemoryMB value (%d)", r.MemoryMaxMB, r.MemoryMB))
 	}
 
+	if r.NUMA != nil {
+		if err := r.NUMA.Validate(r.Cores); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -2334,6 +2484,9 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.NUMA != nil {
+		r.NUMA = other.NUMA
+	}
 }
 
 // Equal Resources.
@@ -2353,7 +2506,8 @@ func (r *Resources) Equal(o *Resources) bool {
 		r.DiskMB == o.DiskMB &&
 		r.IOPS == o.IOPS &&
 		r.Networks.Equal(&o.Networks) &&
-		r.Devices.Equal(&o.Devices)
+		r.Devices.Equal(&o.Devices) &&
+		r.NUMA.Equal(o.NUMA)
 }
 
 // ResourceDevices are part of Resources.
@@ -2441,6 +2595,8 @@ func (r *Resources) Copy() *Resources {
 		}
 	}
 
+	newR.NUMA = r.NUMA.Copy()
+
 	return newR
 }
 
@@ -3081,6 +3237,40 @@ type NodeCpuResources struct {
 	// This value is currently only reported on Linux platforms which support cgroups and is
 	// discovered by inspecting the cpuset of the agent's cgroup.
 	ReservableCpuCores []uint16
+
+	// NUMANodes is the set of NUMA nodes of the machine and the cpus local
+	// to each of them. This value is currently only reported on Linux.
+	NUMANodes []*NodeNUMANode
+
+	// ThreadSiblings groups the cpus sharing the same physical core. This
+	// value is currently only reported on Linux.
+	ThreadSiblings [][]uint16
+}
+
+// NodeNUMANode is a NUMA node of the machine.
+type NodeNUMANode struct {
+	// ID is the NUMA node ID as reported by the kernel.
+	ID uint16
+
+	// Cores is the set of cpus local to the NUMA node.
+	Cores []uint16
+}
+
+func (n *NodeNUMANode) Copy() *NodeNUMANode {
+	if n == nil {
+		return nil
+	}
+	nn := new(NodeNUMANode)
+	*nn = *n
+	nn.Cores = slices.Clone(n.Cores)
+	return nn
+}
+
+func (n *NodeNUMANode) Equal(o *NodeNUMANode) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.ID == o.ID && slices.Equal(n.Cores, o.Cores)
 }
 
 func (n NodeCpuResources) Copy() NodeCpuResources {
@@ -3090,6 +3280,20 @@ func (n NodeCpuResources) Copy() NodeCpuResources {
 		copy(newN.ReservableCpuCores, n.ReservableCpuCores)
 	}
 
+	if n.NUMANodes != nil {
+		newN.NUMANodes = make([]*NodeNUMANode, len(n.NUMANodes))
+		for i, numa := range n.NUMANodes {
+			newN.NUMANodes[i] = numa.Copy()
+		}
+	}
+
+	if n.ThreadSiblings != nil {
+		newN.ThreadSiblings = make([][]uint16, len(n.ThreadSiblings))
+		for i, siblings := range n.ThreadSiblings {
+			newN.ThreadSiblings[i] = slices.Clone(siblings)
+		}
+	}
+
 	return newN
 }
 
@@ -3109,6 +3313,14 @@ func (n *NodeCpuResources) Merge(o *NodeCpuResources) {
 	if len(o.ReservableCpuCores) != 0 {
 		n.ReservableCpuCores = o.ReservableCpuCores
 	}
+
+	if len(o.NUMANodes) != 0 {
+		n.NUMANodes = o.NUMANodes
+	}
+
+	if len(o.ThreadSiblings) != 0 {
+		n.ThreadSiblings = o.ThreadSiblings
+	}
 }
 
 func (n *NodeCpuResources) Equal(o *NodeCpuResources) bool {
@@ -3136,6 +3348,24 @@ func (n *NodeCpuResources) Equal(o *NodeCpuResources) bool {
 			return false
 		}
 	}
+
+	if len(n.NUMANodes) != len(o.NUMANodes) {
+		return false
+	}
+	for i := range n.NUMANodes {
+		if !n.NUMANodes[i].Equal(o.NUMANodes[i]) {
+			return false
+		}
+	}
+
+	if len(n.ThreadSiblings) != len(o.ThreadSiblings) {
+		return false
+	}
+	for i := range n.ThreadSiblings {
+		if !slices.Equal(n.ThreadSiblings[i], o.ThreadSiblings[i]) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -3399,6 +3629,10 @@ func (n *NodeDevice) Copy() *NodeDevice {
 type NodeDeviceLocality struct {
 	// PciBusID is the PCI Bus ID for the device.
 	PciBusID string
+
+	// NUMANode is the ID of the NUMA node local to the device, or nil if it
+	// is unknown.
+	NUMANode *uint16
 }
 
 func (n *NodeDeviceLocality) Equal(o *NodeDeviceLocality) bool {
@@ -3414,6 +3648,10 @@ func (n *NodeDeviceLocality) Equal(o *NodeDeviceLocality) bool {
 		return false
 	}
 
+	if !pointer.Eq(n.NUMANode, o.NUMANode) {
+		return false
+	}
+
 	return true
 }
 
@@ -3424,6 +3662,7 @@ func (n *NodeDeviceLocality) Copy() *NodeDeviceLocality {
 
 	// Copy the primitives
 	nn := *n
+	nn.NUMANode = pointer.Copy(n.NUMANode)
 	return &nn
 }
 
@@ -4118,6 +4357,10 @@ type Job struct {
 	// for dispatching.
 	ParameterizedJob *ParameterizedJobConfig
 
+	// Preemption controls whether and how often the allocations of this job
+	// may be preempted by higher priority jobs.
+	Preemption *PreemptionPolicy
+
 	// Dispatched is used to identify if the Job has been dispatched from a
 	// parameterized job.
 	Dispatched bool
@@ -4276,6 +4519,7 @@ func (j *Job) Copy() *Job {
 	nj.Periodic = nj.Periodic.Copy()
 	nj.Meta = maps.Clone(nj.Meta)
 	nj.ParameterizedJob = nj.ParameterizedJob.Copy()
+	nj.Preemption = nj.Preemption.Copy()
 	return nj
 }
 
@@ -4425,6 +4669,12 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if j.Preemption != nil {
+		if err := j.Preemption.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, multierror.Prefix(err, "Preemption:"))
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -4659,6 +4909,13 @@ func (j *Job) RequiredSignals() map[string]map[string][]string {
 				taskSignals[t.ChangeSignal] = struct{}{}
 			}
 
+			// Check if any file from a variable change mode uses signals
+			for _, f := range task.FileFromVariables {
+				if f.ChangeMode == VariableChangeModeSignal {
+					taskSignals[f.ChangeSignal] = struct{}{}
+				}
+			}
+
 			// Flatten and sort the signals
 			l := len(taskSignals)
 			if l == 0 {
@@ -5014,6 +5271,10 @@ type Namespace struct {
 	// Capabilities is the set of capabilities allowed for this namespace
 	Capabilities *NamespaceCapabilities
 
+	// Limits is the set of resource limits enforced on the allocations of
+	// the namespace
+	Limits *NamespaceLimits
+
 	// Meta is the set of metadata key/value pairs that attached to the namespace
 	Meta map[string]string
 
@@ -5033,6 +5294,114 @@ type NamespaceCapabilities struct {
 	DisabledTaskDrivers []string
 }
 
+// NamespaceLimits is the maximum amount of resources the non-terminal
+// allocations of a namespace may use. A zero value means the dimension is not
+// limited.
+type NamespaceLimits struct {
+	// CPU is the total CPU in MHz
+	CPU int
+
+	// MemoryMB is the total memory in MB
+	MemoryMB int
+
+	// Allocs is the number of allocations
+	Allocs int
+}
+
+func (l *NamespaceLimits) Copy() *NamespaceLimits {
+	if l == nil {
+		return nil
+	}
+	nl := *l
+	return &nl
+}
+
+func (l *NamespaceLimits) Equal(o *NamespaceLimits) bool {
+	if l == nil || o == nil {
+		return l == o
+	}
+	return *l == *o
+}
+
+func (l *NamespaceLimits) Validate() error {
+	var mErr multierror.Error
+	if l.CPU < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("cpu limit must be non-negative, got %d", l.CPU))
+	}
+	if l.MemoryMB < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("memory limit must be non-negative, got %d", l.MemoryMB))
+	}
+	if l.Allocs < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("allocs limit must be non-negative, got %d", l.Allocs))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Exceeded returns a description of the first limit the usage goes over, or
+// an empty string if the usage is within the limits.
+func (l *NamespaceLimits) Exceeded(usage *NamespaceUsage) string {
+	switch {
+	case l == nil:
+		return ""
+	case l.CPU > 0 && usage.CPU > l.CPU:
+		return fmt.Sprintf("cpu exhausted (%d > %d)", usage.CPU, l.CPU)
+	case l.MemoryMB > 0 && usage.MemoryMB > l.MemoryMB:
+		return fmt.Sprintf("memory exhausted (%d > %d)", usage.MemoryMB, l.MemoryMB)
+	case l.Allocs > 0 && usage.Allocs > l.Allocs:
+		return fmt.Sprintf("allocs exhausted (%d > %d)", usage.Allocs, l.Allocs)
+	}
+	return ""
+}
+
+// NamespaceUsage is the amount of resources used by the non-terminal
+// allocations of a namespace.
+type NamespaceUsage struct {
+	Namespace string
+	CPU       int
+	MemoryMB  int
+	Allocs    int
+}
+
+func (u *NamespaceUsage) Copy() *NamespaceUsage {
+	if u == nil {
+		return nil
+	}
+	nu := *u
+	return &nu
+}
+
+// Add adds the resources of the allocation to the usage.
+func (u *NamespaceUsage) Add(alloc *Allocation) {
+	cpu, mem := allocUsage(alloc)
+	u.CPU += cpu
+	u.MemoryMB += mem
+	u.Allocs++
+}
+
+// Subtract removes the resources of the allocation from the usage.
+func (u *NamespaceUsage) Subtract(alloc *Allocation) {
+	cpu, mem := allocUsage(alloc)
+	u.CPU -= cpu
+	u.MemoryMB -= mem
+	u.Allocs--
+}
+
+// allocUsage returns the CPU and memory counted against the limits of the
+// namespace of the allocation.
+func allocUsage(alloc *Allocation) (cpu, mem int) {
+	if alloc.AllocatedResources == nil && alloc.Resources == nil && alloc.TaskResources == nil {
+		return 0, 0
+	}
+	r := alloc.ComparableResources()
+	return int(r.Flattened.Cpu.CpuShares), int(r.Flattened.Memory.MemoryMB)
+}
+
+// NamespaceUsageResponse is used to return the usage of a namespace
+type NamespaceUsageResponse struct {
+	Usage *NamespaceUsage
+	QueryMeta
+}
+
 func (n *Namespace) Validate() error {
 	var mErr multierror.Error
 
@@ -5045,6 +5414,11 @@ func (n *Namespace) Validate() error {
 		err := fmt.Errorf("description longer than %d", maxNamespaceDescriptionLength)
 		mErr.Errors = append(mErr.Errors, err)
 	}
+	if n.Limits != nil {
+		if err := n.Limits.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
 
 	return mErr.ErrorOrNil()
 }
@@ -5069,6 +5443,11 @@ func (n *Namespace) SetHash() []byte {
 			_, _ = hash.Write([]byte(driver))
 		}
 	}
+	if n.Limits != nil {
+		_, _ = hash.Write([]byte(strconv.Itoa(n.Limits.CPU)))
+		_, _ = hash.Write([]byte(strconv.Itoa(n.Limits.MemoryMB)))
+		_, _ = hash.Write([]byte(strconv.Itoa(n.Limits.Allocs)))
+	}
 
 	// sort keys to ensure hash stability when meta is stored later
 	var keys []string
@@ -5101,6 +5480,7 @@ func (n *Namespace) Copy() *Namespace {
 		c.DisabledTaskDrivers = slices.Clone(n.Capabilities.DisabledTaskDrivers)
 		nc.Capabilities = c
 	}
+	nc.Limits = n.Limits.Copy()
 	if n.Meta != nil {
 		nc.Meta = make(map[string]string, len(n.Meta))
 		for k, v := range n.Meta {
@@ -5381,6 +5761,38 @@ func (d *ParameterizedJobConfig) Copy() *ParameterizedJobConfig {
 	return nd
 }
 
+// PreemptionPolicy is used to protect the allocations of a job from being
+// preempted by higher priority jobs.
+type PreemptionPolicy struct {
+	// NeverPreempt prevents the allocations of the job from ever being
+	// preempted, regardless of the priority of other jobs.
+	NeverPreempt bool
+
+	// MaxPerHour is the maximum number of allocations of the job that may be
+	// preempted within an hour. Zero means there is no limit.
+	MaxPerHour int
+
+	// ProtectDeployments prevents the allocations of the job from being
+	// preempted while a deployment of the job is in progress.
+	ProtectDeployments bool
+}
+
+func (p *PreemptionPolicy) Validate() error {
+	if p.MaxPerHour < 0 {
+		return fmt.Errorf("MaxPerHour must be >= 0; got %d", p.MaxPerHour)
+	}
+	return nil
+}
+
+func (p *PreemptionPolicy) Copy() *PreemptionPolicy {
+	if p == nil {
+		return nil
+	}
+	np := new(PreemptionPolicy)
+	*np = *p
+	return np
+}
+
 // DispatchedID returns an ID appropriate for a job dispatched against a
 // particular parameterized job
 func DispatchedID(templateID, idPrefixTemplate string, t time.Time) string {
@@ -6193,6 +6605,10 @@ type TaskGroup struct {
 	// MaxClientDisconnect, if set, configures the client to allow placed
 	// allocations for tasks in this group to attempt to resume running without a restart.
 	MaxClientDisconnect *time.Duration
+
+	// Gang requires all the allocations of the task group placed by an
+	// evaluation to be placed together or not at all.
+	Gang bool
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -6343,6 +6759,10 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, errors.New("max_client_disconnect cannot be negative"))
 	}
 
+	if tg.Gang && (j.Type == JobTypeSystem || j.Type == JobTypeSysBatch) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Gang scheduling is not supported for %q jobs", j.Type))
+	}
+
 	for idx, constr := range tg.Constraints {
 		if err := constr.Validate(); err != nil {
 			outer := fmt.Errorf("Constraint %d validation failed: %s", idx+1, err)
@@ -7063,6 +7483,18 @@ type Task struct {
 
 	// CSIPluginConfig is used to configure the plugin supervisor for the task.
 	CSIPluginConfig *TaskCSIPluginConfig
+
+	// Identities are the named workload identities of the task, signed in
+	// addition to the default identity.
+	Identities []*WorkloadIdentity
+
+	// EnvFromVariables are the variables whose items are exposed to the task
+	// as environment variables.
+	EnvFromVariables []*EnvFromVariable
+
+	// FileFromVariables are the variable items written to files in the
+	// task's secrets directory.
+	FileFromVariables []*FileFromVariable
 }
 
 // UsesConnect is for conveniently detecting if the Task is able to make use
@@ -7146,6 +7578,30 @@ func (t *Task) Copy() *Task {
 		nt.Templates = templates
 	}
 
+	if t.Identities != nil {
+		identities := make([]*WorkloadIdentity, len(t.Identities))
+		for i, wi := range nt.Identities {
+			identities[i] = wi.Copy()
+		}
+		nt.Identities = identities
+	}
+
+	if t.EnvFromVariables != nil {
+		envs := make([]*EnvFromVariable, len(t.EnvFromVariables))
+		for i, e := range t.EnvFromVariables {
+			envs[i] = e.Copy()
+		}
+		nt.EnvFromVariables = envs
+	}
+
+	if t.FileFromVariables != nil {
+		files := make([]*FileFromVariable, len(t.FileFromVariables))
+		for i, f := range t.FileFromVariables {
+			files[i] = f.Copy()
+		}
+		nt.FileFromVariables = files
+	}
+
 	return nt
 }
 
@@ -7190,6 +7646,18 @@ func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 	for _, template := range t.Templates {
 		template.Canonicalize()
 	}
+
+	for _, wi := range t.Identities {
+		wi.Canonicalize()
+	}
+
+	for _, e := range t.EnvFromVariables {
+		e.Canonicalize()
+	}
+
+	for _, f := range t.FileFromVariables {
+		f.Canonicalize()
+	}
 }
 
 func (t *Task) GoString() string {
@@ -7286,6 +7754,43 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 		}
 	}
 
+	identities := make(map[string]struct{}, len(t.Identities))
+	for idx, wi := range t.Identities {
+		if wi == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Identity %d is empty", idx+1))
+			continue
+		}
+		if err := wi.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Identity %q validation failed: %v", wi.Name, err))
+		}
+		if _, ok := identities[wi.Name]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Identity %q is defined more than once", wi.Name))
+		}
+		identities[wi.Name] = struct{}{}
+	}
+
+	for idx, e := range t.EnvFromVariables {
+		if err := e.Validate(); err != nil {
+			outer := fmt.Errorf("Env from variable %d validation failed: %s", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
+	fileDestinations := make(map[string]int, len(t.FileFromVariables))
+	for idx, f := range t.FileFromVariables {
+		if err := f.Validate(); err != nil {
+			outer := fmt.Errorf("File from variable %d validation failed: %s", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+
+		if other, ok := fileDestinations[f.Destination]; ok {
+			outer := fmt.Errorf("File from variable %d has same destination as %d", idx+1, other)
+			mErr.Errors = append(mErr.Errors, outer)
+		} else {
+			fileDestinations[f.Destination] = idx + 1
+		}
+	}
+
 	destinations := make(map[string]int, len(t.Templates))
 	for idx, tmpl := range t.Templates {
 		if err := tmpl.Validate(); err != nil {
@@ -9732,9 +10237,14 @@ type Allocation struct {
 	// to stop running because it got preempted
 	PreemptedByAllocation string
 
+	// PreemptedTime is the time the allocation was preempted, in Unix
+	// nanoseconds. It's set along with PreemptedByAllocation.
+	PreemptedTime int64
+
 	// SignedIdentities is a map of task names to signed identity/capability
-	// claim tokens for those tasks. If needed, it is populated in the plan
-	// applier.
+	// claim tokens for those tasks. The named workload identities of a task
+	// are keyed by WorkloadIdentityKey. If needed, it is populated in the
+	// plan applier.
 	SignedIdentities map[string]string `json:"-"`
 
 	// SigningKeyID is the key used to sign the SignedIdentities field.
@@ -10471,9 +10981,39 @@ type IdentityClaims struct {
 	AllocationID string `json:"nomad_allocation_id"`
 	TaskName     string `json:"nomad_task"`
 
+	// IdentityName is the name of the workload identity the claims were
+	// signed for. It is empty for the default identity of a task, which is
+	// the only identity that may be used to authenticate to Nomad.
+	IdentityName string `json:"nomad_identity,omitempty"`
+
+	// ExtraClaims are the extra claims of a named workload identity. They
+	// are added to the top level of the token when it is signed.
+	ExtraClaims map[string]string `json:"-"`
+
 	jwt.RegisteredClaims
 }
 
+// MarshalJSON flattens the extra claims into the encoded claims. Extra
+// claims never override the claims set by Nomad.
+func (c *IdentityClaims) MarshalJSON() ([]byte, error) {
+	type alias IdentityClaims
+	buf, err := json.Marshal((*alias)(c))
+	if err != nil || len(c.ExtraClaims) == 0 {
+		return buf, err
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(buf, &claims); err != nil {
+		return nil, err
+	}
+	for k, v := range c.ExtraClaims {
+		if _, ok := claims[k]; !ok {
+			claims[k] = v
+		}
+	}
+	return json.Marshal(claims)
+}
+
 // AllocationDiff is another named type for Allocation (to use the same fields),
 // which is used to represent the delta for an Allocation. If you need a method
 // defined on the al
@@ -10748,6 +11288,59 @@ func (a *AllocMetric) MaxNormScore() *NodeScoreMeta {
 	return a.ScoreMetaData[0]
 }
 
+// TaskGroupExplanation describes why a task group can or can not be placed
+// on each node of the cluster. It is computed by re-running feasibility
+// checking and ranking against the current state without creating a plan.
+type TaskGroupExplanation struct {
+	TaskGroup string
+
+	// Previous is the metric recorded by the last scheduling attempt of the
+	// evaluation, if any.
+	Previous *AllocMetric
+
+	// Metrics aggregates the results of checking every node.
+	Metrics *AllocMetric
+
+	// Nodes is the per-node breakdown, sorted so the node that came closest
+	// to fitting the task group is first.
+	Nodes []*NodeExplanation
+}
+
+// BestNode returns the node that came closest to fitting the task group.
+func (t *TaskGroupExplanation) BestNode() *NodeExplanation {
+	if t == nil || len(t.Nodes) == 0 {
+		return nil
+	}
+	return t.Nodes[0]
+}
+
+// NodeExplanation describes the result of checking whether a task group fits
+// a single node.
+type NodeExplanation struct {
+	NodeID     string
+	NodeName   string
+	NodeClass  string
+	Datacenter string
+
+	// Feasible is true if the task group could be placed on the node.
+	Feasible bool
+
+	// FilteredBy is the constraint or check that filtered the node out.
+	FilteredBy string
+
+	// ExhaustedDimension is the resource dimension, device or port that was
+	// exhausted on the node.
+	ExhaustedDimension string
+
+	// Score is the normalized score of a feasible node.
+	Score float64
+
+	// CPUShortfall and MemoryShortfall are the amount of CPU in MHz and
+	// memory in MB missing on an exhausted node for the task group to fit.
+	CPUShortfall    int64
+	MemoryShortfall int64
+}
+
 // NodeScoreMeta captures scoring meta data derived from
 // different scoring factors.
 type NodeScoreMeta struct {
@@ -10957,6 +11550,10 @@ const (
 	// active key
 	CoreJobVariablesRekey = "variables-rekey"
 
+	// CoreJobVariablesGC is used to purge the versions of variables that were
+	// deleted before the variables GC threshold.
+	CoreJobVariablesGC = "variables-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -11565,6 +12162,25 @@ func (p *Plan) PopUpdate(alloc *Allocation) {
 	}
 }
 
+// RemoveUpdate removes the most recent update of the allocation from the
+// plan. Unlike PopUpdate, the update doesn't need to be the last one of the
+// node.
+func (p *Plan) RemoveUpdate(alloc *Allocation) {
+	existing := p.NodeUpdate[alloc.NodeID]
+	for i := len(existing) - 1; i >= 0; i-- {
+		if existing[i].ID != alloc.ID {
+			continue
+		}
+		existing = append(existing[:i:i], existing[i+1:]...)
+		if len(existing) > 0 {
+			p.NodeUpdate[alloc.NodeID] = existing
+		} else {
+			delete(p.NodeUpdate, alloc.NodeID)
+		}
+		return
+	}
+}
+
 // AppendAlloc appends the alloc to the plan allocations.
 // Uses the passed job if explicitly passed, otherwise
 // it is assumed the alloc will use the plan Job version.
@@ -11678,6 +12294,10 @@ type PlanAnnotations struct {
 
 	// PreemptedAllocs is the set of allocations to be preempted to make the placement successful.
 	PreemptedAllocs []*AllocListStub
+
+	// PreemptionReasons explains why each of the PreemptedAllocs would be
+	// preempted, keyed by allocation ID.
+	PreemptionReasons map[string]string
 }
 
 // DesiredUpdates is the set of changes the scheduler would like to make given
@@ -12013,6 +12633,21 @@ type ACLToken struct {
 	// creation. This is a string version of a time.Duration like "2m".
 	ExpirationTTL time.Duration
 
+	// Disabled is set by the leader on the local client tokens that were not
+	// used within the idle timeout. Disabled tokens are rejected like expired
+	// tokens, but they are kept and can be enabled again by an operator.
+	Disabled bool
+
+	// EnableTime is when the token was last enabled after being disabled. The
+	// idle timeout of the token restarts from it. It is set by the servers.
+	EnableTime *time.Time
+
+	// LastUsedTime and LastUsedIP detail when and from where the token was
+	// last used. They are populated from the token usage when the token is
+	// read and are never stored with the token.
+	LastUsedTime *time.Time
+	LastUsedIP   string
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -12072,10 +12707,23 @@ type ACLTokenListStub struct {
 	Hash           []byte
 	CreateTime     time.Time
 	ExpirationTime *time.Time
+	Disabled       bool
+	LastUsedTime   *time.Time
+	LastUsedIP     string
 	CreateIndex    uint64
 	ModifyIndex    uint64
 }
 
+// SetUsage populates the last used fields of the token stub from the usage of
+// its token.
+func (a *ACLTokenListStub) SetUsage(usage *ACLTokenUsage) {
+	if usage == nil {
+		return
+	}
+	a.LastUsedTime = pointer.Of(usage.LastUsedTime)
+	a.LastUsedIP = usage.LastUsedIP
+}
+
 // SetHash is used to compute and set the hash of the ACL token. It only hashes
 // fields which can be updated, and as such, does not hash fields such as
 // ExpirationTime.
@@ -12097,6 +12745,9 @@ func (a *ACLToken) SetHash() []byte {
 	} else {
 		_, _ = hash.Write([]byte("local"))
 	}
+	if a.Disabled {
+		_, _ = hash.Write([]byte("disabled"))
+	}
 
 	// Iterate the ACL role links and hash the ID. The ID is immutable and the
 	// canonical way to reference a role. The name can be modified by
@@ -12124,6 +12775,9 @@ func (a *ACLToken) Stub() *ACLTokenListStub {
 		Hash:           a.Hash,
 		CreateTime:     a.CreateTime,
 		ExpirationTime: a.ExpirationTime,
+		Disabled:       a.Disabled,
+		LastUsedTime:   a.LastUsedTime,
+		LastUsedIP:     a.LastUsedIP,
 		CreateIndex:    a.CreateIndex,
 		ModifyIndex:    a.ModifyIndex,
 	}
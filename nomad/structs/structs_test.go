@@ -5829,6 +5829,18 @@ func TestParameterizedJobConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestPreemptionPolicy_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	p := &PreemptionPolicy{MaxPerHour: -1}
+	err := p.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "MaxPerHour")
+
+	p.MaxPerHour = 5
+	require.NoError(t, p.Validate())
+}
+
 func TestParameterizedJobConfig_Validate_NonBatch(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5891,6 +5903,27 @@ func TestJobConfig_Validate_MaxClientDisconnect(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestJobConfig_Validate_Gang(t *testing.T) {
+	ci.Parallel(t)
+
+	// Gang task groups are valid for service and batch jobs
+	job := testJob()
+	job.TaskGroups[0].Gang = true
+	require.NoError(t, job.Validate())
+
+	job.Type = JobTypeBatch
+	require.NoError(t, job.Validate())
+
+	// Gang task groups are not valid for system jobs
+	job.Type = JobTypeSystem
+	job.Update = UpdateStrategy{}
+	job.TaskGroups[0].Update = nil
+	job.TaskGroups[0].ReschedulePolicy = nil
+	err := job.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Gang scheduling is not supported for "system" jobs`)
+}
+
 func TestParameterizedJobConfig_Canonicalize(t *testing.T) {
 	ci.Parallel(t)
 
@@ -7157,3 +7190,46 @@ func requireErrors(t *testing.T, err error, expected ...string) {
 
 	require.Equal(t, expected, found)
 }
+
+func TestNUMAResource_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		affinity string
+		cores    int
+		expErr   string
+	}{
+		{affinity: "", cores: 0},
+		{affinity: NUMAAffinityNone, cores: 0},
+		{affinity: NUMAAffinityPrefer, cores: 2},
+		{affinity: NUMAAffinityRequire, cores: 0, expErr: "requires the task to reserve cores"},
+		{affinity: "bogus", cores: 2, expErr: "invalid NUMA affinity"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.affinity, func(t *testing.T) {
+			err := (&NUMAResource{Affinity: tc.affinity}).Validate(tc.cores)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestNamespaceLimits_Exceeded(t *testing.T) {
+	ci.Parallel(t)
+
+	limits := &NamespaceLimits{CPU: 1000, Allocs: 2}
+
+	require.Empty(t, limits.Exceeded(&NamespaceUsage{CPU: 1000, MemoryMB: 1 << 20, Allocs: 2}))
+	require.Equal(t, "cpu exhausted (1001 > 1000)", limits.Exceeded(&NamespaceUsage{CPU: 1001}))
+	require.Equal(t, "allocs exhausted (3 > 2)", limits.Exceeded(&NamespaceUsage{Allocs: 3}))
+
+	var unlimited *NamespaceLimits
+	require.Empty(t, unlimited.Exceeded(&NamespaceUsage{CPU: 1 << 20}))
+
+	ns := &Namespace{Name: "foo", Limits: &NamespaceLimits{MemoryMB: -1}}
+	require.ErrorContains(t, ns.Validate(), "memory limit must be non-negative")
+}
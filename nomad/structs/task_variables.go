@@ -0,0 +1,175 @@
+package structs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// VariableChangeModeNoop takes no action when a variable consumed by a
+	// task is updated.
+	VariableChangeModeNoop = "noop"
+
+	// VariableChangeModeSignal signals the task when a variable consumed by
+	// the task is updated.
+	VariableChangeModeSignal = "signal"
+
+	// VariableChangeModeRestart restarts the task when a variable consumed by
+	// the task is updated.
+	VariableChangeModeRestart = "restart"
+)
+
+// EnvFromVariable exposes the items of a variable to a task as environment
+// variables. The variable is read with the workload identity of the task
+// from the job's namespace.
+type EnvFromVariable struct {
+	// Path is the path of the variable.
+	Path string
+
+	// Prefix is prepended to the name of each item to build the name of its
+	// environment variable.
+	Prefix string
+
+	// ChangeMode is used to configure the task's behavior when the variable
+	// is updated. Environment variables can only be updated by restarting
+	// the task.
+	ChangeMode string
+}
+
+// Copy returns a copy of this EnvFromVariable.
+func (e *EnvFromVariable) Copy() *EnvFromVariable {
+	if e == nil {
+		return nil
+	}
+	ne := new(EnvFromVariable)
+	*ne = *e
+	return ne
+}
+
+// Canonicalize sets the defaults of the EnvFromVariable.
+func (e *EnvFromVariable) Canonicalize() {
+	if e.ChangeMode == "" {
+		e.ChangeMode = VariableChangeModeRestart
+	}
+}
+
+// Validate returns if the EnvFromVariable is valid.
+func (e *EnvFromVariable) Validate() error {
+	var mErr multierror.Error
+	if e.Path == "" {
+		_ = multierror.Append(&mErr, fmt.Errorf("Must specify a variable path"))
+	}
+
+	switch e.ChangeMode {
+	case VariableChangeModeNoop, VariableChangeModeRestart:
+	case VariableChangeModeSignal:
+		_ = multierror.Append(&mErr, fmt.Errorf("Cannot use signals with environment variables"))
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Unknown change mode %q", e.ChangeMode))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// EnvName returns the name of the environment variable of the item of the
+// variable with the given key. Keys are upper-cased and characters that are
+// not valid in environment variable names are replaced with underscores.
+func (e *EnvFromVariable) EnvName(key string) string {
+	return e.Prefix + strings.ToUpper(helper.CleanEnvVar(key, '_'))
+}
+
+// FileFromVariable writes an item of a variable to a file in the secrets
+// directory of a task. The variable is read with the workload identity of
+// the task from the job's namespace.
+type FileFromVariable struct {
+	// Path is the path of the variable.
+	Path string
+
+	// Item is the key of the item written to the file.
+	Item string
+
+	// Destination is the path of the file, relative to the task's secrets
+	// directory.
+	Destination string
+
+	// Perms is the permission the file should be written out with.
+	Perms string
+
+	// ChangeMode is used to configure the task's behavior when the variable
+	// is updated.
+	ChangeMode string
+
+	// ChangeSignal is the signal sent to the task when the variable is
+	// updated. This is only valid when using the signal change mode.
+	ChangeSignal string
+}
+
+// Copy returns a copy of this FileFromVariable.
+func (f *FileFromVariable) Copy() *FileFromVariable {
+	if f == nil {
+		return nil
+	}
+	nf := new(FileFromVariable)
+	*nf = *f
+	return nf
+}
+
+// Canonicalize sets the defaults of the FileFromVariable.
+func (f *FileFromVariable) Canonicalize() {
+	if f.ChangeSignal != "" {
+		f.ChangeSignal = strings.ToUpper(f.ChangeSignal)
+	}
+	if f.ChangeMode == "" {
+		f.ChangeMode = VariableChangeModeRestart
+	}
+	if f.Perms == "" {
+		f.Perms = "0644"
+	}
+}
+
+// Validate returns if the FileFromVariable is valid.
+func (f *FileFromVariable) Validate() error {
+	var mErr multierror.Error
+	if f.Path == "" {
+		_ = multierror.Append(&mErr, fmt.Errorf("Must specify a variable path"))
+	}
+	if f.Item == "" {
+		_ = multierror.Append(&mErr, fmt.Errorf("Must specify a variable item"))
+	}
+
+	// Verify the destination stays in the secrets directory
+	if f.Destination == "" {
+		_ = multierror.Append(&mErr, fmt.Errorf("Must specify a destination for the file"))
+	} else if dest := filepath.Clean(f.Destination); filepath.IsAbs(dest) ||
+		dest == "." || dest == ".." || strings.HasPrefix(dest, ".."+string(filepath.Separator)) {
+		_ = multierror.Append(&mErr, fmt.Errorf("Destination must be a file path relative to the secrets directory"))
+	}
+
+	if f.Perms != "" {
+		if _, err := strconv.ParseUint(f.Perms, 8, 12); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Failed to parse %q as octal: %v", f.Perms, err))
+		}
+	}
+
+	switch f.ChangeMode {
+	case VariableChangeModeSignal:
+		if f.ChangeSignal == "" {
+			_ = multierror.Append(&mErr, fmt.Errorf("Signal must be specified when using change mode %q", VariableChangeModeSignal))
+		}
+	case VariableChangeModeNoop, VariableChangeModeRestart:
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Unknown change mode %q", f.ChangeMode))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// DiffID fulfills the DiffableWithID interface.
+func (f *FileFromVariable) DiffID() string {
+	return f.Destination
+}
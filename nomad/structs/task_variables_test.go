@@ -0,0 +1,102 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvFromVariable_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	e := &EnvFromVariable{Path: "nomad/jobs/db"}
+	e.Canonicalize()
+	require.Equal(t, VariableChangeModeRestart, e.ChangeMode)
+	require.NoError(t, e.Validate())
+
+	e.ChangeMode = VariableChangeModeSignal
+	require.ErrorContains(t, e.Validate(), "Cannot use signals with environment variables")
+
+	e = &EnvFromVariable{ChangeMode: "reload"}
+	err := e.Validate()
+	require.ErrorContains(t, err, "Must specify a variable path")
+	require.ErrorContains(t, err, `Unknown change mode "reload"`)
+}
+
+func TestEnvFromVariable_EnvName(t *testing.T) {
+	ci.Parallel(t)
+
+	e := &EnvFromVariable{Prefix: "DB_"}
+	require.Equal(t, "DB_PASSWORD", e.EnvName("password"))
+	require.Equal(t, "DB_TLS_CERT", e.EnvName("tls-cert"))
+	require.Equal(t, "DB_TLS.KEY", e.EnvName("tls.key"))
+}
+
+func TestFileFromVariable_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	valid := func() *FileFromVariable {
+		f := &FileFromVariable{
+			Path:        "nomad/jobs/db",
+			Item:        "cert",
+			Destination: "db/cert.pem",
+		}
+		f.Canonicalize()
+		return f
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(*FileFromVariable)
+		errMsg string
+	}{
+		{
+			name:   "valid",
+			modify: func(*FileFromVariable) {},
+		},
+		{
+			name:   "missing item",
+			modify: func(f *FileFromVariable) { f.Item = "" },
+			errMsg: "Must specify a variable item",
+		},
+		{
+			name:   "missing destination",
+			modify: func(f *FileFromVariable) { f.Destination = "" },
+			errMsg: "Must specify a destination for the file",
+		},
+		{
+			name:   "absolute destination",
+			modify: func(f *FileFromVariable) { f.Destination = "/etc/passwd" },
+			errMsg: "Destination must be a file path relative to the secrets directory",
+		},
+		{
+			name:   "escaping destination",
+			modify: func(f *FileFromVariable) { f.Destination = "db/../../local/cert.pem" },
+			errMsg: "Destination must be a file path relative to the secrets directory",
+		},
+		{
+			name:   "invalid perms",
+			modify: func(f *FileFromVariable) { f.Perms = "rw" },
+			errMsg: `Failed to parse "rw" as octal`,
+		},
+		{
+			name:   "signal without signal",
+			modify: func(f *FileFromVariable) { f.ChangeMode = VariableChangeModeSignal },
+			errMsg: "Signal must be specified",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := valid()
+			tc.modify(f)
+			err := f.Validate()
+			if tc.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.errMsg)
+			}
+		})
+	}
+}
@@ -31,6 +31,22 @@ const (
 	// Reply: VariablesByNameResponse
 	VariablesReadRPCMethod = "Variables.Read"
 
+	// VariablesHistoryRPCMethod is the RPC method for listing the versions of
+	// a variable kept in the state store, including those of a deleted
+	// variable.
+	//
+	// Args: VariablesHistoryRequest
+	// Reply: VariablesHistoryResponse
+	VariablesHistoryRPCMethod = "Variables.History"
+
+	// VariablesExpireDeletedRPCMethod is the RPC method used by the core
+	// scheduler to purge the versions of variables that were deleted before
+	// the variables GC threshold.
+	//
+	// Args: VariablesExpireDeletedRequest
+	// Reply: GenericResponse
+	VariablesExpireDeletedRPCMethod = "Variables.ExpireDeleted"
+
 	// maxVariableSize is the maximum size of the unencrypted contents of a
 	// variable. This size is deliberately set low and is not configurable, to
 	// discourage DoS'ing the cluster
@@ -47,6 +63,11 @@ type VariableMetadata struct {
 	CreateTime  int64
 	ModifyIndex uint64
 	ModifyTime  int64
+
+	// Version is incremented every time the items of the variable change,
+	// and continues from the last version of a deleted variable if it's
+	// written again.
+	Version uint64
 }
 
 // VariableEncrypted structs are returned from the Encrypter's encrypt
@@ -211,6 +232,40 @@ func (sv VariableMetadata) GetCreateIndex() uint64 {
 	return sv.CreateIndex
 }
 
+// VariableVersion is a version of a variable kept in the state store after
+// the variable was updated or deleted. The versions of a deleted variable are
+// kept until the variables GC threshold passes, so it can be restored.
+type VariableVersion struct {
+	VariableEncrypted
+
+	// DeleteIndex and DeleteTime are set on the last version of a deleted
+	// variable.
+	DeleteIndex uint64
+	DeleteTime  int64
+}
+
+// Deleted returns true if this is the last version of a deleted variable.
+func (vv *VariableVersion) Deleted() bool {
+	return vv.DeleteIndex != 0
+}
+
+// Metadata returns the metadata of the version, which is the list object of
+// the history of a variable.
+func (vv *VariableVersion) Metadata() *VariableVersionMetadata {
+	return &VariableVersionMetadata{
+		VariableMetadata: vv.VariableMetadata,
+		DeleteIndex:      vv.DeleteIndex,
+		DeleteTime:       vv.DeleteTime,
+	}
+}
+
+// VariableVersionMetadata is the metadata of a version of a variable.
+type VariableVersionMetadata struct {
+	VariableMetadata
+	DeleteIndex uint64
+	DeleteTime  int64
+}
+
 // VariablesQuota is used to track the total size of variables entries per
 // namespace. The total length of Variable.EncryptedData in bytes will be added
 // to the VariablesQuota table in the same transaction as a write, update, or
@@ -243,6 +298,11 @@ const (
 	VarOpDelete    VarOp = "delete"
 	VarOpDeleteCAS VarOp = "delete-cas"
 	VarOpCAS       VarOp = "cas"
+
+	// VarOpRekey replaces the ciphertext of a variable, or of one of its
+	// previous versions, without creating a new version. It's only used to
+	// re-encrypt variables with the active root key.
+	VarOpRekey VarOp = "rekey"
 )
 
 // VarOpResult constants give possible operations results from a transaction.
@@ -293,6 +353,11 @@ func (r *VariablesApplyResponse) IsRedacted() bool {
 type VarApplyStateRequest struct {
 	Op  VarOp              // Which operation are we performing
 	Var *VariableEncrypted // Which directory entry
+
+	// MaxVersions is the number of previous versions of the variable to keep.
+	// It's set by the leader so that all servers prune the same versions.
+	MaxVersions int
+
 	WriteRequest
 }
 
@@ -364,6 +429,11 @@ type VariablesListResponse struct {
 
 type VariablesReadRequest struct {
 	Path string
+
+	// Version is the version of the variable to read. The current version is
+	// read if it is zero.
+	Version uint64
+
 	QueryOptions
 }
 
@@ -371,3 +441,23 @@ type VariablesReadResponse struct {
 	Data *VariableDecrypted
 	QueryMeta
 }
+
+type VariablesHistoryRequest struct {
+	Path string
+	QueryOptions
+}
+
+type VariablesHistoryResponse struct {
+	// Data is the list of versions of the variable, newest first
+	Data []*VariableVersionMetadata
+	QueryMeta
+}
+
+// VariablesExpireDeletedRequest is used to purge the versions of variables
+// that were deleted before a given time.
+type VariablesExpireDeletedRequest struct {
+	// Before is the time in UnixNano before which deleted variables are
+	// purged.
+	Before int64
+	WriteRequest
+}
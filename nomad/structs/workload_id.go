@@ -0,0 +1,286 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	multierror "github.com/hashicorp/go-multierror"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// WorkloadIdentityChangeModeNoop takes no action when an identity is
+	// renewed. Tasks are expected to re-read the token file.
+	WorkloadIdentityChangeModeNoop = "noop"
+
+	// WorkloadIdentityChangeModeSignal signals the task when an identity is
+	// renewed.
+	WorkloadIdentityChangeModeSignal = "signal"
+
+	// WorkloadIdentityChangeModeRestart restarts the task when an identity is
+	// renewed.
+	WorkloadIdentityChangeModeRestart = "restart"
+
+	// MinWorkloadIdentityTTL is the shortest TTL allowed for a workload
+	// identity. Shorter TTLs would have clients renewing identities faster
+	// than is reasonable for the servers to sign them.
+	MinWorkloadIdentityTTL = time.Minute
+)
+
+var (
+	// validWorkloadIdentityName is used to validate workload identity names.
+	// Names are used in file and environment variable names, so they are
+	// restricted to characters safe for both.
+	validWorkloadIdentityName = regexp.MustCompile("^[a-zA-Z0-9_]{1,128}$")
+
+	// workloadIdentityClaimVar matches the variables that can be
+	// interpolated into the value of an extra workload identity claim.
+	workloadIdentityClaimVar = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+	// reservedWorkloadIdentityClaims are the claims set by Nomad that may not
+	// be overridden by the extra claims of a workload identity. In addition
+	// all claims prefixed with "nomad_" are reserved.
+	reservedWorkloadIdentityClaims = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+)
+
+const (
+	workloadIdentityVarDatacenter = "node.datacenter"
+	workloadIdentityVarNodeClass  = "node.class"
+	workloadIdentityVarNodeID     = "node.unique.id"
+	workloadIdentityVarNodeName   = "node.unique.name"
+	workloadIdentityVarMetaPrefix = "meta."
+)
+
+// WorkloadIdentity is a named identity for a task. In addition to the
+// default identity used to authenticate the task to Nomad, a task may have
+// any number of named identities meant to be presented to third parties,
+// each signed with its own audience, lifetime and extra claims.
+type WorkloadIdentity struct {
+	// Name of the identity. It must be unique within the task.
+	Name string
+
+	// Audience is the set of "aud" claims of the identity.
+	Audience []string
+
+	// TTL is the lifetime of the identity. Identities with a TTL are renewed
+	// by the client before they expire. A zero TTL means the identity lives
+	// as long as the allocation.
+	TTL time.Duration
+
+	// Env marks whether the identity should be exposed to the task as the
+	// NOMAD_TOKEN_<name> environment variable.
+	Env bool
+
+	// File marks whether the identity should be written to the task's
+	// secrets directory as nomad_<name>.jwt.
+	File bool
+
+	// ChangeMode is used to configure the task's behavior when the identity
+	// is renewed.
+	ChangeMode string
+
+	// ChangeSignal is the signal sent to the task when the identity is
+	// renewed. This is only valid when using the signal change mode.
+	ChangeSignal string
+
+	// Claims are extra claims added to the identity. Values may interpolate
+	// ${node.datacenter}, ${node.class}, ${node.unique.id},
+	// ${node.unique.name} and ${meta.<key>}, where meta is the combined job,
+	// group and task meta.
+	Claims map[string]string
+}
+
+// Copy returns a copy of this WorkloadIdentity.
+func (wi *WorkloadIdentity) Copy() *WorkloadIdentity {
+	if wi == nil {
+		return nil
+	}
+
+	nwi := new(WorkloadIdentity)
+	*nwi = *wi
+	nwi.Audience = slices.Clone(wi.Audience)
+	nwi.Claims = maps.Clone(wi.Claims)
+	return nwi
+}
+
+// Equal returns whether the two workload identities are the same.
+func (wi *WorkloadIdentity) Equal(other *WorkloadIdentity) bool {
+	if wi == nil || other == nil {
+		return wi == other
+	}
+
+	switch {
+	case wi.Name != other.Name:
+		return false
+	case !slices.Equal(wi.Audience, other.Audience):
+		return false
+	case wi.TTL != other.TTL:
+		return false
+	case wi.Env != other.Env:
+		return false
+	case wi.File != other.File:
+		return false
+	case wi.ChangeMode != other.ChangeMode:
+		return false
+	case wi.ChangeSignal != other.ChangeSignal:
+		return false
+	case len(wi.Claims) != len(other.Claims):
+		return false
+	}
+
+	for k, v := range wi.Claims {
+		if ov, ok := other.Claims[k]; !ok || ov != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Canonicalize sets the defaults of the workload identity.
+func (wi *WorkloadIdentity) Canonicalize() {
+	if wi.ChangeSignal != "" {
+		wi.ChangeSignal = strings.ToUpper(wi.ChangeSignal)
+	}
+
+	if wi.ChangeMode == "" {
+		wi.ChangeMode = WorkloadIdentityChangeModeNoop
+	}
+
+	if len(wi.Claims) == 0 {
+		wi.Claims = nil
+	}
+}
+
+// Validate returns if the workload identity is valid.
+func (wi *WorkloadIdentity) Validate() error {
+	if wi == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if !validWorkloadIdentityName.MatchString(wi.Name) {
+		_ = multierror.Append(&mErr, fmt.Errorf("Invalid name %q: must only contain alphanumeric characters or underscores", wi.Name))
+	}
+
+	if len(wi.Audience) == 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Audience cannot be empty"))
+	}
+	for _, aud := range wi.Audience {
+		if aud == "" {
+			_ = multierror.Append(&mErr, fmt.Errorf("Audience cannot contain an empty value"))
+			break
+		}
+	}
+
+	if wi.TTL < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("TTL cannot be negative"))
+	} else if wi.TTL > 0 && wi.TTL < MinWorkloadIdentityTTL {
+		_ = multierror.Append(&mErr, fmt.Errorf("TTL must be at least %v", MinWorkloadIdentityTTL))
+	}
+
+	switch wi.ChangeMode {
+	case WorkloadIdentityChangeModeSignal:
+		if wi.ChangeSignal == "" {
+			_ = multierror.Append(&mErr, fmt.Errorf("Signal must be specified when using change mode %q", WorkloadIdentityChangeModeSignal))
+		}
+	case WorkloadIdentityChangeModeNoop, WorkloadIdentityChangeModeRestart:
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Unknown change mode %q", wi.ChangeMode))
+	}
+
+	if wi.ChangeMode != WorkloadIdentityChangeModeNoop && wi.TTL == 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Change mode %q requires a TTL", wi.ChangeMode))
+	}
+
+	for k, v := range wi.Claims {
+		if k == "" {
+			_ = multierror.Append(&mErr, fmt.Errorf("Claim names cannot be empty"))
+			continue
+		}
+		if strings.HasPrefix(k, "nomad_") || slices.Contains(reservedWorkloadIdentityClaims, k) {
+			_ = multierror.Append(&mErr, fmt.Errorf("Claim %q is reserved", k))
+		}
+		for _, match := range workloadIdentityClaimVar.FindAllStringSubmatch(v, -1) {
+			if !validWorkloadIdentityClaimVar(match[1]) {
+				_ = multierror.Append(&mErr, fmt.Errorf("Claim %q uses unknown variable %q", k, match[0]))
+			}
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// validWorkloadIdentityClaimVar returns whether the variable can be
+// interpolated into the value of an extra claim.
+func validWorkloadIdentityClaimVar(v string) bool {
+	switch v {
+	case workloadIdentityVarDatacenter, workloadIdentityVarNodeClass,
+		workloadIdentityVarNodeID, workloadIdentityVarNodeName:
+		return true
+	}
+	return strings.HasPrefix(v, workloadIdentityVarMetaPrefix) &&
+		len(v) > len(workloadIdentityVarMetaPrefix)
+}
+
+// WorkloadIdentityKey returns the key of a named workload identity in the
+// SignedIdentities of an allocation. Task names may not contain a slash so
+// the key never collides with the task's default identity.
+func WorkloadIdentityKey(taskName, identityName string) string {
+	return taskName + "/" + identityName
+}
+
+// ToWorkloadIdentityClaims returns the claims of the named workload identity
+// of a task. The node is used to interpolate the extra claims of the
+// identity; variables referring to the node are empty if it is nil.
+func (a *Allocation) ToWorkloadIdentityClaims(job *Job, node *Node, taskName string, wi *WorkloadIdentity) *IdentityClaims {
+	claims := a.ToTaskIdentityClaims(job, taskName)
+	if claims == nil {
+		return nil
+	}
+
+	claims.IdentityName = wi.Name
+	claims.Subject = strings.Join([]string{
+		a.Namespace, claims.JobID, a.TaskGroup, taskName, wi.Name}, ":")
+	claims.Audience = slices.Clone(wi.Audience)
+	if wi.TTL > 0 {
+		claims.ExpiresAt = jwt.NewNumericDate(claims.IssuedAt.Add(wi.TTL))
+	}
+
+	if len(wi.Claims) == 0 {
+		return claims
+	}
+
+	var meta map[string]string
+	if job != nil {
+		meta = job.CombinedTaskMeta(a.TaskGroup, taskName)
+	}
+
+	claims.ExtraClaims = make(map[string]string, len(wi.Claims))
+	for k, v := range wi.Claims {
+		claims.ExtraClaims[k] = workloadIdentityClaimVar.ReplaceAllStringFunc(v, func(match string) string {
+			name := match[2 : len(match)-1]
+			if strings.HasPrefix(name, workloadIdentityVarMetaPrefix) {
+				return meta[strings.TrimPrefix(name, workloadIdentityVarMetaPrefix)]
+			}
+			if node == nil {
+				return ""
+			}
+			switch name {
+			case workloadIdentityVarDatacenter:
+				return node.Datacenter
+			case workloadIdentityVarNodeClass:
+				return node.NodeClass
+			case workloadIdentityVarNodeID:
+				return node.ID
+			case workloadIdentityVarNodeName:
+				return node.Name
+			}
+			return ""
+		})
+	}
+	return claims
+}
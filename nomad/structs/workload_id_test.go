@@ -0,0 +1,182 @@
+package structs
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkloadIdentity_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	valid := func() *WorkloadIdentity {
+		return &WorkloadIdentity{
+			Name:       "vault",
+			Audience:   []string{"vault.io"},
+			ChangeMode: WorkloadIdentityChangeModeNoop,
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(*WorkloadIdentity)
+		errMsg string
+	}{
+		{
+			name:   "valid",
+			modify: func(*WorkloadIdentity) {},
+		},
+		{
+			name:   "invalid name",
+			modify: func(wi *WorkloadIdentity) { wi.Name = "my-identity" },
+			errMsg: `Invalid name "my-identity"`,
+		},
+		{
+			name:   "missing audience",
+			modify: func(wi *WorkloadIdentity) { wi.Audience = nil },
+			errMsg: "Audience cannot be empty",
+		},
+		{
+			name:   "short ttl",
+			modify: func(wi *WorkloadIdentity) { wi.TTL = time.Second },
+			errMsg: "TTL must be at least",
+		},
+		{
+			name: "signal without signal",
+			modify: func(wi *WorkloadIdentity) {
+				wi.TTL = time.Hour
+				wi.ChangeMode = WorkloadIdentityChangeModeSignal
+			},
+			errMsg: "Signal must be specified",
+		},
+		{
+			name:   "restart without ttl",
+			modify: func(wi *WorkloadIdentity) { wi.ChangeMode = WorkloadIdentityChangeModeRestart },
+			errMsg: `Change mode "restart" requires a TTL`,
+		},
+		{
+			name:   "reserved claim",
+			modify: func(wi *WorkloadIdentity) { wi.Claims = map[string]string{"aud": "x"} },
+			errMsg: `Claim "aud" is reserved`,
+		},
+		{
+			name:   "reserved nomad claim",
+			modify: func(wi *WorkloadIdentity) { wi.Claims = map[string]string{"nomad_job_id": "x"} },
+			errMsg: `Claim "nomad_job_id" is reserved`,
+		},
+		{
+			name: "unknown variable",
+			modify: func(wi *WorkloadIdentity) {
+				wi.Claims = map[string]string{"pool": "${node.pool}"}
+			},
+			errMsg: `Claim "pool" uses unknown variable "${node.pool}"`,
+		},
+		{
+			name: "known variables",
+			modify: func(wi *WorkloadIdentity) {
+				wi.Claims = map[string]string{
+					"dc":   "${node.datacenter}",
+					"team": "team-${meta.team}",
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wi := valid()
+			tc.modify(wi)
+			err := wi.Validate()
+			if tc.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.errMsg)
+			}
+		})
+	}
+}
+
+func TestTask_Validate_Identities(t *testing.T) {
+	ci.Parallel(t)
+
+	task := &Task{
+		Identities: []*WorkloadIdentity{
+			{Name: "vault", Audience: []string{"vault.io"}, ChangeMode: WorkloadIdentityChangeModeNoop},
+			{Name: "vault", Audience: []string{"vault.io"}, ChangeMode: WorkloadIdentityChangeModeNoop},
+		},
+	}
+	err := task.Validate(DefaultEphemeralDisk(), JobTypeService, nil, nil)
+	require.ErrorContains(t, err, `Identity "vault" is defined more than once`)
+}
+
+func TestAllocation_ToWorkloadIdentityClaims(t *testing.T) {
+	ci.Parallel(t)
+
+	job := &Job{
+		ID:        "example",
+		Namespace: DefaultNamespace,
+		Meta:      map[string]string{"team": "job"},
+		TaskGroups: []*TaskGroup{{
+			Name: "web",
+			Tasks: []*Task{{
+				Name: "server",
+				Meta: map[string]string{"team": "task"},
+			}},
+		}},
+	}
+	alloc := &Allocation{
+		ID:        "alloc",
+		Namespace: DefaultNamespace,
+		JobID:     job.ID,
+		TaskGroup: "web",
+		Job:       job,
+	}
+	node := &Node{ID: "node", Name: "node1", Datacenter: "dc1", NodeClass: "large"}
+
+	wi := &WorkloadIdentity{
+		Name:     "aws",
+		Audience: []string{"sts.amazonaws.com"},
+		TTL:      time.Hour,
+		Claims: map[string]string{
+			"dc":      "${node.datacenter}",
+			"node":    "${node.unique.name}/${node.class}",
+			"team":    "${meta.team}",
+			"missing": "x${meta.missing}",
+		},
+	}
+
+	claims := alloc.ToWorkloadIdentityClaims(job, node, "server", wi)
+	require.Equal(t, "aws", claims.IdentityName)
+	require.Equal(t, "server", claims.TaskName)
+	require.Equal(t, "default:example:web:server:aws", claims.Subject)
+	require.Equal(t, []string{"sts.amazonaws.com"}, []string(claims.Audience))
+	require.Equal(t, time.Hour, claims.ExpiresAt.Sub(claims.IssuedAt.Time))
+	require.Equal(t, map[string]string{
+		"dc":      "dc1",
+		"node":    "node1/large",
+		"team":    "task",
+		"missing": "x",
+	}, claims.ExtraClaims)
+
+	// extra claims are flattened into the encoded claims
+	buf, err := json.Marshal(claims)
+	require.NoError(t, err)
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf, &out))
+	require.Equal(t, "dc1", out["dc"])
+	require.Equal(t, "aws", out["nomad_identity"])
+	require.Equal(t, "alloc", out["nomad_allocation_id"])
+	require.NotContains(t, out, "ExtraClaims")
+
+	// the default identity has no expiry or identity name
+	claims = alloc.ToTaskIdentityClaims(job, "server")
+	buf, err = json.Marshal(claims)
+	require.NoError(t, err)
+	out = nil
+	require.NoError(t, json.Unmarshal(buf, &out))
+	require.NotContains(t, out, "nomad_identity")
+	require.NotContains(t, out, "exp")
+}
@@ -12,7 +12,6 @@ import (
 	memdb "github.com/hashicorp/go-memdb"
 
 	"github.com/hashicorp/nomad/acl"
-	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -67,12 +66,19 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 		now := time.Now().UnixNano()
 		ev.CreateTime = now // existing will override if it exists
 		ev.ModifyTime = now
+	case structs.VarOpRekey:
+		// The metadata is kept, only the ciphertext is replaced
+		ev, err = sv.encrypt(args.Var)
+		if err != nil {
+			return fmt.Errorf("variable error: encrypt: %w", err)
+		}
 	case structs.VarOpDelete, structs.VarOpDeleteCAS:
 		ev = &structs.VariableEncrypted{
 			VariableMetadata: structs.VariableMetadata{
 				Namespace:   args.Var.Namespace,
 				Path:        args.Var.Path,
 				ModifyIndex: args.Var.ModifyIndex,
+				ModifyTime:  time.Now().UnixNano(), // recorded as the delete time
 			},
 		}
 	}
@@ -81,6 +87,7 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 	sveArgs := structs.VarApplyStateRequest{
 		Op:           args.Op,
 		Var:          ev,
+		MaxVersions:  sv.srv.config.VariablesMaxVersions,
 		WriteRequest: args.WriteRequest,
 	}
 
@@ -124,6 +131,12 @@ func svePreApply(sv *Variables, args *structs.VariablesApplyRequest, vd *structs
 				err = structs.ErrPermissionDenied
 				return
 			}
+		case structs.VarOpRekey:
+			// Only the leader re-encrypts variables
+			if !aclObj.IsManagement() {
+				err = structs.ErrPermissionDenied
+				return
+			}
 		default:
 			err = fmt.Errorf("svPreApply: unexpected VarOp received: %q", args.Op)
 			return
@@ -145,6 +158,12 @@ func svePreApply(sv *Variables, args *structs.VariablesApplyRequest, vd *structs
 			err = fmt.Errorf("delete requires a Path")
 			return
 		}
+
+	case structs.VarOpRekey:
+		if args.Var.Path == "" {
+			err = fmt.Errorf("rekey requires a Path")
+			return
+		}
 	}
 
 	return
@@ -234,6 +253,25 @@ func (sv *Variables) Read(args *structs.VariablesReadRequest, reply *structs.Var
 				return err
 			}
 
+			// Previous versions are read from the versions table, which also
+			// holds the last version of a deleted variable
+			if args.Version != 0 && (out == nil || out.Version != args.Version) {
+				version, err := s.GetVariableVersion(ws,
+					args.RequestNamespace(), args.Path, args.Version)
+				if err != nil {
+					return err
+				}
+				reply.Data = nil
+				if version != nil {
+					dv, err := sv.decrypt(&version.VariableEncrypted)
+					if err != nil {
+						return err
+					}
+					reply.Data = dv
+				}
+				return sv.srv.setReplyQueryMeta(s, state.TableVariablesVersions, &reply.QueryMeta)
+			}
+
 			// Setup the output
 			reply.Data = nil
 			if out != nil {
@@ -252,6 +290,77 @@ func (sv *Variables) Read(args *structs.VariablesReadRequest, reply *structs.Var
 	return sv.srv.blockingRPC(&opts)
 }
 
+// History is used to list the versions of a variable kept in state, including
+// the current version and the versions of a deleted variable.
+func (sv *Variables) History(args *structs.VariablesHistoryRequest, reply *structs.VariablesHistoryResponse) error {
+	if done, err := sv.srv.forward(structs.VariablesHistoryRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "history"}, time.Now())
+
+	_, _, err := sv.handleMixedAuthEndpoint(args.QueryOptions,
+		acl.PolicyList, args.Path)
+	if err != nil {
+		return err
+	}
+
+	return sv.srv.blockingRPC(&blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			out, err := s.GetVariable(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+			versions, err := s.GetVariableVersions(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+
+			reply.Data = make([]*structs.VariableVersionMetadata, 0, len(versions)+1)
+			if out != nil {
+				reply.Data = append(reply.Data, &structs.VariableVersionMetadata{
+					VariableMetadata: out.VariableMetadata,
+				})
+			}
+			for _, version := range versions {
+				reply.Data = append(reply.Data, version.Metadata())
+			}
+
+			// Creating a variable doesn't write to the versions table
+			if err := sv.srv.setReplyQueryMeta(s, state.TableVariablesVersions, &reply.QueryMeta); err != nil {
+				return err
+			}
+			if out != nil && out.ModifyIndex > reply.Index {
+				reply.Index = out.ModifyIndex
+			}
+			return nil
+		}})
+}
+
+// ExpireDeleted is used by the core scheduler to purge the versions of
+// variables that were deleted before the variables GC threshold.
+func (sv *Variables) ExpireDeleted(args *structs.VariablesExpireDeletedRequest, reply *structs.GenericResponse) error {
+	if done, err := sv.srv.forward(structs.VariablesExpireDeletedRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "expire_deleted"}, time.Now())
+
+	// Check management level permissions
+	if aclObj, err := sv.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	_, index, err := sv.srv.raftApply(structs.VarExpireDeletedRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
 // List is used to list variables held within state. It supports single
 // and wildcard namespace listings.
 func (sv *Variables) List(
@@ -475,15 +584,12 @@ func (sv *Variables) handleMixedAuthEndpoint(args structs.QueryOptions, cap, pat
 
 func (sv *Variables) authenticate(args structs.QueryOptions) (*acl.ACL, *structs.IdentityClaims, error) {
 
-	// Perform the initial token resolution.
-	aclObj, err := sv.srv.ResolveToken(args.AuthToken)
-	if err == nil {
-		return aclObj, nil, nil
-	}
-	if helper.IsUUID(args.AuthToken) {
-		// early return for ErrNotFound or other errors if it's formed
-		// like an ACLToken.SecretID
-		return nil, nil, err
+	// Workload identities are verified here rather than resolved to their
+	// attached policies, so that they keep their implicit access to the
+	// variables of their own job.
+	if !sv.srv.config.ACLEnabled || !isWorkloadIdentity(args.AuthToken) {
+		aclObj, err := sv.srv.ResolveToken(args.AuthToken)
+		return aclObj, nil, err
 	}
 
 	// Attempt to verify the token as a JWT with a workload
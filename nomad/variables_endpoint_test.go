@@ -27,6 +27,7 @@ func TestVariablesEndpoint_auth(t *testing.T) {
 	})
 	defer shutdown()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
 
 	const ns = "nondefault-namespace"
 
@@ -368,6 +369,7 @@ func TestVariablesEndpoint_Apply_ACL(t *testing.T) {
 	})
 	defer shutdown()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
 	codec := rpcClient(t, srv)
 	state := srv.fsm.State()
 
@@ -549,6 +551,7 @@ func TestVariablesEndpoint_ListFiltering(t *testing.T) {
 	})
 	defer shutdown()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
 	codec := rpcClient(t, srv)
 
 	ns := "nondefault-namespace"
@@ -624,6 +627,7 @@ func TestVariablesEndpoint_ComplexACLPolicies(t *testing.T) {
 	})
 	defer shutdown()
 	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
 	codec := rpcClient(t, srv)
 
 	idx := uint64(1000)
@@ -746,6 +750,7 @@ func TestVariablesEndpoint_GetVariable_Blocking(t *testing.T) {
 	state := s1.fsm.State()
 	codec := rpcClient(t, s1)
 	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForKeyring(t, s1.RPC, "global")
 
 	// First create an unrelated variable.
 	delay := 100 * time.Millisecond
@@ -848,6 +853,73 @@ func TestVariablesEndpoint_GetVariable_Blocking(t *testing.T) {
 	}
 }
 
+func TestVariablesEndpoint_Versions(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanup := TestServer(t, nil)
+	defer cleanup()
+	codec := rpcClient(t, srv)
+	testutil.WaitForLeader(t, srv.RPC)
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+
+	apply := func(op structs.VarOp, items structs.VariableItems) {
+		req := &structs.VariablesApplyRequest{
+			Op: op,
+			Var: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: "versioned"},
+				Items:            items,
+			},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.VariablesApplyResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, req, &resp))
+		must.True(t, resp.IsOk())
+	}
+	apply(structs.VarOpSet, structs.VariableItems{"key": "one"})
+	apply(structs.VarOpSet, structs.VariableItems{"key": "two"})
+	apply(structs.VarOpDelete, nil)
+
+	// The current version of a deleted variable can't be read
+	readReq := &structs.VariablesReadRequest{
+		Path:         "versioned",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var readResp structs.VariablesReadResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	must.Nil(t, readResp.Data)
+
+	// But its previous versions can, until they are garbage collected
+	readReq.Version = 1
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	must.NotNil(t, readResp.Data)
+	must.Eq(t, structs.VariableItems{"key": "one"}, readResp.Data.Items)
+	must.Eq(t, 1, readResp.Data.Version)
+
+	historyReq := &structs.VariablesHistoryRequest{
+		Path:         "versioned",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var historyResp structs.VariablesHistoryResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesHistoryRPCMethod, historyReq, &historyResp))
+	must.Len(t, 2, historyResp.Data)
+	must.Eq(t, 2, historyResp.Data[0].Version)
+	must.NonZero(t, historyResp.Data[0].DeleteTime)
+	must.Eq(t, 1, historyResp.Data[1].Version)
+	must.Zero(t, historyResp.Data[1].DeleteTime)
+
+	// Writing the variable again lists it as the current version
+	apply(structs.VarOpSet, structs.VariableItems{"key": "one"})
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesHistoryRPCMethod, historyReq, &historyResp))
+	must.Len(t, 3, historyResp.Data)
+	must.Eq(t, 3, historyResp.Data[0].Version)
+	must.Zero(t, historyResp.Data[0].DeleteTime)
+
+	readReq.Version = 3
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, readReq, &readResp))
+	must.NotNil(t, readResp.Data)
+	must.Eq(t, structs.VariableItems{"key": "one"}, readResp.Data.Items)
+}
+
 func writeVar(t *testing.T, s *Server, idx uint64, ns, path string) {
 	store := s.fsm.State()
 	sv := mock.Variable()
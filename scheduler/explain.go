@@ -0,0 +1,238 @@
+package scheduler
+
+import (
+	"sort"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ExplainTaskGroup re-runs the feasibility checks and ranking of a task group
+// against the given state without creating a plan. Every ready node in the
+// job's datacenters is checked on its own, with a fresh context, so that the
+// result contains the exact reason each node was filtered or exhausted
+// instead of the class based short-circuiting used while scheduling.
+func ExplainTaskGroup(logger log.Logger, state State, eval *structs.Evaluation,
+	job *structs.Job, tg *structs.TaskGroup) (*structs.TaskGroupExplanation, error) {
+
+	nodes, _, byDC, err := readyNodesInDCs(state, job.Datacenters)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &structs.TaskGroupExplanation{
+		TaskGroup: tg.Name,
+		Previous:  eval.FailedTGAllocs[tg.Name].Copy(),
+		Metrics:   &structs.AllocMetric{NodesAvailable: byDC},
+		Nodes:     make([]*structs.NodeExplanation, 0, len(nodes)),
+	}
+
+	// The nodes are sorted relative to the largest ask, which only differs
+	// between nodes for tasks reserving cores
+	var sortCPU, sortMem int64
+	options := &SelectOptions{AllocName: structs.AllocName(job.ID, tg.Name, 0)}
+
+	for _, node := range nodes {
+		ctx := NewEvalContext(nil, state, eval.MakePlan(job), logger)
+		stack := newExplainStack(job, ctx)
+		stack.SetJob(job)
+		stack.SetNodes([]*structs.Node{node})
+
+		option := stack.Select(tg, options)
+		metrics := ctx.Metrics()
+		metrics.PopulateScoreMetaData()
+		mergeAllocMetric(explanation.Metrics, metrics)
+
+		nodeExpl := &structs.NodeExplanation{
+			NodeID:     node.ID,
+			NodeName:   node.Name,
+			NodeClass:  node.NodeClass,
+			Datacenter: node.Datacenter,
+		}
+
+		switch {
+		case option != nil:
+			nodeExpl.Feasible = true
+			if best := metrics.MaxNormScore(); best != nil {
+				nodeExpl.Score = best.NormScore
+			}
+		case len(metrics.ConstraintFiltered) > 0:
+			nodeExpl.FilteredBy = firstKey(metrics.ConstraintFiltered)
+		case len(metrics.DimensionExhausted) > 0:
+			nodeExpl.ExhaustedDimension = firstKey(metrics.DimensionExhausted)
+			askCPU, askMem := taskGroupAsk(tg, node)
+			sortCPU = helper.Max(sortCPU, askCPU)
+			sortMem = helper.Max(sortMem, askMem)
+			cpu, mem, err := nodeHeadroom(ctx, node)
+			if err != nil {
+				return nil, err
+			}
+			nodeExpl.CPUShortfall = helper.Max(askCPU-cpu, 0)
+			nodeExpl.MemoryShortfall = helper.Max(askMem-mem, 0)
+		default:
+			// The node was neither filtered nor exhausted but still not
+			// selected, which happens when it is removed by a check that
+			// does not record metrics.
+			nodeExpl.FilteredBy = "unknown"
+		}
+
+		explanation.Nodes = append(explanation.Nodes, nodeExpl)
+	}
+
+	sortNodeExplanations(explanation.Nodes, sortCPU, sortMem)
+	return explanation, nil
+}
+
+// newExplainStack returns the stack the scheduler for the job's type would
+// use to place its allocations.
+func newExplainStack(job *structs.Job, ctx Context) Stack {
+	switch job.Type {
+	case structs.JobTypeSystem:
+		return NewSystemStack(false, ctx)
+	case structs.JobTypeSysBatch:
+		return NewSystemStack(true, ctx)
+	default:
+		return NewGenericStack(job.Type == structs.JobTypeBatch, ctx)
+	}
+}
+
+// taskGroupAsk returns the CPU and memory requested by all tasks of the group
+// on the node. Tasks reserving cores are allocated the CPU shares of their
+// cores, which depend on the node.
+func taskGroupAsk(tg *structs.TaskGroup, node *structs.Node) (cpu, mem int64) {
+	cpu, cores, mem := taskGroupAskCores(tg)
+	if cores > 0 {
+		cpu += cores * nodeSharesPerCore(node)
+	}
+	return cpu, mem
+}
+
+// taskGroupAskCores returns the CPU, number of cores and memory requested by
+// all tasks of the group.
+func taskGroupAskCores(tg *structs.TaskGroup) (cpu, cores, mem int64) {
+	for _, task := range tg.Tasks {
+		if task.Resources == nil {
+			continue
+		}
+		cpu += int64(task.Resources.CPU)
+		cores += int64(task.Resources.Cores)
+		mem += int64(task.Resources.MemoryMB)
+	}
+	return cpu, cores, mem
+}
+
+// nodeSharesPerCore returns the CPU shares of each core of the node, or zero
+// if the node doesn't fingerprint its cores.
+func nodeSharesPerCore(node *structs.Node) int64 {
+	if node == nil || node.NodeResources == nil || node.NodeResources.Cpu.TotalCpuCores == 0 {
+		return 0
+	}
+	return node.NodeResources.Cpu.SharesPerCore()
+}
+
+// nodeHeadroom returns the CPU and memory still available on the node once
+// its reserved resources and proposed allocations are accounted for.
+func nodeHeadroom(ctx Context, node *structs.Node) (cpu, mem int64, err error) {
+	proposed, err := ctx.ProposedAllocs(node.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	_, _, used, err := structs.AllocsFit(node, proposed, nil, false)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	available := node.ComparableResources()
+	available.Subtract(node.ComparableReservedResources())
+	available.Subtract(used)
+	return available.Flattened.Cpu.CpuShares, available.Flattened.Memory.MemoryMB, nil
+}
+
+// sortNodeExplanations sorts the nodes from the closest to fitting the task
+// group to the furthest: feasible nodes by descending score, then exhausted
+// nodes by ascending relative shortfall, then filtered nodes.
+func sortNodeExplanations(nodes []*structs.NodeExplanation, askCPU, askMem int64) {
+	rank := func(n *structs.NodeExplanation) int {
+		switch {
+		case n.Feasible:
+			return 0
+		case n.ExhaustedDimension != "":
+			return 1
+		default:
+			return 2
+		}
+	}
+	shortfall := func(n *structs.NodeExplanation) float64 {
+		var s float64
+		if askCPU > 0 {
+			s += float64(n.CPUShortfall) / float64(askCPU)
+		}
+		if askMem > 0 {
+			s += float64(n.MemoryShortfall) / float64(askMem)
+		}
+		return s
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if ra, rb := rank(a), rank(b); ra != rb {
+			return ra < rb
+		}
+		switch rank(a) {
+		case 0:
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+		case 1:
+			if sa, sb := shortfall(a), shortfall(b); sa != sb {
+				return sa < sb
+			}
+		}
+		return a.NodeName < b.NodeName
+	})
+}
+
+// mergeAllocMetric adds the counters of src into dst.
+func mergeAllocMetric(dst, src *structs.AllocMetric) {
+	dst.NodesEvaluated += src.NodesEvaluated
+	dst.NodesFiltered += src.NodesFiltered
+	dst.NodesExhausted += src.NodesExhausted
+	dst.AllocationTime += src.AllocationTime
+	dst.ClassFiltered = mergeCounts(dst.ClassFiltered, src.ClassFiltered)
+	dst.ConstraintFiltered = mergeCounts(dst.ConstraintFiltered, src.ConstraintFiltered)
+	dst.ClassExhausted = mergeCounts(dst.ClassExhausted, src.ClassExhausted)
+	dst.DimensionExhausted = mergeCounts(dst.DimensionExhausted, src.DimensionExhausted)
+	dst.QuotaExhausted = append(dst.QuotaExhausted, src.QuotaExhausted...)
+	dst.ScoreMetaData = append(dst.ScoreMetaData, src.ScoreMetaData...)
+	sort.Slice(dst.ScoreMetaData, func(i, j int) bool {
+		return dst.ScoreMetaData[i].NormScore > dst.ScoreMetaData[j].NormScore
+	})
+	if len(dst.ScoreMetaData) > structs.MaxRetainedNodeScores {
+		dst.ScoreMetaData = dst.ScoreMetaData[:structs.MaxRetainedNodeScores]
+	}
+}
+
+func mergeCounts(dst, src map[string]int) map[string]int {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]int, len(src))
+	}
+	for k, v := range src {
+		dst[k] += v
+	}
+	return dst
+}
+
+// firstKey returns the lexically smallest key of the map.
+func firstKey(m map[string]int) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys[0]
+}
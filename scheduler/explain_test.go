@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestExplainTaskGroup(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// fits is able to run the task group
+	fits := mock.Node()
+	fits.Name = "fits"
+	must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), fits))
+
+	// filtered does not match the job's kernel constraint
+	filtered := mock.Node()
+	filtered.Name = "filtered"
+	filtered.Attributes["kernel.name"] = "windows"
+	filtered.ComputeClass()
+	must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), filtered))
+
+	// exhausted does not have enough memory left
+	exhausted := mock.Node()
+	exhausted.Name = "exhausted"
+	exhausted.NodeResources.Memory.MemoryMB = 300
+	must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), exhausted))
+
+	// other is in a datacenter the job does not use
+	other := mock.Node()
+	other.Datacenter = "dc2"
+	must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), other))
+
+	job := mock.Job()
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   job.Namespace,
+		ID:          "e1b1ef3c-2a8b-4d63-9c2b-6bd6e4e0d7f6",
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerQueuedAllocs,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusBlocked,
+		FailedTGAllocs: map[string]*structs.AllocMetric{
+			"web": {NodesEvaluated: 3, CoalescedFailures: 9},
+		},
+	}
+
+	tg := job.TaskGroups[0]
+	out, err := ExplainTaskGroup(testlog.HCLogger(t), h.State, eval, job, tg)
+	must.NoError(t, err)
+
+	must.Eq(t, "web", out.TaskGroup)
+	must.Eq(t, 9, out.Previous.CoalescedFailures)
+	must.Eq(t, 3, out.Metrics.NodesEvaluated)
+	must.Eq(t, 1, out.Metrics.NodesFiltered)
+	must.Eq(t, 1, out.Metrics.NodesExhausted)
+	must.Eq(t, map[string]int{"dc1": 3}, out.Metrics.NodesAvailable)
+	must.Len(t, 3, out.Nodes)
+
+	// Nodes are sorted from closest to furthest to fitting
+	must.Eq(t, fits.ID, out.BestNode().NodeID)
+	must.True(t, out.Nodes[0].Feasible)
+	must.Greater(t, 0, out.Nodes[0].Score)
+
+	must.Eq(t, exhausted.ID, out.Nodes[1].NodeID)
+	must.False(t, out.Nodes[1].Feasible)
+	must.Eq(t, "memory", out.Nodes[1].ExhaustedDimension)
+	must.Eq(t, 0, out.Nodes[1].CPUShortfall)
+	must.Eq(t, 212, out.Nodes[1].MemoryShortfall)
+
+	must.Eq(t, filtered.ID, out.Nodes[2].NodeID)
+	must.False(t, out.Nodes[2].Feasible)
+	must.Eq(t, "${attr.kernel.name} = linux", out.Nodes[2].FilteredBy)
+}
@@ -514,6 +514,10 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 	// Capture current time to use as the start time for any rescheduled allocations
 	now := time.Now()
 
+	// Track the placements of gang task groups so they can be backed out if
+	// any allocation of the group fails to be placed
+	var gangs map[string]*gangPlacements
+
 	// Have to handle destructive changes first as we need to discount their
 	// resources. To understand this imagine the resources were reduced and the
 	// count was scaled up.
@@ -653,6 +657,21 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 				// Track the placement
 				s.plan.AppendAlloc(alloc, downgradedJob)
 
+				if tg.Gang {
+					if gangs == nil {
+						gangs = make(map[string]*gangPlacements)
+					}
+					gang, ok := gangs[tg.Name]
+					if !ok {
+						gang = &gangPlacements{}
+						gangs[tg.Name] = gang
+					}
+					gang.placed = append(gang.placed, alloc)
+					if stopPrevAlloc {
+						gang.stopped = append(gang.stopped, prevAllocation)
+					}
+				}
+
 			} else {
 				// Lazy initialize the failed map
 				if s.failedTGAllocs == nil {
@@ -675,9 +694,82 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 		}
 	}
 
+	// Gang task groups are placed as a whole or not at all
+	for name, gang := range gangs {
+		if metric, ok := s.failedTGAllocs[name]; ok {
+			s.backOutPlacements(name, gang)
+			metric.CoalescedFailures += len(gang.placed)
+		}
+	}
+
 	return nil
 }
 
+// gangPlacements are the placements of a gang task group made while computing
+// placements, along with the allocations that were stopped to make room for
+// them.
+type gangPlacements struct {
+	placed  []*structs.Allocation
+	stopped []*structs.Allocation
+}
+
+// backOutPlacements removes the placements of a gang task group from the
+// plan, along with the preemptions they require. The stops of the allocations
+// they replace are only undone for the allocations stopped while computing
+// placements, as the reconciler's own stops, such as those of lost
+// allocations, must be committed regardless.
+func (s *GenericScheduler) backOutPlacements(tgName string, gang *gangPlacements) {
+	backedOut := make(map[string]struct{}, len(gang.placed))
+	for _, alloc := range gang.placed {
+		backedOut[alloc.ID] = struct{}{}
+	}
+
+	for nodeID, allocs := range s.plan.NodeAllocation {
+		if allocs = structs.RemoveAllocs(allocs, gang.placed); len(allocs) > 0 {
+			s.plan.NodeAllocation[nodeID] = allocs
+		} else {
+			delete(s.plan.NodeAllocation, nodeID)
+		}
+	}
+	for _, alloc := range gang.stopped {
+		s.plan.RemoveUpdate(alloc)
+	}
+
+	var preempted []*structs.Allocation
+	for nodeID, allocs := range s.plan.NodePreemptions {
+		for _, alloc := range allocs {
+			if _, ok := backedOut[alloc.PreemptedByAllocation]; ok {
+				preempted = append(preempted, alloc)
+			}
+		}
+		if allocs = structs.RemoveAllocs(allocs, preempted); len(allocs) > 0 {
+			s.plan.NodePreemptions[nodeID] = allocs
+		} else {
+			delete(s.plan.NodePreemptions, nodeID)
+		}
+	}
+
+	if len(preempted) == 0 || s.plan.Annotations == nil {
+		return
+	}
+
+	preemptedIDs := make(map[string]struct{}, len(preempted))
+	for _, alloc := range preempted {
+		preemptedIDs[alloc.ID] = struct{}{}
+		delete(s.plan.Annotations.PreemptionReasons, alloc.ID)
+	}
+	var stubs []*structs.AllocListStub
+	for _, stub := range s.plan.Annotations.PreemptedAllocs {
+		if _, ok := preemptedIDs[stub.ID]; !ok {
+			stubs = append(stubs, stub)
+		}
+	}
+	s.plan.Annotations.PreemptedAllocs = stubs
+	if desired, ok := s.plan.Annotations.DesiredTGUpdates[tgName]; ok {
+		desired.Preemptions -= uint64(len(preempted))
+	}
+}
+
 // propagateTaskState copies task handles from previous allocations to
 // replacement allocations when the previous allocation is being drained or was
 // lost. Remote task drivers rely on this to reconnect to remote tasks when the
@@ -832,6 +924,7 @@ func (s *GenericScheduler) handlePreemptions(option *RankedNode, alloc *structs.
 
 		if s.eval.AnnotatePlan && s.plan.Annotations != nil {
 			s.plan.Annotations.PreemptedAllocs = append(s.plan.Annotations.PreemptedAllocs, stop.Stub(nil))
+			annotatePreemptionReason(s.plan.Annotations, stop, alloc, s.job.Priority, option.PreemptionReasons[stop.ID])
 			if s.plan.Annotations.DesiredTGUpdates != nil {
 				desired := s.plan.Annotations.DesiredTGUpdates[missing.TaskGroup().Name]
 				desired.Preemptions += 1
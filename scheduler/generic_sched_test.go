@@ -1120,6 +1120,283 @@ func TestServiceSched_JobRegister_AllocFail(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_Gang(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create two nodes, which can only run 6 of the gang's allocations
+	for i := 0; i < 2; i++ {
+		node := mock.Node()
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job with a gang task group of 10 allocations and a regular
+	// task group of 1 allocation
+	job := mock.Job()
+	job.TaskGroups[0].Gang = true
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 1000
+	cache := job.TaskGroups[0].Copy()
+	cache.Name = "cache"
+	cache.Count = 1
+	cache.Gang = false
+	cache.Tasks[0].Resources.CPU = 100
+	job.TaskGroups = append(job.TaskGroups, cache)
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Ensure only the regular task group was placed
+	require.Len(t, h.Plans, 1)
+	var planned []*structs.Allocation
+	for _, allocs := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocs...)
+	}
+	require.Len(t, planned, 1)
+	require.Equal(t, "cache", planned[0].TaskGroup)
+
+	// Ensure the gang is blocked as a whole
+	require.Len(t, h.CreateEvals, 1)
+	require.Equal(t, structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+
+	require.Len(t, h.Evals, 1)
+	outEval := h.Evals[0]
+	require.Len(t, outEval.FailedTGAllocs, 1)
+	metrics := outEval.FailedTGAllocs["web"]
+	require.NotNil(t, metrics)
+	require.Equal(t, 9, metrics.CoalescedFailures)
+	require.Equal(t, 10, outEval.QueuedAllocations["web"])
+	require.Equal(t, 0, outEval.QueuedAllocations["cache"])
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
+func TestServiceSched_JobRegister_NamespaceLimits(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Limit the namespace to 4 allocations
+	ns := mock.Namespace()
+	ns.Name = structs.DefaultNamespace
+	ns.Limits = &structs.NamespaceLimits{Allocs: 4}
+	require.NoError(t, h.State.UpsertNamespaces(h.NextIndex(), []*structs.Namespace{ns}))
+
+	// Create an allocation of another job using one of the allocations
+	other := mock.Alloc()
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{other}))
+
+	job := mock.Job()
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Ensure only the allocations within the limits were placed
+	require.Len(t, h.Plans, 1)
+	var planned []*structs.Allocation
+	for _, allocs := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocs...)
+	}
+	require.Len(t, planned, 3)
+
+	// Ensure the eval is blocked on the limits of the namespace
+	require.Len(t, h.CreateEvals, 1)
+	require.Equal(t, structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+	require.Equal(t, structs.DefaultNamespace, h.CreateEvals[0].QuotaLimitReached)
+
+	metrics := h.Evals[0].FailedTGAllocs["web"]
+	require.NotNil(t, metrics)
+	require.Equal(t, []string{"allocs exhausted (5 > 4)"}, metrics.QuotaExhausted)
+	require.Equal(t, 7, h.Evals[0].QueuedAllocations["web"])
+}
+
+func TestServiceSched_JobRegister_NamespaceLimits_Cores(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create nodes with 1000 MHz per core
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		node.NodeResources.Cpu.TotalCpuCores = 4
+		node.NodeResources.Cpu.ReservableCpuCores = []uint16{0, 1, 2, 3}
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Limit the namespace to 2500 MHz
+	ns := mock.Namespace()
+	ns.Name = structs.DefaultNamespace
+	ns.Limits = &structs.NamespaceLimits{CPU: 2500}
+	require.NoError(t, h.State.UpsertNamespaces(h.NextIndex(), []*structs.Namespace{ns}))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 0
+	job.TaskGroups[0].Tasks[0].Resources.Cores = 1
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Ensure the shares of the reserved cores are counted against the limits
+	require.Len(t, h.Plans, 1)
+	var planned []*structs.Allocation
+	for _, allocs := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocs...)
+	}
+	require.Len(t, planned, 2)
+
+	metrics := h.Evals[0].FailedTGAllocs["web"]
+	require.NotNil(t, metrics)
+	require.Equal(t, []string{"cpu exhausted (3000 > 2500)"}, metrics.QuotaExhausted)
+}
+
+func TestServiceSched_Gang_BackOutLostReplacements(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create a down node running the gang, and a node which can only run one
+	// of its replacements
+	down := mock.Node()
+	down.Status = structs.NodeStatusDown
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), down))
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), mock.Node()))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	job.TaskGroups[0].Gang = true
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 2500
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 2; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = down.ID
+		alloc.Name = fmt.Sprintf("my-job.web[%d]", i)
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerNodeUpdate,
+		JobID:       job.ID,
+		NodeID:      down.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// The replacements are backed out, but the lost allocations are still
+	// marked as lost
+	require.Len(t, h.Plans, 1)
+	plan := h.Plans[0]
+	require.Empty(t, plan.NodeAllocation)
+	require.Len(t, plan.NodeUpdate[down.ID], 2)
+	for _, alloc := range plan.NodeUpdate[down.ID] {
+		require.Equal(t, structs.AllocDesiredStatusStop, alloc.DesiredStatus)
+		require.Equal(t, structs.AllocClientStatusLost, alloc.ClientStatus)
+	}
+
+	require.Len(t, h.CreateEvals, 1)
+	require.Equal(t, structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
+func TestServiceSched_Gang_BackOutDestructiveUpdates(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	node := mock.Node()
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	job.TaskGroups[0].Gang = true
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 1000
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 2; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = fmt.Sprintf("my-job.web[%d]", i)
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// Update the job so that only one of the new allocations fits
+	job2 := job.Copy()
+	job2.TaskGroups[0].Tasks[0].Resources.CPU = 2500
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job2))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// The existing allocations are kept running as the gang doesn't fit
+	for _, plan := range h.Plans {
+		require.Empty(t, plan.NodeAllocation)
+		require.Empty(t, plan.NodeUpdate)
+	}
+
+	require.Len(t, h.CreateEvals, 1)
+	require.Equal(t, structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 func TestServiceSched_JobRegister_CreateBlockedEval(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NamespaceLimitIterator is a FeasibleIterator which returns no nodes when
+// placing the task group would take the namespace of the job over its limits.
+// The usage of the namespace accounts for the allocations placed, updated and
+// stopped by the plan being built.
+type NamespaceLimitIterator struct {
+	ctx    Context
+	source FeasibleIterator
+
+	namespace string
+	limits    *structs.NamespaceLimits
+	ask       *structs.NamespaceUsage
+
+	// askCores is the number of cores reserved by the task group, whose CPU
+	// shares are added to the ask of each node.
+	askCores int64
+
+	// usage is the usage of the namespace in the state, which is read once
+	// per evaluation.
+	usage *structs.NamespaceUsage
+
+	// existing caches the allocations of the plan as known to the state, so
+	// that each is only looked up once per evaluation. Allocations that are
+	// not in the state are cached as nil.
+	existing map[string]*structs.Allocation
+
+	// checked, planned and exhausted cache the usage of the namespace once
+	// the plan is applied and the result of the limit check until the
+	// iterator is reset, since the plan does not change while selecting a
+	// node. The limits are checked for each node when the task group
+	// reserves cores.
+	checked   bool
+	planned   *structs.NamespaceUsage
+	exhausted string
+}
+
+// NewNamespaceLimitIterator returns a NamespaceLimitIterator wrapping the
+// source iterator.
+func NewNamespaceLimitIterator(ctx Context, source FeasibleIterator) *NamespaceLimitIterator {
+	return &NamespaceLimitIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *NamespaceLimitIterator) SetJob(job *structs.Job) {
+	iter.checked = false
+
+	// The job is set again with the same namespace when placing allocations
+	// of older versions of the job
+	if iter.usage != nil && iter.namespace == job.Namespace {
+		return
+	}
+
+	iter.namespace = job.Namespace
+	iter.limits = nil
+	iter.usage = nil
+	iter.existing = make(map[string]*structs.Allocation)
+
+	state := iter.ctx.State()
+	ns, err := state.NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		iter.ctx.Logger().Named("namespace_limits").Error("failed to lookup namespace",
+			"namespace", job.Namespace, "error", err)
+		return
+	}
+	if ns == nil || ns.Limits == nil {
+		return
+	}
+
+	usage, err := state.NamespaceUsage(nil, job.Namespace)
+	if err != nil {
+		iter.ctx.Logger().Named("namespace_limits").Error("failed to lookup namespace usage",
+			"namespace", job.Namespace, "error", err)
+		return
+	}
+	iter.limits = ns.Limits
+	iter.usage = usage
+}
+
+func (iter *NamespaceLimitIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	cpu, cores, mem := taskGroupAskCores(tg)
+	iter.ask = &structs.NamespaceUsage{CPU: int(cpu), MemoryMB: int(mem), Allocs: 1}
+	iter.askCores = cores
+	iter.checked = false
+}
+
+func (iter *NamespaceLimitIterator) Next() *structs.Node {
+	if iter.limits == nil {
+		return iter.source.Next()
+	}
+
+	if !iter.checked {
+		iter.checked = true
+		iter.exhausted = ""
+		iter.planned = iter.planUsage()
+		if iter.askCores == 0 {
+			iter.setExhausted(iter.check(0))
+		}
+	}
+
+	if iter.askCores == 0 {
+		if iter.exhausted != "" {
+			return nil
+		}
+		return iter.source.Next()
+	}
+
+	for {
+		node := iter.source.Next()
+		if node == nil {
+			return nil
+		}
+		exhausted := iter.check(nodeSharesPerCore(node))
+		if exhausted == "" {
+			return node
+		}
+		iter.setExhausted(exhausted)
+	}
+}
+
+// setExhausted records the first limit of the namespace exceeded by placing
+// the task group.
+func (iter *NamespaceLimitIterator) setExhausted(exhausted string) {
+	if exhausted == "" || iter.exhausted != "" {
+		return
+	}
+	iter.exhausted = exhausted
+	iter.ctx.Metrics().ExhaustQuota([]string{exhausted})
+	iter.ctx.Eligibility().SetQuotaLimitReached(iter.namespace)
+}
+
+func (iter *NamespaceLimitIterator) Reset() {
+	iter.checked = false
+	iter.source.Reset()
+}
+
+// check returns the limit of the namespace exceeded by placing the task group
+// on a node with the given CPU shares per core, if any.
+func (iter *NamespaceLimitIterator) check(sharesPerCore int64) string {
+	if iter.planned == nil {
+		return ""
+	}
+
+	usage := iter.planned.Copy()
+	if iter.ask != nil {
+		usage.CPU += iter.ask.CPU + int(iter.askCores*sharesPerCore)
+		usage.MemoryMB += iter.ask.MemoryMB
+		usage.Allocs += iter.ask.Allocs
+	}
+	return iter.limits.Exceeded(usage)
+}
+
+// planUsage returns the usage of the namespace once the plan of the context
+// is applied, or nil if it can't be computed.
+func (iter *NamespaceLimitIterator) planUsage() *structs.NamespaceUsage {
+	usage, err := iter.computePlanUsage()
+	if err != nil {
+		iter.ctx.Logger().Named("namespace_limits").Error("failed to compute namespace usage",
+			"namespace", iter.namespace, "error", err)
+		return nil
+	}
+	return usage
+}
+
+// computePlanUsage returns the usage of the namespace once the plan of the
+// context is applied.
+func (iter *NamespaceLimitIterator) computePlanUsage() (*structs.NamespaceUsage, error) {
+	usage := iter.usage.Copy()
+	plan := iter.ctx.Plan()
+	seen := make(map[string]struct{})
+
+	// remove subtracts the allocation as it is known to the state, since the
+	// allocations stopped by the plan may be stripped of their resources
+	remove := func(alloc *structs.Allocation) error {
+		if alloc.Namespace != iter.namespace {
+			return nil
+		}
+		if _, ok := seen[alloc.ID]; ok {
+			return nil
+		}
+		seen[alloc.ID] = struct{}{}
+
+		existing, ok := iter.existing[alloc.ID]
+		if !ok {
+			var err error
+			existing, err = iter.ctx.State().AllocByID(nil, alloc.ID)
+			if err != nil {
+				return err
+			}
+			iter.existing[alloc.ID] = existing
+		}
+		if existing != nil && !existing.TerminalStatus() {
+			usage.Subtract(existing)
+		}
+		return nil
+	}
+
+	for _, allocs := range plan.NodeUpdate {
+		for _, alloc := range allocs {
+			if err := remove(alloc); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, allocs := range plan.NodePreemptions {
+		for _, alloc := range allocs {
+			if err := remove(alloc); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			if err := remove(alloc); err != nil {
+				return nil, err
+			}
+			if alloc.Namespace == iter.namespace && !alloc.TerminalStatus() {
+				usage.Add(alloc)
+			}
+		}
+	}
+
+	return usage, nil
+}
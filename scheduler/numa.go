@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/exp/slices"
+)
+
+// selectReservedCores selects the cores to reserve for a task among the
+// available cores of the node. When the task requests NUMA affinity, the cores
+// are selected from a single NUMA node, preferring the NUMA nodes local to the
+// devices assigned to the task, and cores sharing a physical core are selected
+// together so that tasks share physical cores as little as possible. Other
+// tasks are reserved the lowest available cores. If the cores can not be
+// reserved, the exhausted dimension is returned instead.
+func selectReservedCores(cpu *structs.NodeCpuResources, available cpuset.CPUSet, count int,
+	numa *structs.NUMAResource, deviceNUMANodes []uint16) ([]uint16, string) {
+
+	if available.Size() < count {
+		return nil, "cores"
+	}
+
+	if !numa.Requested() {
+		return available.ToSlice()[:count], ""
+	}
+
+	required := numa.Affinity == structs.NUMAAffinityRequire
+	local := make(map[uint16]struct{}, len(deviceNUMANodes))
+	for _, id := range deviceNUMANodes {
+		local[id] = struct{}{}
+	}
+
+	type candidate struct {
+		id    uint16
+		local bool
+		cores cpuset.CPUSet
+	}
+	var candidates []candidate
+	for _, node := range cpu.NUMANodes {
+		cores := available.Intersection(cpuset.New(node.Cores...))
+		if cores.Size() < count {
+			continue
+		}
+
+		_, isLocal := local[node.ID]
+		if required && len(local) > 0 && !isLocal {
+			// Devices must be co-located with the cores
+			continue
+		}
+		candidates = append(candidates, candidate{id: node.ID, local: isLocal, cores: cores})
+	}
+
+	if len(candidates) == 0 {
+		if required {
+			return nil, "cores: numa"
+		}
+		return orderBySiblings(cpu, available)[:count], ""
+	}
+
+	// Prefer the NUMA nodes local to the devices, then the NUMA node with
+	// the fewest available cores to limit fragmentation
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.local != b.local {
+			return a.local
+		}
+		if a.cores.Size() != b.cores.Size() {
+			return a.cores.Size() < b.cores.Size()
+		}
+		return a.id < b.id
+	})
+	return orderBySiblings(cpu, candidates[0].cores)[:count], ""
+}
+
+// orderBySiblings orders the available cores so that the cores of physical
+// cores that are entirely available come first, grouped with their siblings,
+// followed by the cores of partially used physical cores.
+func orderBySiblings(cpu *structs.NodeCpuResources, available cpuset.CPUSet) []uint16 {
+	cores := available.ToSlice()
+	if len(cpu.ThreadSiblings) == 0 {
+		return cores
+	}
+
+	var whole, partial []uint16
+	grouped := cpuset.New()
+	for _, siblings := range cpu.ThreadSiblings {
+		set := cpuset.New(siblings...)
+		free := available.Intersection(set)
+		switch {
+		case free.Size() == 0:
+			continue
+		case free.Equal(set):
+			whole = append(whole, free.ToSlice()...)
+		default:
+			partial = append(partial, free.ToSlice()...)
+		}
+		grouped = grouped.Union(free)
+	}
+
+	ordered := append(whole, partial...)
+	return append(ordered, available.Difference(grouped).ToSlice()...)
+}
+
+// devicesNUMANodes returns the NUMA nodes local to the devices assigned to a
+// task, if known.
+func devicesNUMANodes(node *structs.Node, devices []*structs.AllocatedDeviceResource) []uint16 {
+	var ids []uint16
+	seen := make(map[uint16]struct{})
+	for _, device := range devices {
+		for _, nodeDevice := range node.NodeResources.Devices {
+			if !nodeDevice.ID().Equal(device.ID()) {
+				continue
+			}
+
+			for _, instance := range nodeDevice.Instances {
+				if instance.Locality == nil || instance.Locality.NUMANode == nil {
+					continue
+				}
+				if !slices.Contains(device.DeviceIDs, instance.ID) {
+					continue
+				}
+				id := *instance.Locality.NUMANode
+				if _, ok := seen[id]; !ok {
+					seen[id] = struct{}{}
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// numaTestCPU returns two NUMA nodes of 4 cores, where cores n and n+1 are
+// hyperthread siblings.
+func numaTestCPU() *structs.NodeCpuResources {
+	return &structs.NodeCpuResources{
+		ReservableCpuCores: []uint16{0, 1, 2, 3, 4, 5, 6, 7},
+		NUMANodes: []*structs.NodeNUMANode{
+			{ID: 0, Cores: []uint16{0, 1, 2, 3}},
+			{ID: 1, Cores: []uint16{4, 5, 6, 7}},
+		},
+		ThreadSiblings: [][]uint16{{0, 1}, {2, 3}, {4, 5}, {6, 7}},
+	}
+}
+
+func TestSelectReservedCores(t *testing.T) {
+	ci.Parallel(t)
+
+	prefer := &structs.NUMAResource{Affinity: structs.NUMAAffinityPrefer}
+	require := &structs.NUMAResource{Affinity: structs.NUMAAffinityRequire}
+
+	cases := []struct {
+		name      string
+		available []uint16
+		count     int
+		numa      *structs.NUMAResource
+		devices   []uint16
+		expCores  []uint16
+		expDim    string
+	}{
+		{
+			name:      "not enough cores",
+			available: []uint16{0, 1},
+			count:     3,
+			expDim:    "cores",
+		},
+		{
+			name:      "no affinity takes lowest cores",
+			available: []uint16{1, 2, 3, 4},
+			count:     2,
+			expCores:  []uint16{1, 2},
+		},
+		{
+			name:      "affinity takes siblings before partial cores",
+			available: []uint16{1, 2, 3, 4},
+			count:     2,
+			numa:      prefer,
+			expCores:  []uint16{2, 3},
+		},
+		{
+			name:      "prefer smallest fitting numa node",
+			available: []uint16{1, 2, 3, 4, 5, 6, 7},
+			count:     3,
+			numa:      prefer,
+			expCores:  []uint16{2, 3, 1},
+		},
+		{
+			name:      "prefer falls back across numa nodes",
+			available: []uint16{2, 3, 4, 5},
+			count:     3,
+			numa:      prefer,
+			expCores:  []uint16{2, 3, 4},
+		},
+		{
+			name:      "require fails across numa nodes",
+			available: []uint16{2, 3, 4, 5},
+			count:     3,
+			numa:      require,
+			expDim:    "cores: numa",
+		},
+		{
+			name:      "prefer device local numa node",
+			available: []uint16{0, 1, 2, 3, 4, 5, 6, 7},
+			count:     2,
+			numa:      prefer,
+			devices:   []uint16{1},
+			expCores:  []uint16{4, 5},
+		},
+		{
+			name:      "require device local numa node",
+			available: []uint16{0, 1, 2, 3, 6, 7},
+			count:     3,
+			numa:      require,
+			devices:   []uint16{1},
+			expDim:    "cores: numa",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cores, dim := selectReservedCores(numaTestCPU(), cpuset.New(tc.available...), tc.count, tc.numa, tc.devices)
+			must.Eq(t, tc.expDim, dim)
+			must.Eq(t, tc.expCores, cores)
+		})
+	}
+}
+
+func TestOrderBySiblings(t *testing.T) {
+	ci.Parallel(t)
+
+	cpu := numaTestCPU()
+	cpu.ThreadSiblings = cpu.ThreadSiblings[:3]
+
+	// 6 and 7 are not part of a sibling group, 0 and 5 are partially used
+	ordered := orderBySiblings(cpu, cpuset.New(0, 2, 3, 5, 6, 7))
+	must.Eq(t, []uint16{2, 3, 0, 5, 6, 7}, ordered)
+
+	// Without topology the cores are ordered by ID
+	ordered = orderBySiblings(&structs.NodeCpuResources{}, cpuset.New(3, 1, 2))
+	must.Eq(t, []uint16{1, 2, 3}, ordered)
+}
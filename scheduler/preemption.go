@@ -3,6 +3,7 @@ package scheduler
 import (
 	"math"
 	"sort"
+	"time"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -12,6 +13,10 @@ import (
 // number of allocations being preempted exceeds max_parallel value in the job's migrate stanza
 const maxParallelPenalty = 50.0
 
+// preemptionBudgetWindow is the period over which the MaxPerHour preemption
+// budget of a job is enforced.
+const preemptionBudgetWindow = time.Hour
+
 type groupedAllocs struct {
 	priority int
 	allocs   []*structs.Allocation
@@ -22,6 +27,18 @@ type allocInfo struct {
 	resources   *structs.ComparableResources
 }
 
+// jobPreemptionInfo captures the preemption policy of a job owning candidate
+// allocations.
+type jobPreemptionInfo struct {
+	// protected is true if none of the job's allocations may be preempted.
+	protected bool
+
+	// budget is the number of the job's allocations that may still be
+	// preempted within the budget window, or -1 if there is no limit. It does
+	// not account for preemptions already in the plan.
+	budget int
+}
+
 // PreemptionResource interface is implemented by different
 // types of resources.
 type PreemptionResource interface {
@@ -117,6 +134,14 @@ type Preemptor struct {
 	// currentAllocs is the candidate set used to find preemptible allocations
 	currentAllocs []*structs.Allocation
 
+	// jobInfo caches the preemption policy of the jobs owning candidate
+	// allocations
+	jobInfo map[structs.NamespacedID]*jobPreemptionInfo
+
+	// selected tracks the number of allocations per job returned by previous
+	// calls of this preemptor, which count against the job's budget
+	selected map[structs.NamespacedID]int
+
 	// ctx is the context from the scheduler stack
 	ctx Context
 }
@@ -127,6 +152,8 @@ func NewPreemptor(jobPriority int, ctx Context, jobID *structs.NamespacedID) *Pr
 		jobPriority:        jobPriority,
 		jobID:              jobID,
 		allocDetails:       make(map[string]*allocInfo),
+		jobInfo:            make(map[structs.NamespacedID]*jobPreemptionInfo),
+		selected:           make(map[structs.NamespacedID]int),
 		ctx:                ctx,
 	}
 }
@@ -153,6 +180,11 @@ func (p *Preemptor) SetCandidates(allocs []*structs.Allocation) {
 			continue
 		}
 
+		// Ignore any allocations protected by their job's preemption policy
+		if !p.withinBudget([]*structs.Allocation{alloc}) {
+			continue
+		}
+
 		maxParallel := 0
 		tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
 		if tg != nil && tg.Migrate != nil {
@@ -182,6 +214,103 @@ func (p *Preemptor) SetPreemptions(allocs []*structs.Allocation) {
 	}
 }
 
+// getJobInfo returns the preemption policy of the job owning the allocation.
+// The latest version of the job is used so that a policy change applies to
+// allocations placed before it.
+func (p *Preemptor) getJobInfo(alloc *structs.Allocation) *jobPreemptionInfo {
+	id := structs.NewNamespacedID(alloc.JobID, alloc.Namespace)
+	if info, ok := p.jobInfo[id]; ok {
+		return info
+	}
+
+	info := &jobPreemptionInfo{budget: -1}
+	p.jobInfo[id] = info
+
+	job := alloc.Job
+	if latest, err := p.ctx.State().JobByID(nil, alloc.Namespace, alloc.JobID); err == nil && latest != nil {
+		job = latest
+	}
+	if job == nil || job.Preemption == nil {
+		return info
+	}
+	policy := job.Preemption
+
+	if policy.NeverPreempt {
+		info.protected = true
+		return info
+	}
+
+	if policy.ProtectDeployments {
+		deployment, err := p.ctx.State().LatestDeploymentByJobID(nil, alloc.Namespace, alloc.JobID)
+		if err != nil {
+			p.ctx.Logger().Named("preemption").Error("failed to lookup deployment", "job_id", alloc.JobID, "error", err)
+			info.protected = true
+			return info
+		}
+		if deployment != nil && deployment.Active() {
+			info.protected = true
+			return info
+		}
+	}
+
+	if policy.MaxPerHour > 0 {
+		allocs, err := p.ctx.State().AllocsByJob(nil, alloc.Namespace, alloc.JobID, true)
+		if err != nil {
+			p.ctx.Logger().Named("preemption").Error("failed to lookup allocations", "job_id", alloc.JobID, "error", err)
+			info.protected = true
+			return info
+		}
+
+		since := time.Now().Add(-preemptionBudgetWindow).UnixNano()
+		info.budget = policy.MaxPerHour
+		for _, a := range allocs {
+			if a.PreemptedByAllocation != "" && a.PreemptedTime >= since {
+				info.budget--
+			}
+		}
+	}
+
+	return info
+}
+
+// withinBudget returns whether preempting all of the given allocations
+// respects the preemption policy of their jobs, accounting for preemptions
+// already in the plan and those previously selected by this preemptor.
+func (p *Preemptor) withinBudget(allocs []*structs.Allocation) bool {
+	counts := make(map[structs.NamespacedID]int)
+	for _, alloc := range allocs {
+		info := p.getJobInfo(alloc)
+		if info.protected {
+			return false
+		}
+		if info.budget < 0 {
+			continue
+		}
+
+		id := structs.NewNamespacedID(alloc.JobID, alloc.Namespace)
+		if counts[id] == 0 {
+			for _, c := range p.currentPreemptions[id] {
+				counts[id] += c
+			}
+			counts[id] += p.selected[id]
+		}
+		counts[id]++
+		if counts[id] > info.budget {
+			return false
+		}
+	}
+	return true
+}
+
+// selectPreemptions records the allocations returned for preemption so they
+// count against the budget of their jobs in later calls.
+func (p *Preemptor) selectPreemptions(allocs []*structs.Allocation) []*structs.Allocation {
+	for _, alloc := range allocs {
+		p.selected[structs.NewNamespacedID(alloc.JobID, alloc.Namespace)]++
+	}
+	return allocs
+}
+
 // getNumPreemptions counts the number of other allocations being preempted that match the job and task group of
 // the alloc under consideration. This is used as a scoring factor to minimize too many allocs of the same job being preempted at once
 func (p *Preemptor) getNumPreemptions(alloc *structs.Allocation) int {
@@ -231,6 +360,14 @@ func (p *Preemptor) PreemptForTaskGroup(resourceAsk *structs.AllocatedResources)
 				}
 			}
 			closestAlloc := allocGrp.allocs[closestAllocIndex]
+			allocGrp.allocs[closestAllocIndex] = allocGrp.allocs[len(allocGrp.allocs)-1]
+			allocGrp.allocs = allocGrp.allocs[:len(allocGrp.allocs)-1]
+
+			// Skip the alloc if preempting it would exceed its job's budget
+			if !p.withinBudget(append(bestAllocs, closestAlloc)) {
+				continue
+			}
+
 			closestResources := p.allocDetails[closestAlloc.ID].resources
 			availableResources.Add(closestResources)
 
@@ -239,9 +376,6 @@ func (p *Preemptor) PreemptForTaskGroup(resourceAsk *structs.AllocatedResources)
 
 			bestAllocs = append(bestAllocs, closestAlloc)
 
-			allocGrp.allocs[closestAllocIndex] = allocGrp.allocs[len(allocGrp.allocs)-1]
-			allocGrp.allocs = allocGrp.allocs[:len(allocGrp.allocs)-1]
-
 			// This is the remaining total of resources needed
 			resourcesNeeded.Subtract(closestResources)
 		}
@@ -260,8 +394,7 @@ func (p *Preemptor) PreemptForTaskGroup(resourceAsk *structs.AllocatedResources)
 	basePreemptionResource := GetBasePreemptionResourceFactory()
 	resourcesNeeded = resourceAsk.Comparable()
 	filteredBestAllocs := p.filterSuperset(bestAllocs, p.nodeRemainingResources, resourcesNeeded, basePreemptionResource)
-	return filteredBestAllocs
-
+	return p.selectPreemptions(filteredBestAllocs)
 }
 
 // PreemptForNetwork tries to find allocations to preempt to meet network resources.
@@ -450,7 +583,10 @@ OUTER:
 		},
 	}
 	filteredBestAllocs := p.filterSuperset(allocsToPreempt, nodeRemainingResources, resourcesNeeded, preemptionResourceFactory)
-	return filteredBestAllocs
+	if !p.withinBudget(filteredBestAllocs) {
+		return nil
+	}
+	return p.selectPreemptions(filteredBestAllocs)
 }
 
 // deviceGroupAllocs represents a group of allocs that share a device
@@ -548,7 +684,11 @@ OUTER:
 
 	// Find the combination of allocs with lowest net priority
 	if len(preemptionOptions) > 0 {
-		return selectBestAllocs(preemptionOptions, int(neededCount))
+		bestAllocs := selectBestAllocs(preemptionOptions, int(neededCount))
+		if !p.withinBudget(bestAllocs) {
+			return nil
+		}
+		return p.selectPreemptions(bestAllocs)
 	}
 
 	return nil
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -1570,3 +1571,162 @@ func createAllocInner(id string, job *structs.Job, resource *structs.Resources,
 	}
 	return alloc
 }
+
+func TestPreemption_JobPolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	// The test setup:
+	//  * a node with 4000 MHz of CPU
+	//  * a low priority job with 4 allocs, each using 1000 MHz
+	//
+	// Then schedule a high priority job needing 1800 MHz, which can only be
+	// placed by preempting 2 of the low priority allocs if their job's
+	// preemption policy allows it.
+	cases := []struct {
+		name          string
+		policy        *structs.PreemptionPolicy
+		recent        int
+		old           int
+		deployment    bool
+		expPreemption bool
+	}{
+		{
+			name:          "no policy",
+			expPreemption: true,
+		},
+		{
+			name:          "never preempt",
+			policy:        &structs.PreemptionPolicy{NeverPreempt: true},
+			expPreemption: false,
+		},
+		{
+			name:          "within budget",
+			policy:        &structs.PreemptionPolicy{MaxPerHour: 2},
+			expPreemption: true,
+		},
+		{
+			name:          "budget too small",
+			policy:        &structs.PreemptionPolicy{MaxPerHour: 1},
+			expPreemption: false,
+		},
+		{
+			name:          "budget used by recent preemptions",
+			policy:        &structs.PreemptionPolicy{MaxPerHour: 2},
+			recent:        1,
+			expPreemption: false,
+		},
+		{
+			name:          "budget not used by old preemptions",
+			policy:        &structs.PreemptionPolicy{MaxPerHour: 2},
+			old:           2,
+			expPreemption: true,
+		},
+		{
+			name:          "no active deployment",
+			policy:        &structs.PreemptionPolicy{ProtectDeployments: true},
+			expPreemption: true,
+		},
+		{
+			name:          "active deployment",
+			policy:        &structs.PreemptionPolicy{ProtectDeployments: true},
+			deployment:    true,
+			expPreemption: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			node := mock.Node()
+			node.NodeResources.Cpu.CpuShares = 4000
+			require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+			lowPrioJob := mock.Job()
+			lowPrioJob.Priority = 5
+			lowPrioJob.Preemption = tc.policy
+			lowPrioJob.TaskGroups[0].Count = 4
+			lowPrioJob.TaskGroups[0].Networks = nil
+			lowPrioJob.TaskGroups[0].Tasks[0].Services = nil
+			lowPrioJob.TaskGroups[0].Tasks[0].Resources.Networks = nil
+			lowPrioJob.TaskGroups[0].Tasks[0].Resources.CPU = 1000
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), lowPrioJob))
+
+			var allocs []*structs.Allocation
+			for i := 0; i < 4; i++ {
+				alloc := createAlloc(uuid.Generate(), lowPrioJob, lowPrioJob.TaskGroups[0].Tasks[0].Resources)
+				alloc.NodeID = node.ID
+				allocs = append(allocs, alloc)
+			}
+			for i := 0; i < tc.recent; i++ {
+				alloc := createAlloc(uuid.Generate(), lowPrioJob, lowPrioJob.TaskGroups[0].Tasks[0].Resources)
+				alloc.NodeID = node.ID
+				alloc.DesiredStatus = structs.AllocDesiredStatusEvict
+				alloc.ClientStatus = structs.AllocClientStatusComplete
+				alloc.PreemptedByAllocation = uuid.Generate()
+				alloc.PreemptedTime = time.Now().UnixNano()
+				alloc.ModifyTime = time.Now().UnixNano()
+				allocs = append(allocs, alloc)
+			}
+			for i := 0; i < tc.old; i++ {
+				// preempted over an hour ago, but modified since
+				alloc := createAlloc(uuid.Generate(), lowPrioJob, lowPrioJob.TaskGroups[0].Tasks[0].Resources)
+				alloc.NodeID = node.ID
+				alloc.DesiredStatus = structs.AllocDesiredStatusEvict
+				alloc.ClientStatus = structs.AllocClientStatusComplete
+				alloc.PreemptedByAllocation = uuid.Generate()
+				alloc.PreemptedTime = time.Now().Add(-2 * time.Hour).UnixNano()
+				alloc.ModifyTime = time.Now().UnixNano()
+				allocs = append(allocs, alloc)
+			}
+			require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+			if tc.deployment {
+				d := mock.Deployment()
+				d.JobID = lowPrioJob.ID
+				d.JobVersion = lowPrioJob.Version
+				d.JobCreateIndex = lowPrioJob.CreateIndex
+				require.NoError(t, h.State.UpsertDeployment(h.NextIndex(), d))
+			}
+
+			highPrioJob := mock.Job()
+			highPrioJob.Priority = 100
+			highPrioJob.TaskGroups[0].Count = 1
+			highPrioJob.TaskGroups[0].Networks = nil
+			highPrioJob.TaskGroups[0].Tasks[0].Services = nil
+			highPrioJob.TaskGroups[0].Tasks[0].Resources.Networks = nil
+			highPrioJob.TaskGroups[0].Tasks[0].Resources.CPU = 1800
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), highPrioJob))
+
+			eval := &structs.Evaluation{
+				Namespace:    structs.DefaultNamespace,
+				ID:           uuid.Generate(),
+				Priority:     highPrioJob.Priority,
+				TriggeredBy:  structs.EvalTriggerJobRegister,
+				JobID:        highPrioJob.ID,
+				Status:       structs.EvalStatusPending,
+				AnnotatePlan: true,
+			}
+			require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+			require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+			if !tc.expPreemption {
+				for _, plan := range h.Plans {
+					require.Empty(t, plan.NodePreemptions)
+				}
+				return
+			}
+
+			require.Len(t, h.Plans, 1)
+			plan := h.Plans[0]
+			require.Len(t, plan.NodePreemptions[node.ID], 2)
+
+			require.NotNil(t, plan.Annotations)
+			require.Len(t, plan.Annotations.PreemptionReasons, 2)
+			for _, alloc := range plan.NodePreemptions[node.ID] {
+				require.Contains(t, plan.Annotations.PreemptionReasons[alloc.ID], "cpu exhausted")
+				require.Contains(t, plan.Annotations.PreemptionReasons[alloc.ID], "job priority 5 < 100")
+			}
+		})
+	}
+}
@@ -33,6 +33,10 @@ type RankedNode struct {
 	// PreemptedAllocs is used by the BinpackIterator to identify allocs
 	// that should be preempted in order to make the placement
 	PreemptedAllocs []*structs.Allocation
+
+	// PreemptionReasons records the resource each of the PreemptedAllocs is
+	// preempted for, keyed by allocation ID
+	PreemptionReasons map[string]string
 }
 
 func (r *RankedNode) GoString() string {
@@ -52,6 +56,16 @@ func (r *RankedNode) ProposedAllocs(ctx Context) ([]*structs.Allocation, error)
 	return p, nil
 }
 
+// setPreemptionReason records the reason the allocations are preempted.
+func (r *RankedNode) setPreemptionReason(allocs []*structs.Allocation, reason string) {
+	if r.PreemptionReasons == nil {
+		r.PreemptionReasons = make(map[string]string, len(allocs))
+	}
+	for _, alloc := range allocs {
+		r.PreemptionReasons[alloc.ID] = reason
+	}
+}
+
 func (r *RankedNode) SetTaskResources(task *structs.Task,
 	resource *structs.AllocatedTaskResources) {
 	if r.TaskResources == nil {
@@ -313,6 +327,7 @@ OUTER:
 					continue OUTER
 				}
 				allocsToPreempt = append(allocsToPreempt, netPreemptions...)
+				option.setPreemptionReason(netPreemptions, fmt.Sprintf("network: %s", err))
 
 				// First subtract out preempted allocations
 				proposed = structs.RemoveAllocs(proposed, netPreemptions)
@@ -383,6 +398,7 @@ OUTER:
 						continue OUTER
 					}
 					allocsToPreempt = append(allocsToPreempt, netPreemptions...)
+					option.setPreemptionReason(netPreemptions, fmt.Sprintf("network: %s", err))
 
 					// First subtract out preempted allocations
 					proposed = structs.RemoveAllocs(proposed, netPreemptions)
@@ -428,6 +444,7 @@ OUTER:
 						continue OUTER
 					}
 					allocsToPreempt = append(allocsToPreempt, devicePreemptions...)
+					option.setPreemptionReason(devicePreemptions, fmt.Sprintf("devices: %s", err))
 
 					// First subtract out preempted allocations
 					proposed = structs.RemoveAllocs(proposed, allocsToPreempt)
@@ -475,15 +492,21 @@ OUTER:
 				// set of CPUs not yet reserved on the node
 				availableCPUSet := nodeCPUSet.Difference(allocatedCPUSet)
 
+				// Select the cores to reserve, honoring the NUMA affinity of the
+				// task and the locality of its devices
+				nodeCpu := &option.Node.NodeResources.Cpu
+				reservedCores, dim := selectReservedCores(nodeCpu, availableCPUSet, task.Resources.Cores,
+					task.Resources.NUMA, devicesNUMANodes(option.Node, taskResources.Devices))
+
 				// If not enough cores are available mark the node as exhausted
-				if availableCPUSet.Size() < task.Resources.Cores {
+				if reservedCores == nil {
 					// TODO preemption
-					iter.ctx.Metrics().ExhaustedNode(option.Node, "cores")
+					iter.ctx.Metrics().ExhaustedNode(option.Node, dim)
 					continue OUTER
 				}
 
 				// Set the task's reserved cores
-				taskResources.Cpu.ReservedCores = availableCPUSet.ToSlice()[0:task.Resources.Cores]
+				taskResources.Cpu.ReservedCores = reservedCores
 				// Total CPU usage on the node is still tracked by CPUShares. Even though the task will have the entire
 				// core reserved, we still track overall usage by cpu shares.
 				taskResources.Cpu.CpuShares = option.Node.NodeResources.Cpu.SharesPerCore() * int64(task.Resources.Cores)
@@ -528,6 +551,7 @@ OUTER:
 				iter.ctx.Metrics().ExhaustedNode(option.Node, dim)
 				continue
 			}
+			option.setPreemptionReason(preemptedAllocs, fmt.Sprintf("%s exhausted", dim))
 		}
 		if len(allocsToPreempt) > 0 {
 			option.PreemptedAllocs = allocsToPreempt
@@ -111,6 +111,13 @@ type State interface {
 
 	// LatestIndex returns the greatest index value for all indexes.
 	LatestIndex() (uint64, error)
+
+	// NamespaceByName is used to lookup a namespace by name
+	NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error)
+
+	// NamespaceUsage returns the resources used by the non-terminal
+	// allocations of the namespace
+	NamespaceUsage(ws memdb.WatchSet, namespace string) (*structs.NamespaceUsage, error)
 }
 
 // Planner interface is used to submit a task allocation plan.
@@ -467,6 +467,7 @@ func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 				preemptedAllocIDs = append(preemptedAllocIDs, stop.ID)
 				if s.eval.AnnotatePlan && s.plan.Annotations != nil {
 					s.plan.Annotations.PreemptedAllocs = append(s.plan.Annotations.PreemptedAllocs, stop.Stub(nil))
+					annotatePreemptionReason(s.plan.Annotations, stop, alloc, s.job.Priority, option.PreemptionReasons[stop.ID])
 					if s.plan.Annotations.DesiredTGUpdates != nil {
 						desired := s.plan.Annotations.DesiredTGUpdates[tgName]
 						desired.Preemptions += 1
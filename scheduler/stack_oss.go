@@ -3,6 +3,8 @@
 
 package scheduler
 
-func NewQuotaIterator(_ Context, source FeasibleIterator) FeasibleIterator {
-	return source
+// NewQuotaIterator returns the iterator enforcing the limits of the namespace
+// of the job.
+func NewQuotaIterator(ctx Context, source FeasibleIterator) FeasibleIterator {
+	return NewNamespaceLimitIterator(ctx, source)
 }
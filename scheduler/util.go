@@ -557,6 +557,15 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 		if !reflect.DeepEqual(at.Templates, bt.Templates) {
 			return true
 		}
+		if !reflect.DeepEqual(at.Identities, bt.Identities) {
+			return true
+		}
+		if !reflect.DeepEqual(at.EnvFromVariables, bt.EnvFromVariables) {
+			return true
+		}
+		if !reflect.DeepEqual(at.FileFromVariables, bt.FileFromVariables) {
+			return true
+		}
 		if !reflect.DeepEqual(at.CSIPluginConfig, bt.CSIPluginConfig) {
 			return true
 		}
@@ -1056,6 +1065,23 @@ func desiredUpdates(diff *diffResult, inplaceUpdates,
 	return desiredTgs
 }
 
+// annotatePreemptionReason records in the plan annotations why the preempted
+// allocation is evicted to make room for alloc.
+func annotatePreemptionReason(annotations *structs.PlanAnnotations, preempted, alloc *structs.Allocation, priority int, reason string) {
+	if annotations.PreemptionReasons == nil {
+		annotations.PreemptionReasons = make(map[string]string)
+	}
+
+	msg := fmt.Sprintf("evicted for %q", alloc.Name)
+	if reason != "" {
+		msg += ": " + reason
+	}
+	if preempted.Job != nil {
+		msg += fmt.Sprintf(", job priority %d < %d", preempted.Job.Priority, priority)
+	}
+	annotations.PreemptionReasons[preempted.ID] = msg
+}
+
 // adjustQueuedAllocations decrements the number of allocations pending per task
 // group based on the number of allocations successfully placed
 func adjustQueuedAllocations(logger log.Logger, result *structs.PlanResult, queuedAllocs map[string]int) {
@@ -837,6 +837,17 @@ func TestTasksUpdated(t *testing.T) {
 	j28 := j27.Copy()
 	j28.TaskGroups[0].Tasks[0].CSIPluginConfig.Type = "monolith"
 	require.True(t, tasksUpdated(j27, j28, name))
+
+	// Alter a workload identity
+	j29 := mock.Job()
+	j29.TaskGroups[0].Tasks[0].Identities = []*structs.WorkloadIdentity{{
+		Name:     "vault",
+		Audience: []string{"vault.io"},
+	}}
+	j30 := j29.Copy()
+	require.False(t, tasksUpdated(j29, j30, name))
+	j30.TaskGroups[0].Tasks[0].Identities[0].TTL = time.Hour
+	require.True(t, tasksUpdated(j29, j30, name))
 }
 
 func TestTasksUpdated_connectServiceUpdated(t *testing.T) {
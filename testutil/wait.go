@@ -141,6 +141,24 @@ func WaitForLeader(t testing.TB, rpc rpcFn) {
 	})
 }
 
+// WaitForKeyring blocks until the keyring is initialized.
+func WaitForKeyring(t testing.TB, rpc rpcFn, region string) {
+	t.Helper()
+	if region == "" {
+		region = "global"
+	}
+	WaitForResult(func() (bool, error) {
+		args := &structs.GenericRequest{
+			QueryOptions: structs.QueryOptions{Region: region},
+		}
+		var resp structs.KeyringListPublicResponse
+		err := rpc("Keyring.ListPublic", args, &resp)
+		return len(resp.PublicKeys) > 0, err
+	}, func(err error) {
+		t.Fatalf("failed to wait for keyring: %v", err)
+	})
+}
+
 // WaitForClient blocks until the client can be found
 func WaitForClient(t testing.TB, rpc rpcFn, nodeID string, region string) {
 